@@ -5,19 +5,33 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/mholtzscher/ugh/internal/flags"
 	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/templates"
 )
 
 //nolint:gochecknoglobals // CLI command definitions are package-level by design.
 var addCmd = &cli.Command{
-	Name:      "add",
-	Aliases:   []string{"a"},
-	Usage:     "Add a task",
-	Category:  "Tasks",
+	Name:     "add",
+	Aliases:  []string{"a"},
+	Usage:    "Add a task",
+	Category: "Tasks",
+	Description: `Add a task. The title accepts the shell DSL, so tags and fields can
+be written inline. Flags win when both set a field; a quoted argument is
+kept verbatim in the title.
+
+With --template, the tasks of a template are created instead of a title,
+filling its {name} placeholders from --var.
+
+		Examples:
+		  ugh add Call mom #family @phone due:friday
+		  ugh add Draft report -p work --due 2026-03-01
+		  ugh add "Email #support about the refund"
+		  ugh add --template onboarding --var name=Sam`,
 	ArgsUsage: "<title>",
 	Flags: []cli.Flag{
 		&cli.StringFlag{
@@ -66,8 +80,23 @@ var addCmd = &cli.Command{
 			Aliases: []string{"x"},
 			Usage:   "mark task done",
 		},
+		&cli.StringFlag{
+			Name:  flags.FlagTemplate,
+			Usage: "create the tasks of a template instead of a title",
+		},
+		&cli.StringSliceFlag{
+			Name:  flags.FlagVar,
+			Usage: "template variable name=value (repeatable)",
+		},
 	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.IsSet(flags.FlagTemplate) {
+			return addFromTemplate(ctx, cmd)
+		}
+		if cmd.IsSet(flags.FlagVar) {
+			return errors.New("--var needs --template")
+		}
+
 		title := strings.TrimSpace(strings.Join(commandArgs(cmd), " "))
 		if title == "" {
 			return errors.New("title required")
@@ -84,21 +113,19 @@ var addCmd = &cli.Command{
 			return fmt.Errorf("sync pull: %w", err)
 		}
 
-		state := cmd.String(flags.FlagState)
-		if cmd.Bool(flags.FlagDone) {
-			state = flags.TaskStateDone
+		titleDates := titleDatesEnabled() && cmd.String(flags.FlagDueOn) == ""
+		req, diagnostics, err := createRequestFromArgs(commandArgs(cmd), svc.MetaSchema(), titleDates)
+		if err != nil {
+			return err
+		}
+		applyAddFlags(cmd, &req)
+		for _, diag := range diagnostics {
+			if _, err = fmt.Fprintln(cmd.Root().ErrWriter, diag.Message); err != nil {
+				return err
+			}
 		}
 
-		task, err := svc.CreateTask(ctx, service.CreateTaskRequest{
-			Title:      title,
-			Notes:      cmd.String(flags.FlagNotes),
-			State:      state,
-			Projects:   cmd.StringSlice(flags.FlagProject),
-			Contexts:   cmd.StringSlice(flags.FlagContext),
-			Meta:       cmd.StringSlice(flags.FlagMeta),
-			DueOn:      cmd.String(flags.FlagDueOn),
-			WaitingFor: cmd.String(flags.FlagWaitingFor),
-		})
+		task, err := svc.CreateTask(ctx, req)
 		if err != nil {
 			return err
 		}
@@ -117,3 +144,82 @@ var addCmd = &cli.Command{
 		return writer.WriteTask(task)
 	},
 }
+
+// addFromTemplate creates the tasks of the --template template. The template
+// sets every field, so it takes no title or task flags.
+func addFromTemplate(ctx context.Context, cmd *cli.Command) error {
+	if len(commandArgs(cmd)) > 0 {
+		return errors.New("--template takes no title; use --var to fill the template")
+	}
+	for _, name := range []string{
+		flags.FlagState, flags.FlagNotes, flags.FlagProject, flags.FlagContext,
+		flags.FlagMeta, flags.FlagDueOn, flags.FlagWaitingFor, flags.FlagDone,
+	} {
+		if cmd.IsSet(name) {
+			return fmt.Errorf("--%s cannot be combined with --template", name)
+		}
+	}
+
+	set, err := loadTemplates()
+	if err != nil {
+		return err
+	}
+	name := cmd.String(flags.FlagTemplate)
+	tmpl, err := set.Lookup(name)
+	if err != nil {
+		return err
+	}
+	vars, err := templates.ParseVars(cmd.StringSlice(flags.FlagVar))
+	if err != nil {
+		return err
+	}
+
+	svc, err := newService(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = svc.Close() }()
+
+	err = maybeSyncBeforeWrite(ctx, svc)
+	if err != nil {
+		return fmt.Errorf("sync pull: %w", err)
+	}
+	tasks, err := templates.Create(ctx, svc, tmpl, vars, time.Now())
+	if err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	err = maybeSyncAfterWrite(ctx, svc)
+	if err != nil {
+		return fmt.Errorf("sync push: %w", err)
+	}
+
+	writer := outputWriter()
+	if writer.TTY && !writer.JSON {
+		return writer.WriteSuccess(fmt.Sprintf("Created %d tasks from %s", len(tasks), name))
+	}
+	return writer.WriteTasks(tasks)
+}
+
+// applyAddFlags applies flags over the request compiled from the title
+// arguments. Single-valued flags win; project, context and meta flags add to
+// any given in the title.
+func applyAddFlags(cmd *cli.Command, req *service.CreateTaskRequest) {
+	if cmd.IsSet(flags.FlagState) {
+		req.State = cmd.String(flags.FlagState)
+	}
+	if cmd.Bool(flags.FlagDone) {
+		req.State = flags.TaskStateDone
+	}
+	if notes := cmd.String(flags.FlagNotes); notes != "" {
+		req.Notes = notes
+	}
+	if due := cmd.String(flags.FlagDueOn); due != "" {
+		req.DueOn = due
+	}
+	if waitingFor := cmd.String(flags.FlagWaitingFor); waitingFor != "" {
+		req.WaitingFor = waitingFor
+	}
+	req.Projects = append(req.Projects, cmd.StringSlice(flags.FlagProject)...)
+	req.Contexts = append(req.Contexts, cmd.StringSlice(flags.FlagContext)...)
+	req.Meta = append(req.Meta, cmd.StringSlice(flags.FlagMeta)...)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+var errBulkAborted = errors.New("aborted")
+
+func bulkFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  flags.FlagWhere,
+			Usage: "apply to every task matching a filter expression instead of IDs",
+		},
+		&cli.BoolFlag{
+			Name:    flags.FlagYes,
+			Aliases: []string{"y"},
+			Usage:   "skip the --where confirmation prompt",
+		},
+	}
+}
+
+// bulkTargets lists the tasks matching a --where expression within scope, in
+// ID order. scope picks the done or open tasks the action applies to; its
+// filter is replaced by the expression.
+func bulkTargets(
+	ctx context.Context, svc service.Service, where string, scope service.ListTasksRequest,
+) ([]*store.Task, error) {
+	filterExpr, _, err := buildListFilter(listFilterOptions{Where: where})
+	if err != nil {
+		return nil, err
+	}
+	if filterExpr == nil {
+		return nil, fmt.Errorf("--%s requires a filter expression", flags.FlagWhere)
+	}
+	scope.Filter = filterExpr
+	tasks, err := svc.ListTasks(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(tasks, func(a, b *store.Task) int { return cmp.Compare(a.ID, b.ID) })
+	return tasks, nil
+}
+
+// confirmBulk previews tasks on stderr and asks before applying action to
+// them. --yes skips the prompt; JSON mode and closed input require it.
+func confirmBulk(cmd *cli.Command, action string, tasks []*store.Task) error {
+	if cmd.Bool(flags.FlagYes) {
+		return nil
+	}
+	if rootJSON {
+		return fmt.Errorf("--%s requires --%s with --json", flags.FlagWhere, flags.FlagYes)
+	}
+
+	root := cmd.Root()
+	var preview strings.Builder
+	_, _ = fmt.Fprintf(&preview, "%s will apply to %d task(s):\n", action, len(tasks))
+	for _, task := range tasks {
+		_, _ = fmt.Fprintf(&preview, "  #%d %s\n", task.ID, task.Title)
+	}
+	_, _ = fmt.Fprint(&preview, "Apply? [y/N] ")
+	if _, err := fmt.Fprint(root.ErrWriter, preview.String()); err != nil {
+		return err
+	}
+
+	answer, err := bufio.NewReader(root.Reader).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errBulkAborted
+	}
+}
+
+func bulkSummary(action string, results []service.BulkTaskResult) output.BulkSummary {
+	summary := output.BulkSummary{Action: action, Results: make([]output.TaskResult, 0, len(results))}
+	for _, result := range results {
+		if result.Status != service.BulkStatusUnchanged {
+			summary.Count++
+		}
+		summary.Results = append(summary.Results, output.TaskResult{
+			ID:     result.ID,
+			Title:  result.Title,
+			Status: result.Status,
+		})
+	}
+	return summary
+}
+
+func taskIDs(tasks []*store.Task) []int64 {
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+// runBulk resolves --where within scope, confirms, applies apply to the
+// matching IDs and writes the per-task results.
+func runBulk(
+	ctx context.Context,
+	cmd *cli.Command,
+	svc service.Service,
+	action string,
+	scope service.ListTasksRequest,
+	apply func(ids []int64) ([]service.BulkTaskResult, error),
+) error {
+	tasks, err := bulkTargets(ctx, svc, cmd.String(flags.FlagWhere), scope)
+	if err != nil {
+		return err
+	}
+	if len(tasks) > 0 {
+		if err = confirmBulk(cmd, action, tasks); err != nil {
+			return err
+		}
+	}
+
+	results, err := apply(taskIDs(tasks))
+	if err != nil {
+		return err
+	}
+	err = maybeSyncAfterWrite(ctx, svc)
+	if err != nil {
+		return fmt.Errorf("sync push: %w", err)
+	}
+	writer := outputWriter()
+	return writer.WriteSummary(bulkSummary(action, results))
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var checkCmd = &cli.Command{
+	Name:     "check",
+	Usage:    "Manage a task's checklist",
+	Category: "Tasks",
+	Description: `Add, check off and remove the checklist items of a task. Items are
+numbered from 1 as shown by ugh show; list shows progress such as [2/5].
+
+		Examples:
+		  ugh check add 7 "pack charger"
+		  ugh check toggle 7 2
+		  ugh check rm 7 1`,
+	Commands: []*cli.Command{
+		checkAddCmd,
+		checkToggleCmd,
+		checkRmCmd,
+	},
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var checkAddCmd = &cli.Command{
+	Name:      "add",
+	Usage:     "Add an item to a task's checklist",
+	ArgsUsage: "<id> <text...>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		args := commandArgs(cmd)
+		if len(args) < 2 {
+			return errors.New("check add requires a task id and item text")
+		}
+		ids, err := parseIDs(args[:1])
+		if err != nil {
+			return err
+		}
+		text := strings.Join(args[1:], " ")
+		return runTaskWrite(ctx, func(svc service.Service) (*store.Task, error) {
+			return svc.AddChecklistItem(ctx, ids[0], text)
+		})
+	},
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var checkToggleCmd = &cli.Command{
+	Name:      "toggle",
+	Aliases:   []string{"t"},
+	Usage:     "Check or uncheck a checklist item",
+	ArgsUsage: "<id> <n>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		id, n, err := checklistItemArgs(cmd, "check toggle")
+		if err != nil {
+			return err
+		}
+		return runTaskWrite(ctx, func(svc service.Service) (*store.Task, error) {
+			return svc.ToggleChecklistItem(ctx, id, n)
+		})
+	},
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var checkRmCmd = &cli.Command{
+	Name:      "rm",
+	Usage:     "Remove a checklist item",
+	ArgsUsage: "<id> <n>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		id, n, err := checklistItemArgs(cmd, "check rm")
+		if err != nil {
+			return err
+		}
+		return runTaskWrite(ctx, func(svc service.Service) (*store.Task, error) {
+			return svc.RemoveChecklistItem(ctx, id, n)
+		})
+	},
+}
+
+func checklistItemArgs(cmd *cli.Command, name string) (int64, int, error) {
+	args := commandArgs(cmd)
+	if len(args) != 2 {
+		return 0, 0, fmt.Errorf("%s requires a task id and an item number", name)
+	}
+	ids, err := parseIDs(args[:1])
+	if err != nil {
+		return 0, 0, err
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("invalid checklist item number: %s", args[1])
+	}
+	return ids[0], n, nil
+}
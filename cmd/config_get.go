@@ -65,6 +65,8 @@ func getConfigValue(cfg *config.Config, key string) (string, error) {
 		return cfg.DB.AuthToken, nil
 	case configKeyDBSyncOnWrite:
 		return strconv.FormatBool(cfg.DB.SyncOnWrite), nil
+	case configKeyTitleDates:
+		return strconv.FormatBool(cfg.Input.TitleDates), nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
@@ -6,4 +6,5 @@ const (
 	configKeyDBSyncURL     = "db.sync_url"
 	configKeyDBAuthToken   = "db.auth_token" //nolint:gosec // This is a config key name, not a credential
 	configKeyDBSyncOnWrite = "db.sync_on_write"
+	configKeyTitleDates    = "input.title_dates"
 )
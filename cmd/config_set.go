@@ -79,6 +79,13 @@ func setConfigValue(cfg *config.Config, key string, value string) error {
 		}
 		cfg.DB.SyncOnWrite = parsed
 		return nil
+	case configKeyTitleDates:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean for %s: %w", configKeyTitleDates, err)
+		}
+		cfg.Input.TitleDates = parsed
+		return nil
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
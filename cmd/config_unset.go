@@ -71,6 +71,9 @@ func unsetConfigValue(cfg *config.Config, key string) error {
 	case configKeyDBSyncOnWrite:
 		cfg.DB.SyncOnWrite = false
 		return nil
+	case configKeyTitleDates:
+		cfg.Input.TitleDates = false
+		return nil
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
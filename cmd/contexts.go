@@ -3,11 +3,14 @@ package cmd
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/mholtzscher/ugh/internal/flags"
 	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
 )
 
 //nolint:gochecknoglobals // CLI command definitions are package-level by design.
@@ -45,6 +48,10 @@ var contextsCmd = &cli.Command{
 			Name:  flags.FlagCounts,
 			Usage: "include counts",
 		},
+		&cli.BoolFlag{
+			Name:  flags.FlagAvailable,
+			Usage: "only contexts whose [contexts] schedule is open now",
+		},
 	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
 		svc, err := newService(ctx)
@@ -62,6 +69,17 @@ var contextsCmd = &cli.Command{
 			return err
 		}
 
+		if cmd.Bool(flags.FlagAvailable) {
+			schedules, scheduleErr := configuredSchedules()
+			if scheduleErr != nil {
+				return scheduleErr
+			}
+			now := time.Now()
+			tags = slices.DeleteFunc(tags, func(tag store.NameCount) bool {
+				return !schedules.Available([]string{tag.Name}, now)
+			})
+		}
+
 		writer := outputWriter()
 		if cmd.Bool(flags.FlagCounts) {
 			return writer.WriteTagsWithCounts(tags)
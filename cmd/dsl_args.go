@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/nlp/compile"
+	"github.com/mholtzscher/ugh/internal/service"
+)
+
+// dslInput joins command arguments into shell DSL input after verb. An
+// argument containing spaces was quoted by the user, so it is quoted again to
+// stay a single title word.
+func dslInput(verb string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, verb)
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		if strings.ContainsFunc(arg, unicode.IsSpace) || strings.Contains(arg, `"`) {
+			arg = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// createRequestFromArgs compiles add arguments with the shell DSL, so tags and
+// field operations in the title apply as they do in the shell. Arguments the
+// DSL cannot tokenize or parse, such as "it's" or "a; b", are kept verbatim as
+// the title with a warning diagnostic; invalid field values are errors.
+func createRequestFromArgs(
+	args []string, schema domain.MetaSchema, titleDates bool,
+) (service.CreateTaskRequest, []nlp.Diagnostic, error) {
+	now := time.Now()
+	parsed, err := nlp.Parse(dslInput("add", args), nlp.ParseOptions{Mode: nlp.ModeCreate, Now: now})
+	if err != nil {
+		if parsed.Command != nil {
+			return service.CreateTaskRequest{}, nil, err
+		}
+		title := strings.TrimSpace(strings.Join(args, " "))
+		diag := nlp.Diagnostic{
+			Severity: nlp.SeverityWarning,
+			Code:     "W_TITLE_VERBATIM",
+			Message:  "kept the title verbatim: " + err.Error(),
+		}
+		return service.CreateTaskRequest{Title: title, State: domain.TaskStateInbox}, []nlp.Diagnostic{diag}, nil
+	}
+	plan, err := compile.Build(parsed, compile.BuildOptions{Now: now, MetaSchema: schema, TitleDates: titleDates})
+	if err != nil {
+		return service.CreateTaskRequest{}, nil, err
+	}
+	return *plan.Create, plan.Diagnostics, nil
+}
+
+// updateRequestFromArgs compiles edit arguments after the task ID, such as
+// "+#work !due state:now", with the shell DSL. id is zero for --where edits.
+func updateRequestFromArgs(args []string, id int64, schema domain.MetaSchema) (service.UpdateTaskRequest, error) {
+	now := time.Now()
+	parsed, err := nlp.Parse(dslInput("set", args), nlp.ParseOptions{Mode: nlp.ModeUpdate, Now: now})
+	if err != nil {
+		return service.UpdateTaskRequest{}, err
+	}
+	if cmd, ok := parsed.Command.(*nlp.UpdateCommand); ok && (cmd.Target == nil || cmd.Target.Kind != nlp.TargetSelected) {
+		return service.UpdateTaskRequest{}, errors.New("edit takes one task id; use --where to change several tasks")
+	}
+	return compile.BuildUpdateRequest(parsed, id, compile.BuildOptions{Now: now, MetaSchema: schema})
+}
+
+// mergeUpdateRequests applies flag changes over DSL changes. Flags win for
+// single-valued fields; list and meta changes from both are kept.
+func mergeUpdateRequests(dsl service.UpdateTaskRequest, flagReq service.UpdateTaskRequest) service.UpdateTaskRequest {
+	merged := dsl
+	merged.Title = firstSet(flagReq.Title, dsl.Title)
+	merged.Notes = firstSet(flagReq.Notes, dsl.Notes)
+	merged.State = firstSet(flagReq.State, dsl.State)
+
+	switch {
+	case flagReq.DueOn != nil:
+		merged.DueOn, merged.ClearDueOn = flagReq.DueOn, false
+	case flagReq.ClearDueOn:
+		merged.DueOn, merged.ClearDueOn = nil, true
+	}
+	switch {
+	case flagReq.WaitingFor != nil:
+		merged.WaitingFor, merged.ClearWaitingFor = flagReq.WaitingFor, false
+	case flagReq.ClearWaitingFor:
+		merged.WaitingFor, merged.ClearWaitingFor = nil, true
+	}
+
+	merged.AddProjects = append(merged.AddProjects, flagReq.AddProjects...)
+	merged.AddContexts = append(merged.AddContexts, flagReq.AddContexts...)
+	merged.RemoveProjects = append(merged.RemoveProjects, flagReq.RemoveProjects...)
+	merged.RemoveContexts = append(merged.RemoveContexts, flagReq.RemoveContexts...)
+	merged.RemoveMetaKeys = append(merged.RemoveMetaKeys, flagReq.RemoveMetaKeys...)
+	if len(flagReq.SetMeta) > 0 {
+		merged.SetMeta = maps.Clone(merged.SetMeta)
+		if merged.SetMeta == nil {
+			merged.SetMeta = make(map[string]string, len(flagReq.SetMeta))
+		}
+		maps.Copy(merged.SetMeta, flagReq.SetMeta)
+	}
+	return merged
+}
+
+func firstSet(values ...*string) *string {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
@@ -32,9 +32,19 @@ Use flags for quick single-field changes without opening an editor.
 		  ugh edit 1 --title "New title"      # Change title
 		  ugh edit 1 -p urgent                # Add project 'urgent'
 		  ugh edit 1 --remove-project old     # Remove project 'old'
-		  ugh edit 1 -c work -m key:val       # Add context and metadata`,
-	ArgsUsage: "<id>",
-	Flags: []cli.Flag{
+		  ugh edit 1 -c work -m key:val       # Add context and metadata
+		  ugh edit 1 +#work !due state:now    # Shell DSL changes after the ID
+		  ugh edit state:now                  # Pick the task interactively
+
+		DSL changes and flags can be mixed; flags win when both set a field.
+
+		Use --where instead of an ID to apply field changes to every matching
+		task in one transaction. Matches are previewed before anything is
+		changed; pass --yes to skip the confirmation.
+
+		  ugh edit --where "#old && state:later" --state now -p new`,
+	ArgsUsage: "[id] [changes...]",
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:    flags.FlagTitle,
 			Aliases: []string{flags.FlagDescription},
@@ -121,16 +131,24 @@ Use flags for quick single-field changes without opening an editor.
 			Aliases: []string{"e"},
 			Usage:   "open in $VISUAL/$EDITOR",
 		},
-	},
+	}, bulkFlags()...),
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		if cmd.Args().Len() != 1 {
-			return errors.New("edit requires a task id")
+		if cmd.String(flags.FlagWhere) != "" {
+			return runBulkEdit(ctx, cmd)
 		}
-		ids, err := parseIDs(commandArgs(cmd))
-		if err != nil {
-			return err
+		// Without a leading id every argument is a change and the task is
+		// picked interactively.
+		args := commandArgs(cmd)
+		var ids []int64
+		ops := args
+		if len(args) > 0 && isTaskIDArg(args[0]) {
+			var err error
+			ids, err = parseIDs(args[:1])
+			if err != nil {
+				return err
+			}
+			ops = args[1:]
 		}
-		id := ids[0]
 
 		svc, err := newService(ctx)
 		if err != nil {
@@ -138,6 +156,12 @@ Use flags for quick single-field changes without opening an editor.
 		}
 		defer func() { _ = svc.Close() }()
 
+		ids, err = idsOrPick(ctx, cmd, svc, ids, service.ListTasksRequest{TodoOnly: true})
+		if err != nil {
+			return err
+		}
+		id := ids[0]
+
 		err = maybeSyncBeforeWrite(ctx, svc)
 		if err != nil {
 			return fmt.Errorf("sync pull: %w", err)
@@ -145,7 +169,7 @@ Use flags for quick single-field changes without opening an editor.
 
 		var saved *store.Task
 		changed := false
-		hasFields := hasFieldFlags(cmd)
+		hasFields := hasFieldFlags(cmd) || len(ops) > 0
 		if cmd.Bool(flags.FlagEditor) && hasFields {
 			return errors.New("cannot combine field flags with --editor")
 		}
@@ -156,7 +180,7 @@ Use flags for quick single-field changes without opening an editor.
 				return err
 			}
 		} else {
-			saved, err = runFlagsMode(ctx, cmd, svc, id)
+			saved, err = runFlagsMode(ctx, cmd, svc, id, ops)
 			if err != nil {
 				return err
 			}
@@ -224,9 +248,12 @@ func runEditorMode(ctx context.Context, svc service.Service, id int64) (*store.T
 		State:      edited.State,
 		DueOn:      edited.DueOn,
 		WaitingFor: edited.WaitingFor,
+		FollowUpOn: edited.FollowUpOn,
 		Projects:   edited.Projects,
 		Contexts:   edited.Contexts,
 		Meta:       edited.Meta,
+		Checklist:  edited.ChecklistItems(),
+		Annotation: edited.Annotation,
 	})
 	if updateErr != nil {
 		return nil, false, updateErr
@@ -235,14 +262,97 @@ func runEditorMode(ctx context.Context, svc service.Service, id int64) (*store.T
 	return updatedTask, true, nil
 }
 
-func runFlagsMode(ctx context.Context, cmd *cli.Command, svc service.Service, id int64) (*store.Task, error) {
+func runBulkEdit(ctx context.Context, cmd *cli.Command) error {
+	ops := commandArgs(cmd)
+	switch {
+	case len(ops) > 0 && isTaskIDArg(ops[0]):
+		return fmt.Errorf("cannot combine a task id with --%s", flags.FlagWhere)
+	case cmd.Bool(flags.FlagEditor):
+		return fmt.Errorf("cannot combine --%s with --%s", flags.FlagEditor, flags.FlagWhere)
+	case cmd.Bool(flags.FlagDone) || cmd.Bool(flags.FlagUndone):
+		return fmt.Errorf("use done --%s or undo --%s to change completion in bulk", flags.FlagWhere, flags.FlagWhere)
+	case !hasFieldFlags(cmd) && len(ops) == 0:
+		return fmt.Errorf("edit --%s requires at least one field flag", flags.FlagWhere)
+	}
+
+	svc, err := newService(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = svc.Close() }()
+
+	req, err := editRequest(cmd, svc, 0, ops)
+	if err != nil {
+		return err
+	}
+
+	err = maybeSyncBeforeWrite(ctx, svc)
+	if err != nil {
+		return fmt.Errorf("sync pull: %w", err)
+	}
+	scope := service.ListTasksRequest{}
+	return runBulk(ctx, cmd, svc, "edit", scope, func(ids []int64) ([]service.BulkTaskResult, error) {
+		return svc.BulkUpdateTasks(ctx, ids, req)
+	})
+}
+
+func runFlagsMode(
+	ctx context.Context, cmd *cli.Command, svc service.Service, id int64, ops []string,
+) (*store.Task, error) {
+	req, err := editRequest(cmd, svc, id, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply field updates first.
+	updated, err := svc.UpdateTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Bool(flags.FlagDone) {
+		_, err = svc.SetDone(ctx, []int64{id}, true)
+		if err != nil {
+			return nil, err
+		}
+		return svc.GetTask(ctx, id)
+	}
+	if cmd.Bool(flags.FlagUndone) {
+		_, err = svc.SetDone(ctx, []int64{id}, false)
+		if err != nil {
+			return nil, err
+		}
+		return svc.GetTask(ctx, id)
+	}
+	return updated, nil
+}
+
+// editRequest combines DSL operations given after the task ID with field
+// flags; flags win where both set the same field.
+func editRequest(cmd *cli.Command, svc service.Service, id int64, ops []string) (service.UpdateTaskRequest, error) {
+	req, err := updateRequestFromFlags(cmd)
+	if err != nil {
+		return service.UpdateTaskRequest{}, err
+	}
+	if len(ops) > 0 {
+		dsl, dslErr := updateRequestFromArgs(ops, id, svc.MetaSchema())
+		if dslErr != nil {
+			return service.UpdateTaskRequest{}, dslErr
+		}
+		req = mergeUpdateRequests(dsl, req)
+	}
+	req.ID = id
+	return req, nil
+}
+
+// updateRequestFromFlags builds the field changes shared by single and bulk
+// edits; the caller sets the task ID.
+func updateRequestFromFlags(cmd *cli.Command) (service.UpdateTaskRequest, error) {
 	meta, err := parseMetaFlags(cmd.StringSlice(flags.FlagMeta))
 	if err != nil {
-		return nil, fmt.Errorf("parse meta: %w", err)
+		return service.UpdateTaskRequest{}, fmt.Errorf("parse meta: %w", err)
 	}
 
 	req := service.UpdateTaskRequest{
-		ID:              id,
 		AddProjects:     cmd.StringSlice(flags.FlagProject),
 		AddContexts:     cmd.StringSlice(flags.FlagContext),
 		SetMeta:         meta,
@@ -269,27 +379,7 @@ func runFlagsMode(ctx context.Context, cmd *cli.Command, svc service.Service, id
 	if waitingFor := cmd.String(flags.FlagWaitingFor); waitingFor != "" {
 		req.WaitingFor = &waitingFor
 	}
-
-	// Apply field updates first.
-	updated, err := svc.UpdateTask(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-	if cmd.Bool(flags.FlagDone) {
-		_, err = svc.SetDone(ctx, []int64{id}, true)
-		if err != nil {
-			return nil, err
-		}
-		return svc.GetTask(ctx, id)
-	}
-	if cmd.Bool(flags.FlagUndone) {
-		_, err = svc.SetDone(ctx, []int64{id}, false)
-		if err != nil {
-			return nil, err
-		}
-		return svc.GetTask(ctx, id)
-	}
-	return updated, nil
+	return req, nil
 }
 
 func parseMetaFlags(meta []string) (map[string]string, error) {
@@ -6,55 +6,91 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mholtzscher/ugh/internal/config"
 	"github.com/mholtzscher/ugh/internal/nlp"
 	"github.com/mholtzscher/ugh/internal/nlp/compile"
 )
 
+// whereParsePrefix turns --where text into a filter command for the DSL parser.
+const whereParsePrefix = "find "
+
 type listFilterOptions struct {
-	Where   string
-	State   string
-	Project string
-	Context string
-	Search  string
-	DueSet  bool
+	View      config.View
+	Where     string
+	State     string
+	Project   string
+	Context   string
+	Search    string
+	DueSet    bool
+	Available bool
 }
 
 func buildListFilterExpr(opts listFilterOptions) (nlp.FilterExpr, error) {
-	whereExpr, err := parseWhereExpr(opts.Where)
+	expr, _, err := buildListFilter(opts)
+	return expr, err
+}
+
+// buildListFilter is like buildListFilterExpr but also returns the sort keys
+// from opts.Where followed by those of opts.View.
+func buildListFilter(opts listFilterOptions) (nlp.FilterExpr, []nlp.SortKey, error) {
+	whereExpr, sortKeys, err := parseWhereExpr(opts.Where)
+	if err != nil {
+		return nil, nil, err
+	}
+	viewExpr, viewSort, err := parseWhereExpr(opts.View.Where)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("view: %w", err)
 	}
+	viewSortKeys, err := nlp.ParseSortKeys(opts.View.Sort)
+	if err != nil {
+		return nil, nil, fmt.Errorf("view sort: %w", err)
+	}
+	sortKeys = append(append(sortKeys, viewSortKeys...), viewSort...)
 
 	expr := andExpr(
+		viewExpr,
 		whereExpr,
 		stateExpr(opts.State),
 		projectExpr(opts.Project),
 		contextExpr(opts.Context),
 		textExpr(opts.Search),
 		dueSetExpr(opts.DueSet),
+		availableExpr(opts.Available),
 	)
 
-	return compile.NormalizeFilterExpr(expr, compile.BuildOptions{Now: time.Now()})
+	schema, err := configuredMetaSchema()
+	if err != nil {
+		return nil, nil, err
+	}
+	expr, err = compile.NormalizeFilterExpr(expr, compile.BuildOptions{Now: time.Now(), MetaSchema: schema})
+	if err != nil {
+		return nil, nil, nlp.LocateError(strings.TrimSpace(opts.Where), err)
+	}
+	return expr, sortKeys, nil
 }
 
-func parseWhereExpr(where string) (nlp.FilterExpr, error) {
+func parseWhereExpr(where string) (nlp.FilterExpr, []nlp.SortKey, error) {
 	where = strings.TrimSpace(where)
 	if where == "" {
 		var emptyExpr nlp.FilterExpr
-		return emptyExpr, nil
+		return emptyExpr, nil, nil
 	}
 
-	parsed, err := nlp.Parse("find "+where, nlp.ParseOptions{Mode: nlp.ModeFilter, Now: time.Now()})
+	parsed, err := nlp.Parse(whereParsePrefix+where, nlp.ParseOptions{Mode: nlp.ModeFilter, Now: time.Now()})
 	if err != nil {
-		return nil, fmt.Errorf("parse --where: %w", err)
+		var diagnosticErr nlp.DiagnosticError
+		if errors.As(err, &diagnosticErr) {
+			err = diagnosticErr.TrimPrefix(whereParsePrefix)
+		}
+		return nil, nil, fmt.Errorf("parse --where: %w", err)
 	}
 
 	filterCmd, ok := parsed.Command.(*nlp.FilterCommand)
-	if !ok || filterCmd.Expr == nil {
-		return nil, errors.New("parse --where: expected filter expression")
+	if !ok || (filterCmd.Expr == nil && len(filterCmd.SortKeys) == 0) {
+		return nil, nil, errors.New("parse --where: expected filter expression")
 	}
 
-	return filterCmd.Expr, nil
+	return filterCmd.Expr, filterCmd.SortKeys, nil
 }
 
 func andExpr(exprs ...nlp.FilterExpr) nlp.FilterExpr {
@@ -114,3 +150,10 @@ func dueSetExpr(enabled bool) nlp.FilterExpr {
 	}
 	return nlp.Predicate{Kind: nlp.PredDue, Text: nlp.FilterWildcard}
 }
+
+func availableExpr(enabled bool) nlp.FilterExpr {
+	if !enabled {
+		return nil
+	}
+	return nlp.Predicate{Kind: nlp.PredAvailable}
+}
@@ -3,10 +3,15 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mholtzscher/ugh/internal/config"
 	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/output"
 	"github.com/mholtzscher/ugh/internal/service"
 )
 
@@ -69,10 +74,25 @@ var listCmd = &cli.Command{
 			Name:  flags.FlagWhere,
 			Usage: "filter expression (e.g. \"state:now or project:work\")",
 		},
+		&cli.StringFlag{
+			Name:  flags.FlagView,
+			Usage: "start from a built-in or saved view",
+		},
 		&cli.BoolFlag{
 			Name:  flags.FlagRecent,
 			Usage: "show most recently changed tasks",
 		},
+		&cli.StringFlag{
+			Name:  flags.FlagSort,
+			Usage: "sort order (" + nlp.SortFieldsUsage + "), comma-separated; prefix - for descending",
+		},
+		&cli.StringFlag{
+			Name:  flags.FlagGroupBy,
+			Usage: "group tasks by " + flags.GroupByUsage,
+			Action: flags.StringAction(
+				flags.OneOfCaseInsensitiveRule(flags.FieldGroup, flags.GroupByValues()...),
+			),
+		},
 		&cli.IntFlag{
 			Name:  flags.FlagLimit,
 			Usage: "max tasks to show",
@@ -88,7 +108,17 @@ var listCmd = &cli.Command{
 			limit = 0
 		}
 
-		filterExpr, err := buildListFilterExpr(listFilterOptions{
+		var view config.View
+		if name := cmd.String(flags.FlagView); name != "" {
+			resolved, viewErr := resolveView(name)
+			if viewErr != nil {
+				return viewErr
+			}
+			view = resolved
+		}
+
+		filterExpr, whereSort, err := buildListFilter(listFilterOptions{
+			View:    view,
 			Where:   cmd.String(flags.FlagWhere),
 			State:   cmd.String(flags.FlagState),
 			Project: cmd.String(flags.FlagProject),
@@ -98,6 +128,12 @@ var listCmd = &cli.Command{
 		if err != nil {
 			return err
 		}
+		sortKeys, err := nlp.ParseSortKeys(cmd.String(flags.FlagSort))
+		if err != nil {
+			return fmt.Errorf("parse --sort: %w", err)
+		}
+		// --sort takes precedence over sort clauses in --where and --view.
+		sortKeys = append(sortKeys, whereSort...)
 
 		svc, err := newService(ctx)
 		if err != nil {
@@ -112,6 +148,7 @@ var listCmd = &cli.Command{
 			Filter:   filterExpr,
 			Recent:   cmd.Bool(flags.FlagRecent),
 			Limit:    int64(limit),
+			Sort:     sortKeys,
 		}
 		tasks, err := svc.ListTasks(ctx, req)
 		if err != nil {
@@ -119,6 +156,16 @@ var listCmd = &cli.Command{
 		}
 
 		writer := outputWriter()
+		if groupBy := cmd.String(flags.FlagGroupBy); groupBy != "" {
+			groups, groupErr := output.GroupTasks(tasks, groupBy)
+			if groupErr != nil {
+				return groupErr
+			}
+			return writer.WriteTaskGroups(groups)
+		}
+		if len(sortKeys) > 0 && sortKeys[0].Field == nlp.SortFieldUrgency {
+			return writer.WriteScoredTasks(svc.Urgency().ScoreAll(tasks, time.Now()), false)
+		}
 		return writer.WriteTasks(tasks)
 	},
 }
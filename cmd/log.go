@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/service"
 )
 
 const defaultTaskLogLimit = 20
@@ -16,7 +17,7 @@ var logCmd = &cli.Command{
 	Name:      "log",
 	Usage:     "Show version history for a task",
 	Category:  "Tasks",
-	ArgsUsage: "<id>",
+	ArgsUsage: "[id]",
 	Flags: []cli.Flag{
 		&cli.IntFlag{
 			Name:  flags.FlagLimit,
@@ -25,12 +26,16 @@ var logCmd = &cli.Command{
 		},
 	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		if cmd.Args().Len() != 1 {
-			return errors.New("log requires a task id")
+		if cmd.Args().Len() > 1 {
+			return errors.New("log takes a single task id")
 		}
-		ids, err := parseIDs(commandArgs(cmd))
-		if err != nil {
-			return err
+		var ids []int64
+		if cmd.Args().Len() == 1 {
+			var err error
+			ids, err = parseIDs(commandArgs(cmd))
+			if err != nil {
+				return err
+			}
 		}
 
 		svc, err := newService(ctx)
@@ -39,6 +44,11 @@ var logCmd = &cli.Command{
 		}
 		defer func() { _ = svc.Close() }()
 
+		ids, err = idsOrPick(ctx, cmd, svc, ids, service.ListTasksRequest{All: true})
+		if err != nil {
+			return err
+		}
+
 		versions, err := svc.ListTaskVersions(ctx, ids[0], int64(cmd.Int(flags.FlagLimit)))
 		if err != nil {
 			return err
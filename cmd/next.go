@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/service"
+)
+
+const defaultNextLimit = 5
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var nextCmd = &cli.Command{
+	Name:     "next",
+	Usage:    "Recommend the most urgent tasks to do next",
+	Category: "Lists",
+	Description: `Rank open tasks by urgency and show the best ones to do right now.
+With --context, tasks that only belong to other contexts are left out and
+tasks in the context score higher. --explain shows how each score adds up;
+set the weights in the [urgency] config section.
+
+		Examples:
+		  ugh next                      # Top 5 tasks
+		  ugh next --context @office    # Top tasks you can do at the office
+		  ugh next -n 1 --explain       # The top task and why`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    flags.FlagContext,
+			Aliases: []string{"c"},
+			Usage:   "context you are in, e.g. @office",
+		},
+		&cli.IntFlag{
+			Name:    flags.FlagLimit,
+			Aliases: []string{"n"},
+			Usage:   "number of tasks to show",
+			Value:   defaultNextLimit,
+		},
+		&cli.BoolFlag{
+			Name:  flags.FlagExplain,
+			Usage: "show the factors behind each score",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		limit := cmd.Int(flags.FlagLimit)
+		if limit <= 0 {
+			return errors.New("limit must be greater than 0")
+		}
+
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		tasks, err := svc.ListTasks(ctx, service.ListTasksRequest{TodoOnly: true})
+		if err != nil {
+			return err
+		}
+
+		scorer := svc.Urgency()
+		scorer.Context = strings.TrimPrefix(strings.TrimSpace(cmd.String(flags.FlagContext)), "@")
+		ranked := scorer.Rank(tasks, time.Now())
+		if len(ranked) > limit {
+			ranked = ranked[:limit]
+		}
+		return outputWriter().WriteScoredTasks(ranked, cmd.Bool(flags.FlagExplain))
+	},
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var noteCmd = &cli.Command{
+	Name:      "note",
+	Aliases:   []string{"annotate"},
+	Usage:     "Add a timestamped annotation to a task",
+	Category:  "Tasks",
+	ArgsUsage: "<id> <text...>",
+	Description: `Annotations are kept apart from notes and only ever added to, so a
+running log survives concurrent edits. ugh show lists them oldest first and
+text searches match them.
+
+		Examples:
+		  ugh note 12 "called vendor, waiting on quote"
+		  ugh list --search vendor`,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		args := commandArgs(cmd)
+		if len(args) < 2 {
+			return errors.New("note requires a task id and text")
+		}
+		ids, err := parseIDs(args[:1])
+		if err != nil {
+			return err
+		}
+		text := strings.Join(args[1:], " ")
+		return runTaskWrite(ctx, func(svc service.Service) (*store.Task, error) {
+			return svc.AddAnnotation(ctx, ids[0], text)
+		})
+	},
+}
@@ -15,8 +15,17 @@ var nowCmd = &cli.Command{
 	Aliases:  []string{"n"},
 	Usage:    "List tasks you can act on now",
 	Category: "Lists",
-	Action: func(ctx context.Context, _ *cli.Command) error {
-		filterExpr, err := buildListFilterExpr(listFilterOptions{State: flags.TaskStateNow})
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  flags.FlagAvailable,
+			Usage: "only tasks whose contexts are available now (see [contexts] in config)",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		filterExpr, err := buildListFilterExpr(listFilterOptions{
+			State:     flags.TaskStateNow,
+			Available: cmd.Bool(flags.FlagAvailable),
+		})
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/picker"
+	"github.com/mholtzscher/ugh/internal/service"
+)
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var pickCmd = &cli.Command{
+	Name:     "pick",
+	Usage:    "Pick a task interactively and print its id",
+	Category: "Tasks",
+	Description: `Search tasks by title, project and context and print the id of the
+picked task.
+
+When stdin is a terminal this opens a fuzzy finder, drawn on stderr when
+stdout is captured. Otherwise the tasks are listed with numbers on stderr
+and the choice is read from stdin; type text to narrow the list.
+
+Commands that take a task id (show, edit, done, undo, rm, log) open the
+same picker when no id is given.
+
+		Examples:
+		  ugh pick                  # Print the id of an open task
+		  ugh show $(ugh pick -a)   # Pick from all tasks`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    flags.FlagAll,
+			Aliases: []string{"a"},
+			Usage:   "include completed tasks",
+			Action: flags.BoolAction(
+				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone),
+			),
+		},
+		&cli.BoolFlag{
+			Name:    flags.FlagDone,
+			Aliases: []string{"x"},
+			Usage:   "only completed tasks",
+			Action: flags.BoolAction(
+				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone),
+			),
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		req := service.ListTasksRequest{
+			All:      cmd.Bool(flags.FlagAll),
+			DoneOnly: cmd.Bool(flags.FlagDone),
+			TodoOnly: !cmd.Bool(flags.FlagAll) && !cmd.Bool(flags.FlagDone),
+		}
+		id, err := pickTaskID(ctx, cmd, svc, req)
+		if err != nil {
+			return err
+		}
+
+		writer := outputWriter()
+		if writer.JSON {
+			task, getErr := svc.GetTask(ctx, id)
+			if getErr != nil {
+				return getErr
+			}
+			return writer.WriteTask(task)
+		}
+		_, err = fmt.Fprintln(writer.Out, id)
+		return err
+	},
+}
+
+// pickTaskID lets the user pick one of the tasks req lists. The picker
+// prompts on stderr so stdout stays clean for the command's own output.
+func pickTaskID(ctx context.Context, cmd *cli.Command, svc service.Service, req service.ListTasksRequest) (int64, error) {
+	tasks, err := svc.ListTasks(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	root := cmd.Root()
+	task, err := picker.New(root.Reader, root.ErrWriter).Pick(tasks)
+	if err != nil {
+		return 0, err
+	}
+	return task.ID, nil
+}
+
+// idsOrPick returns ids, or the id of a picked task when none were given.
+func idsOrPick(
+	ctx context.Context,
+	cmd *cli.Command,
+	svc service.Service,
+	ids []int64,
+	req service.ListTasksRequest,
+) ([]int64, error) {
+	if len(ids) > 0 {
+		return ids, nil
+	}
+	id, err := pickTaskID(ctx, cmd, svc, req)
+	if err != nil {
+		return nil, err
+	}
+	return []int64{id}, nil
+}
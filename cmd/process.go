@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/clarify"
+)
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var processCmd = &cli.Command{
+	Name:     "process",
+	Aliases:  []string{"clarify"},
+	Usage:    "Process the inbox one task at a time",
+	Category: "Lists",
+	Description: `Walk through inbox tasks, oldest first, and decide what each one is.
+
+On a terminal every action is a single key; otherwise each action is read
+as a line from stdin.
+
+  n  do now                 l  defer to later
+  w  delegate (waiting for) x  delete
+  p  add a project          c  add a context
+  d  set a due date         P  make it a project with a next action
+  s  skip                   q  quit
+
+Projects, contexts and due dates keep the task on screen so it can be
+tagged before it is filed. Each decision is saved immediately, so quitting
+keeps the work already done.`,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		err = maybeSyncBeforeWrite(ctx, svc)
+		if err != nil {
+			return fmt.Errorf("sync pull: %w", err)
+		}
+
+		writer := outputWriter()
+		root := cmd.Root()
+		prompts := writer.Out
+		if writer.JSON {
+			prompts = root.ErrWriter
+		}
+		processor := clarify.New(svc, clarify.Options{
+			In:  root.Reader,
+			Out: prompts,
+			Raw: clarify.TerminalRaw(os.Stdin),
+		})
+		summary, err := processor.Run(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = maybeSyncAfterWrite(ctx, svc)
+		if err != nil {
+			return fmt.Errorf("sync push: %w", err)
+		}
+		if writer.JSON {
+			return writer.WriteSummary(summary)
+		}
+		return writer.WriteSuccess(summary.String())
+	},
+}
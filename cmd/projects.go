@@ -22,7 +22,7 @@ var projectsCmd = &cli.Command{
 			Aliases: []string{"a"},
 			Usage:   "include completed tasks",
 			Action: flags.BoolAction(
-				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo),
+				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo, flags.FlagStalled),
 			),
 		},
 		&cli.BoolFlag{
@@ -30,7 +30,7 @@ var projectsCmd = &cli.Command{
 			Aliases: []string{"x"},
 			Usage:   "only completed tasks",
 			Action: flags.BoolAction(
-				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo),
+				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo, flags.FlagStalled),
 			),
 		},
 		&cli.BoolFlag{
@@ -38,13 +38,20 @@ var projectsCmd = &cli.Command{
 			Aliases: []string{"t"},
 			Usage:   "only pending tasks",
 			Action: flags.BoolAction(
-				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo),
+				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo, flags.FlagStalled),
 			),
 		},
 		&cli.BoolFlag{
 			Name:  flags.FlagCounts,
 			Usage: "include counts",
 		},
+		&cli.BoolFlag{
+			Name:  flags.FlagStalled,
+			Usage: "only projects with open tasks but no now task",
+			Action: flags.BoolAction(
+				flags.MutuallyExclusiveBoolFlagsRule(flags.FlagAll, flags.FlagDone, flags.FlagTodo, flags.FlagStalled),
+			),
+		},
 	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
 		svc, err := newService(ctx)
@@ -53,6 +60,10 @@ var projectsCmd = &cli.Command{
 		}
 		defer func() { _ = svc.Close() }()
 
+		if cmd.Bool(flags.FlagStalled) {
+			return writeStalledProjects(ctx, svc)
+		}
+
 		tags, err := svc.ListProjects(ctx, service.ListTagsRequest{
 			All:      cmd.Bool(flags.FlagAll),
 			DoneOnly: cmd.Bool(flags.FlagDone),
@@ -69,3 +80,13 @@ var projectsCmd = &cli.Command{
 		return writer.WriteTags(tags)
 	},
 }
+
+// writeStalledProjects lists the projects with open tasks but no now task,
+// least recently active first.
+func writeStalledProjects(ctx context.Context, svc service.Service) error {
+	projects, err := svc.ListStalledProjects(ctx)
+	if err != nil {
+		return err
+	}
+	return outputWriter().WriteStalledProjects(projects)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/review"
+	"github.com/mholtzscher/ugh/internal/service"
+)
+
+type reviewStatusResult struct {
+	LastReview    *time.Time `json:"lastReview"`
+	DaysSince     *int       `json:"daysSince"`
+	TasksReviewed int64      `json:"tasksReviewed"`
+	TasksChanged  int64      `json:"tasksChanged"`
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var reviewCmd = &cli.Command{
+	Name:     "review",
+	Usage:    "Walk through the weekly review",
+	Category: "Lists",
+	Description: `Go through the GTD weekly review one step at a time:
+
+  1. waiting tasks that have waited longer than --waiting-days
+  2. overdue tasks and tasks due in the next week
+  3. projects with no now action
+  4. later tasks untouched for --stale-days
+  5. tasks done this week
+
+Each step lists its tasks with numbers. Type a number and changes in the
+shell's set syntax to edit a task in place, enter to go on, or q to stop.
+The review is recorded once the last step is done.
+
+		Examples:
+		  ugh review                    # Start the review
+		  ugh review --waiting-days 14  # Only chase waits older than two weeks
+		  ugh review --status           # Show when the last review happened`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  flags.FlagStatus,
+			Usage: "show when the last review was completed",
+		},
+		&cli.IntFlag{
+			Name:  flags.FlagWaitingDays,
+			Usage: "list waiting tasks older than this many days",
+			Value: review.DefaultWaitingDays,
+		},
+		&cli.IntFlag{
+			Name:  flags.FlagStaleDays,
+			Usage: "list later tasks untouched for this many days",
+			Value: review.DefaultStaleDays,
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		if cmd.Bool(flags.FlagStatus) {
+			return writeReviewStatus(ctx, svc)
+		}
+
+		err = maybeSyncBeforeWrite(ctx, svc)
+		if err != nil {
+			return fmt.Errorf("sync pull: %w", err)
+		}
+
+		writer := outputWriter()
+		root := cmd.Root()
+		prompts := writer.Out
+		if writer.JSON {
+			prompts = root.ErrWriter
+		}
+		reviewer := review.New(svc, review.Options{
+			In:          root.Reader,
+			Out:         prompts,
+			WaitingDays: int(cmd.Int(flags.FlagWaitingDays)),
+			StaleDays:   int(cmd.Int(flags.FlagStaleDays)),
+		})
+		summary, err := reviewer.Run(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = maybeSyncAfterWrite(ctx, svc)
+		if err != nil {
+			return fmt.Errorf("sync push: %w", err)
+		}
+		if writer.JSON {
+			return writer.WriteSummary(summary)
+		}
+		if !summary.Recorded {
+			return writer.WriteWarning(summary.String())
+		}
+		return writer.WriteSuccess(summary.String())
+	},
+}
+
+func writeReviewStatus(ctx context.Context, svc service.Service) error {
+	last, err := svc.LastReview(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := outputWriter()
+	result := reviewStatusResult{}
+	if last != nil {
+		completed := last.CompletedAt.Local()
+		days := int(time.Since(completed).Hours() / domain.HoursPerDay)
+		result = reviewStatusResult{
+			LastReview:    &completed,
+			DaysSince:     &days,
+			TasksReviewed: last.TasksReviewed,
+			TasksChanged:  last.TasksChanged,
+		}
+	}
+	if writer.JSON {
+		return writer.WriteSummary(result)
+	}
+	if last == nil {
+		return writer.WriteInfo("No weekly review recorded yet")
+	}
+	completed := fmt.Sprintf("%s (%s)", result.LastReview.Format(domain.DateLayoutYYYYMMDD), daysAgo(*result.DaysSince))
+	return writer.WriteKeyValues([]output.KeyValue{
+		{Key: "last_review", Value: completed},
+		{Key: "tasks_reviewed", Value: strconv.FormatInt(result.TasksReviewed, 10)},
+		{Key: "tasks_changed", Value: strconv.FormatInt(result.TasksChanged, 10)},
+	})
+}
+
+func daysAgo(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return strconv.Itoa(days) + " days ago"
+	}
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mholtzscher/ugh/internal/flags"
 	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/service"
 )
 
 //nolint:gochecknoglobals // CLI command definitions are package-level by design.
@@ -14,11 +16,20 @@ var rmCmd = &cli.Command{
 	Name:      "rm",
 	Usage:     "Delete tasks",
 	Category:  "Tasks",
-	ArgsUsage: "<id...>",
+	ArgsUsage: "[id...]",
+	Flags:     bulkFlags(),
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		ids, err := parseIDs(commandArgs(cmd))
-		if err != nil {
-			return err
+		where := cmd.String(flags.FlagWhere)
+		var ids []int64
+		switch {
+		case where != "" && cmd.Args().Len() > 0:
+			return fmt.Errorf("cannot combine task ids with --%s", flags.FlagWhere)
+		case cmd.Args().Len() > 0:
+			var err error
+			ids, err = parseIDs(commandArgs(cmd))
+			if err != nil {
+				return err
+			}
 		}
 		svc, err := newService(ctx)
 		if err != nil {
@@ -30,6 +41,17 @@ var rmCmd = &cli.Command{
 		if err != nil {
 			return fmt.Errorf("sync pull: %w", err)
 		}
+		if where != "" {
+			scope := service.ListTasksRequest{All: true}
+			return runBulk(ctx, cmd, svc, "rm", scope, func(ids []int64) ([]service.BulkTaskResult, error) {
+				return svc.BulkDeleteTasks(ctx, ids)
+			})
+		}
+
+		ids, err = idsOrPick(ctx, cmd, svc, ids, service.ListTasksRequest{All: true})
+		if err != nil {
+			return err
+		}
 
 		count, err := svc.DeleteTasks(ctx, ids)
 		if err != nil {
@@ -74,17 +74,27 @@ var rootCmd = &cli.Command{
 	},
 	Commands: []*cli.Command{
 		addCmd,
+		templateCmd,
 		inboxCmd,
 		nowCmd,
+		nextCmd,
 		waitingCmd,
 		laterCmd,
+		snoozedCmd,
 		calendarCmd,
+		processCmd,
+		reviewCmd,
+		viewCmd,
 		listCmd,
+		pickCmd,
 		logCmd,
 		showCmd,
 		editCmd,
+		checkCmd,
 		doneCmd,
 		undoCmd,
+		snoozeCmd,
+		noteCmd,
 		rmCmd,
 		projectsCmd,
 		contextsCmd,
@@ -24,6 +24,10 @@ var shellCmd = &cli.Command{
 			Name:  "stdin",
 			Usage: "Execute commands from stdin (scripting mode)",
 		},
+		&cli.StringFlag{
+			Name:  "session",
+			Usage: "Resume the named shell session, saving it after every command",
+		},
 	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
 		svc, err := newService(ctx)
@@ -41,6 +45,18 @@ var shellCmd = &cli.Command{
 		writer := outputWriter()
 		writer.JSON = false
 		opts.Writer = writer
+		opts.Views = configuredViews()
+		opts.Templates, err = loadTemplates()
+		if err != nil {
+			return err
+		}
+		opts.TitleDates = titleDatesEnabled()
+		opts.People = configuredPeople()
+		opts.Shellrc, err = shellrcPath()
+		if err != nil {
+			return err
+		}
+		opts.Session = cmd.String("session")
 		if cmd.String("file") != "" {
 			opts.Mode = shell.ModeScriptFile
 			opts.InputFile = cmd.String("file")
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/service"
 )
 
 //nolint:gochecknoglobals // CLI command definitions are package-level by design.
@@ -13,14 +15,18 @@ var showCmd = &cli.Command{
 	Aliases:   []string{"s"},
 	Usage:     "Show a task",
 	Category:  "Tasks",
-	ArgsUsage: "<id>",
+	ArgsUsage: "[id]",
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		if cmd.Args().Len() != 1 {
-			return errors.New("show requires a task id")
+		if cmd.Args().Len() > 1 {
+			return errors.New("show takes a single task id")
 		}
-		ids, err := parseIDs(commandArgs(cmd))
-		if err != nil {
-			return err
+		var ids []int64
+		if cmd.Args().Len() == 1 {
+			var err error
+			ids, err = parseIDs(commandArgs(cmd))
+			if err != nil {
+				return err
+			}
 		}
 
 		svc, err := newService(ctx)
@@ -29,6 +35,11 @@ var showCmd = &cli.Command{
 		}
 		defer func() { _ = svc.Close() }()
 
+		ids, err = idsOrPick(ctx, cmd, svc, ids, service.ListTasksRequest{All: true})
+		if err != nil {
+			return err
+		}
+
 		task, err := svc.GetTask(ctx, ids[0])
 		if err != nil {
 			return err
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/nlp/compile"
+	"github.com/mholtzscher/ugh/internal/service"
+)
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var snoozeCmd = &cli.Command{
+	Name:      "snooze",
+	Aliases:   []string{"z"},
+	Usage:     "Move tasks to later until a date",
+	Category:  "Tasks",
+	ArgsUsage: "[id...]",
+	Description: `Move tasks to later until a date. After the date the next ugh command,
+shell line or daemon sync returns each task to the state it had before.
+
+		Examples:
+		  ugh snooze 12 --until "next monday"
+		  ugh snooze 3 4 --until "tomorrow 9am"
+		  ugh snooze 7 --until 2026-11-02`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  flags.FlagUntil,
+			Usage: "when the tasks come back (YYYY-MM-DD, \"YYYY-MM-DD HH:MM\" or e.g. friday)",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.String(flags.FlagUntil) == "" {
+			return errors.New("snooze needs --" + flags.FlagUntil)
+		}
+		until, err := compile.ResolveUntil(cmd.String(flags.FlagUntil), time.Now())
+		if err != nil {
+			return err
+		}
+		var ids []int64
+		if cmd.Args().Len() > 0 {
+			ids, err = parseIDs(commandArgs(cmd))
+			if err != nil {
+				return err
+			}
+		}
+
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		err = maybeSyncBeforeWrite(ctx, svc)
+		if err != nil {
+			return fmt.Errorf("sync pull: %w", err)
+		}
+		ids, err = idsOrPick(ctx, cmd, svc, ids, service.ListTasksRequest{TodoOnly: true})
+		if err != nil {
+			return err
+		}
+
+		snoozed, err := svc.SnoozeTasks(ctx, ids, until)
+		if err != nil {
+			return err
+		}
+		err = maybeSyncAfterWrite(ctx, svc)
+		if err != nil {
+			return fmt.Errorf("sync push: %w", err)
+		}
+		return outputWriter().WriteSnoozed(snoozed)
+	},
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+)
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var snoozedCmd = &cli.Command{
+	Name:     "snoozed",
+	Usage:    "List snoozed tasks and when they come back",
+	Category: "Lists",
+	Description: `List snoozed tasks, soonest first, with the date each comes back and
+the state it returns to.
+
+		Examples:
+		  ugh snoozed          # List snoozed tasks
+		  ugh snoozed --json   # Include exact return times`,
+	Action: func(ctx context.Context, _ *cli.Command) error {
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		tasks, err := svc.ListSnoozed(ctx)
+		if err != nil {
+			return err
+		}
+		return outputWriter().WriteSnoozed(tasks)
+	},
+}
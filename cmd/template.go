@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/templates"
+)
+
+type templateSaveResult struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	ID     int64  `json:"id"`
+	File   string `json:"file"`
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var templateCmd = &cli.Command{
+	Name:     "template",
+	Aliases:  []string{"tpl"},
+	Usage:    "List and save task templates",
+	Category: "Tasks",
+	Description: `Templates create a task and its child tasks in one go. They are read
+from [templates.<name>] in the config and from <name>.toml files in the
+templates directory next to it. {name} placeholders are filled from
+--var name=value, or name:value in the shell.
+
+		Examples:
+		  ugh template                       # List templates
+		  ugh template save 42 onboarding    # Capture task 42 as a template
+		  ugh add --template onboarding --var name=Sam`,
+	Commands: []*cli.Command{
+		templateListCmd,
+		templateSaveCmd,
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		return templateListCmd.Action(ctx, cmd)
+	},
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var templateListCmd = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List templates with their task counts and variables",
+	Action: func(_ context.Context, _ *cli.Command) error {
+		set, err := loadTemplates()
+		if err != nil {
+			return err
+		}
+		items := make([]output.TemplateItem, 0, len(set))
+		for _, name := range set.Names() {
+			tmpl := set[name]
+			items = append(items, output.TemplateItem{
+				Name:  name,
+				Title: tmpl.Title,
+				Tasks: templates.Count(tmpl),
+				Vars:  templates.Variables(tmpl),
+			})
+		}
+		return outputWriter().WriteTemplates(items)
+	},
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var templateSaveCmd = &cli.Command{
+	Name:  "save",
+	Usage: "Save a task as a template in the templates directory",
+	Description: `Save a task's title, notes, state, projects, contexts and meta as a
+template. A due date is kept as an offset from today. Edit the saved file to
+add {name} placeholders or child tasks.`,
+	ArgsUsage: "<id> <name>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  flags.FlagForce,
+			Usage: "replace an existing template file",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 2 {
+			return errors.New("template save requires a task id and a name")
+		}
+		ids, err := parseIDs(commandArgs(cmd)[:1])
+		if err != nil {
+			return err
+		}
+		name := strings.ToLower(strings.TrimSpace(cmd.Args().Get(1)))
+		if !templates.ValidName(name) {
+			return fmt.Errorf("invalid template name %q (use lowercase letters, digits, - and _)", name)
+		}
+		if loadedConfig != nil {
+			for configured := range loadedConfig.Templates {
+				if strings.EqualFold(strings.TrimSpace(configured), name) {
+					return fmt.Errorf("template %s is defined in the config", name)
+				}
+			}
+		}
+
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		task, err := svc.GetTask(ctx, ids[0])
+		if err != nil {
+			return err
+		}
+		dir, err := templatesDir()
+		if err != nil {
+			return err
+		}
+		path, err := templates.Save(dir, name, templates.FromTask(task, time.Now()), cmd.Bool(flags.FlagForce))
+		if err != nil {
+			return err
+		}
+
+		writer := outputWriter()
+		if writer.JSON {
+			enc := json.NewEncoder(writer.Out)
+			return enc.Encode(templateSaveResult{Action: "save", Name: name, ID: task.ID, File: path})
+		}
+		return writer.WriteSuccess(fmt.Sprintf("saved template %s from task #%d", name, task.ID))
+	},
+}
@@ -7,7 +7,9 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mholtzscher/ugh/internal/flags"
 	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/service"
 )
 
 //nolint:gochecknoglobals // CLI command definitions are package-level by design.
@@ -16,11 +18,20 @@ var undoCmd = &cli.Command{
 	Aliases:   []string{"u"},
 	Usage:     "Mark tasks as not done",
 	Category:  "Tasks",
-	ArgsUsage: "<id...>",
+	ArgsUsage: "[id...]",
+	Flags:     bulkFlags(),
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		ids, err := parseIDs(commandArgs(cmd))
-		if err != nil {
-			return err
+		where := cmd.String(flags.FlagWhere)
+		var ids []int64
+		switch {
+		case where != "" && cmd.Args().Len() > 0:
+			return fmt.Errorf("cannot combine task ids with --%s", flags.FlagWhere)
+		case cmd.Args().Len() > 0:
+			var err error
+			ids, err = parseIDs(commandArgs(cmd))
+			if err != nil {
+				return err
+			}
 		}
 		svc, err := newService(ctx)
 		if err != nil {
@@ -32,6 +43,17 @@ var undoCmd = &cli.Command{
 		if err != nil {
 			return fmt.Errorf("sync pull: %w", err)
 		}
+		if where != "" {
+			scope := service.ListTasksRequest{DoneOnly: true}
+			return runBulk(ctx, cmd, svc, "undo", scope, func(ids []int64) ([]service.BulkTaskResult, error) {
+				return svc.BulkSetDone(ctx, ids, false)
+			})
+		}
+
+		ids, err = idsOrPick(ctx, cmd, svc, ids, service.ListTasksRequest{DoneOnly: true})
+		if err != nil {
+			return err
+		}
 
 		count, err := svc.SetDone(ctx, ids, false)
 		if err != nil {
@@ -4,11 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mholtzscher/ugh/internal/config"
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/schedule"
 	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/templates"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 func parseIDs(args []string) ([]int64, error) {
@@ -26,6 +36,11 @@ func parseIDs(args []string) ([]int64, error) {
 	return ids, nil
 }
 
+func isTaskIDArg(arg string) bool {
+	_, err := strconv.ParseInt(arg, 10, 64)
+	return err == nil
+}
+
 func commandArgs(cmd *cli.Command) []string {
 	if cmd == nil {
 		return nil
@@ -40,11 +55,170 @@ func commandArgs(cmd *cli.Command) []string {
 
 // newService returns a Service implementation using direct database access.
 func newService(ctx context.Context) (service.Service, error) {
+	schema, err := configuredMetaSchema()
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := configuredSchedules()
+	if err != nil {
+		return nil, err
+	}
 	st, err := openStore(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return service.NewTaskService(st), nil
+	svc := service.NewTaskService(st,
+		service.WithMetaSchema(schema),
+		service.WithUrgency(configuredUrgency()),
+		service.WithSchedules(schedules),
+	)
+	// Snoozed tasks come back on the first command after their date, so
+	// reads such as now and snoozed never show an expired snooze.
+	if _, err = svc.WakeSnoozed(ctx, time.Now()); err != nil {
+		_ = svc.Close()
+		return nil, fmt.Errorf("wake snoozed tasks: %w", err)
+	}
+	return svc, nil
+}
+
+// configuredMetaSchema converts the [meta.schema] config section.
+func configuredMetaSchema() (domain.MetaSchema, error) {
+	schema := domain.MetaSchema{}
+	if loadedConfig == nil {
+		return schema, nil
+	}
+	for key, field := range loadedConfig.Meta.Schema {
+		metaType, err := domain.ParseMetaType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("meta.schema.%s: %w", key, err)
+		}
+		schema[key] = domain.MetaField{Type: metaType, Values: field.Values}
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// titleDatesEnabled reports whether input.title_dates is on.
+func titleDatesEnabled() bool {
+	return loadedConfig != nil && loadedConfig.Input.TitleDates
+}
+
+// configuredSchedules parses the [contexts] availability schedules.
+func configuredSchedules() (schedule.Table, error) {
+	if loadedConfig == nil {
+		return schedule.Table{}, nil
+	}
+	return schedule.ParseTable(loadedConfig.Contexts)
+}
+
+// configuredUrgency builds the urgency scorer from the [urgency] config
+// section, keeping the default weight for any factor it leaves unset.
+func configuredUrgency() urgency.Scorer {
+	scorer := urgency.NewScorer()
+	if loadedConfig == nil {
+		return scorer
+	}
+	cfg := loadedConfig.Urgency
+	overrides := []struct {
+		value  *float64
+		weight *float64
+	}{
+		{cfg.Due, &scorer.Weights.Due},
+		{cfg.Age, &scorer.Weights.Age},
+		{cfg.State, &scorer.Weights.State},
+		{cfg.Project, &scorer.Weights.Project},
+		{cfg.Context, &scorer.Weights.Context},
+		{cfg.Blocked, &scorer.Weights.Blocked},
+		{cfg.Waiting, &scorer.Weights.Waiting},
+	}
+	for _, override := range overrides {
+		if override.value != nil {
+			*override.weight = *override.value
+		}
+	}
+	scorer.Projects = cfg.Projects
+	return scorer
+}
+
+// configuredPeople returns the known people from input.people.
+func configuredPeople() []string {
+	if loadedConfig == nil {
+		return nil
+	}
+	return loadedConfig.Input.People
+}
+
+// configDir returns the directory of the active config file.
+func configDir() (string, error) {
+	configPath := rootConfigPath
+	if configPath == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			return "", fmt.Errorf("get default config path: %w", err)
+		}
+		configPath = defaultPath
+	}
+	return filepath.Dir(configPath), nil
+}
+
+// shellrcPath returns the shellrc next to the active config file, where
+// shell variables, aliases and macros are kept.
+func shellrcPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shellrc"), nil
+}
+
+// templatesDir returns the templates directory next to the active config file.
+func templatesDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, templates.DirName), nil
+}
+
+// loadTemplates returns the templates from [templates] and the templates
+// directory.
+func loadTemplates() (templates.Set, error) {
+	dir, err := templatesDir()
+	if err != nil {
+		return nil, err
+	}
+	var configured map[string]config.Template
+	if loadedConfig != nil {
+		configured = loadedConfig.Templates
+	}
+	return templates.Load(dir, configured)
+}
+
+// configuredViews returns the user-defined views from [views] keyed by
+// lowercase name.
+func configuredViews() map[string]config.View {
+	if loadedConfig == nil || len(loadedConfig.Views) == 0 {
+		return nil
+	}
+	views := make(map[string]config.View, len(loadedConfig.Views))
+	for name, view := range loadedConfig.Views {
+		views[strings.ToLower(strings.TrimSpace(name))] = view
+	}
+	return views
+}
+
+// resolveView looks up a built-in or user-defined view by name.
+func resolveView(name string) (config.View, error) {
+	if builtin, ok := nlp.LookupBuiltinView(name); ok {
+		return config.View{Where: builtin.Where}, nil
+	}
+	view, ok := configuredViews()[strings.ToLower(strings.TrimSpace(name))]
+	if !ok || strings.TrimSpace(view.Where) == "" {
+		return config.View{}, fmt.Errorf("unknown view: %s", name)
+	}
+	return view, nil
 }
 
 func autoSyncEnabled() bool {
@@ -64,3 +238,27 @@ func maybeSyncAfterWrite(ctx context.Context, svc service.Service) error {
 	}
 	return svc.Push(ctx)
 }
+
+// runTaskWrite applies write to one task between the sync pull and push, then
+// prints the task.
+func runTaskWrite(ctx context.Context, write func(service.Service) (*store.Task, error)) error {
+	svc, err := newService(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = svc.Close() }()
+
+	err = maybeSyncBeforeWrite(ctx, svc)
+	if err != nil {
+		return fmt.Errorf("sync pull: %w", err)
+	}
+	task, err := write(svc)
+	if err != nil {
+		return err
+	}
+	err = maybeSyncAfterWrite(ctx, svc)
+	if err != nil {
+		return fmt.Errorf("sync push: %w", err)
+	}
+	return outputWriter().WriteTask(task)
+}
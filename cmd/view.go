@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/mholtzscher/ugh/internal/config"
+	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/service"
+)
+
+const viewSaveName = "save"
+
+type viewSaveResult struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	Where  string `json:"where"`
+	Sort   string `json:"sort,omitempty"`
+	File   string `json:"file"`
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var viewCmd = &cli.Command{
+	Name:      "view",
+	Usage:     "Run a built-in or saved view",
+	ArgsUsage: "[name]",
+	Category:  "Lists",
+	Commands: []*cli.Command{
+		viewSaveCmd,
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() == 0 {
+			writer := outputWriter()
+			return writer.WriteViewHelp(output.NewViewHelp(configuredViews(), "ugh view <name>"))
+		}
+		if cmd.Args().Len() > 1 {
+			return errors.New("view accepts a single name")
+		}
+
+		if strings.EqualFold(cmd.Args().First(), nlp.StalledViewName) {
+			svc, err := newService(ctx)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = svc.Close() }()
+			return writeStalledProjects(ctx, svc)
+		}
+
+		view, err := resolveView(cmd.Args().First())
+		if err != nil {
+			return err
+		}
+		filterExpr, sortKeys, err := buildListFilter(listFilterOptions{View: view})
+		if err != nil {
+			return err
+		}
+
+		svc, err := newService(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = svc.Close() }()
+
+		tasks, err := svc.ListTasks(ctx, service.ListTasksRequest{Filter: filterExpr, Sort: sortKeys})
+		if err != nil {
+			return err
+		}
+
+		writer := outputWriter()
+		return writer.WriteTasks(tasks)
+	},
+}
+
+//nolint:gochecknoglobals // CLI command definitions are package-level by design.
+var viewSaveCmd = &cli.Command{
+	Name:      viewSaveName,
+	Usage:     "Save a view to the config file",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     flags.FlagWhere,
+			Usage:    "filter expression (e.g. \"state:now && (#work || @office)\")",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  flags.FlagSort,
+			Usage: "sort order (" + nlp.SortFieldsUsage + "), comma-separated; prefix - for descending",
+		},
+	},
+	Action: func(_ context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return errors.New("view save requires a name")
+		}
+		name := strings.ToLower(strings.TrimSpace(cmd.Args().First()))
+		if err := validateViewName(name); err != nil {
+			return err
+		}
+		view := config.View{
+			Where: strings.TrimSpace(cmd.String(flags.FlagWhere)),
+			Sort:  strings.TrimSpace(cmd.String(flags.FlagSort)),
+		}
+		// Reject views that would fail when run.
+		if _, _, err := buildListFilter(listFilterOptions{View: view}); err != nil {
+			return err
+		}
+
+		cfg := loadedConfig
+		if cfg == nil {
+			cfg = &config.Config{Version: config.DefaultVersion}
+		}
+		if cfg.Views == nil {
+			cfg.Views = map[string]config.View{}
+		}
+		cfg.Views[name] = view
+
+		cfgPath, err := configPathForWrite()
+		if err != nil {
+			return err
+		}
+		if err = config.Save(cfgPath, *cfg); err != nil {
+			return err
+		}
+		loadedConfig = cfg
+		loadedConfigWas = true
+
+		writer := outputWriter()
+		if writer.JSON {
+			enc := json.NewEncoder(writer.Out)
+			return enc.Encode(viewSaveResult{
+				Action: "save",
+				Name:   name,
+				Where:  view.Where,
+				Sort:   view.Sort,
+				File:   cfgPath,
+			})
+		}
+		return writer.WriteSuccess("saved view " + name)
+	},
+}
+
+func validateViewName(name string) error {
+	if !nlp.IsValidViewName(name) {
+		return fmt.Errorf("invalid view name %q (use lowercase letters, digits, - and _)", name)
+	}
+	if nlp.IsReservedViewName(name) || name == viewSaveName {
+		return fmt.Errorf("view name %q is reserved", name)
+	}
+	return nil
+}
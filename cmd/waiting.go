@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/flags"
+	"github.com/mholtzscher/ugh/internal/output"
 	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
 )
 
 //nolint:gochecknoglobals // CLI command definitions are package-level by design.
@@ -15,7 +22,19 @@ var waitingCmd = &cli.Command{
 	Aliases:  []string{"w"},
 	Usage:    "List waiting-for items",
 	Category: "Lists",
-	Action: func(ctx context.Context, _ *cli.Command) error {
+	Description: `List tasks in the waiting state. With --by, group them by the person
+they wait on, with how long each has waited and its follow-up date.
+
+		Examples:
+		  ugh waiting        # List waiting tasks
+		  ugh waiting --by   # Group waiting tasks by person`,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  flags.FlagBy,
+			Usage: "group by person with days waited and follow-up dates",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
 		filterExpr, err := buildListFilterExpr(listFilterOptions{State: flags.TaskStateWaiting})
 		if err != nil {
 			return err
@@ -36,6 +55,58 @@ var waitingCmd = &cli.Command{
 		}
 
 		writer := outputWriter()
-		return writer.WriteTasks(tasks)
+		if !cmd.Bool(flags.FlagBy) {
+			return writer.WriteTasks(tasks)
+		}
+
+		since, err := svc.ListWaitingSince(ctx)
+		if err != nil {
+			return err
+		}
+		return writer.WriteWaitingGroups(groupWaiting(tasks, since, time.Now()))
 	},
 }
+
+// groupWaiting groups waiting tasks by person, ignoring case, in name order
+// with tasks not waiting on anyone last. Each group lists the longest wait
+// first and is named as that task spells the person.
+func groupWaiting(tasks []*store.Task, since map[int64]time.Time, now time.Time) []output.WaitingGroup {
+	index := map[string]int{}
+	groups := make([]output.WaitingGroup, 0)
+	for _, task := range tasks {
+		key := strings.ToLower(strings.TrimSpace(task.WaitingFor))
+		pos, ok := index[key]
+		if !ok {
+			pos = len(groups)
+			index[key] = pos
+			groups = append(groups, output.WaitingGroup{})
+		}
+		start, ok := since[task.ID]
+		if !ok {
+			start = task.UpdatedAt
+		}
+		groups[pos].Items = append(groups[pos].Items, output.WaitingItem{
+			Task:  task,
+			Since: start,
+			Days:  int(now.Sub(start).Hours() / domain.HoursPerDay),
+		})
+	}
+
+	for i := range groups {
+		items := groups[i].Items
+		slices.SortFunc(items, func(a, b output.WaitingItem) int {
+			return cmp.Or(a.Since.Compare(b.Since), cmp.Compare(a.Task.ID, b.Task.ID))
+		})
+		groups[i].Person = strings.TrimSpace(items[0].Task.WaitingFor)
+	}
+	slices.SortStableFunc(groups, func(a, b output.WaitingGroup) int {
+		if (a.Person == "") != (b.Person == "") {
+			if a.Person == "" {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(strings.ToLower(a.Person), strings.ToLower(b.Person))
+	})
+	return groups
+}
@@ -0,0 +1,9 @@
+-- name: InsertTaskAnnotation :one
+INSERT INTO task_annotations (
+  task_id,
+  text,
+  created_at
+) VALUES (
+  ?, ?, ?
+)
+RETURNING id, task_id, text, created_at;
@@ -0,0 +1,19 @@
+-- name: InsertReview :one
+INSERT INTO reviews (
+  completed_at,
+  tasks_reviewed,
+  tasks_changed
+) VALUES (
+  ?, ?, ?
+)
+RETURNING id, completed_at, tasks_reviewed, tasks_changed;
+
+-- name: GetLastReview :one
+SELECT
+  id,
+  completed_at,
+  tasks_reviewed,
+  tasks_changed
+FROM reviews
+ORDER BY completed_at DESC, id DESC
+LIMIT 1;
@@ -0,0 +1,51 @@
+-- name: UpsertShellSession :exec
+INSERT INTO shell_sessions (
+  name,
+  updated_at,
+  selected_task_id,
+  last_task_ids_json,
+  previous_results_json,
+  context_project,
+  context_context,
+  context_filter
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT(name) DO UPDATE SET
+  updated_at = excluded.updated_at,
+  selected_task_id = excluded.selected_task_id,
+  last_task_ids_json = excluded.last_task_ids_json,
+  previous_results_json = excluded.previous_results_json,
+  context_project = excluded.context_project,
+  context_context = excluded.context_context,
+  context_filter = excluded.context_filter;
+
+-- name: GetShellSession :one
+SELECT
+  name,
+  updated_at,
+  selected_task_id,
+  last_task_ids_json,
+  previous_results_json,
+  context_project,
+  context_context,
+  context_filter
+FROM shell_sessions
+WHERE name = ?;
+
+-- name: ListShellSessions :many
+SELECT
+  name,
+  updated_at,
+  selected_task_id,
+  last_task_ids_json,
+  previous_results_json,
+  context_project,
+  context_context,
+  context_filter
+FROM shell_sessions
+ORDER BY updated_at DESC, name;
+
+-- name: DeleteShellSession :execrows
+DELETE FROM shell_sessions
+WHERE name = ?;
@@ -10,14 +10,17 @@ INSERT INTO task_versions (
   notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   updated_at,
   deleted,
   projects_json,
   contexts_json,
-  meta_json
+  meta_json,
+  checklist_json
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING version_id;
 
@@ -30,15 +33,18 @@ INSERT INTO tasks_current (
   notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   created_at,
   updated_at,
   projects_json,
   contexts_json,
   meta_json,
+  checklist_json,
   version_id
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 ON CONFLICT(id) DO UPDATE SET
   state = excluded.state,
@@ -47,12 +53,15 @@ ON CONFLICT(id) DO UPDATE SET
   notes = excluded.notes,
   due_on = excluded.due_on,
   waiting_for = excluded.waiting_for,
+  follow_up_on = excluded.follow_up_on,
+  snoozed_until = excluded.snoozed_until,
   completed_at = excluded.completed_at,
   created_at = excluded.created_at,
   updated_at = excluded.updated_at,
   projects_json = excluded.projects_json,
   contexts_json = excluded.contexts_json,
   meta_json = excluded.meta_json,
+  checklist_json = excluded.checklist_json,
   version_id = excluded.version_id;
 
 -- name: DeleteTaskCurrent :exec
@@ -68,12 +77,15 @@ SELECT
   CAST(notes AS TEXT) AS notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   created_at,
   updated_at,
   projects_json,
   contexts_json,
   meta_json,
+  checklist_json,
   version_id
 FROM tasks_current
 WHERE id = ?;
@@ -88,12 +100,15 @@ SELECT
   notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   updated_at,
   deleted,
   projects_json,
   contexts_json,
-  meta_json
+  meta_json,
+  checklist_json
 FROM task_versions
 WHERE task_id = ?
 ORDER BY version_id DESC
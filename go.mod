@@ -3,6 +3,7 @@ module github.com/mholtzscher/ugh
 go 1.25.7
 
 require (
+	atomicgo.dev/cursor v0.2.0
 	github.com/BurntSushi/toml v1.6.0
 	github.com/Masterminds/squirrel v1.5.4
 	github.com/alecthomas/participle/v2 v2.1.4
@@ -20,7 +21,6 @@ require (
 require (
 	4d63.com/gocheckcompilerdirectives v1.3.0 // indirect
 	4d63.com/gochecknoglobals v0.2.2 // indirect
-	atomicgo.dev/cursor v0.2.0 // indirect
 	atomicgo.dev/keyboard v0.2.9 // indirect
 	atomicgo.dev/schedule v0.1.0 // indirect
 	codeberg.org/chavacava/garif v0.2.0 // indirect
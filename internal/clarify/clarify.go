@@ -0,0 +1,347 @@
+// Package clarify walks through the inbox one task at a time and applies a
+// single-key decision to each, as in the GTD clarify step.
+package clarify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/term"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/nlp/compile"
+	"github.com/mholtzscher/ugh/internal/prompt"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/termutil"
+)
+
+const (
+	keyNow         = 'n'
+	keyLater       = 'l'
+	keyWaiting     = 'w'
+	keyProject     = 'p'
+	keyContext     = 'c'
+	keyDue         = 'd'
+	keyDelete      = 'x'
+	keyMakeProject = 'P'
+	keySkip        = 's'
+	keyQuit        = 'q'
+	keyHelp        = '?'
+	keyInterrupt   = 3 // Ctrl-C in raw mode
+)
+
+const actionPrompt = "[n]ow [l]ater [w]aiting [p]roject [c]ontext [d]ue [x]delete [P]make project [s]kip [q]uit [?] "
+
+// RawFunc switches the terminal to raw mode so an action is one key press,
+// and returns a function that restores it.
+type RawFunc func() (restore func(), err error)
+
+// Options configures a Processor.
+type Options struct {
+	In  io.Reader
+	Out io.Writer
+	// Raw reads actions as single key presses. Without it each action is
+	// the first character of a line, as in scripts.
+	Raw RawFunc
+	// Now resolves relative due dates; it defaults to time.Now.
+	Now func() time.Time
+}
+
+// TerminalRaw returns a RawFunc for file, or nil when it is not a terminal.
+func TerminalRaw(file *os.File) RawFunc {
+	if !termutil.IsTerminal(file) || file.Fd() > uintptr(math.MaxInt) {
+		return nil
+	}
+	fd := int(file.Fd())
+	return func() (func(), error) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, fmt.Errorf("enter raw mode: %w", err)
+		}
+		return func() { _ = term.Restore(fd, state) }, nil
+	}
+}
+
+// Summary reports how far processing got.
+type Summary struct {
+	Total int `json:"total"`
+	// Processed counts the tasks that left the inbox.
+	Processed int  `json:"processed"`
+	Skipped   int  `json:"skipped"`
+	Quit      bool `json:"quit"`
+}
+
+func (s Summary) String() string {
+	if s.Total == 0 {
+		return "Inbox zero: nothing to process"
+	}
+	left := s.Total - s.Processed
+	if left == 0 {
+		return fmt.Sprintf("Processed %d of %d inbox tasks; inbox zero", s.Processed, s.Total)
+	}
+	return fmt.Sprintf("Processed %d of %d inbox tasks; %d left", s.Processed, s.Total, left)
+}
+
+// Processor walks through inbox tasks, oldest first.
+type Processor struct {
+	svc service.Service
+	ui  *prompt.Prompter
+	raw RawFunc
+	now func() time.Time
+}
+
+// New creates a Processor that changes tasks through svc.
+func New(svc service.Service, opts Options) *Processor {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Processor{
+		svc: svc,
+		ui:  prompt.New(opts.In, opts.Out),
+		raw: opts.Raw,
+		now: now,
+	}
+}
+
+// Run processes every inbox task until the inbox is empty or the user quits.
+// Each decision is saved as it is made.
+func (p *Processor) Run(ctx context.Context) (Summary, error) {
+	tasks, err := p.svc.ListTasks(ctx, service.ListTasksRequest{
+		TodoOnly: true,
+		Filter:   nlp.Predicate{Kind: nlp.PredState, Text: domain.TaskStateInbox},
+		Sort:     []nlp.SortKey{{Field: nlp.SortFieldID}},
+	})
+	if err != nil {
+		return Summary{}, fmt.Errorf("list inbox: %w", err)
+	}
+
+	summary := Summary{Total: len(tasks)}
+	for i, task := range tasks {
+		processed, err := p.processTask(ctx, task, i+1, len(tasks))
+		if errors.Is(err, prompt.ErrQuit) {
+			summary.Quit = true
+			break
+		}
+		if err != nil {
+			return summary, err
+		}
+		if processed {
+			summary.Processed++
+		} else {
+			summary.Skipped++
+		}
+	}
+	return summary, nil
+}
+
+// processTask asks for actions on task until one moves it out of the inbox or
+// skips it. Projects, contexts and due dates keep the task current so it can
+// be tagged before it is filed.
+func (p *Processor) processTask(ctx context.Context, task *store.Task, n, total int) (bool, error) {
+	for {
+		p.ui.Printf("\n[%d of %d] %s\n", n, total, describe(task))
+		key, err := p.readKey(actionPrompt)
+		if err != nil {
+			return false, err
+		}
+
+		var updated *store.Task
+		switch key {
+		case keyNow:
+			updated, err = p.file(ctx, task, domain.TaskStateNow)
+		case keyLater:
+			updated, err = p.file(ctx, task, domain.TaskStateLater)
+		case keyWaiting:
+			updated, err = p.delegate(ctx, task)
+		case keyProject, keyContext, keyDue:
+			var next *store.Task
+			if next, err = p.annotate(ctx, task, key); err == nil && next != nil {
+				task = next
+			}
+		case keyDelete:
+			if _, err = p.svc.DeleteTasks(ctx, []int64{task.ID}); err == nil {
+				p.ui.Printf("Deleted #%d\n", task.ID)
+				return true, nil
+			}
+		case keyMakeProject:
+			updated, err = p.makeProject(ctx, task)
+		case keySkip, '\r', '\n':
+			return false, nil
+		case keyQuit, keyInterrupt:
+			return false, prompt.ErrQuit
+		case keyHelp:
+			p.printHelp()
+			continue
+		default:
+			p.ui.Printf("Unknown action %q\n", key)
+			p.printHelp()
+			continue
+		}
+
+		if errors.Is(err, prompt.ErrQuit) {
+			return false, err
+		}
+		if err != nil {
+			p.ui.Printf("Error: %v\n", err)
+			continue
+		}
+		if updated != nil {
+			p.ui.Printf("Moved #%d to %s\n", updated.ID, updated.State)
+			return true, nil
+		}
+	}
+}
+
+// file moves task out of the inbox into state.
+func (p *Processor) file(ctx context.Context, task *store.Task, state string) (*store.Task, error) {
+	return p.svc.UpdateTask(ctx, service.UpdateTaskRequest{ID: task.ID, State: &state})
+}
+
+func (p *Processor) delegate(ctx context.Context, task *store.Task) (*store.Task, error) {
+	who, err := p.ui.ReadLine("Waiting for: ")
+	if err != nil || who == "" {
+		return nil, err
+	}
+	state := domain.TaskStateWaiting
+	return p.svc.UpdateTask(ctx, service.UpdateTaskRequest{
+		ID:         task.ID,
+		State:      &state,
+		WaitingFor: &who,
+	})
+}
+
+// annotate adds a project, context or due date to task and returns it
+// updated, or nil when the answer was empty.
+func (p *Processor) annotate(ctx context.Context, task *store.Task, key rune) (*store.Task, error) {
+	req := service.UpdateTaskRequest{ID: task.ID}
+	switch key {
+	case keyProject:
+		name, err := p.ui.ReadLine("Project: ")
+		if err != nil || name == "" {
+			return nil, err
+		}
+		req.AddProjects = []string{strings.TrimPrefix(name, "#")}
+	case keyContext:
+		name, err := p.ui.ReadLine("Context: ")
+		if err != nil || name == "" {
+			return nil, err
+		}
+		req.AddContexts = []string{strings.TrimPrefix(name, "@")}
+	case keyDue:
+		value, err := p.ui.ReadLine("Due: ")
+		if err != nil || value == "" {
+			return nil, err
+		}
+		due, err := compile.NormalizeDate(value, p.now())
+		if err != nil {
+			return nil, err
+		}
+		req.DueOn = &due
+	}
+	return p.svc.UpdateTask(ctx, req)
+}
+
+// makeProject turns task into a project: it is tagged with the project and
+// kept as a later task for the outcome, and the first next action, if given,
+// is created as a now task on the project.
+func (p *Processor) makeProject(ctx context.Context, task *store.Task) (*store.Task, error) {
+	name := projectSlug(task.Title)
+	answer, err := p.ui.ReadLine(fmt.Sprintf("Project name [%s]: ", name))
+	if err != nil {
+		return nil, err
+	}
+	if answer = strings.TrimPrefix(answer, "#"); answer != "" {
+		name = answer
+	}
+	if name == "" {
+		return nil, errors.New("project name required")
+	}
+	next, err := p.ui.ReadLine("Next action (empty for none): ")
+	if err != nil {
+		return nil, err
+	}
+
+	updated, created, err := p.svc.MakeProject(ctx, task.ID, name, next)
+	if err != nil {
+		return nil, err
+	}
+	if created != nil {
+		p.ui.Printf("Created next action #%d: %s\n", created.ID, created.Title)
+	}
+	return updated, nil
+}
+
+// readKey reads one action. In raw mode it is a single key press; otherwise
+// it is the first character of the next line. End of input quits.
+func (p *Processor) readKey(question string) (rune, error) {
+	p.ui.Printf("%s", question)
+	if p.raw == nil {
+		line, err := p.ui.ReadLine("")
+		if err != nil {
+			return 0, err
+		}
+		if line == "" {
+			return '\n', nil
+		}
+		return []rune(line)[0], nil
+	}
+
+	restore, err := p.raw()
+	if err != nil {
+		return 0, err
+	}
+	key, err := p.ui.ReadRune()
+	restore()
+	if err != nil {
+		return 0, err
+	}
+	if unicode.IsPrint(key) {
+		p.ui.Printf("%c\n", key)
+	} else {
+		p.ui.Printf("\n")
+	}
+	return key, nil
+}
+
+func (p *Processor) printHelp() {
+	p.ui.Printf(`  n  do now                 l  defer to later
+  w  delegate (waiting for)  x  delete
+  p  add a project           c  add a context
+  d  set a due date          P  make it a project with a next action
+  s  skip                    q  quit, keeping what is done
+`)
+}
+
+// describe is the task line shown while processing.
+func describe(task *store.Task) string {
+	parts := []string{fmt.Sprintf("#%d %s", task.ID, task.Title)}
+	for _, project := range task.Projects {
+		parts = append(parts, "#"+project)
+	}
+	for _, context := range task.Contexts {
+		parts = append(parts, "@"+context)
+	}
+	if task.DueOn != nil {
+		parts = append(parts, "due:"+task.DueOn.Format(domain.DateLayoutYYYYMMDD))
+	}
+	return strings.Join(parts, " ")
+}
+
+// projectSlug suggests a project name from a title: lower-case words joined
+// by dashes.
+func projectSlug(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
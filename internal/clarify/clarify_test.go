@@ -0,0 +1,127 @@
+package clarify_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/clarify"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+func newInbox(t *testing.T, titles ...string) service.Service {
+	t.Helper()
+
+	ctx := context.Background()
+	st, err := store.Open(ctx, store.Options{Path: filepath.Join(t.TempDir(), "test.sqlite")})
+	require.NoError(t, err, "open store error")
+	t.Cleanup(func() { _ = st.Close() })
+
+	svc := service.NewTaskService(st)
+	for _, title := range titles {
+		_, err = svc.CreateTask(ctx, service.CreateTaskRequest{Title: title, State: "inbox"})
+		require.NoError(t, err, "create %q", title)
+	}
+	return svc
+}
+
+func run(t *testing.T, svc service.Service, input string) (clarify.Summary, string) {
+	t.Helper()
+
+	var out bytes.Buffer
+	processor := clarify.New(svc, clarify.Options{
+		In:  strings.NewReader(input),
+		Out: &out,
+		Now: func() time.Time { return time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC) },
+	})
+	summary, err := processor.Run(context.Background())
+	require.NoError(t, err, "run error")
+	return summary, out.String()
+}
+
+func TestProcessAppliesActions(t *testing.T) {
+	t.Parallel()
+
+	svc := newInbox(t, "Call mom", "Get quote", "Old idea", "Renovate kitchen")
+	input := strings.Join([]string{
+		"c", "phone", "d", "2026-03-10", "n",
+		"w", "Bob",
+		"x",
+		"P", "", "Measure walls",
+	}, "\n") + "\n"
+
+	summary, out := run(t, svc, input)
+	assert.Equal(t, clarify.Summary{Total: 4, Processed: 4}, summary, "summary mismatch")
+	assert.Contains(t, out, "[1 of 4] #1 Call mom", "progress mismatch")
+	assert.Contains(t, out, "[1 of 4] #1 Call mom @phone due:2026-03-10", "annotations should show")
+	assert.Contains(t, out, "[4 of 4] #4 Renovate kitchen", "progress mismatch")
+	assert.Equal(t, "Processed 4 of 4 inbox tasks; inbox zero", summary.String(), "summary text mismatch")
+
+	ctx := context.Background()
+	call, err := svc.GetTask(ctx, 1)
+	require.NoError(t, err, "get task 1")
+	assert.Equal(t, store.StateNow, call.State, "call state mismatch")
+	assert.Equal(t, []string{"phone"}, call.Contexts, "call contexts mismatch")
+
+	quote, err := svc.GetTask(ctx, 2)
+	require.NoError(t, err, "get task 2")
+	assert.Equal(t, store.StateWaiting, quote.State, "quote state mismatch")
+	assert.Equal(t, "Bob", quote.WaitingFor, "waiting for mismatch")
+
+	_, err = svc.GetTask(ctx, 3)
+	require.Error(t, err, "deleted task should be gone")
+
+	kitchen, err := svc.GetTask(ctx, 4)
+	require.NoError(t, err, "get task 4")
+	assert.Equal(t, store.StateLater, kitchen.State, "project task state mismatch")
+	assert.Equal(t, []string{"renovate-kitchen"}, kitchen.Projects, "project slug mismatch")
+
+	next, err := svc.GetTask(ctx, 5)
+	require.NoError(t, err, "get next action")
+	assert.Equal(t, "Measure walls", next.Title, "next action title mismatch")
+	assert.Equal(t, store.StateNow, next.State, "next action state mismatch")
+	assert.Equal(t, []string{"renovate-kitchen"}, next.Projects, "next action project mismatch")
+}
+
+func TestProcessQuitKeepsDoneWork(t *testing.T) {
+	t.Parallel()
+
+	svc := newInbox(t, "First", "Second", "Third")
+
+	summary, _ := run(t, svc, "l\ns\nq\n")
+	assert.Equal(t, clarify.Summary{Total: 3, Processed: 1, Skipped: 1, Quit: true}, summary, "summary mismatch")
+	assert.Equal(t, "Processed 1 of 3 inbox tasks; 2 left", summary.String(), "summary text mismatch")
+
+	first, err := svc.GetTask(context.Background(), 1)
+	require.NoError(t, err, "get task 1")
+	assert.Equal(t, store.StateLater, first.State, "first task should stay filed")
+
+	summary, _ = run(t, svc, "n\n")
+	assert.Equal(t, 2, summary.Total, "only unfiled tasks remain")
+	assert.True(t, summary.Quit, "end of input should quit")
+}
+
+func TestProcessRetriesBadInput(t *testing.T) {
+	t.Parallel()
+
+	svc := newInbox(t, "Only")
+
+	summary, out := run(t, svc, "z\nd\n2026-13-45\nw\n\nn\n")
+	assert.Equal(t, 1, summary.Processed, "task should be processed after retries")
+	assert.Contains(t, out, `Unknown action 'z'`, "unknown key should be reported")
+	assert.Contains(t, out, "Error: invalid date format", "bad date should be reported")
+}
+
+func TestProcessEmptyInbox(t *testing.T) {
+	t.Parallel()
+
+	summary, _ := run(t, newInbox(t), "")
+	assert.Equal(t, "Inbox zero: nothing to process", summary.String(), "summary text mismatch")
+}
@@ -37,11 +37,67 @@ type Display struct {
 	Timezone       string `toml:"timezone"`        // "local" or IANA timezone (default: "local")
 }
 
+// Input holds task entry configuration.
+type Input struct {
+	TitleDates bool     `toml:"title_dates"`      // Move date phrases in new task titles into the due date (default: false)
+	People     []string `toml:"people,omitempty"` // Names the shell completes after waiting:
+}
+
+// Meta holds optional typing rules for task meta keys.
+type Meta struct {
+	Schema map[string]MetaField `toml:"schema,omitempty"` // Declared type per meta key
+}
+
+// MetaField declares the type of one meta key. It decodes from either a bare
+// type name (points = "int") or a table (size = { type = "enum", values = [...] }).
+type MetaField struct {
+	Type   string   `toml:"type"`
+	Values []string `toml:"values,omitempty"` // Allowed values for enum fields
+}
+
+// Urgency holds the weights of the urgency score. Unset weights keep their defaults.
+type Urgency struct {
+	Due      *float64           `toml:"due,omitempty"`      // Due today or overdue (default: 12)
+	Age      *float64           `toml:"age,omitempty"`      // A year old or more (default: 2)
+	State    *float64           `toml:"state,omitempty"`    // In now; inbox counts half (default: 4)
+	Project  *float64           `toml:"project,omitempty"`  // Times the project priority (default: 3)
+	Context  *float64           `toml:"context,omitempty"`  // In the current context (default: 2)
+	Blocked  *float64           `toml:"blocked,omitempty"`  // Has meta blocked set (default: -5)
+	Waiting  *float64           `toml:"waiting,omitempty"`  // In waiting (default: -3)
+	Projects map[string]float64 `toml:"projects,omitempty"` // Priority per project, usually 0 to 1
+}
+
+// View is a saved search. Where uses the filter DSL; Sort uses the --sort syntax.
+type View struct {
+	Where string `toml:"where"`
+	Sort  string `toml:"sort,omitempty"`
+}
+
+// Template is a reusable task with optional child tasks. String fields may
+// use {name} placeholders filled from template variables; Due is an offset
+// from the day the template is used, such as "3d" or "2w".
+type Template struct {
+	Title    string            `toml:"title"`
+	Notes    string            `toml:"notes,omitempty"`
+	State    string            `toml:"state,omitempty"`
+	Projects []string          `toml:"projects,omitempty"`
+	Contexts []string          `toml:"contexts,omitempty"`
+	Meta     map[string]string `toml:"meta,omitempty"`
+	Due      string            `toml:"due,omitempty"`
+	Tasks    []Template        `toml:"tasks,omitempty"` // Child tasks, created after the template task
+}
+
 type Config struct {
-	Version int     `toml:"version"`
-	DB      DB      `toml:"db"`
-	Daemon  Daemon  `toml:"daemon"`
-	Display Display `toml:"display"`
+	Version   int                 `toml:"version"`
+	DB        DB                  `toml:"db"`
+	Daemon    Daemon              `toml:"daemon"`
+	Display   Display             `toml:"display"`
+	Input     Input               `toml:"input"`
+	Meta      Meta                `toml:"meta,omitempty"`
+	Urgency   Urgency             `toml:"urgency,omitempty"`
+	Contexts  map[string]string   `toml:"contexts,omitempty"` // Availability schedule per context
+	Views     map[string]View     `toml:"views,omitempty"`
+	Templates map[string]Template `toml:"templates,omitempty"`
 }
 
 type LoadResult struct {
@@ -58,6 +114,40 @@ var (
 	ErrOutsideHome = errors.New("db.path outside home directory not allowed")
 )
 
+// UnmarshalTOML accepts the bare-string shorthand for a meta field type.
+func (f *MetaField) UnmarshalTOML(data any) error {
+	switch typed := data.(type) {
+	case string:
+		f.Type = typed
+		return nil
+	case map[string]any:
+		if value, ok := typed["type"]; ok {
+			name, isString := value.(string)
+			if !isString {
+				return fmt.Errorf("meta field type must be a string, got %T", value)
+			}
+			f.Type = name
+		}
+		if value, ok := typed["values"]; ok {
+			items, isList := value.([]any)
+			if !isList {
+				return fmt.Errorf("meta field values must be a list, got %T", value)
+			}
+			f.Values = make([]string, 0, len(items))
+			for _, item := range items {
+				name, isString := item.(string)
+				if !isString {
+					return fmt.Errorf("meta field values must be strings, got %T", item)
+				}
+				f.Values = append(f.Values, name)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("meta field must be a type name or table, got %T", data)
+	}
+}
+
 func DefaultPath() (string, error) {
 	configDir, err := userConfigDir()
 	if err != nil {
@@ -53,6 +53,89 @@ path = "~/.local/share/ugh/ugh.sqlite"
 	assert.Equal(t, cfgPath, result.UsedPath, "Load() UsedPath mismatch")
 }
 
+func TestLoad_MetaSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.toml")
+	cfgContent := `version = 1
+
+[meta.schema]
+points = "int"
+size = { type = "enum", values = ["s", "m", "l"] }
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(cfgContent), 0o600), "write config error")
+
+	result, err := Load(cfgPath, false)
+	require.NoError(t, err, "Load() error")
+	assert.Equal(t, map[string]MetaField{
+		"points": {Type: "int"},
+		"size":   {Type: "enum", Values: []string{"s", "m", "l"}},
+	}, result.Config.Meta.Schema, "Load() meta schema mismatch")
+}
+
+func TestLoad_Views(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.toml")
+	cfgContent := `version = 1
+
+[views.focus]
+where = "state:now && (#work || @office)"
+sort = "due"
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(cfgContent), 0o600), "write config error")
+
+	result, err := Load(cfgPath, false)
+	require.NoError(t, err, "Load() error")
+	assert.Equal(t, map[string]View{
+		"focus": {Where: "state:now && (#work || @office)", Sort: "due"},
+	}, result.Config.Views, "Load() views mismatch")
+}
+
+func TestLoad_Urgency(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.toml")
+	cfgContent := `version = 1
+
+[urgency]
+due = 20
+blocked = -2.5
+
+[urgency.projects]
+launch = 1
+chores = 0.25
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(cfgContent), 0o600), "write config error")
+
+	result, err := Load(cfgPath, false)
+	require.NoError(t, err, "Load() error")
+	weights := result.Config.Urgency
+	require.NotNil(t, weights.Due, "Load() urgency due missing")
+	assert.InDelta(t, 20.0, *weights.Due, 0, "Load() urgency due mismatch")
+	require.NotNil(t, weights.Blocked, "Load() urgency blocked missing")
+	assert.InDelta(t, -2.5, *weights.Blocked, 0, "Load() urgency blocked mismatch")
+	assert.Nil(t, weights.Age, "Load() unset urgency weight should stay nil")
+	assert.Equal(t, map[string]float64{"launch": 1, "chores": 0.25}, weights.Projects,
+		"Load() urgency projects mismatch")
+}
+
+func TestLoad_Contexts(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.toml")
+	cfgContent := `version = 1
+
+[contexts]
+"@office" = "Mon-Fri 09:00-17:00"
+phone = "always"
+`
+	require.NoError(t, os.WriteFile(cfgPath, []byte(cfgContent), 0o600), "write config error")
+
+	result, err := Load(cfgPath, false)
+	require.NoError(t, err, "Load() error")
+	assert.Equal(t, map[string]string{
+		"@office": "Mon-Fri 09:00-17:00",
+		"phone":   "always",
+	}, result.Config.Contexts, "Load() contexts mismatch")
+}
+
 func TestLoad_Invalid(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfgPath := filepath.Join(tmpDir, "invalid.toml")
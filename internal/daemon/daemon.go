@@ -158,6 +158,15 @@ func (d *Daemon) syncOnce(ctx context.Context) error {
 	}
 	defer func() { _ = st.Close() }()
 
+	// Wake snoozed tasks first so the sync pushes their return.
+	woken, err := st.WakeSnoozed(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("wake snoozed tasks: %w", err)
+	}
+	if len(woken) > 0 {
+		d.logger.InfoContext(ctx, "woke snoozed tasks", "count", len(woken))
+	}
+
 	err = st.Sync(ctx)
 	if err != nil {
 		return fmt.Errorf("sync: %w", err)
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// HoursPerDay converts a duration to a count of days.
+const HoursPerDay = 24
+
+// DateIn returns midnight in loc on t's calendar date. Due dates are stored
+// as dates, so t is not converted to loc first; this lets them compare with
+// the local day.
+func DateIn(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// StartOfDay returns midnight of t's calendar day in t's location.
+func StartOfDay(t time.Time) time.Time {
+	return DateIn(t, t.Location())
+}
@@ -0,0 +1,22 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+)
+
+func TestDateInKeepsCalendarDate(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	due := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, time.March, 10, 0, 0, 0, 0, loc), domain.DateIn(due, loc), "date mismatch")
+
+	now := time.Date(2026, time.March, 10, 23, 30, 0, 0, loc)
+	assert.Equal(t, time.Date(2026, time.March, 10, 0, 0, 0, 0, loc), domain.StartOfDay(now), "start of day mismatch")
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MetaType string
+
+const (
+	MetaTypeInt   MetaType = "int"
+	MetaTypeFloat MetaType = "float"
+	MetaTypeDate  MetaType = "date"
+	MetaTypeBool  MetaType = "bool"
+	MetaTypeEnum  MetaType = "enum"
+
+	MetaTypesUsage = "int|float|date|bool|enum"
+)
+
+// MetaField declares the type of a single meta key.
+type MetaField struct {
+	Type   MetaType
+	Values []string
+}
+
+// MetaSchema maps meta keys to their declared types. Keys without an entry
+// are free-form strings.
+type MetaSchema map[string]MetaField
+
+func ParseMetaType(value string) (MetaType, error) {
+	switch MetaType(strings.ToLower(strings.TrimSpace(value))) {
+	case MetaTypeInt:
+		return MetaTypeInt, nil
+	case MetaTypeFloat:
+		return MetaTypeFloat, nil
+	case MetaTypeDate:
+		return MetaTypeDate, nil
+	case MetaTypeBool:
+		return MetaTypeBool, nil
+	case MetaTypeEnum:
+		return MetaTypeEnum, nil
+	default:
+		return "", fmt.Errorf("invalid meta type %q (expected %s)", value, MetaTypesUsage)
+	}
+}
+
+// Validate reports schema entries with unknown types or enums without values.
+func (s MetaSchema) Validate() error {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		field := s[key]
+		if strings.TrimSpace(key) == "" {
+			return errors.New("meta schema: key cannot be empty")
+		}
+		if _, err := ParseMetaType(string(field.Type)); err != nil {
+			return fmt.Errorf("meta schema %q: %w", key, err)
+		}
+		if field.Type == MetaTypeEnum && len(field.Values) == 0 {
+			return fmt.Errorf("meta schema %q: enum requires values", key)
+		}
+	}
+	return nil
+}
+
+// Field returns the declared field for key, if any.
+func (s MetaSchema) Field(key string) (MetaField, bool) {
+	field, ok := s[strings.TrimSpace(key)]
+	return field, ok
+}
+
+// NormalizeValue validates value against the type declared for key and
+// returns its canonical form. Undeclared keys are returned trimmed.
+func (s MetaSchema) NormalizeValue(key string, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	field, ok := s.Field(key)
+	if !ok {
+		return value, nil
+	}
+	normalized, err := field.Normalize(value)
+	if err != nil {
+		return "", fmt.Errorf("meta %q: %w", key, err)
+	}
+	return normalized, nil
+}
+
+// NormalizeMeta validates every entry in meta and returns a normalized copy.
+func (s MetaSchema) NormalizeMeta(meta map[string]string) (map[string]string, error) {
+	if meta == nil {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	result := make(map[string]string, len(meta))
+	for _, key := range keys {
+		normalized, err := s.NormalizeValue(key, meta[key])
+		if err != nil {
+			return nil, err
+		}
+		result[key] = normalized
+	}
+	return result, nil
+}
+
+// Ordered reports whether values of the field can be compared with < and >.
+func (f MetaField) Ordered() bool {
+	switch f.Type {
+	case MetaTypeInt, MetaTypeFloat, MetaTypeDate, MetaTypeEnum:
+		return true
+	case MetaTypeBool:
+		return false
+	default:
+		return false
+	}
+}
+
+// Numeric reports whether the field stores numbers.
+func (f MetaField) Numeric() bool {
+	return f.Type == MetaTypeInt || f.Type == MetaTypeFloat
+}
+
+// Rank returns the declared position of an enum value.
+func (f MetaField) Rank(value string) (int, bool) {
+	idx := slices.IndexFunc(f.Values, func(candidate string) bool {
+		return strings.EqualFold(candidate, strings.TrimSpace(value))
+	})
+	return idx, idx >= 0
+}
+
+// Normalize validates value against the field type and returns its canonical form.
+func (f MetaField) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch f.Type {
+	case MetaTypeInt:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid int %q", value)
+		}
+		return strconv.FormatInt(parsed, 10), nil
+	case MetaTypeFloat:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid float %q", value)
+		}
+		return strconv.FormatFloat(parsed, 'f', -1, 64), nil
+	case MetaTypeDate:
+		if _, err := time.Parse(DateLayoutYYYYMMDD, value); err != nil {
+			return "", InvalidDateFormatError(value)
+		}
+		return value, nil
+	case MetaTypeBool:
+		parsed, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			return "", fmt.Errorf("invalid bool %q", value)
+		}
+		return strconv.FormatBool(parsed), nil
+	case MetaTypeEnum:
+		idx, ok := f.Rank(value)
+		if !ok {
+			return "", fmt.Errorf("invalid value %q (expected %s)", value, strings.Join(f.Values, "|"))
+		}
+		return f.Values[idx], nil
+	default:
+		return "", fmt.Errorf("invalid meta type %q (expected %s)", f.Type, MetaTypesUsage)
+	}
+}
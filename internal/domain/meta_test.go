@@ -0,0 +1,72 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+)
+
+func TestMetaFieldNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		field   domain.MetaField
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "int trims leading zeros", field: domain.MetaField{Type: domain.MetaTypeInt}, input: " 007 ", want: "7"},
+		{name: "int rejects text", field: domain.MetaField{Type: domain.MetaTypeInt}, input: "lots", wantErr: true},
+		{name: "float canonical", field: domain.MetaField{Type: domain.MetaTypeFloat}, input: "2.50", want: "2.5"},
+		{name: "date valid", field: domain.MetaField{Type: domain.MetaTypeDate}, input: "2026-03-01", want: "2026-03-01"},
+		{name: "date invalid", field: domain.MetaField{Type: domain.MetaTypeDate}, input: "03/01", wantErr: true},
+		{name: "bool lowercase", field: domain.MetaField{Type: domain.MetaTypeBool}, input: "TRUE", want: "true"},
+		{
+			name:  "enum canonical case",
+			field: domain.MetaField{Type: domain.MetaTypeEnum, Values: []string{"s", "m", "l"}},
+			input: "M",
+			want:  "m",
+		},
+		{
+			name:    "enum unknown value",
+			field:   domain.MetaField{Type: domain.MetaTypeEnum, Values: []string{"s", "m", "l"}},
+			input:   "xl",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.field.Normalize(tc.input)
+			if tc.wantErr {
+				require.Error(t, err, "Normalize(%q) should return error", tc.input)
+				return
+			}
+			require.NoError(t, err, "Normalize(%q) error", tc.input)
+			assert.Equal(t, tc.want, got, "Normalize(%q) mismatch", tc.input)
+		})
+	}
+}
+
+func TestMetaSchemaValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, domain.MetaSchema{"points": {Type: domain.MetaTypeInt}}.Validate())
+	require.Error(t, domain.MetaSchema{"size": {Type: domain.MetaTypeEnum}}.Validate(), "enum without values")
+	require.Error(t, domain.MetaSchema{"x": {Type: "decimal"}}.Validate(), "unknown type")
+}
+
+func TestMetaSchemaNormalizeValueLeavesUndeclaredKeys(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.MetaSchema{}.NormalizeValue("owner", " alice ")
+	require.NoError(t, err, "NormalizeValue(undeclared) error")
+	assert.Equal(t, "alice", got, "undeclared value should only be trimmed")
+}
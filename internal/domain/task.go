@@ -15,6 +15,15 @@ const (
 	TaskStatesUsage = TaskStateInbox + "|" + TaskStateNow + "|" + TaskStateWaiting + "|" + TaskStateLater + "|" + TaskStateDone
 )
 
+const (
+	GroupByProject = "project"
+	GroupByContext = "context"
+	GroupByState   = "state"
+	GroupByDueWeek = "due-week"
+
+	GroupByUsage = GroupByProject + "|" + GroupByContext + "|" + GroupByState + "|" + GroupByDueWeek
+)
+
 const (
 	DateLayoutYYYYMMDD = "2006-01-02"
 	DateTextYYYYMMDD   = "YYYY-MM-DD"
@@ -61,6 +70,10 @@ func InvalidDueOnFormatError(value string) error {
 	return fmt.Errorf("invalid due_on %q: expected %s", value, DateTextYYYYMMDD)
 }
 
+func InvalidFollowUpOnFormatError(value string) error {
+	return fmt.Errorf("invalid follow_up_on %q: expected %s", value, DateTextYYYYMMDD)
+}
+
 func InvalidMetaFormatError(value string) error {
 	return fmt.Errorf("invalid meta format: %s (expected %s)", value, MetaTextKeyValue)
 }
@@ -25,12 +25,20 @@ var taskSchemaJSON []byte
 type TaskTOML struct {
 	Title      string            `toml:"title"`
 	Notes      string            `toml:"notes,omitempty"`
+	Annotation string            `toml:"annotation"`
 	State      string            `toml:"state"`
 	DueOn      string            `toml:"due_on,omitempty"`
 	WaitingFor string            `toml:"waiting_for,omitempty"`
+	FollowUpOn string            `toml:"follow_up_on,omitempty"`
 	Projects   []string          `toml:"projects,omitempty"`
 	Contexts   []string          `toml:"contexts,omitempty"`
 	Meta       map[string]string `toml:"meta,omitempty"`
+	Checklist  []ChecklistTOML   `toml:"checklist,omitempty"`
+}
+
+type ChecklistTOML struct {
+	Text string `toml:"text"`
+	Done bool   `toml:"done"`
 }
 
 func TaskToTOML(task *store.Task) TaskTOML {
@@ -47,35 +55,70 @@ func TaskToTOML(task *store.Task) TaskTOML {
 		meta = map[string]string{}
 	}
 
+	checklist := make([]ChecklistTOML, 0, len(task.Checklist))
+	for _, item := range task.Checklist {
+		checklist = append(checklist, ChecklistTOML{Text: item.Text, Done: item.Done})
+	}
+
 	return TaskTOML{
 		Title:      task.Title,
 		Notes:      task.Notes,
 		State:      string(task.State),
 		DueOn:      formatDay(task.DueOn),
 		WaitingFor: task.WaitingFor,
+		FollowUpOn: formatDay(task.FollowUpOn),
 		Projects:   projects,
 		Contexts:   contexts,
 		Meta:       meta,
+		Checklist:  checklist,
 	}
 }
 
+// ChecklistItems returns the edited checklist as store items.
+func (t *TaskTOML) ChecklistItems() []store.ChecklistItem {
+	items := make([]store.ChecklistItem, 0, len(t.Checklist))
+	for _, item := range t.Checklist {
+		items = append(items, store.ChecklistItem{Text: item.Text, Done: item.Done})
+	}
+	return items
+}
+
 const taskSchemaFileName = "ugh-task.schema.json"
 
-func taskTOMLHeader(taskID int64) string {
+func taskTOMLHeader(task *store.Task) string {
 	return fmt.Sprintf(`# Task %d - Edit and save to apply changes
 # Lines starting with # are ignored
 #
 # Fields:
 #   title        - The action title (required)
 #   notes        - Optional notes
+#   annotation   - A new annotation to add; existing ones are listed below
 #   state        - %s
 #   due_on       - %s
 #   waiting_for  - Optional string
+#   follow_up_on - %s
 #   projects     - List of project names
 #   contexts     - List of context names
 #   meta         - Key-value pairs
+#   checklist    - [[checklist]] tables with text and done
+%s
+`, task.ID, domain.TaskStatesUsage, domain.DateTextYYYYMMDD, domain.DateTextYYYYMMDD,
+		annotationsHeader(task.Annotations))
+}
 
-`, taskID, domain.TaskStatesUsage, domain.DateTextYYYYMMDD)
+// annotationsHeader lists existing annotations as comments, since they can
+// only be added to.
+func annotationsHeader(annotations []store.Annotation) string {
+	if len(annotations) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("#\n# Annotations (read-only):\n")
+	for _, annotation := range annotations {
+		text := strings.Join(strings.Fields(annotation.Text), " ")
+		_, _ = fmt.Fprintf(&b, "#   %s  %s\n", annotation.CreatedAt.Local().Format("2006-01-02 15:04"), text)
+	}
+	return b.String()
 }
 
 //nolint:funlen
@@ -111,7 +154,7 @@ enabled = true
 		}
 	}
 
-	header := taskTOMLHeader(task.ID)
+	header := taskTOMLHeader(task)
 	if useSchemaHeader {
 		header = fmt.Sprintf("#:schema %s\n%s", schemaRef, header)
 	}
@@ -218,10 +261,18 @@ func validate(t *TaskTOML) error {
 			return domain.InvalidDueOnFormatError(t.DueOn)
 		}
 	}
+	t.Annotation = strings.TrimSpace(t.Annotation)
 	t.WaitingFor = strings.TrimSpace(t.WaitingFor)
+	t.FollowUpOn = strings.TrimSpace(t.FollowUpOn)
+	if t.FollowUpOn != "" {
+		if _, err := time.Parse(domain.DateLayoutYYYYMMDD, t.FollowUpOn); err != nil {
+			return domain.InvalidFollowUpOnFormatError(t.FollowUpOn)
+		}
+	}
 
 	t.Projects = cleanTags(t.Projects)
 	t.Contexts = cleanTags(t.Contexts)
+	t.Checklist = cleanChecklist(t.Checklist)
 
 	return nil
 }
@@ -251,3 +302,15 @@ func cleanTags(tags []string) []string {
 	}
 	return result
 }
+
+// cleanChecklist trims item text and drops items left empty.
+func cleanChecklist(items []ChecklistTOML) []ChecklistTOML {
+	result := make([]ChecklistTOML, 0, len(items))
+	for _, item := range items {
+		item.Text = strings.TrimSpace(item.Text)
+		if item.Text != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -4,6 +4,8 @@ import "github.com/mholtzscher/ugh/internal/domain"
 
 const (
 	FlagAll           = "all"
+	FlagAvailable     = "available"
+	FlagBy            = "by"
 	FlagClear         = "clear"
 	FlagCompleted     = "completed"
 	FlagConfigPath    = "config"
@@ -14,10 +16,12 @@ const (
 	FlagDescription   = "description"
 	FlagFailed        = "failed"
 	FlagForce         = "force"
+	FlagGroupBy       = "group-by"
 	FlagIntent        = "intent"
 	FlagTitle         = "title"
 	FlagDone          = "done"
 	FlagEditor        = "editor"
+	FlagExplain       = "explain"
 	FlagJSON          = "json"
 	FlagLimit         = "limit"
 	FlagLines         = "lines"
@@ -35,21 +39,32 @@ const (
 	FlagRemoveProject = "remove-project"
 	FlagSearch        = "search"
 	FlagSeed          = "seed"
+	FlagSort          = "sort"
+	FlagStaleDays     = "stale-days"
+	FlagStalled       = "stalled"
 	FlagState         = "state"
+	FlagStatus        = "status"
 	FlagSuccess       = "success"
 	FlagCount         = "count"
 	FlagChurn         = "churn"
+	FlagTemplate      = "template"
 	FlagTodo          = "todo"
 	FlagUndone        = "undone"
+	FlagUntil         = "until"
+	FlagVar           = "var"
+	FlagView          = "view"
 	FlagDueOn         = "due"
+	FlagWaitingDays   = "waiting-days"
 	FlagWaitingFor    = "waiting-for"
 	FlagWhere         = "where"
+	FlagYes           = "yes"
 )
 
 const (
 	FieldState = "state"
 	FieldDate  = "date"
 	FieldMeta  = "meta"
+	FieldGroup = "group"
 )
 
 const (
@@ -62,6 +77,15 @@ const (
 	TaskStatesUsage = domain.TaskStatesUsage
 )
 
+const (
+	GroupByProject = domain.GroupByProject
+	GroupByContext = domain.GroupByContext
+	GroupByState   = domain.GroupByState
+	GroupByDueWeek = domain.GroupByDueWeek
+
+	GroupByUsage = domain.GroupByUsage
+)
+
 const (
 	DateLayoutYYYYMMDD = domain.DateLayoutYYYYMMDD
 	DateTextYYYYMMDD   = domain.DateTextYYYYMMDD
@@ -73,3 +97,7 @@ const (
 func TaskStates() []string {
 	return []string{TaskStateInbox, TaskStateNow, TaskStateWaiting, TaskStateLater, TaskStateDone}
 }
+
+func GroupByValues() []string {
+	return []string{GroupByProject, GroupByContext, GroupByState, GroupByDueWeek}
+}
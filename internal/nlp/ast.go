@@ -1,11 +1,14 @@
 package nlp
 
+import "github.com/alecthomas/participle/v2/lexer"
+
 //go:generate go run golang.org/x/tools/cmd/stringer@latest -type=Field -trimprefix=Field -output=ast_field_string.go
-//go:generate go run golang.org/x/tools/cmd/stringer@latest -type=PredicateKind,TargetKind,TagKind,FilterBoolOp -output=ast_string.go
+//go:generate go run golang.org/x/tools/cmd/stringer@latest -type=PredicateKind,TargetKind,TagKind,FilterBoolOp,CompareOp -output=ast_string.go
 
-// Root is the top-level grammar entrypoint for the DSL.
+// Root is the top-level grammar entrypoint for the DSL: statements separated
+// by ";", each a pipeline of commands joined by "|".
 type Root struct {
-	Cmd Command `parser:"@@"`
+	Statements []*statementNode `parser:"@@ ( Semicolon @@ )* Semicolon?"`
 }
 
 type Command interface {
@@ -24,12 +27,26 @@ type ContextVerb string
 
 type LogVerb string
 
+type DoneVerb string
+
+type UndoVerb string
+
+type DeleteVerb string
+
+type ShowVerb string
+
+type SnoozeVerb string
+
+type NoteVerb string
+
 const (
-	viewNameInbox    = "inbox"
-	viewNameNow      = "now"
-	viewNameWaiting  = "waiting"
-	viewNameLater    = "later"
-	viewNameCalendar = "calendar"
+	viewNameInbox     = "inbox"
+	viewNameNow       = "now"
+	viewNameWaiting   = "waiting"
+	viewNameLater     = "later"
+	viewNameCalendar  = "calendar"
+	viewNameFollowUp  = "follow-up"
+	viewNameAvailable = "available"
 
 	FilterWildcard = "*"
 )
@@ -39,11 +56,22 @@ type CreateCommand struct {
 	Parts []CreatePart `parser:"@@*"`
 
 	Title string
+	// Words are the title tokens in order; Title is their joined text.
+	Words []TitleWord
 	Ops   []Operation
 }
 
 func (*CreateCommand) command() {}
 
+// TitleWord is one token of a create title. Quoted words came from a quoted
+// string and are kept verbatim. Glued words followed the word before them
+// without a space, as the comma in "apples, pears" does.
+type TitleWord struct {
+	Text   string
+	Quoted bool
+	Glued  bool
+}
+
 type CreatePart interface {
 	createPart()
 }
@@ -55,7 +83,10 @@ type CreateOpPart struct {
 func (*CreateOpPart) createPart() {}
 
 type CreateText struct {
-	Text OpValue `parser:"@(Ident | Quoted | HashNumber | Comma)"`
+	Pos    lexer.Position
+	EndPos lexer.Position
+	Quoted OpValue `parser:"  @Quoted"`
+	Text   OpValue `parser:"| @(Ident | HashNumber | Comma)"`
 }
 
 func (*CreateText) createPart() {}
@@ -65,18 +96,27 @@ type CreateOp interface {
 }
 
 type UpdateCommand struct {
-	Verb   UpdateVerb  `parser:"@@"`
-	Target *TargetRef  `parser:"@@?"`
-	Ops    []Operation `parser:"@@*"`
+	Verb   UpdateVerb   `parser:"@@"`
+	Where  *UpdateWhere `parser:"( @@"`
+	Target *TargetRef   `parser:"| @@ )?"`
+	Ops    []Operation  `parser:"@@*"`
+
+	// WhereExpr is the filter from Where; when set, the update applies to
+	// every matching task instead of Target.
+	WhereExpr FilterExpr
 }
 
 func (*UpdateCommand) command() {}
 
 type FilterCommand struct {
-	Verb  FilterVerb     `parser:"@@"`
-	Chain *FilterOrChain `parser:"@@"`
+	Verb  FilterVerb        `parser:"@@"`
+	Chain *FilterOrChain    `parser:"@@?"`
+	Sort  *FilterSortClause `parser:"@@?"`
 
-	Expr FilterExpr
+	Expr     FilterExpr
+	SortKeys []SortKey
+	// Piped restricts the filter to the tasks of the previous pipeline stage.
+	Piped bool
 }
 
 func (*FilterCommand) command() {}
@@ -89,8 +129,17 @@ type ViewCommand struct {
 func (*ViewCommand) command() {}
 
 type ContextCommand struct {
-	Verb ContextVerb `parser:"@@"`
-	Arg  *ContextArg `parser:"@@?"`
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Verb  ContextVerb    `parser:"@@"`
+	Arg   *ContextArg    `parser:"( @@"`
+	Chain *FilterOrChain `parser:"| @@ )?"`
+
+	// Filter is the sticky filter expression from Chain, e.g.
+	// "context state:now && due:today", and FilterText its source.
+	Filter     FilterExpr
+	FilterText string
 }
 
 func (*ContextCommand) command() {}
@@ -102,6 +151,84 @@ type LogCommand struct {
 
 func (*LogCommand) command() {}
 
+type DoneCommand struct {
+	Verb   DoneVerb   `parser:"@@"`
+	Target *TargetRef `parser:"@@?"`
+}
+
+func (*DoneCommand) command() {}
+
+type UndoCommand struct {
+	Verb   UndoVerb   `parser:"@@"`
+	Target *TargetRef `parser:"@@?"`
+}
+
+func (*UndoCommand) command() {}
+
+type DeleteCommand struct {
+	Verb   DeleteVerb `parser:"@@"`
+	Target *TargetRef `parser:"@@?"`
+}
+
+func (*DeleteCommand) command() {}
+
+// ShowCommand shows task details by target, e.g. "show 3" or "show it". It
+// only matches when the rest of the command is a target or nothing, so
+// "show #work" stays a filter.
+type ShowCommand struct {
+	Verb   ShowVerb
+	Target *TargetRef
+}
+
+func (*ShowCommand) command() {}
+
+// SnoozeCommand moves tasks to later until a date, e.g. "snooze it until
+// friday" or "snooze 3 until tomorrow 9am".
+type SnoozeCommand struct {
+	Verb   SnoozeVerb
+	Target *TargetRef
+	// Until is the date phrase after "until".
+	Until string
+}
+
+func (*SnoozeCommand) command() {}
+
+// NoteCommand adds an annotation to tasks, e.g. "note it called vendor,
+// waiting on quote".
+type NoteCommand struct {
+	Verb   NoteVerb
+	Target *TargetRef
+	// Text is the rest of the stage after the target, as typed.
+	Text string
+
+	textStart, textEnd int
+}
+
+func (*NoteCommand) command() {}
+
+// TemplateCommand creates tasks from a named template, e.g. "add from
+// onboarding name:Sam".
+type TemplateCommand struct {
+	Verb CreateVerb
+	Name string
+	// Vars fill the template placeholders, keyed by lowercase name.
+	Vars map[string]string
+}
+
+func (*TemplateCommand) command() {}
+
+// UpdateWhere selects update targets with a single filter term, e.g.
+// "set where #x state:now"; parenthesize compound filters:
+// "set where (#x && state:later) state:now".
+type UpdateWhere struct {
+	Filter *FilterNotExpr `parser:"'where' @@"`
+}
+
+// FilterSortClause is a trailing "sort:due,-updated" or "order by due" clause.
+type FilterSortClause struct {
+	Spec string
+}
+
 type ViewTarget struct {
 	Name string
 }
@@ -143,9 +270,10 @@ type FilterAtom struct {
 }
 
 type FilterPredicate struct {
-	Field *FilterFieldPredicate `parser:"@@"`
-	Tag   *FilterTagPredicate   `parser:"| @@"`
-	Text  *FilterTextPredicate  `parser:"| @@"`
+	Field  *FilterFieldPredicate  `parser:"@@"`
+	Absent *FilterAbsentPredicate `parser:"| @@"`
+	Tag    *FilterTagPredicate    `parser:"| @@"`
+	Text   *FilterTextPredicate   `parser:"| @@"`
 }
 
 type FilterFieldPredicate struct {
@@ -153,6 +281,13 @@ type FilterFieldPredicate struct {
 	Value *FilterValue `parser:"@@?"`
 }
 
+// FilterAbsentPredicate matches tasks where a field is empty, e.g. !due or
+// !meta:points.
+type FilterAbsentPredicate struct {
+	Field string `parser:"@ClearField"`
+	Key   string `parser:"(Colon @Ident)?"`
+}
+
 type FilterTagPredicate struct {
 	Project string `parser:"@ProjectTag"`
 	Context string `parser:"| @ContextTag"`
@@ -167,11 +302,25 @@ type TargetKind int
 const (
 	TargetSelected TargetKind = iota
 	TargetID
+	// TargetIDs is a list and/or range of IDs, e.g. 3,5,9 or 10-14.
+	TargetIDs
+	// TargetLast is every task from the previous command ("those").
+	TargetLast
+	// TargetRecent is one task of the previous command counted from the end
+	// (N): 1 for "it", "this", "last" and "$last", 2 for "that".
+	TargetRecent
+	// TargetResult is every task of the Nth previous command, e.g. ^2.
+	TargetResult
+	// TargetRow is the Nth row of the previous command's results, e.g. %3.
+	TargetRow
 )
 
 type TargetRef struct {
 	Kind TargetKind
 	ID   int64
+	IDs  []int64
+	// N is the position used by TargetRecent, TargetResult and TargetRow.
+	N int
 }
 
 // OpValue is a string-like value that can span multiple tokens and is
@@ -194,6 +343,7 @@ const (
 	FieldProjects
 	FieldContexts
 	FieldMeta
+	FieldFollowUp
 )
 
 type Operation interface {
@@ -281,16 +431,74 @@ const (
 	PredText
 	PredID
 	PredRecent
+	PredMeta
+	PredTitle
+	PredNotes
+	PredWaiting
+	PredFollowUp
+	// PredAvailable matches tasks whose contexts are usable now under the
+	// configured context schedules.
+	PredAvailable
+)
+
+// CompareOp is the comparison applied by a predicate. Predicates without an
+// explicit operator use CompareEq.
+type CompareOp int
+
+const (
+	CompareEq CompareOp = iota
+	CompareLt
+	CompareLte
+	CompareGt
+	CompareGte
+	CompareRange
+	CompareContains
+	CompareRegex
 )
 
 type Predicate struct {
 	Kind PredicateKind
 
 	Text string
+
+	// Key names the meta key for PredMeta predicates.
+	Key string
+	// Op is the comparison for PredMeta, PredFollowUp and text field predicates;
+	// CompareRange matches values between Text and Upper inclusive.
+	Op    CompareOp
+	Upper string
+	// IDs makes a PredID predicate match any of several tasks, such as the
+	// input of a pipeline stage; Text is empty then.
+	IDs []int64
 }
 
 func (Predicate) filterExpr() {}
 
+const (
+	SortFieldID      = "id"
+	SortFieldTitle   = "title"
+	SortFieldState   = "state"
+	SortFieldDue     = "due"
+	SortFieldCreated = "created"
+	SortFieldUpdated = "updated"
+	SortFieldMeta    = "meta"
+	// SortFieldUrgency orders by computed urgency, least urgent first like
+	// the other fields. The service scores tasks itself; the store never sees
+	// this field.
+	SortFieldUrgency = "urgency"
+)
+
+// SortFieldsUsage lists the accepted sort fields for help text and errors.
+const SortFieldsUsage = "id|title|state|due|created|updated|urgency|meta:<key>"
+
+// SortKey orders filter results by a single field.
+type SortKey struct {
+	Field string
+	// Key names the meta key when Field is SortFieldMeta.
+	Key  string
+	Desc bool
+}
+
 // HasProjectTag returns true if the command has an explicit project tag.
 func (c *CreateCommand) HasProjectTag() bool {
 	return c != nil && hasTag(c.Ops, TagProject)
@@ -359,6 +567,18 @@ func (f *FilterCommand) InjectContext(name string) {
 	}
 }
 
+// InjectFilter ANDs a sticky filter expression into the filter expression.
+func (f *FilterCommand) InjectFilter(expr FilterExpr) {
+	if f == nil || expr == nil {
+		return
+	}
+	if f.Expr == nil {
+		f.Expr = expr
+	} else {
+		f.Expr = FilterBinary{Op: FilterAnd, Left: f.Expr, Right: expr}
+	}
+}
+
 // HasProjectTag returns true if the update has an explicit project tag.
 func (u *UpdateCommand) HasProjectTag() bool {
 	return u != nil && hasTag(u.Ops, TagProject)
@@ -16,11 +16,12 @@ func _() {
 	_ = x[FieldProjects-5]
 	_ = x[FieldContexts-6]
 	_ = x[FieldMeta-7]
+	_ = x[FieldFollowUp-8]
 }
 
-const _Field_name = "TitleNotesDueWaitingStateProjectsContextsMeta"
+const _Field_name = "TitleNotesDueWaitingStateProjectsContextsMetaFollowUp"
 
-var _Field_index = [...]uint8{0, 5, 10, 13, 20, 25, 33, 41, 45}
+var _Field_index = [...]uint8{0, 5, 10, 13, 20, 25, 33, 41, 45, 53}
 
 func (i Field) String() string {
 	idx := int(i) - 0
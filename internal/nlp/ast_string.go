@@ -1,4 +1,4 @@
-// Code generated by "stringer -type=PredicateKind,TargetKind,TagKind,FilterBoolOp -output=ast_string.go"; DO NOT EDIT.
+// Code generated by "stringer -type=PredicateKind,TargetKind,TagKind,FilterBoolOp,CompareOp -output=ast_string.go"; DO NOT EDIT.
 
 package nlp
 
@@ -15,11 +15,17 @@ func _() {
 	_ = x[PredText-4]
 	_ = x[PredID-5]
 	_ = x[PredRecent-6]
+	_ = x[PredMeta-7]
+	_ = x[PredTitle-8]
+	_ = x[PredNotes-9]
+	_ = x[PredWaiting-10]
+	_ = x[PredFollowUp-11]
+	_ = x[PredAvailable-12]
 }
 
-const _PredicateKind_name = "PredStatePredDuePredProjectPredContextPredTextPredIDPredRecent"
+const _PredicateKind_name = "PredStatePredDuePredProjectPredContextPredTextPredIDPredRecentPredMetaPredTitlePredNotesPredWaitingPredFollowUpPredAvailable"
 
-var _PredicateKind_index = [...]uint8{0, 9, 16, 27, 38, 46, 52, 62}
+var _PredicateKind_index = [...]uint8{0, 9, 16, 27, 38, 46, 52, 62, 70, 79, 88, 99, 111, 124}
 
 func (i PredicateKind) String() string {
 	idx := int(i) - 0
@@ -34,11 +40,16 @@ func _() {
 	var x [1]struct{}
 	_ = x[TargetSelected-0]
 	_ = x[TargetID-1]
+	_ = x[TargetIDs-2]
+	_ = x[TargetLast-3]
+	_ = x[TargetRecent-4]
+	_ = x[TargetResult-5]
+	_ = x[TargetRow-6]
 }
 
-const _TargetKind_name = "TargetSelectedTargetID"
+const _TargetKind_name = "TargetSelectedTargetIDTargetIDsTargetLastTargetRecentTargetResultTargetRow"
 
-var _TargetKind_index = [...]uint8{0, 14, 22}
+var _TargetKind_index = [...]uint8{0, 14, 22, 31, 41, 53, 65, 74}
 
 func (i TargetKind) String() string {
 	idx := int(i) - 0
@@ -85,3 +96,28 @@ func (i FilterBoolOp) String() string {
 	}
 	return _FilterBoolOp_name[_FilterBoolOp_index[idx]:_FilterBoolOp_index[idx+1]]
 }
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[CompareEq-0]
+	_ = x[CompareLt-1]
+	_ = x[CompareLte-2]
+	_ = x[CompareGt-3]
+	_ = x[CompareGte-4]
+	_ = x[CompareRange-5]
+	_ = x[CompareContains-6]
+	_ = x[CompareRegex-7]
+}
+
+const _CompareOp_name = "CompareEqCompareLtCompareLteCompareGtCompareGteCompareRangeCompareContainsCompareRegex"
+
+var _CompareOp_index = [...]uint8{0, 9, 18, 28, 37, 47, 59, 74, 86}
+
+func (i CompareOp) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_CompareOp_index)-1 {
+		return "CompareOp(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _CompareOp_name[_CompareOp_index[idx]:_CompareOp_index[idx+1]]
+}
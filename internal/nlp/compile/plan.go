@@ -3,6 +3,7 @@ package compile
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -19,20 +20,54 @@ type Plan struct {
 	Intent nlp.Intent
 
 	Create *service.CreateTaskRequest
-	Update *service.UpdateTaskRequest
-	Filter *service.ListTasksRequest
+	// Update is the first (usually only) entry of Updates, or the request
+	// applied to every match of Where.
+	Update  *service.UpdateTaskRequest
+	Updates []service.UpdateTaskRequest
+	Filter  *service.ListTasksRequest
 
 	Target nlp.TargetRef
+	// TaskIDs are the resolved targets of done, undo, delete, show, snooze
+	// and note.
+	TaskIDs []int64
+	// ImplicitTarget reports that TaskIDs came from the selection or a
+	// pronoun rather than IDs typed in the command.
+	ImplicitTarget bool
+	// SnoozeUntil is when snoozed tasks return.
+	SnoozeUntil time.Time
+	// NoteText is the annotation a note adds.
+	NoteText string
+	// TemplateName and TemplateVars name the template an "add from" uses and
+	// fill its placeholders.
+	TemplateName string
+	TemplateVars map[string]string
+	// Where selects the tasks for a filter-targeted update; Update.ID is
+	// unset when it is non-nil.
+	Where nlp.FilterExpr
+
+	// Diagnostics report compile-time adjustments, such as a due date taken
+	// from the title.
+	Diagnostics []nlp.Diagnostic
 }
 
 type BuildOptions struct {
 	SelectedTaskID *int64
-	Now            time.Time
+	// LastTaskIDs resolves "those" targets to the previous command's tasks.
+	LastTaskIDs []int64
+	// PreviousResults holds the task IDs of the commands before the last one,
+	// most recent first, for ^2 and beyond.
+	PreviousResults [][]int64
+	Now             time.Time
+	MetaSchema      domain.MetaSchema
+	// TitleDates moves a date phrase in a create title into the due date.
+	TitleDates bool
 }
 
 const (
 	splitNParts     = 2
 	nextWeekDaySpan = 7
+	// untilLayoutDateTime is the exact date and time form of a snooze date.
+	untilLayoutDateTime = "2006-01-02 15:04"
 )
 
 func Build(result nlp.ParseResult, opts BuildOptions) (Plan, error) {
@@ -42,17 +77,28 @@ func Build(result nlp.ParseResult, opts BuildOptions) (Plan, error) {
 
 	switch cmd := result.Command.(type) {
 	case *nlp.CreateCommand:
-		req, err := buildCreateRequest(cmd, opts)
+		req, diagnostics, err := buildCreateRequest(cmd, opts)
 		if err != nil {
 			return Plan{}, err
 		}
-		return Plan{Intent: nlp.IntentCreate, Create: &req}, nil
+		return Plan{Intent: nlp.IntentCreate, Create: &req, Diagnostics: diagnostics}, nil
 	case *nlp.UpdateCommand:
-		req, target, err := buildUpdateRequest(cmd, opts)
+		if cmd.WhereExpr != nil {
+			where, err := NormalizeFilterExpr(cmd.WhereExpr, opts)
+			if err != nil {
+				return Plan{}, err
+			}
+			req, err := buildUpdateOps(cmd, 0, opts)
+			if err != nil {
+				return Plan{}, err
+			}
+			return Plan{Intent: nlp.IntentUpdate, Update: &req, Where: where}, nil
+		}
+		reqs, target, err := buildUpdateRequests(cmd, opts)
 		if err != nil {
 			return Plan{}, err
 		}
-		return Plan{Intent: nlp.IntentUpdate, Update: &req, Target: target}, nil
+		return Plan{Intent: nlp.IntentUpdate, Update: &reqs[0], Updates: reqs, Target: target}, nil
 	case *nlp.FilterCommand:
 		req, err := buildFilterRequest(cmd, opts)
 		if err != nil {
@@ -64,90 +110,268 @@ func Build(result nlp.ParseResult, opts BuildOptions) (Plan, error) {
 	case *nlp.ContextCommand:
 		return Plan{Intent: nlp.IntentContext}, nil
 	case *nlp.LogCommand:
-		return Plan{Intent: nlp.IntentLog, Target: *cmd.Target}, nil
+		target, _, err := resolveTargets(cmd.Target, opts)
+		if err != nil {
+			return Plan{}, err
+		}
+		return Plan{Intent: nlp.IntentLog, Target: target}, nil
+	case *nlp.DoneCommand:
+		return buildTargetPlan(nlp.IntentDone, cmd.Target, opts)
+	case *nlp.UndoCommand:
+		return buildTargetPlan(nlp.IntentUndo, cmd.Target, opts)
+	case *nlp.DeleteCommand:
+		return buildTargetPlan(nlp.IntentDelete, cmd.Target, opts)
+	case *nlp.ShowCommand:
+		return buildTargetPlan(nlp.IntentShow, cmd.Target, opts)
+	case *nlp.SnoozeCommand:
+		until, err := ResolveUntil(cmd.Until, opts.Now)
+		if err != nil {
+			return Plan{}, err
+		}
+		plan, err := buildTargetPlan(nlp.IntentSnooze, cmd.Target, opts)
+		plan.SnoozeUntil = until
+		return plan, err
+	case *nlp.NoteCommand:
+		plan, err := buildTargetPlan(nlp.IntentNote, cmd.Target, opts)
+		plan.NoteText = strings.TrimSpace(cmd.Text)
+		return plan, err
+	case *nlp.TemplateCommand:
+		return Plan{Intent: nlp.IntentTemplate, TemplateName: cmd.Name, TemplateVars: cmd.Vars}, nil
 	default:
 		return Plan{}, fmt.Errorf("unsupported parse command type %T", result.Command)
 	}
 }
 
-func buildCreateRequest(cmd *nlp.CreateCommand, opts BuildOptions) (service.CreateTaskRequest, error) {
+func buildCreateRequest(
+	cmd *nlp.CreateCommand, opts BuildOptions,
+) (service.CreateTaskRequest, []nlp.Diagnostic, error) {
 	req := service.CreateTaskRequest{Title: strings.TrimSpace(cmd.Title), State: domain.TaskStateInbox}
 
 	for _, op := range cmd.Ops {
 		switch typed := op.(type) {
 		case nlp.SetOp:
-			if err := applyCreateSet(&req, typed, opts.Now); err != nil {
-				return service.CreateTaskRequest{}, err
+			if err := applyCreateSet(&req, typed, opts); err != nil {
+				return service.CreateTaskRequest{}, nil, err
 			}
 		case nlp.AddOp:
-			if err := applyCreateAdd(&req, typed); err != nil {
-				return service.CreateTaskRequest{}, err
+			if err := applyCreateAdd(&req, typed, opts); err != nil {
+				return service.CreateTaskRequest{}, nil, err
 			}
 		case nlp.RemoveOp:
-			return service.CreateTaskRequest{}, errors.New("remove operations are not supported during create")
+			return service.CreateTaskRequest{}, nil, errors.New("remove operations are not supported during create")
 		case nlp.ClearOp:
 			if err := applyCreateClear(&req, typed); err != nil {
-				return service.CreateTaskRequest{}, err
+				return service.CreateTaskRequest{}, nil, err
 			}
 		case nlp.TagOp:
 			applyTag(&req, typed)
 		default:
-			return service.CreateTaskRequest{}, fmt.Errorf("unsupported create op type %T", op)
+			return service.CreateTaskRequest{}, nil, fmt.Errorf("unsupported create op type %T", op)
+		}
+	}
+
+	var diagnostics []nlp.Diagnostic
+	if opts.TitleDates && req.DueOn == "" && !hasTitleSet(cmd.Ops) {
+		if found, ok := ExtractTitleDate(cmd.Words, opts.Now); ok {
+			req.Title = found.Title
+			req.DueOn = found.DueOn
+			diagnostics = append(diagnostics, TitleDateDiagnostic(found))
 		}
 	}
 
 	req.Title = strings.TrimSpace(req.Title)
 	if req.Title == "" {
-		return service.CreateTaskRequest{}, errors.New("title is required")
+		return service.CreateTaskRequest{}, nil, errors.New("title is required")
 	}
 
-	return req, nil
+	return req, diagnostics, nil
 }
 
-//nolint:gocognit // update operation compilation is intentionally explicit by op type.
-func buildUpdateRequest(cmd *nlp.UpdateCommand, opts BuildOptions) (service.UpdateTaskRequest, nlp.TargetRef, error) {
-	resolvedTarget := nlp.TargetRef{Kind: nlp.TargetSelected}
-	if cmd.Target != nil {
-		resolvedTarget = *cmd.Target
+func hasTitleSet(ops []nlp.Operation) bool {
+	return slices.ContainsFunc(ops, func(op nlp.Operation) bool {
+		set, ok := op.(nlp.SetOp)
+		return ok && set.Field == nlp.FieldTitle
+	})
+}
+
+// BuildUpdateRequest compiles the operations of an update command into a
+// request for id, ignoring its target. An id of zero gives a template for a
+// bulk update, as with "set where".
+func BuildUpdateRequest(result nlp.ParseResult, id int64, opts BuildOptions) (service.UpdateTaskRequest, error) {
+	cmd, ok := result.Command.(*nlp.UpdateCommand)
+	if !ok {
+		return service.UpdateTaskRequest{}, fmt.Errorf("expected update command, got %T", result.Command)
 	}
-	if resolvedTarget.Kind == nlp.TargetSelected {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return buildUpdateOps(cmd, id, opts)
+}
+
+// buildUpdateRequests compiles one request per resolved target ID.
+func buildUpdateRequests(
+	cmd *nlp.UpdateCommand, opts BuildOptions,
+) ([]service.UpdateTaskRequest, nlp.TargetRef, error) {
+	target, ids, err := resolveTargets(cmd.Target, opts)
+	if err != nil {
+		return nil, nlp.TargetRef{}, err
+	}
+
+	reqs := make([]service.UpdateTaskRequest, 0, len(ids))
+	for _, id := range ids {
+		req, reqErr := buildUpdateOps(cmd, id, opts)
+		if reqErr != nil {
+			return nil, nlp.TargetRef{}, reqErr
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, target, nil
+}
+
+func buildTargetPlan(intent nlp.Intent, ref *nlp.TargetRef, opts BuildOptions) (Plan, error) {
+	target, ids, err := resolveTargets(ref, opts)
+	if err != nil {
+		return Plan{}, err
+	}
+	return Plan{Intent: intent, Target: target, TaskIDs: ids, ImplicitTarget: implicitTarget(ref)}, nil
+}
+
+// implicitTarget reports whether ref leaves the task to the session: no
+// target, the selection, or a pronoun such as "it" or "those".
+func implicitTarget(ref *nlp.TargetRef) bool {
+	if ref == nil {
+		return true
+	}
+	switch ref.Kind {
+	case nlp.TargetSelected, nlp.TargetLast, nlp.TargetRecent:
+		return true
+	case nlp.TargetID, nlp.TargetIDs, nlp.TargetResult, nlp.TargetRow:
+		return false
+	default:
+		return false
+	}
+}
+
+func resolveTargets(ref *nlp.TargetRef, opts BuildOptions) (nlp.TargetRef, []int64, error) {
+	target := nlp.TargetRef{Kind: nlp.TargetSelected}
+	if ref != nil {
+		target = *ref
+	}
+
+	switch target.Kind {
+	case nlp.TargetSelected:
 		if opts.SelectedTaskID == nil || *opts.SelectedTaskID <= 0 {
-			return service.UpdateTaskRequest{}, nlp.TargetRef{}, errors.New("selected target requires SelectedTaskID")
+			return nlp.TargetRef{}, nil, errors.New("selected target requires SelectedTaskID")
+		}
+		id := *opts.SelectedTaskID
+		return nlp.TargetRef{Kind: nlp.TargetID, ID: id}, []int64{id}, nil
+	case nlp.TargetID:
+		if target.ID > 0 {
+			return target, []int64{target.ID}, nil
+		}
+	case nlp.TargetIDs:
+		if len(target.IDs) > 0 && !slices.ContainsFunc(target.IDs, func(id int64) bool { return id <= 0 }) {
+			return target, slices.Clone(target.IDs), nil
+		}
+	case nlp.TargetLast:
+		if len(opts.LastTaskIDs) == 0 {
+			return nlp.TargetRef{}, nil, errors.New("no tasks from a previous command to target")
 		}
-		resolvedTarget = nlp.TargetRef{Kind: nlp.TargetID, ID: *opts.SelectedTaskID}
+		ids := slices.Clone(opts.LastTaskIDs)
+		return nlp.TargetRef{Kind: nlp.TargetIDs, IDs: ids}, ids, nil
+	case nlp.TargetRecent, nlp.TargetResult, nlp.TargetRow:
+		return resolveReference(target, opts)
 	}
+	return nlp.TargetRef{}, nil, errors.New("target must resolve to a task id")
+}
 
-	if resolvedTarget.Kind != nlp.TargetID || resolvedTarget.ID <= 0 {
-		return service.UpdateTaskRequest{}, nlp.TargetRef{}, errors.New("update target must resolve to a task id")
+// resolveReference resolves a pronoun or result reference against the
+// session's previous results.
+func resolveReference(ref nlp.TargetRef, opts BuildOptions) (nlp.TargetRef, []int64, error) {
+	last := opts.LastTaskIDs
+	if ref.Kind != nlp.TargetRecent && ref.N < 1 {
+		token := fmt.Sprintf("%%%d", ref.N)
+		if ref.Kind == nlp.TargetResult {
+			token = fmt.Sprintf("^%d", ref.N)
+		}
+		return nlp.TargetRef{}, nil, nlp.TokenError{
+			Err:   fmt.Errorf("invalid reference %s; positions start at 1", token),
+			Token: token,
+		}
+	}
+	switch ref.Kind {
+	case nlp.TargetRecent:
+		// "that" falls back to the only task when there is one, and pronouns
+		// fall back to the selected task when there are no previous results.
+		if len(last) > 0 {
+			id := last[max(len(last)-ref.N, 0)]
+			return nlp.TargetRef{Kind: nlp.TargetID, ID: id}, []int64{id}, nil
+		}
+		if opts.SelectedTaskID != nil && *opts.SelectedTaskID > 0 {
+			id := *opts.SelectedTaskID
+			return nlp.TargetRef{Kind: nlp.TargetID, ID: id}, []int64{id}, nil
+		}
+		return nlp.TargetRef{}, nil, errors.New("no previous or selected task to refer to")
+	case nlp.TargetRow:
+		if ref.N > len(last) {
+			return nlp.TargetRef{}, nil, nlp.TokenError{
+				Err:   fmt.Errorf("%%%d is out of range; the last result has %d task(s)", ref.N, len(last)),
+				Token: fmt.Sprintf("%%%d", ref.N),
+			}
+		}
+		id := last[ref.N-1]
+		return nlp.TargetRef{Kind: nlp.TargetID, ID: id}, []int64{id}, nil
+	default:
+		ids := last
+		if ref.N > 1 {
+			ids = nil
+			if ref.N-2 < len(opts.PreviousResults) {
+				ids = opts.PreviousResults[ref.N-2]
+			}
+		}
+		if len(ids) == 0 {
+			return nlp.TargetRef{}, nil, nlp.TokenError{
+				Err:   fmt.Errorf("^%d has no tasks", ref.N),
+				Token: fmt.Sprintf("^%d", ref.N),
+			}
+		}
+		ids = slices.Clone(ids)
+		if len(ids) == 1 {
+			return nlp.TargetRef{Kind: nlp.TargetID, ID: ids[0]}, ids, nil
+		}
+		return nlp.TargetRef{Kind: nlp.TargetIDs, IDs: ids}, ids, nil
 	}
+}
 
+//nolint:gocognit // update operation compilation is intentionally explicit by op type.
+func buildUpdateOps(cmd *nlp.UpdateCommand, id int64, opts BuildOptions) (service.UpdateTaskRequest, error) {
 	req := service.UpdateTaskRequest{
-		ID:      resolvedTarget.ID,
+		ID:      id,
 		SetMeta: map[string]string{},
 	}
 
 	for _, op := range cmd.Ops {
 		switch typed := op.(type) {
 		case nlp.SetOp:
-			if err := applyUpdateSet(&req, typed, opts.Now); err != nil {
-				return service.UpdateTaskRequest{}, nlp.TargetRef{}, err
+			if err := applyUpdateSet(&req, typed, opts); err != nil {
+				return service.UpdateTaskRequest{}, err
 			}
 		case nlp.AddOp:
-			if err := applyUpdateAdd(&req, typed); err != nil {
-				return service.UpdateTaskRequest{}, nlp.TargetRef{}, err
+			if err := applyUpdateAdd(&req, typed, opts); err != nil {
+				return service.UpdateTaskRequest{}, err
 			}
 		case nlp.RemoveOp:
 			if err := applyUpdateRemove(&req, typed); err != nil {
-				return service.UpdateTaskRequest{}, nlp.TargetRef{}, err
+				return service.UpdateTaskRequest{}, err
 			}
 		case nlp.ClearOp:
 			if err := applyUpdateClear(&req, typed); err != nil {
-				return service.UpdateTaskRequest{}, nlp.TargetRef{}, err
+				return service.UpdateTaskRequest{}, err
 			}
 		case nlp.TagOp:
 			applyUpdateTag(&req, typed)
 		default:
-			return service.UpdateTaskRequest{}, nlp.TargetRef{}, fmt.Errorf("unsupported update op type %T", op)
+			return service.UpdateTaskRequest{}, fmt.Errorf("unsupported update op type %T", op)
 		}
 	}
 
@@ -157,7 +381,7 @@ func buildUpdateRequest(cmd *nlp.UpdateCommand, opts BuildOptions) (service.Upda
 	req.RemoveContexts = unique(req.RemoveContexts)
 	req.RemoveMetaKeys = unique(req.RemoveMetaKeys)
 
-	return req, resolvedTarget, nil
+	return req, nil
 }
 
 func buildFilterRequest(cmd *nlp.FilterCommand, opts BuildOptions) (service.ListTasksRequest, error) {
@@ -165,7 +389,18 @@ func buildFilterRequest(cmd *nlp.FilterCommand, opts BuildOptions) (service.List
 	if err != nil {
 		return service.ListTasksRequest{}, err
 	}
-	return service.ListTasksRequest{Filter: expr}, nil
+	if cmd.Piped {
+		if len(opts.LastTaskIDs) == 0 {
+			return service.ListTasksRequest{}, errors.New("no tasks from a previous command to target")
+		}
+		ids := nlp.Predicate{Kind: nlp.PredID, IDs: slices.Clone(opts.LastTaskIDs)}
+		if expr == nil {
+			expr = ids
+		} else {
+			expr = nlp.FilterBinary{Op: nlp.FilterAnd, Left: ids, Right: expr}
+		}
+	}
+	return service.ListTasksRequest{Filter: expr, Sort: cmd.SortKeys}, nil
 }
 
 func NormalizeFilterExpr(expr nlp.FilterExpr, opts BuildOptions) (nlp.FilterExpr, error) {
@@ -209,9 +444,10 @@ func compilePredicate(pred nlp.Predicate, opts BuildOptions) (nlp.Predicate, err
 
 	if compiled.Text == nlp.FilterWildcard {
 		switch pred.Kind {
-		case nlp.PredDue, nlp.PredProject, nlp.PredContext:
+		case nlp.PredDue, nlp.PredProject, nlp.PredContext, nlp.PredMeta,
+			nlp.PredTitle, nlp.PredNotes, nlp.PredWaiting, nlp.PredFollowUp:
 			return compiled, nil
-		case nlp.PredState, nlp.PredText, nlp.PredID, nlp.PredRecent:
+		case nlp.PredState, nlp.PredText, nlp.PredID, nlp.PredRecent, nlp.PredAvailable:
 			return nlp.Predicate{}, fmt.Errorf("wildcard is not supported for %v", pred.Kind)
 		default:
 			return nlp.Predicate{}, fmt.Errorf("unsupported predicate kind %v", pred.Kind)
@@ -220,7 +456,7 @@ func compilePredicate(pred nlp.Predicate, opts BuildOptions) (nlp.Predicate, err
 
 	switch pred.Kind {
 	case nlp.PredState:
-		state, err := domain.NormalizeState(compiled.Text)
+		state, err := normalizeState(compiled.Text)
 		if err != nil {
 			return nlp.Predicate{}, err
 		}
@@ -229,16 +465,28 @@ func compilePredicate(pred nlp.Predicate, opts BuildOptions) (nlp.Predicate, err
 		if compiled.Text == "" {
 			return nlp.Predicate{}, errors.New("filter value cannot be empty")
 		}
-		dueDate, err := normalizeDate(compiled.Text, opts.Now)
+		dueDate, err := NormalizeDate(compiled.Text, opts.Now)
 		if err != nil {
 			return nlp.Predicate{}, err
 		}
 		compiled.Text = dueDate
+	case nlp.PredFollowUp:
+		if compiled.Text == "" {
+			return nlp.Predicate{}, errors.New("filter value cannot be empty")
+		}
+		followUp, err := normalizeFollowUpDate(compiled.Text, opts.Now)
+		if err != nil {
+			return nlp.Predicate{}, err
+		}
+		compiled.Text = followUp
 	case nlp.PredProject, nlp.PredContext, nlp.PredText:
 		if compiled.Text == "" {
 			return nlp.Predicate{}, errors.New("filter value cannot be empty")
 		}
 	case nlp.PredID:
+		if len(compiled.IDs) > 0 {
+			return compiled, nil
+		}
 		id, err := strconv.ParseInt(compiled.Text, 10, 64)
 		if err != nil || id <= 0 {
 			return nlp.Predicate{}, fmt.Errorf("invalid id filter %q", pred.Text)
@@ -253,6 +501,19 @@ func compilePredicate(pred nlp.Predicate, opts BuildOptions) (nlp.Predicate, err
 			return nlp.Predicate{}, fmt.Errorf("invalid recent limit %q", pred.Text)
 		}
 		compiled.Text = strconv.FormatInt(limit, 10)
+	case nlp.PredAvailable:
+		return compiled, nil
+	case nlp.PredMeta:
+		return compileMetaPredicate(compiled, opts)
+	case nlp.PredTitle, nlp.PredNotes, nlp.PredWaiting:
+		if compiled.Text == "" {
+			return nlp.Predicate{}, errors.New("filter value cannot be empty")
+		}
+		if compiled.Op == nlp.CompareRegex {
+			if _, err := regexp.Compile(compiled.Text); err != nil {
+				return nlp.Predicate{}, fmt.Errorf("invalid regex %q: %w", compiled.Text, err)
+			}
+		}
 	default:
 		return nlp.Predicate{}, fmt.Errorf("unsupported predicate kind %v", pred.Kind)
 	}
@@ -260,7 +521,69 @@ func compilePredicate(pred nlp.Predicate, opts BuildOptions) (nlp.Predicate, err
 	return compiled, nil
 }
 
-func applyCreateSet(req *service.CreateTaskRequest, op nlp.SetOp, now time.Time) error {
+func compileMetaPredicate(pred nlp.Predicate, opts BuildOptions) (nlp.Predicate, error) {
+	pred.Key = strings.TrimSpace(pred.Key)
+	pred.Upper = strings.TrimSpace(pred.Upper)
+	if pred.Key == "" {
+		return nlp.Predicate{}, errors.New("meta filter requires a key")
+	}
+	if pred.Text == "" {
+		return nlp.Predicate{}, errors.New("filter value cannot be empty")
+	}
+
+	field, typed := opts.MetaSchema.Field(pred.Key)
+	if pred.Op != nlp.CompareEq && typed && !field.Ordered() {
+		return nlp.Predicate{}, fmt.Errorf("meta %q (%s) does not support ordered comparisons", pred.Key, field.Type)
+	}
+
+	text, err := normalizeMetaValue(opts.MetaSchema, pred.Key, pred.Text, opts.Now)
+	if err != nil {
+		return nlp.Predicate{}, err
+	}
+	pred.Text = text
+	if pred.Op == nlp.CompareRange {
+		if pred.Upper == "" {
+			return nlp.Predicate{}, errors.New("meta range requires an upper bound")
+		}
+		upper, upperErr := normalizeMetaValue(opts.MetaSchema, pred.Key, pred.Upper, opts.Now)
+		if upperErr != nil {
+			return nlp.Predicate{}, upperErr
+		}
+		pred.Upper = upper
+	}
+	return pred, nil
+}
+
+// normalizeMetaValue validates a meta value against the schema. Date-typed
+// keys also accept the relative forms understood by due:.
+func normalizeMetaValue(schema domain.MetaSchema, key string, value string, now time.Time) (string, error) {
+	if field, ok := schema.Field(key); ok && field.Type == domain.MetaTypeDate {
+		day, err := NormalizeDate(value, now)
+		if err != nil {
+			return "", fmt.Errorf("meta %q: %w", key, err)
+		}
+		value = day
+	}
+	return schema.NormalizeValue(key, value)
+}
+
+func normalizeMetaEntries(entries []string, opts BuildOptions) ([]string, error) {
+	out := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		k, v, err := parseMetaValue(entry)
+		if err != nil {
+			return nil, err
+		}
+		v, err = normalizeMetaValue(opts.MetaSchema, k, v, opts.Now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, k+domain.MetaSeparatorColon+v)
+	}
+	return out, nil
+}
+
+func applyCreateSet(req *service.CreateTaskRequest, op nlp.SetOp, opts BuildOptions) error {
 	value := strings.TrimSpace(string(op.Value))
 	switch op.Field {
 	case nlp.FieldTitle:
@@ -268,15 +591,21 @@ func applyCreateSet(req *service.CreateTaskRequest, op nlp.SetOp, now time.Time)
 	case nlp.FieldNotes:
 		req.Notes = value
 	case nlp.FieldDue:
-		due, err := normalizeDate(value, now)
+		due, err := NormalizeDate(value, opts.Now)
 		if err != nil {
 			return err
 		}
 		req.DueOn = due
 	case nlp.FieldWaiting:
 		req.WaitingFor = value
+	case nlp.FieldFollowUp:
+		until, err := normalizeFollowUpDate(value, opts.Now)
+		if err != nil {
+			return err
+		}
+		req.FollowUpOn = until
 	case nlp.FieldState:
-		state, err := domain.NormalizeState(value)
+		state, err := normalizeState(value)
 		if err != nil {
 			return err
 		}
@@ -286,25 +615,32 @@ func applyCreateSet(req *service.CreateTaskRequest, op nlp.SetOp, now time.Time)
 	case nlp.FieldContexts:
 		req.Contexts = parseList(value)
 	case nlp.FieldMeta:
-		meta := parseList(value)
-		req.Meta = unique(append([]string(nil), meta...))
+		meta, err := normalizeMetaEntries(parseList(value), opts)
+		if err != nil {
+			return err
+		}
+		req.Meta = unique(meta)
 	default:
 		return fmt.Errorf("unsupported create set field %v", op.Field)
 	}
 	return nil
 }
 
-func applyCreateAdd(req *service.CreateTaskRequest, op nlp.AddOp) error {
+func applyCreateAdd(req *service.CreateTaskRequest, op nlp.AddOp, opts BuildOptions) error {
 	value := strings.TrimSpace(string(op.Value))
 	switch op.Field {
-	case nlp.FieldTitle, nlp.FieldNotes, nlp.FieldDue, nlp.FieldWaiting, nlp.FieldState:
+	case nlp.FieldTitle, nlp.FieldNotes, nlp.FieldDue, nlp.FieldWaiting, nlp.FieldFollowUp, nlp.FieldState:
 		return errors.New("+ supports projects/contexts/meta only")
 	case nlp.FieldProjects:
 		req.Projects = unique(append(req.Projects, parseList(value)...))
 	case nlp.FieldContexts:
 		req.Contexts = unique(append(req.Contexts, parseList(value)...))
 	case nlp.FieldMeta:
-		req.Meta = unique(append(req.Meta, parseList(value)...))
+		meta, err := normalizeMetaEntries(parseList(value), opts)
+		if err != nil {
+			return err
+		}
+		req.Meta = unique(append(req.Meta, meta...))
 	default:
 		return fmt.Errorf("unsupported add field %v", op.Field)
 	}
@@ -321,6 +657,8 @@ func applyCreateClear(req *service.CreateTaskRequest, op nlp.ClearOp) error {
 		req.DueOn = ""
 	case nlp.FieldWaiting:
 		req.WaitingFor = ""
+	case nlp.FieldFollowUp:
+		req.FollowUpOn = ""
 	case nlp.FieldProjects:
 		req.Projects = nil
 	case nlp.FieldContexts:
@@ -341,7 +679,7 @@ func applyTag(req *service.CreateTaskRequest, op nlp.TagOp) {
 	req.Contexts = unique(append(req.Contexts, strings.TrimSpace(op.Value)))
 }
 
-func applyUpdateSet(req *service.UpdateTaskRequest, op nlp.SetOp, now time.Time) error {
+func applyUpdateSet(req *service.UpdateTaskRequest, op nlp.SetOp, opts BuildOptions) error {
 	value := strings.TrimSpace(string(op.Value))
 	switch op.Field {
 	case nlp.FieldTitle:
@@ -349,7 +687,7 @@ func applyUpdateSet(req *service.UpdateTaskRequest, op nlp.SetOp, now time.Time)
 	case nlp.FieldNotes:
 		req.Notes = ptr(value)
 	case nlp.FieldDue:
-		due, err := normalizeDate(value, now)
+		due, err := NormalizeDate(value, opts.Now)
 		if err != nil {
 			return err
 		}
@@ -358,18 +696,21 @@ func applyUpdateSet(req *service.UpdateTaskRequest, op nlp.SetOp, now time.Time)
 	case nlp.FieldWaiting:
 		req.WaitingFor = ptr(value)
 		req.ClearWaitingFor = false
-	case nlp.FieldState:
-		state, err := domain.NormalizeState(value)
+	case nlp.FieldFollowUp:
+		until, err := normalizeFollowUpDate(value, opts.Now)
 		if err != nil {
 			return err
 		}
-		req.State = ptr(state)
-	case nlp.FieldMeta:
-		k, v, err := parseMetaValue(value)
+		req.FollowUpOn = ptr(until)
+		req.ClearFollowUpOn = false
+	case nlp.FieldState:
+		state, err := normalizeState(value)
 		if err != nil {
 			return err
 		}
-		req.SetMeta[k] = v
+		req.State = ptr(state)
+	case nlp.FieldMeta:
+		return setUpdateMeta(req, value, opts)
 	case nlp.FieldProjects, nlp.FieldContexts:
 		return fmt.Errorf("set %q is not supported; use + or - operations", op.Field)
 	default:
@@ -378,31 +719,40 @@ func applyUpdateSet(req *service.UpdateTaskRequest, op nlp.SetOp, now time.Time)
 	return nil
 }
 
-func applyUpdateAdd(req *service.UpdateTaskRequest, op nlp.AddOp) error {
+func applyUpdateAdd(req *service.UpdateTaskRequest, op nlp.AddOp, opts BuildOptions) error {
 	value := strings.TrimSpace(string(op.Value))
 	switch op.Field {
-	case nlp.FieldTitle, nlp.FieldNotes, nlp.FieldDue, nlp.FieldWaiting, nlp.FieldState:
+	case nlp.FieldTitle, nlp.FieldNotes, nlp.FieldDue, nlp.FieldWaiting, nlp.FieldFollowUp, nlp.FieldState:
 		return fmt.Errorf("unsupported add field %v", op.Field)
 	case nlp.FieldProjects:
 		req.AddProjects = append(req.AddProjects, parseList(value)...)
 	case nlp.FieldContexts:
 		req.AddContexts = append(req.AddContexts, parseList(value)...)
 	case nlp.FieldMeta:
-		k, v, err := parseMetaValue(value)
-		if err != nil {
-			return err
-		}
-		req.SetMeta[k] = v
+		return setUpdateMeta(req, value, opts)
 	default:
 		return fmt.Errorf("unsupported add field %v", op.Field)
 	}
 	return nil
 }
 
+func setUpdateMeta(req *service.UpdateTaskRequest, value string, opts BuildOptions) error {
+	k, v, err := parseMetaValue(value)
+	if err != nil {
+		return err
+	}
+	v, err = normalizeMetaValue(opts.MetaSchema, k, v, opts.Now)
+	if err != nil {
+		return err
+	}
+	req.SetMeta[k] = v
+	return nil
+}
+
 func applyUpdateRemove(req *service.UpdateTaskRequest, op nlp.RemoveOp) error {
 	value := strings.TrimSpace(string(op.Value))
 	switch op.Field {
-	case nlp.FieldTitle, nlp.FieldNotes, nlp.FieldDue, nlp.FieldWaiting, nlp.FieldState:
+	case nlp.FieldTitle, nlp.FieldNotes, nlp.FieldDue, nlp.FieldWaiting, nlp.FieldFollowUp, nlp.FieldState:
 		return fmt.Errorf("unsupported remove field %v", op.Field)
 	case nlp.FieldProjects:
 		req.RemoveProjects = append(req.RemoveProjects, parseList(value)...)
@@ -434,6 +784,9 @@ func applyUpdateClear(req *service.UpdateTaskRequest, op nlp.ClearOp) error {
 	case nlp.FieldWaiting:
 		req.ClearWaitingFor = true
 		req.WaitingFor = nil
+	case nlp.FieldFollowUp:
+		req.ClearFollowUpOn = true
+		req.FollowUpOn = nil
 	case nlp.FieldNotes:
 		req.Notes = ptr("")
 	case nlp.FieldProjects, nlp.FieldContexts, nlp.FieldMeta:
@@ -452,7 +805,41 @@ func applyUpdateTag(req *service.UpdateTaskRequest, op nlp.TagOp) {
 	req.AddContexts = append(req.AddContexts, strings.TrimSpace(op.Value))
 }
 
-func normalizeDate(value string, now time.Time) (string, error) {
+// NormalizeDate resolves a YYYY-MM-DD date or a phrase such as "friday" or
+// "next-week" relative to now, returning YYYY-MM-DD.
+func NormalizeDate(value string, now time.Time) (string, error) {
+	return normalizeDate(value, now)
+}
+
+// normalizeFollowUpDate is NormalizeDate for follow-up dates, which look
+// ahead: "friday" is the coming Friday rather than the last one.
+func normalizeFollowUpDate(value string, now time.Time) (string, error) {
+	return normalizeDate(value, now, naturaldate.WithDirection(naturaldate.Future))
+}
+
+// ResolveUntil resolves when a snooze ends: YYYY-MM-DD, "YYYY-MM-DD HH:MM"
+// or a phrase such as "friday", "next monday" or "tomorrow 9am", looking
+// ahead from now. A day without a time means its start. The result must be
+// after now.
+func ResolveUntil(value string, now time.Time) (time.Time, error) {
+	lower := strings.ToLower(strings.TrimSpace(value))
+	until, err := time.ParseInLocation(untilLayoutDateTime, lower, now.Location())
+	if err != nil {
+		until, err = time.ParseInLocation(domain.DateLayoutYYYYMMDD, lower, now.Location())
+	}
+	if err != nil {
+		until, err = naturaldate.Parse(lower, now, naturaldate.WithDirection(naturaldate.Future))
+		if err != nil {
+			return time.Time{}, domain.InvalidDateFormatError(value)
+		}
+	}
+	if !until.After(now) {
+		return time.Time{}, fmt.Errorf("snooze date %q is not in the future", value)
+	}
+	return until, nil
+}
+
+func normalizeDate(value string, now time.Time, options ...naturaldate.Option) (string, error) {
 	lower := strings.ToLower(strings.TrimSpace(value))
 	if _, err := time.Parse(domain.DateLayoutYYYYMMDD, lower); err == nil {
 		return lower, nil
@@ -463,7 +850,7 @@ func normalizeDate(value string, now time.Time) (string, error) {
 		return day.AddDate(0, 0, nextWeekDaySpan).Format(domain.DateLayoutYYYYMMDD), nil
 	}
 
-	normalized, err := naturaldate.Parse(lower, day)
+	normalized, err := naturaldate.Parse(lower, day, options...)
 	if err != nil {
 		return "", domain.InvalidDateFormatError(value)
 	}
@@ -483,6 +870,23 @@ func parseList(value string) []string {
 	return unique(out)
 }
 
+// normalizeState is domain.NormalizeState with the closest state as a hint
+// when value is a likely typo.
+func normalizeState(value string) (string, error) {
+	state, err := domain.NormalizeState(value)
+	if err == nil {
+		return state, nil
+	}
+	tokenErr := nlp.TokenError{Err: err, Token: strings.TrimSpace(value)}
+	states := []string{
+		domain.TaskStateInbox, domain.TaskStateNow, domain.TaskStateWaiting, domain.TaskStateLater, domain.TaskStateDone,
+	}
+	if suggestion, ok := nlp.Suggest(value, states); ok {
+		tokenErr.Hint = nlp.DidYouMean(suggestion)
+	}
+	return "", tokenErr
+}
+
 func parseMetaValue(value string) (string, string, error) {
 	k, v, ok := strings.Cut(value, domain.MetaSeparatorColon)
 	if !ok {
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/nlp"
 	"github.com/mholtzscher/ugh/internal/nlp/compile"
 )
@@ -42,6 +43,47 @@ func TestBuildCreatePlanNormalizesNaturalLanguageDueDate(t *testing.T) {
 	require.Equal(t, "2026-02-16", plan.Create.DueOn, "due mismatch")
 }
 
+func TestBuildCreatePlanTitleDates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	parsed, err := nlp.Parse(`add buy milk tomorrow #groceries`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(create) error")
+
+	plan, err := compile.Build(parsed, compile.BuildOptions{Now: now})
+	require.NoError(t, err, "Build(create) error")
+	assert.Equal(t, "buy milk tomorrow", plan.Create.Title, "title should be kept when title dates are off")
+	assert.Empty(t, plan.Create.DueOn, "due should be unset when title dates are off")
+
+	plan, err = compile.Build(parsed, compile.BuildOptions{Now: now, TitleDates: true})
+	require.NoError(t, err, "Build(create) error")
+	assert.Equal(t, "buy milk", plan.Create.Title, "title mismatch")
+	assert.Equal(t, "2026-10-20", plan.Create.DueOn, "due mismatch")
+	require.Len(t, plan.Diagnostics, 1, "diagnostics count mismatch")
+	assert.Equal(t, nlp.SeverityInfo, plan.Diagnostics[0].Severity, "diagnostic severity mismatch")
+	assert.Equal(t, `due 2026-10-20 from "tomorrow"`, plan.Diagnostics[0].Message, "diagnostic message mismatch")
+}
+
+func TestBuildCreatePlanTitleDatesKeepsExplicitDueAndQuotes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	opts := compile.BuildOptions{Now: now, TitleDates: true}
+	for _, input := range []string{
+		`add ship it tomorrow due:2026-12-01`,
+		`add "ship it tomorrow"`,
+		`add ship it title:"ship it tomorrow"`,
+	} {
+		parsed, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.NoError(t, err, "Parse(%q) error", input)
+
+		plan, err := compile.Build(parsed, opts)
+		require.NoError(t, err, "Build(%q) error", input)
+		assert.Contains(t, plan.Create.Title, "tomorrow", "title should be kept for %q", input)
+		assert.Empty(t, plan.Diagnostics, "no date should be extracted for %q", input)
+	}
+}
+
 func TestBuildUpdatePlanResolvesSelectedTarget(t *testing.T) {
 	t.Parallel()
 
@@ -59,6 +101,158 @@ func TestBuildUpdatePlanResolvesSelectedTarget(t *testing.T) {
 	require.Equal(t, []string{"work"}, plan.Update.AddProjects, "add projects mismatch")
 }
 
+func TestBuildUpdatePlanBuildsOneRequestPerTarget(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse(`set 3,5-6 @phone`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(update) error")
+
+	plan, err := compile.Build(parsed, compile.BuildOptions{})
+	require.NoError(t, err, "Build(update) error")
+	require.Len(t, plan.Updates, 3, "updates count mismatch")
+	for i, id := range []int64{3, 5, 6} {
+		assert.Equal(t, id, plan.Updates[i].ID, "update %d id mismatch", i)
+		assert.Equal(t, []string{"phone"}, plan.Updates[i].AddContexts, "update %d contexts mismatch", i)
+	}
+	assert.Equal(t, &plan.Updates[0], plan.Update, "update should be the first request")
+}
+
+func TestBuildUpdatePlanResolvesThoseToLastTaskIDs(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse(`set those state:later`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(update) error")
+
+	plan, err := compile.Build(parsed, compile.BuildOptions{LastTaskIDs: []int64{8, 2}})
+	require.NoError(t, err, "Build(update) error")
+	require.Len(t, plan.Updates, 2, "updates count mismatch")
+	assert.Equal(t, int64(8), plan.Updates[0].ID, "first update id mismatch")
+	assert.Equal(t, int64(2), plan.Updates[1].ID, "second update id mismatch")
+
+	_, err = compile.Build(parsed, compile.BuildOptions{})
+	require.Error(t, err, "those without previous tasks should fail")
+}
+
+func TestBuildUpdatePlanWithWhereSkipsTarget(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse(`set where due:today state:now`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(update where) error")
+
+	now := time.Date(2026, time.February, 14, 9, 0, 0, 0, time.UTC)
+	plan, err := compile.Build(parsed, compile.BuildOptions{Now: now})
+	require.NoError(t, err, "Build(update where) error")
+	require.NotNil(t, plan.Update, "update request is nil")
+	require.Equal(t, int64(0), plan.Update.ID, "update id should be unset")
+	require.NotNil(t, plan.Update.State, "state is nil")
+	require.Equal(t, "now", *plan.Update.State, "state mismatch")
+	require.Equal(t, nlp.Predicate{Kind: nlp.PredDue, Text: "2026-02-14"}, plan.Where, "where mismatch")
+}
+
+func TestBuildTaskActionPlansResolveTargets(t *testing.T) {
+	t.Parallel()
+
+	selected := int64(7)
+	opts := compile.BuildOptions{SelectedTaskID: &selected, LastTaskIDs: []int64{4, 9}}
+	tests := []struct {
+		input      string
+		wantIntent nlp.Intent
+		wantIDs    []int64
+	}{
+		{input: "done", wantIntent: nlp.IntentDone, wantIDs: []int64{7}},
+		{input: "finish 3,5", wantIntent: nlp.IntentDone, wantIDs: []int64{3, 5}},
+		{input: "reopen those", wantIntent: nlp.IntentUndo, wantIDs: []int64{4, 9}},
+		{input: "delete 2-3", wantIntent: nlp.IntentDelete, wantIDs: []int64{2, 3}},
+		{input: "show selected", wantIntent: nlp.IntentShow, wantIDs: []int64{7}},
+	}
+
+	for _, tt := range tests {
+		parsed, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+		require.NoError(t, err, "Parse(%q) error", tt.input)
+
+		plan, err := compile.Build(parsed, opts)
+		require.NoError(t, err, "Build(%q) error", tt.input)
+		assert.Equal(t, tt.wantIntent, plan.Intent, "intent mismatch for %q", tt.input)
+		assert.Equal(t, tt.wantIDs, plan.TaskIDs, "task ids mismatch for %q", tt.input)
+	}
+}
+
+func TestBuildResolvesReferences(t *testing.T) {
+	t.Parallel()
+
+	selected := int64(7)
+	opts := compile.BuildOptions{
+		SelectedTaskID:  &selected,
+		LastTaskIDs:     []int64{4, 9, 12},
+		PreviousResults: [][]int64{{2}, {5, 6}},
+	}
+	tests := []struct {
+		input   string
+		wantIDs []int64
+	}{
+		{input: "done it", wantIDs: []int64{12}},
+		{input: "done this", wantIDs: []int64{12}},
+		{input: "done $last", wantIDs: []int64{12}},
+		{input: "done that", wantIDs: []int64{9}},
+		{input: "done selected", wantIDs: []int64{7}},
+		{input: "done %1", wantIDs: []int64{4}},
+		{input: "show %3", wantIDs: []int64{12}},
+		{input: "rm ^1", wantIDs: []int64{4, 9, 12}},
+		{input: "rm ^2", wantIDs: []int64{2}},
+		{input: "undo ^3", wantIDs: []int64{5, 6}},
+		{input: "log %2", wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		parsed, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+		require.NoError(t, err, "Parse(%q) error", tt.input)
+
+		plan, err := compile.Build(parsed, opts)
+		require.NoError(t, err, "Build(%q) error", tt.input)
+		assert.Equal(t, tt.wantIDs, plan.TaskIDs, "task ids mismatch for %q", tt.input)
+	}
+
+	parsed, err := nlp.Parse("log %2", nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(log) error")
+	plan, err := compile.Build(parsed, opts)
+	require.NoError(t, err, "Build(log) error")
+	assert.Equal(t, int64(9), plan.Target.ID, "log target mismatch")
+}
+
+func TestBuildReferenceErrors(t *testing.T) {
+	t.Parallel()
+
+	opts := compile.BuildOptions{LastTaskIDs: []int64{4}}
+	tests := []struct {
+		input string
+		opts  compile.BuildOptions
+		want  string
+	}{
+		{input: "done %2", opts: opts, want: "%2 is out of range; the last result has 1 task(s)"},
+		{input: "done ^2", opts: opts, want: "^2 has no tasks"},
+		{input: "done it", want: "no previous or selected task to refer to"},
+		{input: "done %0", opts: opts, want: "invalid reference %0; positions start at 1"},
+	}
+
+	for _, tt := range tests {
+		parsed, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+		require.NoError(t, err, "Parse(%q) error", tt.input)
+
+		_, err = compile.Build(parsed, tt.opts)
+		require.EqualError(t, err, tt.want, "Build(%q) error mismatch", tt.input)
+	}
+}
+
+func TestBuildTaskActionPlanRequiresSelectedTask(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse("done", nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(done) error")
+
+	_, err = compile.Build(parsed, compile.BuildOptions{})
+	require.Error(t, err, "done without a selected task should fail")
+}
+
 func TestBuildFilterPlanBuildsBooleanExpression(t *testing.T) {
 	t.Parallel()
 
@@ -157,6 +351,16 @@ func TestNormalizeFilterExpr_AllowsWildcardSetPredicate(t *testing.T) {
 	require.Equal(t, nlp.FilterWildcard, pred.Text, "predicate text mismatch")
 }
 
+func TestNormalizeFilterExpr_RejectsInvalidRegex(t *testing.T) {
+	t.Parallel()
+
+	_, err := compile.NormalizeFilterExpr(
+		nlp.Predicate{Kind: nlp.PredTitle, Op: nlp.CompareRegex, Text: "(unclosed"},
+		compile.BuildOptions{},
+	)
+	require.Error(t, err, "NormalizeFilterExpr() should reject invalid regex")
+}
+
 func TestNormalizeFilterExpr_AllowsWildcardProjectPredicate(t *testing.T) {
 	t.Parallel()
 
@@ -392,6 +596,92 @@ func TestBuildUpdatePlanClearsDueAndWaiting(t *testing.T) {
 	require.Nil(t, plan.Update.WaitingFor, "WaitingFor should be nil when clearing")
 }
 
+func TestBuildFollowUpDates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 8, 10, 0, 0, 0, time.UTC)
+	build := func(input string) compile.Plan {
+		t.Helper()
+		parsed, err := nlp.Parse(input, nlp.ParseOptions{Now: now})
+		require.NoError(t, err, "Parse(%q) error", input)
+		plan, err := compile.Build(parsed, compile.BuildOptions{Now: now})
+		require.NoError(t, err, "Build(%q) error", input)
+		return plan
+	}
+
+	create := build(`add get quote state:waiting waiting:alice until:friday`)
+	require.NotNil(t, create.Create, "create request is nil")
+	assert.Equal(t, "alice", create.Create.WaitingFor, "waiting_for mismatch")
+	assert.Equal(t, "2026-02-13", create.Create.FollowUpOn, "follow-up date mismatch")
+
+	set := build(`set 42 until:tomorrow`)
+	require.NotNil(t, set.Update, "update request is nil")
+	require.NotNil(t, set.Update.FollowUpOn, "follow-up date should be set")
+	assert.Equal(t, "2026-02-09", *set.Update.FollowUpOn, "follow-up date mismatch")
+
+	cleared := build(`set 42 !until`)
+	require.NotNil(t, cleared.Update, "update request is nil")
+	assert.True(t, cleared.Update.ClearFollowUpOn, "ClearFollowUpOn should be true")
+	assert.Nil(t, cleared.Update.FollowUpOn, "FollowUpOn should be nil when clearing")
+
+	filter := build(`find state:waiting && until:<=today`)
+	binary, ok := filter.Filter.Filter.(nlp.FilterBinary)
+	require.True(t, ok, "filter type should be FilterBinary, got %T", filter.Filter.Filter)
+	assert.Equal(t, nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareLte, Text: "2026-02-08"}, binary.Right,
+		"follow-up predicate mismatch")
+}
+
+func TestBuildSnoozePlan(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 8, 10, 0, 0, 0, time.UTC)
+	parsed, err := nlp.Parse("snooze it until friday", nlp.ParseOptions{Now: now})
+	require.NoError(t, err, "Parse error")
+	plan, err := compile.Build(parsed, compile.BuildOptions{Now: now, LastTaskIDs: []int64{7}})
+	require.NoError(t, err, "Build error")
+	assert.Equal(t, nlp.IntentSnooze, plan.Intent, "intent mismatch")
+	assert.Equal(t, []int64{7}, plan.TaskIDs, "task ids mismatch")
+	assert.Equal(t, time.Date(2026, 2, 13, 0, 0, 0, 0, time.UTC), plan.SnoozeUntil, "snooze date mismatch")
+}
+
+func TestBuildTemplatePlan(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse("add from release version:1.4", nlp.ParseOptions{Templates: []string{"release"}})
+	require.NoError(t, err, "Parse error")
+	plan, err := compile.Build(parsed, compile.BuildOptions{})
+	require.NoError(t, err, "Build error")
+	assert.Equal(t, nlp.IntentTemplate, plan.Intent, "intent mismatch")
+	assert.Equal(t, "release", plan.TemplateName, "template name mismatch")
+	assert.Equal(t, map[string]string{"version": "1.4"}, plan.TemplateVars, "template vars mismatch")
+}
+
+func TestResolveUntil(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 8, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-02-08 14:30", want: time.Date(2026, 2, 8, 14, 30, 0, 0, time.UTC)},
+		{value: "next monday", want: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)},
+		{value: "tomorrow 9am", want: time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC)},
+		{value: "in 2 hours", want: time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := compile.ResolveUntil(tt.value, now)
+		require.NoError(t, err, "ResolveUntil(%q) error", tt.value)
+		assert.Equal(t, tt.want, got, "ResolveUntil(%q) mismatch", tt.value)
+	}
+
+	for _, value := range []string{"2026-01-01", "yesterday", "whenever"} {
+		_, err := compile.ResolveUntil(value, now)
+		assert.Error(t, err, "ResolveUntil(%q) should fail", value)
+	}
+}
+
 func TestBuildUpdatePlanClearsNotes(t *testing.T) {
 	t.Parallel()
 
@@ -482,6 +772,65 @@ func TestBuildCreatePlanSetsMetaValues(t *testing.T) {
 	require.Len(t, plan.Create.Meta, 1, "meta count mismatch")
 }
 
+func testMetaSchema() domain.MetaSchema {
+	return domain.MetaSchema{
+		"points": {Type: domain.MetaTypeInt},
+		"review": {Type: domain.MetaTypeDate},
+		"size":   {Type: domain.MetaTypeEnum, Values: []string{"s", "m", "l"}},
+		"billed": {Type: domain.MetaTypeBool},
+	}
+}
+
+func TestBuildUpdatePlanNormalizesTypedMeta(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.February, 10, 12, 0, 0, 0, time.UTC)
+	parsed, err := nlp.Parse(`set 42 meta:points:007 +meta:size:M +meta:review:tomorrow`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(update) error")
+
+	plan, err := compile.Build(parsed, compile.BuildOptions{Now: now, MetaSchema: testMetaSchema()})
+	require.NoError(t, err, "Build(update) error")
+	require.NotNil(t, plan.Update, "update request is nil")
+	assert.Equal(t, map[string]string{"points": "7", "size": "m", "review": "2026-02-11"}, plan.Update.SetMeta)
+}
+
+func TestBuildCreatePlanRejectsInvalidTypedMeta(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse(`add task meta:points:lots`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(create) error")
+
+	_, err = compile.Build(parsed, compile.BuildOptions{MetaSchema: testMetaSchema()})
+	require.Error(t, err, "Build(create) should reject non-int points")
+	assert.Contains(t, err.Error(), "points", "error should name the meta key")
+}
+
+func TestBuildFilterPlanNormalizesMetaComparisons(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse(`find meta:points=01..05 and meta:size<L`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(filter) error")
+
+	plan, err := compile.Build(parsed, compile.BuildOptions{MetaSchema: testMetaSchema()})
+	require.NoError(t, err, "Build(filter) error")
+	require.NotNil(t, plan.Filter, "filter request is nil")
+	assert.Equal(t, nlp.FilterBinary{
+		Op:    nlp.FilterAnd,
+		Left:  nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareRange, Text: "1", Upper: "5"},
+		Right: nlp.Predicate{Kind: nlp.PredMeta, Key: "size", Op: nlp.CompareLt, Text: "l"},
+	}, plan.Filter.Filter)
+}
+
+func TestBuildFilterPlanRejectsOrderedBoolMeta(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := nlp.Parse(`find meta:billed>true`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse(filter) error")
+
+	_, err = compile.Build(parsed, compile.BuildOptions{MetaSchema: testMetaSchema()})
+	require.Error(t, err, "Build(filter) should reject ordered bool comparison")
+}
+
 func TestBuildUpdatePlanRequiresSelectedTaskID(t *testing.T) {
 	t.Parallel()
 
@@ -667,3 +1016,23 @@ func TestBuildUpdatePlanRejectsClearState(t *testing.T) {
 	_, err = compile.Build(parsed, compile.BuildOptions{})
 	require.Error(t, err, "Build(update) should return clear state not supported error")
 }
+
+func TestBuildPipedFilterRestrictsToPreviousTasks(t *testing.T) {
+	t.Parallel()
+
+	program, err := nlp.ParseProgram("find #work | find state:now", nlp.ParseOptions{})
+	require.NoError(t, err, "ParseProgram error")
+	piped := program.Statements[0].Stages[1].Result
+
+	plan, err := compile.Build(piped, compile.BuildOptions{LastTaskIDs: []int64{3, 8}})
+	require.NoError(t, err, "Build error")
+	require.NotNil(t, plan.Filter, "filter should be compiled")
+	assert.Equal(t, nlp.FilterBinary{
+		Op:    nlp.FilterAnd,
+		Left:  nlp.Predicate{Kind: nlp.PredID, IDs: []int64{3, 8}},
+		Right: nlp.Predicate{Kind: nlp.PredState, Text: "now"},
+	}, plan.Filter.Filter, "filter mismatch")
+
+	_, err = compile.Build(piped, compile.BuildOptions{})
+	require.Error(t, err, "piped filter without previous tasks should fail")
+}
@@ -0,0 +1,80 @@
+package compile
+
+import "github.com/mholtzscher/ugh/internal/nlp"
+
+// ProgramPlan is a line of ";"-separated statements, each a pipeline of
+// "|"-joined stages.
+//
+// Stages are compiled as they run rather than up front: a piped stage acts on
+// the tasks the stage before it left, which are only known once that stage
+// has run.
+type ProgramPlan struct {
+	Pipelines []PipelinePlan
+}
+
+// PipelinePlan is one statement of a ProgramPlan.
+type PipelinePlan struct {
+	Stages []StagePlan
+}
+
+// StagePlan is one command of a pipeline.
+type StagePlan struct {
+	// Input is the source text of the stage.
+	Input  string
+	Result nlp.ParseResult
+	// Piped reports that the stage follows a "|" and acts on the tasks of the
+	// stage before it.
+	Piped bool
+}
+
+// StageRunner runs stage against input, the task IDs the stage before it
+// left, and returns the task IDs it leaves for the next stage.
+type StageRunner func(stage StagePlan, input []int64) ([]int64, error)
+
+// BuildProgram arranges the stages of program for Run.
+func BuildProgram(program nlp.Program) ProgramPlan {
+	plan := ProgramPlan{Pipelines: make([]PipelinePlan, 0, len(program.Statements))}
+	for _, statement := range program.Statements {
+		pipeline := PipelinePlan{Stages: make([]StagePlan, 0, len(statement.Stages))}
+		for i, stage := range statement.Stages {
+			pipeline.Stages = append(pipeline.Stages, StagePlan{
+				Input:  stage.Input,
+				Result: stage.Result,
+				Piped:  i > 0,
+			})
+		}
+		plan.Pipelines = append(plan.Pipelines, pipeline)
+	}
+	return plan
+}
+
+// Run runs the stages in order, starting each pipeline with input. A piped
+// stage whose input is empty ends its pipeline: skip is called for it and the
+// next statement runs. Run stops at the first error.
+func (p ProgramPlan) Run(input []int64, run StageRunner, skip func(StagePlan)) error {
+	for _, pipeline := range p.Pipelines {
+		last := input
+		for _, stage := range pipeline.Stages {
+			if stage.Piped && len(last) == 0 {
+				skip(stage)
+				break
+			}
+			next, err := run(stage, last)
+			if err != nil {
+				return err
+			}
+			last = next
+		}
+		input = last
+	}
+	return nil
+}
+
+// Build compiles the stage. A piped stage targets input, the task IDs of the
+// stage before it; other stages resolve "those" from opts as usual.
+func (s StagePlan) Build(input []int64, opts BuildOptions) (Plan, error) {
+	if s.Piped {
+		opts.LastTaskIDs = input
+	}
+	return Build(s.Result, opts)
+}
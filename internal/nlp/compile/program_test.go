@@ -0,0 +1,55 @@
+package compile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/nlp/compile"
+)
+
+func TestBuildProgramMarksPipedStages(t *testing.T) {
+	t.Parallel()
+
+	program, err := nlp.ParseProgram("find #work | set state:now; done 3", nlp.ParseOptions{})
+	require.NoError(t, err, "ParseProgram error")
+
+	plan := compile.BuildProgram(program)
+	require.Len(t, plan.Pipelines, 2, "pipeline count mismatch")
+	require.Len(t, plan.Pipelines[0].Stages, 2, "first pipeline stage count mismatch")
+	assert.False(t, plan.Pipelines[0].Stages[0].Piped, "first stage is not piped")
+	assert.True(t, plan.Pipelines[0].Stages[1].Piped, "stage after | is piped")
+	assert.Equal(t, "set state:now", plan.Pipelines[0].Stages[1].Input, "stage input mismatch")
+
+	stagePlan, err := plan.Pipelines[0].Stages[1].Build([]int64{4, 7}, compile.BuildOptions{LastTaskIDs: []int64{1}})
+	require.NoError(t, err, "Build(piped stage) error")
+	require.Len(t, stagePlan.Updates, 2, "piped set should target its input")
+	assert.Equal(t, int64(4), stagePlan.Updates[0].ID, "first update id mismatch")
+	assert.Equal(t, int64(7), stagePlan.Updates[1].ID, "second update id mismatch")
+}
+
+func TestProgramPlanRunFeedsStagesAndStopsWithoutInput(t *testing.T) {
+	t.Parallel()
+
+	program, err := nlp.ParseProgram("find #a | done; find #b | done; done 9", nlp.ParseOptions{})
+	require.NoError(t, err, "ParseProgram error")
+
+	outputs := map[string][]int64{"find #a": {2, 5}, "done": {2, 5}, "done 9": {9}}
+	var ran []string
+	var inputs [][]int64
+	var skipped []string
+	err = compile.BuildProgram(program).Run(nil, func(stage compile.StagePlan, input []int64) ([]int64, error) {
+		ran = append(ran, stage.Input)
+		inputs = append(inputs, input)
+		return outputs[stage.Input], nil
+	}, func(stage compile.StagePlan) {
+		skipped = append(skipped, stage.Input)
+	})
+	require.NoError(t, err, "Run error")
+
+	assert.Equal(t, []string{"find #a", "done", "find #b", "done 9"}, ran, "stages run mismatch")
+	assert.Equal(t, []int64{2, 5}, inputs[1], "piped stage input mismatch")
+	assert.Equal(t, []string{"done"}, skipped, "stage without input should be skipped")
+}
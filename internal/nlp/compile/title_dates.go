@@ -0,0 +1,190 @@
+package compile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tj/go-naturaldate"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/nlp"
+)
+
+const (
+	maxDatePhraseWords = 4
+	maxDayOfMonth      = 31
+)
+
+// TitleDate is a due date found in the words of a create title.
+type TitleDate struct {
+	// Title is the title with the date phrase removed.
+	Title string
+	// DueOn is the date in YYYY-MM-DD form.
+	DueOn string
+	// Phrase is the text that was removed from the title.
+	Phrase string
+}
+
+// ExtractTitleDate finds the last date phrase among the unquoted title words,
+// such as "tomorrow", "next friday", "on march 3" or "by eod". It reports false
+// when there is none or when removing it would leave the title empty.
+func ExtractTitleDate(words []nlp.TitleWord, now time.Time) (TitleDate, bool) {
+	var (
+		found       bool
+		start, size int
+		due         time.Time
+	)
+	for i := 0; i < len(words); i++ {
+		n, day, ok := matchDatePhraseAt(words, i, now)
+		if !ok {
+			continue
+		}
+		found, start, size, due = true, i, n, day
+		i += n - 1
+	}
+	if !found || size == len(words) {
+		return TitleDate{}, false
+	}
+
+	phrase := nlp.JoinTitleWords(words[start : start+size])
+	rest := make([]nlp.TitleWord, 0, len(words)-size)
+	rest = append(rest, words[:start]...)
+	rest = append(rest, words[start+size:]...)
+	return TitleDate{
+		Title:  nlp.JoinTitleWords(rest),
+		DueOn:  due.Format(domain.DateLayoutYYYYMMDD),
+		Phrase: phrase,
+	}, true
+}
+
+// TitleDateDiagnostic describes an extracted title date for the user.
+func TitleDateDiagnostic(found TitleDate) nlp.Diagnostic {
+	return nlp.Diagnostic{
+		Severity: nlp.SeverityInfo,
+		Code:     "I_TITLE_DATE",
+		Message:  fmt.Sprintf("due %s from %q", found.DueOn, found.Phrase),
+		Hint:     "quote the phrase to keep it in the title",
+	}
+}
+
+// matchDatePhraseAt returns the length and date of the longest date phrase
+// starting at words[i].
+func matchDatePhraseAt(words []nlp.TitleWord, i int, now time.Time) (int, time.Time, bool) {
+	tokens := make([]string, 0, maxDatePhraseWords)
+	for j := i; j < len(words) && len(tokens) < maxDatePhraseWords; j++ {
+		if words[j].Quoted {
+			break
+		}
+		tokens = append(tokens, strings.ToLower(words[j].Text))
+	}
+	for n := len(tokens); n > 0; n-- {
+		if day, ok := parseDatePhrase(tokens[:n], now); ok {
+			return n, day, true
+		}
+	}
+	return 0, time.Time{}, false
+}
+
+// parseDatePhrase recognizes the phrase shapes extracted from titles, with an
+// optional leading "on", "by" or "due". Shapes naturaldate resolves well are
+// handed to it; month-day dates are resolved here.
+func parseDatePhrase(tokens []string, now time.Time) (time.Time, bool) {
+	if len(tokens) > 1 && (tokens[0] == "on" || tokens[0] == "by" || tokens[0] == "due") {
+		tokens = tokens[1:]
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch len(tokens) {
+	case 1:
+		switch {
+		case tokens[0] == "today" || tokens[0] == "tonight" || tokens[0] == "eod":
+			return today, true
+		case tokens[0] == "tomorrow" || isWeekday(tokens[0]):
+			return naturalDate(tokens, now)
+		}
+		if day, err := time.ParseInLocation(domain.DateLayoutYYYYMMDD, tokens[0], now.Location()); err == nil {
+			return day, true
+		}
+	case 2:
+		if tokens[0] == "next" && (tokens[1] == "week" || tokens[1] == "month" || isWeekday(tokens[1])) {
+			return naturalDate(tokens, now)
+		}
+		if tokens[0] == "this" && isWeekday(tokens[1]) {
+			return naturalDate(tokens, now)
+		}
+		return monthDay(tokens[0], tokens[1], today)
+	case 3:
+		if tokens[0] == "in" && isCount(tokens[1]) && isDateUnit(tokens[2]) {
+			return naturalDate(tokens, now)
+		}
+	}
+	return time.Time{}, false
+}
+
+func naturalDate(tokens []string, now time.Time) (time.Time, bool) {
+	day, err := naturaldate.Parse(strings.Join(tokens, " "), now, naturaldate.WithDirection(naturaldate.Future))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// monthDay resolves "march 3" or "mar 3rd" to the next such date on or after
+// today.
+func monthDay(monthText string, dayText string, today time.Time) (time.Time, bool) {
+	month, ok := parseMonth(monthText)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		dayText = strings.TrimSuffix(dayText, suffix)
+	}
+	dayNum, err := strconv.Atoi(dayText)
+	if err != nil || dayNum < 1 || dayNum > maxDayOfMonth {
+		return time.Time{}, false
+	}
+
+	day := time.Date(today.Year(), month, dayNum, 0, 0, 0, 0, today.Location())
+	if day.Month() != month {
+		return time.Time{}, false
+	}
+	if day.Before(today) {
+		day = day.AddDate(1, 0, 0)
+	}
+	return day, true
+}
+
+func parseMonth(text string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		if text == name || (len(text) >= 3 && strings.HasPrefix(name, text)) {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+func isWeekday(text string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if text == strings.ToLower(day.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCount(text string) bool {
+	n, err := strconv.Atoi(text)
+	return err == nil && n > 0
+}
+
+func isDateUnit(text string) bool {
+	switch strings.TrimSuffix(text, "s") {
+	case "day", "week", "month":
+		return true
+	default:
+		return false
+	}
+}
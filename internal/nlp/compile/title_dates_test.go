@@ -0,0 +1,133 @@
+package compile_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/nlp/compile"
+)
+
+func TestExtractTitleDate(t *testing.T) {
+	t.Parallel()
+
+	// Monday.
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		title      string
+		wantTitle  string
+		wantDue    string
+		wantPhrase string
+	}{
+		{
+			name:       "trailing tomorrow",
+			title:      "buy milk tomorrow",
+			wantTitle:  "buy milk",
+			wantDue:    "2026-10-20",
+			wantPhrase: "tomorrow",
+		},
+		{
+			name:       "next weekday",
+			title:      "call mom next friday",
+			wantTitle:  "call mom",
+			wantDue:    "2026-10-23",
+			wantPhrase: "next friday",
+		},
+		{
+			name:       "bare weekday is future",
+			title:      "pay bills monday",
+			wantTitle:  "pay bills",
+			wantDue:    "2026-10-26",
+			wantPhrase: "monday",
+		},
+		{
+			name:       "on month day",
+			title:      "dentist on march 3 at noon",
+			wantTitle:  "dentist at noon",
+			wantDue:    "2027-03-03",
+			wantPhrase: "on march 3",
+		},
+		{
+			name:       "ordinal month day",
+			title:      "renew lease nov 1st",
+			wantTitle:  "renew lease",
+			wantDue:    "2026-11-01",
+			wantPhrase: "nov 1st",
+		},
+		{
+			name:       "by eod",
+			title:      "send report by eod",
+			wantTitle:  "send report",
+			wantDue:    "2026-10-19",
+			wantPhrase: "by eod",
+		},
+		{
+			name:       "in days",
+			title:      "follow up in 3 days",
+			wantTitle:  "follow up",
+			wantDue:    "2026-10-22",
+			wantPhrase: "in 3 days",
+		},
+		{
+			name:       "next week",
+			title:      "plan offsite next week",
+			wantTitle:  "plan offsite",
+			wantDue:    "2026-10-26",
+			wantPhrase: "next week",
+		},
+		{
+			name:       "iso date",
+			title:      "file taxes 2027-04-15",
+			wantTitle:  "file taxes",
+			wantDue:    "2027-04-15",
+			wantPhrase: "2027-04-15",
+		},
+		{
+			name:       "last phrase wins",
+			title:      "move today meeting to friday",
+			wantTitle:  "move today meeting to",
+			wantDue:    "2026-10-23",
+			wantPhrase: "friday",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			found, ok := compile.ExtractTitleDate(titleWords(tt.title), now)
+			require.True(t, ok, "expected a date in %q", tt.title)
+			assert.Equal(t, tt.wantTitle, found.Title, "title mismatch")
+			assert.Equal(t, tt.wantDue, found.DueOn, "due mismatch")
+			assert.Equal(t, tt.wantPhrase, found.Phrase, "phrase mismatch")
+		})
+	}
+}
+
+func TestExtractTitleDateIgnoresNonDates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	for _, title := range []string{"buy milk", "tomorrow", "next steps", "call may", "march 32 budget", "in 3 boxes"} {
+		_, ok := compile.ExtractTitleDate(titleWords(title), now)
+		assert.False(t, ok, "unexpected date in %q", title)
+	}
+
+	quoted := []nlp.TitleWord{{Text: "watch"}, {Text: "tomorrow never dies", Quoted: true}}
+	_, ok := compile.ExtractTitleDate(quoted, now)
+	assert.False(t, ok, "quoted words should not be scanned")
+}
+
+func titleWords(title string) []nlp.TitleWord {
+	fields := strings.Fields(title)
+	words := make([]nlp.TitleWord, 0, len(fields))
+	for _, field := range fields {
+		words = append(words, nlp.TitleWord{Text: field})
+	}
+	return words
+}
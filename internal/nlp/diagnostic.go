@@ -0,0 +1,341 @@
+package nlp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2"
+)
+
+const (
+	// maxShortTypoDistance is the edit distance allowed for words of up to
+	// shortTypoLength runes; longer words allow maxTypoDistance.
+	maxShortTypoDistance = 1
+	maxTypoDistance      = 2
+	shortTypoLength      = 4
+)
+
+//nolint:gochecknoglobals // constant lookup table for field suggestions
+var fieldNames = []string{
+	"title", "notes", "due", "waiting", "until", "state",
+	"project", "projects", "context", "contexts", "meta", "id", "text",
+}
+
+// Span is the half-open range of a diagnostic in the parsed input, as byte
+// offsets and as rune offsets. An empty span marks a position, such as the end
+// of input.
+type Span struct {
+	Start     int
+	End       int
+	RuneStart int
+	RuneEnd   int
+}
+
+// NewSpan returns the span of input[start:end], clamped to the input.
+func NewSpan(input string, start int, end int) Span {
+	start = min(max(start, 0), len(input))
+	end = min(max(end, start), len(input))
+	return Span{
+		Start:     start,
+		End:       end,
+		RuneStart: utf8.RuneCountInString(input[:start]),
+		RuneEnd:   utf8.RuneCountInString(input[:end]),
+	}
+}
+
+// TokenError is an error about one token of the input, such as an unknown
+// state. LocateError turns it into a Diagnostic spanning the token.
+type TokenError struct {
+	Err   error
+	Token string
+	Hint  string
+}
+
+func (e TokenError) Error() string {
+	return e.Err.Error()
+}
+
+func (e TokenError) Unwrap() error {
+	return e.Err
+}
+
+// LocateError returns err as a DiagnosticError positioned in input when it
+// wraps a TokenError. Other errors are returned unchanged.
+func LocateError(input string, err error) error {
+	var tokenErr TokenError
+	if !errors.As(err, &tokenErr) {
+		return err
+	}
+	diag := locateToken(input, tokenErr, Diagnostic{
+		Severity: SeverityError,
+		Code:     "E_VALUE",
+		Message:  err.Error(),
+	})
+	return NewDiagnosticError(err, []Diagnostic{diag})
+}
+
+// locateToken adds the input, the span of the token and the hint of tokenErr
+// to diag.
+func locateToken(input string, tokenErr TokenError, diag Diagnostic) Diagnostic {
+	diag.Input = input
+	if tokenErr.Hint != "" {
+		diag.Hint = tokenErr.Hint
+	}
+	if start, ok := findToken(input, tokenErr.Token); ok {
+		span := NewSpan(input, start, start+len(tokenErr.Token))
+		diag.Span = &span
+	}
+	return diag
+}
+
+// TrimPrefix rebases d onto its input without prefix, such as the "find " the
+// CLI puts before --where text. A span inside the prefix is dropped.
+func (d Diagnostic) TrimPrefix(prefix string) Diagnostic {
+	input, ok := strings.CutPrefix(d.Input, prefix)
+	if !ok {
+		return d
+	}
+	d.Input = input
+	if d.Span != nil {
+		if d.Span.Start < len(prefix) {
+			d.Span = nil
+		} else {
+			span := NewSpan(input, d.Span.Start-len(prefix), d.Span.End-len(prefix))
+			d.Span = &span
+		}
+	}
+	return d
+}
+
+// TrimPrefix returns e with each diagnostic rebased by Diagnostic.TrimPrefix.
+func (e DiagnosticError) TrimPrefix(prefix string) DiagnosticError {
+	diagnostics := e.Diagnostics()
+	for i := range diagnostics {
+		diagnostics[i] = diagnostics[i].TrimPrefix(prefix)
+	}
+	return DiagnosticError{err: e.err, diagnostics: diagnostics}
+}
+
+// Suggest returns the candidate closest to word when it is near enough to be
+// a likely typo.
+func Suggest(word string, candidates []string) (string, bool) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return "", false
+	}
+	limit := maxTypoDistance
+	if utf8.RuneCountInString(word) <= shortTypoLength {
+		limit = maxShortTypoDistance
+	}
+
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		if d := editDistance(word, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
+
+// DidYouMean formats a suggestion as a diagnostic hint.
+func DidYouMean(suggestion string) string {
+	return "did you mean " + suggestion + "?"
+}
+
+// editDistance is the optimal string alignment distance between a and b, so a
+// swap of neighbouring letters counts as one edit.
+func editDistance(a string, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev2 := make([]int, len(br)+1)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(br)]
+}
+
+// findToken returns the byte offset of the first whole-word, case-insensitive
+// occurrence of token in input.
+func findToken(input string, token string) (int, bool) {
+	if token == "" {
+		return 0, false
+	}
+	lower, needle := strings.ToLower(input), strings.ToLower(token)
+	if len(lower) != len(input) {
+		return 0, false
+	}
+	for offset := 0; offset <= len(lower)-len(needle); {
+		idx := strings.Index(lower[offset:], needle)
+		if idx < 0 {
+			return 0, false
+		}
+		start := offset + idx
+		end := start + len(needle)
+		if atWordBoundary(input, start) && atWordBoundary(input, end) {
+			return start, true
+		}
+		offset = start + 1
+	}
+	return 0, false
+}
+
+// atWordBoundary reports whether offset does not split a word of input.
+func atWordBoundary(input string, offset int) bool {
+	before, _ := utf8.DecodeLastRuneInString(input[:offset])
+	after, _ := utf8.DecodeRuneInString(input[offset:])
+	return offset == 0 || offset == len(input) || !isWordRune(before) || !isWordRune(after)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// missingStageSuffix ends participle's message for a "|" with no command
+// after it; participle names the expected node after the stageNode type.
+const missingStageSuffix = "(expected StageNode)"
+
+// parseErrorDiagnostic positions a participle error in input. An unknown
+// leading word is reported as an unknown command and a word followed by an
+// unexpected colon as an unknown field, with suggestions for likely typos.
+func parseErrorDiagnostic(input string, err error) Diagnostic {
+	diag := Diagnostic{
+		Severity: SeverityError,
+		Code:     "E_PARSE",
+		Message:  err.Error(),
+		Hint:     "check command syntax and quoting",
+		Input:    input,
+	}
+	var parseErr participle.Error
+	if !errors.As(err, &parseErr) {
+		return diag
+	}
+	diag.Message = parseErr.Message()
+	start := parseErr.Position().Offset
+	end := start
+	if strings.HasSuffix(diag.Message, missingStageSuffix) {
+		diag.Message = `expected a command after "|"`
+		diag.Hint = `finish the pipeline or remove the "|"`
+	}
+
+	var unexpected *participle.UnexpectedTokenError
+	if errors.As(err, &unexpected) && !unexpected.Unexpected.EOF() {
+		tok := unexpected.Unexpected
+		end = tokenEnd(input, start, tok.Value)
+		switch {
+		case tok.Type == dslSymbols["Ident"] && strings.TrimSpace(input[:start]) == "":
+			diag.Message = fmt.Sprintf("unknown command %q", tok.Value)
+			if verb, ok := Suggest(tok.Value, CommandVerbs()); ok {
+				diag.Hint = DidYouMean(verb)
+			}
+		case tok.Type == dslSymbols["Colon"]:
+			if fieldStart := wordStartBefore(input, start); fieldStart < start {
+				field := input[fieldStart:start]
+				diag.Message = fmt.Sprintf("unknown field %q", field+":")
+				diag.Hint = "quote text that contains a colon"
+				if name, ok := Suggest(field, fieldNames); ok {
+					diag.Hint = DidYouMean(name + ":")
+				}
+				start = fieldStart
+			}
+		}
+	}
+	span := NewSpan(input, start, end)
+	diag.Span = &span
+	return diag
+}
+
+// unknownFilterField reports an unquoted filter word in input[start:end],
+// such as "stat:now", whose field looks like a misspelt field name. Words that
+// are not near any field stay text searches.
+func unknownFilterField(input string, start int, end int) (Diagnostic, bool) {
+	tokens, err := Lex(input)
+	if err != nil {
+		return Diagnostic{}, false
+	}
+	for i := 0; i+1 < len(tokens); i++ {
+		tok, next := tokens[i], tokens[i+1]
+		if tok.Pos.Offset < start || tok.Pos.Offset >= end {
+			continue
+		}
+		if tok.Name != "Ident" || next.Name != "Colon" || next.Pos.Offset != tok.Pos.Offset+len(tok.Value) {
+			continue
+		}
+		if i > 0 && tokens[i-1].Name != "Whitespace" && tokens[i-1].Value != "(" {
+			continue
+		}
+		name, ok := Suggest(tok.Value, fieldNames)
+		if !ok {
+			continue
+		}
+		span := NewSpan(input, tok.Pos.Offset, next.Pos.Offset+len(next.Value))
+		return Diagnostic{
+			Severity: SeverityError,
+			Code:     "E_PARSE",
+			Message:  fmt.Sprintf("unknown field %q", tok.Value+":"),
+			Hint:     DidYouMean(name + ":"),
+			Input:    input,
+			Span:     &span,
+		}, true
+	}
+	return Diagnostic{}, false
+}
+
+// tokenEnd returns the end offset of the token with value at start. Quoted
+// tokens hold their unquoted text, so their end is the closing quote.
+func tokenEnd(input string, start int, value string) int {
+	if start >= len(input) || strings.HasPrefix(input[start:], value) {
+		return start + len(value)
+	}
+	quote := input[start]
+	if quote != '"' && quote != '\'' {
+		return start + len(value)
+	}
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(input)
+}
+
+func wordStartBefore(input string, end int) int {
+	start := end
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(input[:start])
+		if !isWordRune(r) {
+			break
+		}
+		start -= size
+	}
+	return start
+}
+
+// CommandVerbs returns every verb that starts a DSL command.
+func CommandVerbs() []string {
+	return slices.Concat(
+		createVerbs, updateVerbs, filterVerbs, viewVerbs, contextVerbs,
+		logVerbs, doneVerbs, undoVerbs, deleteVerbs,
+	)
+}
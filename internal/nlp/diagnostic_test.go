@@ -0,0 +1,145 @@
+package nlp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+)
+
+func TestParseDiagnosticsCarrySpans(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		message string
+		hint    string
+		span    nlp.Span
+	}{
+		{
+			name:    "unknown verb",
+			input:   "ad buy milk",
+			message: `unknown command "ad"`,
+			hint:    "did you mean add?",
+			span:    nlp.Span{Start: 0, End: 2, RuneStart: 0, RuneEnd: 2},
+		},
+		{
+			name:    "unknown create field",
+			input:   "add milk stat:now",
+			message: `unknown field "stat:"`,
+			hint:    "did you mean state:?",
+			span:    nlp.Span{Start: 9, End: 14, RuneStart: 9, RuneEnd: 14},
+		},
+		{
+			name:    "unknown filter field",
+			input:   "find state:now and stat:later",
+			message: `unknown field "stat:"`,
+			hint:    "did you mean state:?",
+			span:    nlp.Span{Start: 19, End: 24, RuneStart: 19, RuneEnd: 24},
+		},
+		{
+			name:    "colon without a near field",
+			input:   "add Re: budget",
+			message: `unknown field "Re:"`,
+			hint:    "quote text that contains a colon",
+			span:    nlp.Span{Start: 4, End: 7, RuneStart: 4, RuneEnd: 7},
+		},
+		{
+			name:    "unexpected token",
+			input:   `set "Köln" title:x`,
+			message: `unexpected token "Köln"`,
+			hint:    "check command syntax and quoting",
+			span:    nlp.Span{Start: 4, End: 11, RuneStart: 4, RuneEnd: 10},
+		},
+		{
+			name:    "pipe without a command",
+			input:   "find #a |",
+			message: `expected a command after "|"`,
+			hint:    `finish the pipeline or remove the "|"`,
+			span:    nlp.Span{Start: 9, End: 9, RuneStart: 9, RuneEnd: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.Error(t, err, "Parse(%q) should fail", tt.input)
+			assert.EqualError(t, err, tt.message, "error message mismatch")
+
+			var diagnosticErr nlp.DiagnosticError
+			require.ErrorAs(t, err, &diagnosticErr, "error should carry diagnostics")
+			diag := diagnosticErr.Diagnostics()[0]
+			assert.Equal(t, tt.hint, diag.Hint, "hint mismatch")
+			assert.Equal(t, tt.input, diag.Input, "input mismatch")
+			require.NotNil(t, diag.Span, "span should be set")
+			assert.Equal(t, tt.span, *diag.Span, "span mismatch")
+		})
+	}
+}
+
+func TestParseFilterKeepsUnknownFieldsAsText(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"find foo:bar", `find "stat:now"`, "find recent:5"} {
+		_, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.NoError(t, err, "Parse(%q) error", input)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	candidates := []string{"inbox", "now", "waiting", "later", "done"}
+	tests := []struct {
+		word string
+		want string
+		ok   bool
+	}{
+		{word: "nwo", want: "now", ok: true},
+		{word: "Witing", want: "waiting", ok: true},
+		{word: "latr", want: "later", ok: true},
+		{word: "soon", ok: false},
+		{word: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := nlp.Suggest(tt.word, candidates)
+		assert.Equal(t, tt.ok, ok, "Suggest(%q) ok mismatch", tt.word)
+		assert.Equal(t, tt.want, got, "Suggest(%q) mismatch", tt.word)
+	}
+}
+
+func TestLocateErrorPositionsTokenErrors(t *testing.T) {
+	t.Parallel()
+
+	tokenErr := nlp.TokenError{Err: errors.New("invalid state"), Token: "nwo", Hint: "did you mean now?"}
+	err := nlp.LocateError("set 3 title:nwo-ish state:nwo", tokenErr)
+
+	var diagnosticErr nlp.DiagnosticError
+	require.ErrorAs(t, err, &diagnosticErr, "error should carry diagnostics")
+	diag := diagnosticErr.Diagnostics()[0]
+	assert.Equal(t, "did you mean now?", diag.Hint, "hint mismatch")
+	require.NotNil(t, diag.Span, "span should be set")
+	assert.Equal(t, nlp.Span{Start: 26, End: 29, RuneStart: 26, RuneEnd: 29}, *diag.Span, "span mismatch")
+
+	plain := errors.New("boom")
+	assert.Same(t, plain, nlp.LocateError("set 3 state:nwo", plain), "other errors should be unchanged")
+}
+
+func TestDiagnosticTrimPrefix(t *testing.T) {
+	t.Parallel()
+
+	_, err := nlp.Parse("find state:now and stat:later", nlp.ParseOptions{})
+	var diagnosticErr nlp.DiagnosticError
+	require.ErrorAs(t, err, &diagnosticErr, "error should carry diagnostics")
+
+	diag := diagnosticErr.TrimPrefix("find ").Diagnostics()[0]
+	assert.Equal(t, "state:now and stat:later", diag.Input, "input mismatch")
+	require.NotNil(t, diag.Span, "span should be set")
+	assert.Equal(t, nlp.Span{Start: 14, End: 19, RuneStart: 14, RuneEnd: 19}, *diag.Span, "span mismatch")
+}
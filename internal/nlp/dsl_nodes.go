@@ -7,9 +7,26 @@ import (
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
+// statementNode is one ";"-separated statement: a pipeline of one or more
+// stages joined by "|".
+type statementNode struct {
+	Stages []*stageNode `parser:"@@ ( Pipe @@ )*"`
+}
+
+// stageNode is one command of a pipeline. Pos and EndPos are filled in by
+// participle and locate the stage in the input.
+type stageNode struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+	Cmd    Command `parser:"@@"`
+}
+
+// tagOpNode parses #project and @context tags, optionally prefixed with + to
+// add (the default) or - to remove.
 type tagOpNode struct {
-	Kind  TagKind
-	Value string
+	Kind   TagKind
+	Value  string
+	Remove bool
 }
 
 func (*tagOpNode) operation() {}
@@ -23,17 +40,35 @@ func (t *tagOpNode) Parse(lex *lexer.PeekingLexer) error {
 	if tok == nil {
 		return participle.NextMatch
 	}
-	if tok.Type == dslSymbols["ProjectTag"] {
+	if tok.Type == dslSymbols["AddOp"] || tok.Type == dslSymbols["RemoveOp"] {
+		checkpoint := lex.MakeCheckpoint()
 		lex.Next()
-		t.Kind = TagProject
-		t.Value = tok.Value
+		if !t.parseTag(lex) {
+			lex.LoadCheckpoint(checkpoint)
+			return participle.NextMatch
+		}
+		t.Remove = tok.Type == dslSymbols["RemoveOp"]
 		return nil
 	}
-	if tok.Type == dslSymbols["ContextTag"] {
-		lex.Next()
-		t.Kind = TagContext
-		t.Value = tok.Value
+	if t.parseTag(lex) {
 		return nil
 	}
 	return participle.NextMatch
 }
+
+func (t *tagOpNode) parseTag(lex *lexer.PeekingLexer) bool {
+	tok := lex.Peek()
+	switch {
+	case tok == nil:
+		return false
+	case tok.Type == dslSymbols["ProjectTag"]:
+		t.Kind = TagProject
+	case tok.Type == dslSymbols["ContextTag"]:
+		t.Kind = TagContext
+	default:
+		return false
+	}
+	lex.Next()
+	t.Value = tok.Value
+	return true
+}
@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
@@ -14,6 +15,13 @@ import (
 const (
 	contextCommandVerb = "context"
 	filterFieldDue     = "due"
+	filterFieldUntil   = "until"
+	// snoozeUntilWord separates a snooze target from its date.
+	snoozeUntilWord = "until"
+	// templateFromWord follows the create verb in "add from <template>".
+	templateFromWord = "from"
+	// filterWordAvailable is the bare filter word for PredAvailable.
+	filterWordAvailable = "available"
 )
 
 func parseIdent(lex *lexer.PeekingLexer) (string, error) {
@@ -142,6 +150,320 @@ func (v *LogVerb) Parse(lex *lexer.PeekingLexer) error {
 	return nil
 }
 
+//nolint:gochecknoglobals // constant lookup table for verb synonyms
+var doneVerbs = []string{"done", "complete", "finish"}
+
+func (v *DoneVerb) Parse(lex *lexer.PeekingLexer) error {
+	if v == nil {
+		return errors.New("nil DoneVerb")
+	}
+	s, err := parseVerb(lex, doneVerbs)
+	if err != nil {
+		return err
+	}
+	*v = DoneVerb(s)
+	return nil
+}
+
+//nolint:gochecknoglobals // constant lookup table for verb synonyms
+var undoVerbs = []string{"undo", "reopen"}
+
+func (v *UndoVerb) Parse(lex *lexer.PeekingLexer) error {
+	if v == nil {
+		return errors.New("nil UndoVerb")
+	}
+	s, err := parseVerb(lex, undoVerbs)
+	if err != nil {
+		return err
+	}
+	*v = UndoVerb(s)
+	return nil
+}
+
+//nolint:gochecknoglobals // constant lookup table for verb synonyms
+var deleteVerbs = []string{"rm", "delete"}
+
+func (v *DeleteVerb) Parse(lex *lexer.PeekingLexer) error {
+	if v == nil {
+		return errors.New("nil DeleteVerb")
+	}
+	s, err := parseVerb(lex, deleteVerbs)
+	if err != nil {
+		return err
+	}
+	*v = DeleteVerb(s)
+	return nil
+}
+
+//nolint:gochecknoglobals // constant lookup table for verb synonyms
+var showVerbs = []string{"show"}
+
+// Parse matches "show <target>" only when the target is the whole remaining
+// input; otherwise it rewinds so the filter grammar can try.
+func (c *ShowCommand) Parse(lex *lexer.PeekingLexer) error {
+	if c == nil {
+		return errors.New("nil ShowCommand")
+	}
+	checkpoint := lex.MakeCheckpoint()
+	s, err := parseVerb(lex, showVerbs)
+	if err != nil {
+		return err
+	}
+	var target *TargetRef
+	if !atStageEnd(lex.Peek()) {
+		target = &TargetRef{}
+		if err = target.Parse(lex); err != nil || !atStageEnd(lex.Peek()) {
+			lex.LoadCheckpoint(checkpoint)
+			return participle.NextMatch
+		}
+	}
+	c.Verb = ShowVerb(s)
+	c.Target = target
+	return nil
+}
+
+//nolint:gochecknoglobals // constant lookup table for verb synonyms
+var snoozeVerbs = []string{"snooze"}
+
+var errSnoozeUntil = errors.New("snooze needs a date, e.g. snooze it until friday")
+
+// Parse reads "snooze [target] until <date phrase>". The phrase runs to the
+// end of the stage.
+func (c *SnoozeCommand) Parse(lex *lexer.PeekingLexer) error {
+	if c == nil {
+		return errors.New("nil SnoozeCommand")
+	}
+	s, err := parseVerb(lex, snoozeVerbs)
+	if err != nil {
+		return err
+	}
+	c.Verb = SnoozeVerb(s)
+	if tok := lex.Peek(); !atStageEnd(tok) && !strings.EqualFold(tok.Value, snoozeUntilWord) {
+		c.Target = &TargetRef{}
+		if err = c.Target.Parse(lex); errors.Is(err, participle.NextMatch) {
+			return errSnoozeUntil
+		} else if err != nil {
+			return err
+		}
+	}
+	if tok := lex.Peek(); atStageEnd(tok) || !strings.EqualFold(tok.Value, snoozeUntilWord) {
+		return errSnoozeUntil
+	}
+	lex.Next()
+
+	var phrase strings.Builder
+	glue := true
+	for tok := lex.Peek(); !atStageEnd(tok); tok = lex.Peek() {
+		colon := tok.Type == dslSymbols["Colon"]
+		if !glue && !colon {
+			phrase.WriteByte(' ')
+		}
+		phrase.WriteString(tok.Value)
+		glue = colon
+		lex.Next()
+	}
+	c.Until = phrase.String()
+	if c.Until == "" {
+		return errors.New("snooze needs a date after until")
+	}
+	return nil
+}
+
+//nolint:gochecknoglobals // constant lookup table for verb synonyms
+var noteVerbs = []string{"note", "annotate"}
+
+var errNoteUsage = errors.New("note needs a task and text, e.g. note it called vendor")
+
+// Parse reads "note <target> <text>". The text runs to the end of the stage
+// and is taken from the input as typed once parsing is done; quote it to
+// include ; or |. maskNoteText has already made it lexable.
+func (c *NoteCommand) Parse(lex *lexer.PeekingLexer) error {
+	if c == nil {
+		return errors.New("nil NoteCommand")
+	}
+	s, err := parseVerb(lex, noteVerbs)
+	if err != nil {
+		return err
+	}
+	c.Verb = NoteVerb(s)
+	if atStageEnd(lex.Peek()) {
+		return errNoteUsage
+	}
+	c.Target = &TargetRef{}
+	if err = c.Target.Parse(lex); errors.Is(err, participle.NextMatch) {
+		return errNoteUsage
+	} else if err != nil {
+		return err
+	}
+
+	start := lex.Peek()
+	if atStageEnd(start) {
+		return errNoteUsage
+	}
+	tok := start
+	for ; !atStageEnd(tok); tok = lex.Peek() {
+		lex.Next()
+	}
+	c.textStart, c.textEnd = start.Pos.Offset, tok.Pos.Offset
+	return nil
+}
+
+// noteText returns the text of a note as typed, without the quotes when it
+// is a single quoted string.
+func noteText(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, `"`) {
+		if unquoted, err := strconv.Unquote(raw); err == nil {
+			return unquoted
+		}
+	}
+	return raw
+}
+
+// maskNoteText replaces the text of each note stage in input with x's, keeping
+// whitespace and byte offsets, so that text the lexer has no token for, such
+// as an apostrophe or a URL, still parses. The text starts after the verb and
+// a one-word target, or a comma-separated list of them.
+func maskNoteText(input string) string {
+	masked := []byte(input)
+	for start := 0; start < len(input); {
+		end := stageEndOffset(input, start)
+		if from, ok := noteTextOffset(input[start:end]); ok {
+			for i := start + from; i < end; i++ {
+				if !unicode.IsSpace(rune(masked[i])) {
+					masked[i] = 'x'
+				}
+			}
+		}
+		start = end + 1
+	}
+	return string(masked)
+}
+
+// stageEndOffset returns the offset of the ";" or "|" that ends the stage
+// starting at start, or len(input). Quoted strings and "||" do not end it.
+func stageEndOffset(input string, start int) int {
+	for i := start; i < len(input); i++ {
+		switch input[i] {
+		case '"':
+			for i++; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\\' {
+					i++
+				}
+			}
+		case ';':
+			return i
+		case '|':
+			if i+1 < len(input) && input[i+1] == '|' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(input)
+}
+
+// noteTextOffset returns where the text of stage starts when stage is a note
+// with a target and text.
+func noteTextOffset(stage string) (int, bool) {
+	i := skipSpaces(stage, 0)
+	j := wordEnd(stage, i)
+	if !slices.ContainsFunc(noteVerbs, func(verb string) bool { return strings.EqualFold(verb, stage[i:j]) }) {
+		return 0, false
+	}
+	i = skipSpaces(stage, j)
+	for i < len(stage) {
+		j = wordEnd(stage, i)
+		next := skipSpaces(stage, j)
+		if !strings.HasSuffix(stage[i:j], ",") && (next == len(stage) || stage[next] != ',') {
+			break
+		}
+		i = next
+	}
+	i = skipSpaces(stage, j)
+	return i, i < len(stage)
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && unicode.IsSpace(rune(s[i])) {
+		i++
+	}
+	return i
+}
+
+func wordEnd(s string, i int) int {
+	for i < len(s) && !unicode.IsSpace(rune(s[i])) {
+		i++
+	}
+	return i
+}
+
+// Parse reads "add from <template> [name:value ...]". Anything else after the
+// template name, such as a field or a tag, leaves the line to CreateCommand,
+// so "add from home #errands" is still a task titled "from home".
+func (c *TemplateCommand) Parse(lex *lexer.PeekingLexer) error {
+	if c == nil {
+		return errors.New("nil TemplateCommand")
+	}
+	checkpoint := lex.MakeCheckpoint()
+	s, err := parseVerb(lex, createVerbs)
+	if err != nil {
+		return err
+	}
+	if word, identErr := parseIdent(lex); identErr != nil || word != templateFromWord {
+		lex.LoadCheckpoint(checkpoint)
+		return participle.NextMatch
+	}
+	lex.Next()
+	name, err := parseIdent(lex)
+	if err != nil {
+		lex.LoadCheckpoint(checkpoint)
+		return participle.NextMatch
+	}
+	lex.Next()
+
+	vars := map[string]string{}
+	for !atStageEnd(lex.Peek()) {
+		key, value, ok := parseTemplateVar(lex)
+		if !ok {
+			lex.LoadCheckpoint(checkpoint)
+			return participle.NextMatch
+		}
+		vars[key] = value
+	}
+	c.Verb = CreateVerb(s)
+	c.Name = name
+	c.Vars = vars
+	return nil
+}
+
+// parseTemplateVar reads one name:value pair; the value is a word or a quoted
+// string.
+func parseTemplateVar(lex *lexer.PeekingLexer) (string, string, bool) {
+	key, err := parseIdent(lex)
+	if err != nil {
+		return "", "", false
+	}
+	lex.Next()
+	if tok := lex.Peek(); tok == nil || tok.Type != dslSymbols["Colon"] {
+		return "", "", false
+	}
+	lex.Next()
+	tok := lex.Peek()
+	if tok == nil || (tok.Type != dslSymbols["Ident"] && tok.Type != dslSymbols["Quoted"]) {
+		return "", "", false
+	}
+	lex.Next()
+	return key, tok.Value, true
+}
+
+// atStageEnd reports whether tok ends a pipeline stage: the end of input, a
+// ";" or a "|".
+func atStageEnd(tok *lexer.Token) bool {
+	return tok == nil || tok.EOF() || tok.Type == dslSymbols["Semicolon"] || tok.Type == dslSymbols["Pipe"]
+}
+
 func (t *ViewTarget) Parse(lex *lexer.PeekingLexer) error {
 	if t == nil {
 		return errors.New("nil ViewTarget")
@@ -154,48 +476,55 @@ func (t *ViewTarget) Parse(lex *lexer.PeekingLexer) error {
 		return fmt.Errorf("invalid view: %s", tok.Value)
 	}
 
+	// Built-in names and aliases are canonicalized in postProcess; any other
+	// valid name refers to a user-defined view resolved at execution time.
 	s := strings.ToLower(strings.TrimSpace(tok.Value))
-	switch s {
-	case "i", viewNameInbox, "n", viewNameNow, "w", viewNameWaiting, "l", viewNameLater, "c", viewNameCalendar, "today":
-		lex.Next()
-		t.Name = s
-		return nil
-	default:
+	if !IsValidViewName(s) {
 		return fmt.Errorf("invalid view: %s", tok.Value)
 	}
+	lex.Next()
+	t.Name = s
+	return nil
 }
 
+// Parse reads a lone #project, @context or "clear". Anything longer is left
+// for the filter expression alternative of ContextCommand.
 func (a *ContextArg) Parse(lex *lexer.PeekingLexer) error {
 	if a == nil {
 		return errors.New("nil ContextArg")
 	}
 	tok := lex.Peek()
-	if tok == nil {
+	if atStageEnd(tok) {
 		return participle.NextMatch
 	}
 
-	if tok.Type == dslSymbols["ProjectTag"] {
-		lex.Next()
+	checkpoint := lex.MakeCheckpoint()
+	lex.Next()
+	if !atStageEnd(lex.Peek()) {
+		lex.LoadCheckpoint(checkpoint)
+		return participle.NextMatch
+	}
+
+	switch tok.Type {
+	case dslSymbols["ProjectTag"]:
 		a.Project = tok.Value
 		return nil
-	}
-	if tok.Type == dslSymbols["ContextTag"] {
-		lex.Next()
+	case dslSymbols["ContextTag"]:
 		a.Context = tok.Value
 		return nil
-	}
-	if tok.Type == dslSymbols["Ident"] {
-		s := strings.ToLower(strings.TrimSpace(tok.Value))
-		if s == "clear" {
-			lex.Next()
+	case dslSymbols["Ident"]:
+		if strings.EqualFold(strings.TrimSpace(tok.Value), "clear") {
 			a.Clear = true
 			return nil
 		}
 	}
-
-	return fmt.Errorf("invalid context argument: %s", tok.Value)
+	lex.LoadCheckpoint(checkpoint)
+	return participle.NextMatch
 }
 
+// maxTargetRange caps how many IDs a single range target like 1-5 expands to.
+const maxTargetRange = 1000
+
 func (t *TargetRef) Parse(lex *lexer.PeekingLexer) error {
 	if t == nil {
 		return errors.New("nil TargetRef")
@@ -204,43 +533,174 @@ func (t *TargetRef) Parse(lex *lexer.PeekingLexer) error {
 	if tok == nil {
 		return participle.NextMatch
 	}
+	if ok, err := t.parseReference(tok); ok || err != nil {
+		if err == nil {
+			lex.Next()
+		}
+		return err
+	}
 	// If the next token is not a plausible target, treat it as absent.
 	if tok.Type != dslSymbols["Ident"] && tok.Type != dslSymbols["HashNumber"] {
 		return participle.NextMatch
 	}
 
-	text := strings.ToLower(strings.TrimSpace(tok.Value))
-	if tok.Type == dslSymbols["HashNumber"] {
-		id, err := strconv.ParseInt(strings.TrimPrefix(text, "#"), 10, 64)
-		if err != nil || id <= 0 {
-			return fmt.Errorf("invalid update target: %s", tok.Value)
+	ids, err := parseTargetItem(lex)
+	if err != nil {
+		return err
+	}
+	for {
+		next := lex.Peek()
+		if next == nil || next.Type != dslSymbols["Comma"] {
+			break
 		}
 		lex.Next()
+		more, itemErr := parseTargetItem(lex)
+		if itemErr != nil {
+			return itemErr
+		}
+		ids = append(ids, more...)
+	}
+
+	ids = uniqueIDs(ids)
+	if len(ids) == 1 {
 		t.Kind = TargetID
-		t.ID = id
+		t.ID = ids[0]
 		return nil
 	}
+	t.Kind = TargetIDs
+	t.IDs = ids
+	return nil
+}
 
-	switch text {
-	case "selected", "it", "this", "that":
-		lex.Next()
+// parseReference reads a pronoun or result reference from tok. It reports
+// false when tok is not one.
+func (t *TargetRef) parseReference(tok *lexer.Token) (bool, error) {
+	switch tok.Type {
+	case dslSymbols["ResultRef"], dslSymbols["RowRef"]:
+		n, err := strconv.Atoi(tok.Value[1:])
+		if err != nil {
+			return false, fmt.Errorf("invalid reference %s: %w", tok.Value, err)
+		}
+		t.Kind, t.N = TargetRow, n
+		if tok.Type == dslSymbols["ResultRef"] {
+			t.Kind = TargetResult
+		}
+		return true, nil
+	case dslSymbols["Variable"]:
+		if !strings.EqualFold(tok.Value, "$last") {
+			return false, nil
+		}
+		t.Kind, t.N = TargetRecent, 1
+		return true, nil
+	case dslSymbols["Ident"]:
+	default:
+		return false, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(tok.Value)) {
+	case "selected":
 		t.Kind = TargetSelected
-		t.ID = 0
-		return nil
+	case "it", "this", "last":
+		t.Kind, t.N = TargetRecent, 1
+	case "that":
+		t.Kind, t.N = TargetRecent, 2
+	case "those", "these", "them":
+		t.Kind = TargetLast
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseTargetItem consumes one ID ("3", "#3") or range ("10-14", "#10-14",
+// "#10-#14") from a target list.
+func parseTargetItem(lex *lexer.PeekingLexer) ([]int64, error) {
+	tok := lex.Peek()
+	if tok == nil || tok.EOF() {
+		return nil, errors.New("expected task id")
+	}
+	if tok.Type != dslSymbols["Ident"] && tok.Type != dslSymbols["HashNumber"] {
+		return nil, fmt.Errorf("invalid task target: %s", tok.Value)
+	}
+
+	if tok.Type == dslSymbols["Ident"] {
+		if startText, endText, ok := strings.Cut(tok.Value, "-"); ok {
+			lex.Next()
+			return parseTargetRange(tok.Value, startText, endText)
+		}
 	}
+	start, err := parseTargetID(tok.Value)
+	if err != nil {
+		return nil, err
+	}
+	lex.Next()
 
-	if isDigits(text) {
-		id, err := strconv.ParseInt(text, 10, 64)
-		if err != nil || id <= 0 {
-			return fmt.Errorf("invalid update target: %s", tok.Value)
+	// "#10-14" and "#10-#14" lex as HashNumber, RemoveOp, then the range end.
+	if tok.Type == dslSymbols["HashNumber"] {
+		checkpoint := lex.MakeCheckpoint()
+		dash := lex.Next()
+		endTok := lex.Peek()
+		if dash != nil && dash.Type == dslSymbols["RemoveOp"] && isTargetRangeEnd(endTok) {
+			lex.Next()
+			return parseTargetRange(tok.Value+"-"+endTok.Value, tok.Value, endTok.Value)
 		}
-		lex.Next()
-		t.Kind = TargetID
-		t.ID = id
-		return nil
+		lex.LoadCheckpoint(checkpoint)
 	}
+	return []int64{start}, nil
+}
 
-	return fmt.Errorf("invalid update target: %s", tok.Value)
+func isTargetRangeEnd(tok *lexer.Token) bool {
+	if tok == nil {
+		return false
+	}
+	return tok.Type == dslSymbols["HashNumber"] || (tok.Type == dslSymbols["Ident"] && isDigits(tok.Value))
+}
+
+func parseTargetID(text string) (int64, error) {
+	digits := strings.TrimPrefix(strings.TrimSpace(text), "#")
+	if !isDigits(digits) {
+		return 0, fmt.Errorf("invalid task target: %s", text)
+	}
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid task target: %s", text)
+	}
+	return id, nil
+}
+
+func parseTargetRange(text, startText, endText string) ([]int64, error) {
+	start, err := parseTargetID(startText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task target range: %s", text)
+	}
+	end, err := parseTargetID(endText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task target range: %s", text)
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid task target range: %s (end before start)", text)
+	}
+	if end-start >= maxTargetRange {
+		return nil, fmt.Errorf("invalid task target range: %s (more than %d ids)", text, maxTargetRange)
+	}
+	ids := make([]int64, 0, end-start+1)
+	for id := start; id <= end; id++ {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
 }
 
 //nolint:gochecknoglobals // constant lookup table for operator symbols and keywords
@@ -288,6 +748,9 @@ func (f *Field) Capture(values []string) error {
 	case "waiting", "waiting-for", "waiting_for":
 		*f = FieldWaiting
 		return nil
+	case filterFieldUntil:
+		*f = FieldFollowUp
+		return nil
 	case "state":
 		*f = FieldState
 		return nil
@@ -319,7 +782,8 @@ func (v *FilterValue) Parse(lex *lexer.PeekingLexer) error {
 		return errors.New("nil FilterValue")
 	}
 	peek := lex.Peek()
-	if peek == nil {
+	if peek == nil || atSortClause(lex) {
+		// A sort clause may stand alone, as in "find order by due".
 		return participle.NextMatch
 	}
 
@@ -332,7 +796,7 @@ func (v *FilterValue) Parse(lex *lexer.PeekingLexer) error {
 	values := make([]string, 0)
 	for {
 		tok := lex.Peek()
-		if tok == nil || isFilterValueDelimiter(tok) {
+		if tok == nil || isFilterValueDelimiter(tok) || atSortClause(lex) {
 			break
 		}
 		if !isFilterValueToken(tok) {
@@ -349,6 +813,78 @@ func (v *FilterValue) Parse(lex *lexer.PeekingLexer) error {
 	return nil
 }
 
+func (c *FilterSortClause) Parse(lex *lexer.PeekingLexer) error {
+	if c == nil {
+		return errors.New("nil FilterSortClause")
+	}
+	if !atSortClause(lex) {
+		return participle.NextMatch
+	}
+	// Consume the keyword and its trailing ":" or "by".
+	lex.Next()
+	lex.Next()
+
+	var builder strings.Builder
+	afterKey := false
+	for tok := lex.Peek(); !atStageEnd(tok); tok = lex.Peek() {
+		switch tok.Type {
+		case dslSymbols["Ident"]:
+			switch {
+			case afterKey && isSortDirection(tok.Value):
+				// "title desc" keeps the direction with its field.
+				builder.WriteByte(' ')
+			case afterKey:
+				builder.WriteByte(',')
+			}
+			builder.WriteString(tok.Value)
+			afterKey = true
+		case dslSymbols["SetField"], dslSymbols["RemoveField"], dslSymbols["RemoveOp"]:
+			// "meta:" and "-meta:" lex as field tokens inside sort clauses.
+			if afterKey {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(strings.Join(strings.Fields(tok.Value), ""))
+			afterKey = false
+		case dslSymbols["Colon"], dslSymbols["Comma"]:
+			builder.WriteString(tok.Value)
+			afterKey = false
+		default:
+			return fmt.Errorf("unexpected %q in sort clause", tok.Value)
+		}
+		lex.Next()
+	}
+	if builder.Len() == 0 {
+		return errors.New("sort clause requires at least one field")
+	}
+	c.Spec = builder.String()
+	return nil
+}
+
+// atSortClause reports whether the next tokens start a "sort:" or "order by"
+// clause without consuming them.
+func atSortClause(lex *lexer.PeekingLexer) bool {
+	tok := lex.Peek()
+	if tok == nil || tok.Type != dslSymbols["Ident"] {
+		return false
+	}
+	keyword := strings.ToLower(tok.Value)
+	if keyword != "sort" && keyword != "order" {
+		return false
+	}
+
+	checkpoint := lex.MakeCheckpoint()
+	defer lex.LoadCheckpoint(checkpoint)
+	lex.Next()
+	next := lex.Peek()
+	if next == nil {
+		return false
+	}
+	if keyword == "sort" {
+		return next.Type == dslSymbols["Colon"]
+	}
+	return next.Type == dslSymbols["Ident"] && strings.EqualFold(next.Value, "by")
+}
+
 func isFilterValueDelimiter(tok *lexer.Token) bool {
 	if tok.Type == dslSymbols["RParen"] || tok.Type == dslSymbols["AndOp"] || tok.Type == dslSymbols["OrOp"] {
 		return true
@@ -365,7 +901,10 @@ func isFilterValueToken(tok *lexer.Token) bool {
 		tok.Type == dslSymbols["HashNumber"] ||
 		tok.Type == dslSymbols["Star"] ||
 		tok.Type == dslSymbols["Colon"] ||
-		tok.Type == dslSymbols["Comma"]
+		tok.Type == dslSymbols["Comma"] ||
+		tok.Type == dslSymbols["Compare"] ||
+		tok.Type == dslSymbols["Regex"] ||
+		tok.Type == dslSymbols["Quoted"]
 }
 
 func normalizeCapturedField(values []string) string {
@@ -391,12 +930,12 @@ func joinTokens(values []string) string {
 			b.WriteString(tok)
 			continue
 		}
-		if tok == ":" || tok == "," {
+		if isJoinPunct(tok) {
 			b.WriteString(tok)
 			continue
 		}
 		prev := values[i-1]
-		if prev == ":" || prev == "," {
+		if isJoinPunct(prev) {
 			b.WriteString(tok)
 			continue
 		}
@@ -407,6 +946,16 @@ func joinTokens(values []string) string {
 	return strings.TrimSpace(b.String())
 }
 
+// isJoinPunct reports tokens that joinTokens glues to their neighbours.
+func isJoinPunct(tok string) bool {
+	switch tok {
+	case ":", ",", "=", "<", ">", "<=", ">=":
+		return true
+	default:
+		return false
+	}
+}
+
 func trimEmpty(in []string) []string {
 	out := in[:0]
 	for _, s := range in {
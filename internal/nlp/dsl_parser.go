@@ -8,41 +8,57 @@ import (
 )
 
 //nolint:gochecknoglobals // Parser is a package-level singleton for participle.
-var dslParser = participle.MustBuild[Root](
-	participle.Lexer(dslLexer),
-	participle.Elide("Whitespace"),
-	participle.Unquote("Quoted"),
-	participle.CaseInsensitive("Ident"),
-	participle.Map(trimPrefixTokenMapper("#"), "ProjectTag"),
-	participle.Map(trimPrefixTokenMapper("@"), "ContextTag"),
-	participle.Union[Command](
-		&CreateCommand{},
-		&UpdateCommand{},
-		&FilterCommand{},
-		&ViewCommand{},
-		&ContextCommand{},
-		&LogCommand{},
-	),
-	participle.Union[CreatePart](
-		&CreateOpPart{},
-		&CreateText{},
-	),
-	participle.Union[Operation](
-		&SetOp{},
-		&AddOp{},
-		&RemoveOp{},
-		&ClearOp{},
-		&tagOpNode{},
-	),
-	participle.Union[CreateOp](
-		&SetOp{},
-		&AddOp{},
-		&RemoveOp{},
-		&ClearOp{},
-		&tagOpNode{},
-	),
+var dslParser = buildDSLParser(
+	&TemplateCommand{},
+	&CreateCommand{},
+	&UpdateCommand{},
+	&ShowCommand{},
+	&FilterCommand{},
+	&ViewCommand{},
+	&ContextCommand{},
+	&LogCommand{},
+	&DoneCommand{},
+	&UndoCommand{},
+	&DeleteCommand{},
+	&SnoozeCommand{},
+	&NoteCommand{},
 )
 
+// createParser reads a stage as CreateCommand only. It re-reads "add from"
+// lines that name no known template.
+//
+//nolint:gochecknoglobals // Parser is a package-level singleton for participle.
+var createParser = buildDSLParser(&CreateCommand{})
+
+func buildDSLParser(commands ...Command) *participle.Parser[Root] {
+	return participle.MustBuild[Root](
+		participle.Lexer(dslLexer),
+		participle.Elide("Whitespace"),
+		participle.Unquote("Quoted"),
+		participle.CaseInsensitive("Ident"),
+		participle.Map(trimPrefixTokenMapper("#"), "ProjectTag"),
+		participle.Map(trimPrefixTokenMapper("@"), "ContextTag"),
+		participle.Union[Command](commands...),
+		participle.Union[CreatePart](
+			&CreateOpPart{},
+			&CreateText{},
+		),
+		participle.Union[Operation](
+			&SetOp{},
+			&AddOp{},
+			&RemoveOp{},
+			&ClearOp{},
+			&tagOpNode{},
+		),
+		participle.Union[CreateOp](
+			&SetOp{},
+			&AddOp{},
+			&RemoveOp{},
+			&ClearOp{},
+			&tagOpNode{},
+		),
+	)
+}
 func trimPrefixTokenMapper(prefix string) func(lexer.Token) (lexer.Token, error) {
 	return func(tok lexer.Token) (lexer.Token, error) {
 		tok.Value = strings.TrimPrefix(tok.Value, prefix)
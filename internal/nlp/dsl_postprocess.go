@@ -2,6 +2,7 @@ package nlp
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -11,16 +12,23 @@ func (c *CreateCommand) postProcess() error {
 		return errors.New("nil create command")
 	}
 
-	titleTokens := make([]string, 0, len(c.Parts))
+	words := make([]TitleWord, 0, len(c.Parts))
 	ops := make([]Operation, 0, len(c.Parts))
 
+	var prev *CreateText
 	for _, part := range c.Parts {
 		switch typed := part.(type) {
 		case *CreateText:
-			text := strings.TrimSpace(string(typed.Text))
-			if text != "" {
-				titleTokens = append(titleTokens, text)
+			word := TitleWord{Text: strings.TrimSpace(string(typed.Text))}
+			if typed.Quoted != "" {
+				word = TitleWord{Text: strings.TrimSpace(string(typed.Quoted)), Quoted: true}
 			}
+			word.Glued = prev != nil && prev.EndPos.Offset == typed.Pos.Offset
+			if word.Text != "" {
+				words = append(words, word)
+			}
+			prev = typed
+			continue
 		case *CreateOpPart:
 			if typed.Op == nil {
 				continue
@@ -35,9 +43,11 @@ func (c *CreateCommand) postProcess() error {
 		default:
 			// Ignore unknown parts.
 		}
+		prev = nil
 	}
 
-	c.Title = strings.TrimSpace(joinTokens(titleTokens))
+	c.Words = words
+	c.Title = JoinTitleWords(words)
 	c.Ops = ops
 
 	if c.Title == "" && !hasTitleSetOp(c.Ops) {
@@ -46,16 +56,37 @@ func (c *CreateCommand) postProcess() error {
 	return nil
 }
 
-func (u *UpdateCommand) postProcess() {
+// JoinTitleWords joins title words as they were spaced in the input: glued
+// words follow the word before them directly, others after one space.
+func JoinTitleWords(words []TitleWord) string {
+	var b strings.Builder
+	for i, word := range words {
+		if i > 0 && !word.Glued {
+			b.WriteByte(' ')
+		}
+		b.WriteString(word.Text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (u *UpdateCommand) postProcess() error {
 	if u == nil {
-		return
+		return errors.New("nil update command")
 	}
-	if u.Target == nil {
-		u.Target = &TargetRef{Kind: TargetSelected}
+	if u.Where != nil {
+		if err := validateFilterNotExpr(u.Where.Filter); err != nil {
+			return err
+		}
+		u.WhereExpr = u.Where.Filter.toExpr()
+		if u.WhereExpr == nil {
+			return errors.New("update where requires a filter expression")
+		}
+	} else {
+		u.Target = defaultTarget(u.Target)
 	}
 
 	if len(u.Ops) == 0 {
-		return
+		return nil
 	}
 	normalized := make([]Operation, 0, len(u.Ops))
 	for _, op := range u.Ops {
@@ -64,23 +95,76 @@ func (u *UpdateCommand) postProcess() {
 		}
 	}
 	u.Ops = normalized
+	return nil
+}
+
+// pipeCommand prepares a command that follows a "|": a missing target refers
+// to the tasks of the previous stage and filters only search those tasks.
+func pipeCommand(cmd Command) error {
+	switch typed := cmd.(type) {
+	case *UpdateCommand:
+		if typed.Where != nil {
+			return errors.New("set where cannot follow |")
+		}
+		typed.Target = pipedTarget(typed.Target)
+	case *DoneCommand:
+		typed.Target = pipedTarget(typed.Target)
+	case *UndoCommand:
+		typed.Target = pipedTarget(typed.Target)
+	case *DeleteCommand:
+		typed.Target = pipedTarget(typed.Target)
+	case *ShowCommand:
+		typed.Target = pipedTarget(typed.Target)
+	case *SnoozeCommand:
+		typed.Target = pipedTarget(typed.Target)
+	case *FilterCommand:
+		typed.Piped = true
+	default:
+		return errors.New("only find, show, set, done, undo, snooze and rm can follow |")
+	}
+	return nil
+}
+
+func pipedTarget(target *TargetRef) *TargetRef {
+	if target == nil {
+		return &TargetRef{Kind: TargetLast}
+	}
+	return target
+}
+
+// defaultTarget makes a missing target refer to the selected task.
+func defaultTarget(target *TargetRef) *TargetRef {
+	if target == nil {
+		return &TargetRef{Kind: TargetSelected}
+	}
+	return target
 }
 
 func (f *FilterCommand) postProcess() error {
 	if f == nil {
 		return errors.New("nil filter command")
 	}
-	if f.Chain == nil {
+	if f.Chain == nil && f.Sort == nil {
 		return errors.New("filter command requires an expression")
 	}
-	if err := validateFilterChain(f.Chain); err != nil {
-		return err
+	// A sort clause on its own orders every task, leaving Expr nil.
+	if f.Chain != nil {
+		if err := validateFilterChain(f.Chain); err != nil {
+			return err
+		}
+		expr := f.Chain.toExpr()
+		if expr == nil {
+			return errors.New("filter command requires an expression")
+		}
+		f.Expr = expr
 	}
-	expr := f.Chain.toExpr()
-	if expr == nil {
-		return errors.New("filter command requires an expression")
+	if f.Sort != nil {
+		keys, err := ParseSortKeys(f.Sort.Spec)
+		if err != nil {
+			return err
+		}
+		f.SortKeys = keys
 	}
-	f.Expr = expr
 	return nil
 }
 
@@ -103,6 +187,23 @@ func (c *ContextCommand) postProcess() error {
 	if c == nil {
 		return errors.New("nil context command")
 	}
+	if c.Chain != nil {
+		if text, ok := bareTextFilter(c.Chain); ok {
+			return TokenError{
+				Err:   fmt.Errorf("invalid context argument: %s", text),
+				Token: text,
+				Hint:  "use #project, @context, clear or a filter such as text:" + text,
+			}
+		}
+		if err := validateFilterChain(c.Chain); err != nil {
+			return err
+		}
+		c.Filter = c.Chain.toExpr()
+		if c.Filter == nil {
+			return errors.New("context filter requires an expression")
+		}
+		return nil
+	}
 	if c.Arg == nil {
 		return nil
 	}
@@ -126,6 +227,24 @@ func (c *ContextCommand) postProcess() error {
 	return nil
 }
 
+// bareTextFilter reports whether chain is nothing but plain words, which as a
+// context is more likely a typo than a text search.
+func bareTextFilter(chain *FilterOrChain) (string, bool) {
+	if chain.Op != nil || chain.Left == nil || chain.Left.Op != nil {
+		return "", false
+	}
+	not := chain.Left.Left
+	if not == nil || not.Not != nil || not.Atom == nil || not.Atom.Pred == nil || not.Atom.Pred.Text == nil {
+		return "", false
+	}
+	text := string(not.Atom.Pred.Text.Value)
+	// "context available" is a filter that follows the context schedules.
+	if strings.EqualFold(text, filterWordAvailable) {
+		return "", false
+	}
+	return text, true
+}
+
 func (l *LogCommand) postProcess() error {
 	if l == nil {
 		return errors.New("nil log command")
@@ -133,27 +252,23 @@ func (l *LogCommand) postProcess() error {
 	if l.Target == nil {
 		return errors.New("log command requires a task id")
 	}
-	if l.Target.Kind != TargetID {
-		return errors.New("log command requires a numeric task id")
+	switch l.Target.Kind {
+	case TargetID, TargetRecent, TargetRow:
+	default:
+		return errors.New("log command requires a single task id")
 	}
 	return nil
 }
 
 func canonicalViewName(name string) string {
-	switch strings.ToLower(strings.TrimSpace(name)) {
-	case "i", viewNameInbox:
-		return viewNameInbox
-	case "n", viewNameNow:
-		return viewNameNow
-	case "w", viewNameWaiting:
-		return viewNameWaiting
-	case "l", viewNameLater:
-		return viewNameLater
-	case "c", viewNameCalendar, "today":
-		return viewNameCalendar
-	default:
+	name = strings.ToLower(strings.TrimSpace(name))
+	if view, ok := LookupBuiltinView(name); ok {
+		return view.Name
+	}
+	if !IsValidViewName(name) {
 		return ""
 	}
+	return name
 }
 
 func (o *FilterOrChain) toExpr() FilterExpr {
@@ -224,6 +339,9 @@ func (a *FilterAtom) toExpr() FilterExpr {
 	if a.Pred == nil {
 		return nil
 	}
+	if a.Pred.Absent != nil {
+		return a.Pred.Absent.toExpr()
+	}
 	p := a.Pred.toPredicate()
 	if p == nil {
 		return nil
@@ -262,12 +380,24 @@ func (p *FilterFieldPredicate) toPredicate() *Predicate {
 		return &Predicate{Kind: PredState, Text: value}
 	case "due":
 		return &Predicate{Kind: PredDue, Text: value}
+	case filterFieldUntil:
+		pred := parseFollowUpPredicate(value)
+		return &pred
 	case "project", "projects":
 		return &Predicate{Kind: PredProject, Text: value}
 	case "context", "contexts":
 		return &Predicate{Kind: PredContext, Text: value}
 	case "text":
 		return &Predicate{Kind: PredText, Text: value}
+	case "title", "notes", "waiting", "waiting-for", "waiting_for":
+		pred := parseTextFieldPredicate(textFieldKind(field), value)
+		return &pred
+	case "meta":
+		pred, err := parseMetaPredicate(value)
+		if err != nil {
+			return &Predicate{Kind: PredMeta, Text: value}
+		}
+		return &pred
 	case "id":
 		if id, ok := parsePossibleID(value); ok {
 			return &Predicate{Kind: PredID, Text: strconv.FormatInt(id, 10)}
@@ -323,7 +453,13 @@ func validateFilterAtom(atom *FilterAtom) error {
 }
 
 func validateFilterPredicate(pred *FilterPredicate) error {
-	if pred == nil || pred.Field == nil {
+	if pred == nil {
+		return nil
+	}
+	if pred.Absent != nil {
+		return validateAbsentPredicate(pred.Absent)
+	}
+	if pred.Field == nil {
 		return nil
 	}
 	return validateFieldPredicate(pred.Field)
@@ -336,9 +472,156 @@ func validateFieldPredicate(pred *FilterFieldPredicate) error {
 	if pred.Value == nil || strings.TrimSpace(string(*pred.Value)) == "" {
 		return errors.New("expected value")
 	}
+	if normalizeCapturedField([]string{pred.Field}) == "meta" {
+		_, err := parseMetaPredicate(string(*pred.Value))
+		return err
+	}
 	return nil
 }
 
+func validateAbsentPredicate(pred *FilterAbsentPredicate) error {
+	field := normalizeCapturedField([]string{pred.Field})
+	switch field {
+	case "meta":
+		return nil
+	case filterFieldDue, filterFieldUntil, "projects", "contexts", "notes", "waiting", "waiting-for", "waiting_for":
+		if pred.Key != "" {
+			return fmt.Errorf("!%s does not take a key", field)
+		}
+		return nil
+	default:
+		return fmt.Errorf("!%s is not supported in filters", field)
+	}
+}
+
+func (p *FilterAbsentPredicate) toExpr() FilterExpr {
+	if p == nil {
+		return nil
+	}
+	var pred Predicate
+	switch normalizeCapturedField([]string{p.Field}) {
+	case filterFieldDue:
+		pred = Predicate{Kind: PredDue, Text: FilterWildcard}
+	case filterFieldUntil:
+		pred = Predicate{Kind: PredFollowUp, Text: FilterWildcard}
+	case "projects":
+		pred = Predicate{Kind: PredProject, Text: FilterWildcard}
+	case "contexts":
+		pred = Predicate{Kind: PredContext, Text: FilterWildcard}
+	case "meta":
+		pred = Predicate{Kind: PredMeta, Key: strings.TrimSpace(p.Key), Text: FilterWildcard}
+	case "notes":
+		pred = Predicate{Kind: PredNotes, Text: FilterWildcard}
+	case "waiting", "waiting-for", "waiting_for":
+		pred = Predicate{Kind: PredWaiting, Text: FilterWildcard}
+	default:
+		return nil
+	}
+	return FilterNot{Expr: pred}
+}
+
+func textFieldKind(field string) PredicateKind {
+	switch field {
+	case "title":
+		return PredTitle
+	case "notes":
+		return PredNotes
+	default:
+		return PredWaiting
+	}
+}
+
+// parseTextFieldPredicate parses the value of a title:, notes: or waiting:
+// filter. A bare value matches as a substring, =value matches exactly,
+// ~/pattern/ matches a regular expression and * matches any non-empty value.
+func parseTextFieldPredicate(kind PredicateKind, value string) Predicate {
+	value = strings.TrimSpace(value)
+	if value == FilterWildcard {
+		return Predicate{Kind: kind, Text: FilterWildcard}
+	}
+	if rest, ok := strings.CutPrefix(value, "="); ok {
+		return Predicate{Kind: kind, Op: CompareEq, Text: strings.TrimSpace(rest)}
+	}
+	if strings.HasPrefix(value, "~/") && strings.HasSuffix(value, "/") && len(value) >= len("~//") {
+		return Predicate{Kind: kind, Op: CompareRegex, Text: value[2 : len(value)-1]}
+	}
+	return Predicate{Kind: kind, Op: CompareContains, Text: value}
+}
+
+// parseFollowUpPredicate parses the value of an until: filter: a date for
+// equality, a date prefixed with <, <=, > or >=, or * for any follow-up date.
+func parseFollowUpPredicate(value string) Predicate {
+	value = strings.TrimSpace(value)
+	op := CompareEq
+	switch {
+	case strings.HasPrefix(value, "<="):
+		op, value = CompareLte, value[2:]
+	case strings.HasPrefix(value, ">="):
+		op, value = CompareGte, value[2:]
+	case strings.HasPrefix(value, "<"):
+		op, value = CompareLt, value[1:]
+	case strings.HasPrefix(value, ">"):
+		op, value = CompareGt, value[1:]
+	case strings.HasPrefix(value, "="):
+		value = value[1:]
+	}
+	return Predicate{Kind: PredFollowUp, Op: op, Text: strings.TrimSpace(value)}
+}
+
+// parseMetaPredicate parses the value of a meta: filter. Supported forms are
+// key (or key:*) for presence, key=value or key:value for equality,
+// key<value, key<=value, key>value, key>=value, and key=low..high.
+func parseMetaPredicate(value string) (Predicate, error) {
+	value = strings.TrimSpace(value)
+	if value == FilterWildcard {
+		return Predicate{Kind: PredMeta, Text: FilterWildcard}, nil
+	}
+
+	idx := strings.IndexAny(value, ":=<>")
+	if idx < 0 {
+		return Predicate{Kind: PredMeta, Key: value, Text: FilterWildcard}, nil
+	}
+	key := strings.TrimSpace(value[:idx])
+	if key == "" {
+		return Predicate{}, fmt.Errorf("meta filter %q requires a key", value)
+	}
+	rest := value[idx:]
+
+	op := CompareEq
+	switch {
+	case strings.HasPrefix(rest, "<="):
+		op, rest = CompareLte, rest[2:]
+	case strings.HasPrefix(rest, ">="):
+		op, rest = CompareGte, rest[2:]
+	case strings.HasPrefix(rest, "<"):
+		op, rest = CompareLt, rest[1:]
+	case strings.HasPrefix(rest, ">"):
+		op, rest = CompareGt, rest[1:]
+	default:
+		rest = rest[1:]
+	}
+	operand := strings.TrimSpace(rest)
+	if operand == "" {
+		return Predicate{}, fmt.Errorf("meta filter %q requires a value", value)
+	}
+
+	pred := Predicate{Kind: PredMeta, Key: key, Op: op, Text: operand}
+	if op != CompareEq || operand == FilterWildcard {
+		return pred, nil
+	}
+	if low, high, ok := strings.Cut(operand, ".."); ok {
+		low = strings.TrimSpace(low)
+		high = strings.TrimSpace(high)
+		if low == "" || high == "" {
+			return Predicate{}, fmt.Errorf("meta range %q requires both bounds", operand)
+		}
+		pred.Op = CompareRange
+		pred.Text = low
+		pred.Upper = high
+	}
+	return pred, nil
+}
+
 func (p *FilterTagPredicate) toPredicate() *Predicate {
 	if p == nil {
 		return nil
@@ -360,6 +643,9 @@ func (p *FilterTextPredicate) toPredicate() *Predicate {
 	if strings.EqualFold(value, "recent") {
 		return &Predicate{Kind: PredRecent, Text: ""}
 	}
+	if strings.EqualFold(value, filterWordAvailable) {
+		return &Predicate{Kind: PredAvailable}
+	}
 	if recentLimit, ok := strings.CutPrefix(strings.ToLower(value), "recent:"); ok {
 		return &Predicate{Kind: PredRecent, Text: strings.TrimSpace(recentLimit)}
 	}
@@ -428,6 +714,13 @@ func normalizeOperation(op Operation) (Operation, bool) {
 		if typed == nil {
 			return nil, false
 		}
+		if typed.Remove {
+			field := FieldProjects
+			if typed.Kind == TagContext {
+				field = FieldContexts
+			}
+			return RemoveOp{Field: field, Value: OpValue(typed.Value)}, true
+		}
 		return TagOp{Kind: typed.Kind, Value: typed.Value}, true
 	default:
 		return op, true
@@ -10,22 +10,34 @@ var dslLexer = lexer.MustStateful(lexer.Rules{
 		// Quoted strings first (highest priority)
 		{Name: "Quoted", Pattern: `"(?:[^"\\]|\\.)*"`},
 
+		// Regex literals for field predicates, e.g. title:~/^fix/
+		{Name: "Regex", Pattern: `~/(?:[^/\\]|\\.)*/`},
+
 		// Numeric hash IDs (must come before ProjectTag)
 		{Name: "HashNumber", Pattern: `#[0-9]+`},
 
+		// References to earlier results: ^2 is the command before last, %3
+		// the third row of the last result list
+		{Name: "ResultRef", Pattern: `\^[0-9]+`},
+		{Name: "RowRef", Pattern: `%[0-9]+`},
+
 		// Tags - project (#) and context (@)
 		{Name: "ProjectTag", Pattern: `#[a-zA-Z_][a-zA-Z0-9_-]*`},
 		{Name: "ContextTag", Pattern: `@[a-zA-Z_][a-zA-Z0-9_-]*`},
 
+		// Shell variables, e.g. $w; the shell expands them before parsing
+		{Name: "Variable", Pattern: `\$[a-zA-Z_][a-zA-Z0-9_]*`},
+
 		// Tag prefixes for interactive completion/highlighting
 		{Name: "ProjectTagPrefix", Pattern: `#`},
 		{Name: "ContextTagPrefix", Pattern: `@`},
+		{Name: "VariablePrefix", Pattern: `\$`},
 
 		// Field setters with colon (op starters) - MUST come before Ident
 		// These consume the field name and colon together
 		{
 			Name:    "SetField",
-			Pattern: `\b(title|notes|due|waiting|waiting-for|waiting_for|state|project|projects|context|contexts|meta|id|text)\b\s*:`,
+			Pattern: `\b(title|notes|due|waiting|waiting-for|waiting_for|until|state|project|projects|context|contexts|meta|id|text)\b\s*:`,
 		},
 		{
 			Name:    "AddField",
@@ -37,7 +49,7 @@ var dslLexer = lexer.MustStateful(lexer.Rules{
 		},
 		{
 			Name:    "ClearField",
-			Pattern: `!\s*\b(notes|due|waiting|waiting-for|waiting_for|projects|contexts|meta)\b`,
+			Pattern: `!\s*\b(notes|due|waiting|waiting-for|waiting_for|until|projects|contexts|meta)\b`,
 		},
 
 		// Clear op for non-field cases (just the ! symbol)
@@ -50,19 +62,26 @@ var dslLexer = lexer.MustStateful(lexer.Rules{
 		// Punctuation
 		{Name: "Colon", Pattern: `:`},
 		{Name: "Comma", Pattern: `,`},
+		{Name: "Compare", Pattern: `<=|>=|<|>|=`},
 		{Name: "LParen", Pattern: `\(`},
 		{Name: "RParen", Pattern: `\)`},
 
 		// Logical operators
 		{Name: "AndOp", Pattern: `&&`},
 		{Name: "OrOp", Pattern: `\|\|`},
+
+		// Statement separators: ";" chains commands, "|" pipes tasks on
+		{Name: "Semicolon", Pattern: `;`},
+		{Name: "Pipe", Pattern: `\|`},
 		{Name: "Star", Pattern: `\*`},
 
 		// In-progress quoted string support for interactive shell.
 		{Name: "QuoteStart", Pattern: `"`, Action: lexer.Push("String")},
 
-		// Identifiers and words (catch-all for regular words including alphanumeric)
-		{Name: "Ident", Pattern: `[a-zA-Z0-9_-]+`},
+		// Identifiers and words (catch-all for regular words including
+		// alphanumeric). # and @ inside a word, as in bob@example.com, are
+		// part of it; only a word that starts with them is a tag.
+		{Name: "Ident", Pattern: `[a-zA-Z0-9_.-]+(?:[#@][a-zA-Z0-9_.-]*)*`},
 
 		// Whitespace (elided)
 		{Name: "Whitespace", Pattern: `\s+`},
@@ -3,6 +3,8 @@ package nlp
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -10,44 +12,137 @@ import (
 type ParseOptions struct {
 	Mode Mode
 	Now  time.Time
+	// Templates names the templates "add from" can use. A line naming any
+	// other template adds a task titled by its words, e.g. "add from Dana".
+	Templates []string
 }
 
-// Parse parses the input string and returns a ParseResult.
+// Parse parses the input string and returns a ParseResult. The input must be
+// a single command; use ParseProgram for ";" and "|".
 func Parse(input string, opts ParseOptions) (ParseResult, error) {
-	if opts.Now.IsZero() {
-		opts.Now = time.Now()
-	}
-
-	root, err := dslParser.ParseString("", input)
+	root, result, err := parseRoot(input)
 	if err != nil {
+		return result, err
+	}
+	if len(root.Statements) != 1 || len(root.Statements[0].Stages) != 1 {
+		err = errors.New("expected a single command")
 		diagnostics := []Diagnostic{{
 			Severity: SeverityError,
-			Code:     "E_PARSE",
+			Code:     "E_PARSE_CHAIN",
 			Message:  err.Error(),
-			Hint:     "check command syntax and quoting",
+			Hint:     "\";\" and \"|\" are only supported in the shell",
+			Input:    input,
 		}}
-		return ParseResult{
+		return ParseResult{Intent: IntentUnknown, Diagnostics: diagnostics}, NewDiagnosticError(err, diagnostics)
+	}
+	return parseStage(input, root.Statements[0].Stages[0], false, opts)
+}
+
+// ParseProgram parses a line of one or more statements separated by ";",
+// each a pipeline of commands joined by "|". Commands after a "|" without an
+// explicit target act on the tasks of the stage before them.
+func ParseProgram(input string, opts ParseOptions) (Program, error) {
+	root, _, err := parseRoot(input)
+	if err != nil {
+		return Program{}, err
+	}
+
+	program := Program{Statements: make([]Pipeline, 0, len(root.Statements))}
+	for _, statement := range root.Statements {
+		pipeline := Pipeline{Stages: make([]PipelineStage, 0, len(statement.Stages))}
+		for i, stage := range statement.Stages {
+			result, stageErr := parseStage(input, stage, i > 0, opts)
+			if stageErr != nil {
+				return Program{}, stageErr
+			}
+			pipeline.Stages = append(pipeline.Stages, PipelineStage{
+				Input:  strings.TrimSpace(input[stage.Pos.Offset:stage.EndPos.Offset]),
+				Result: result,
+			})
+		}
+		program.Statements = append(program.Statements, pipeline)
+	}
+	return program, nil
+}
+
+func parseRoot(input string) (*Root, ParseResult, error) {
+	root, err := dslParser.ParseString("", maskNoteText(input))
+	if err != nil {
+		diagnostics := []Diagnostic{parseErrorDiagnostic(input, err)}
+		return nil, ParseResult{
 			Intent:      IntentUnknown,
 			Diagnostics: diagnostics,
-		}, NewDiagnosticError(err, diagnostics)
+		}, NewDiagnosticError(errors.New(diagnostics[0].Message), diagnostics)
+	}
+	if root == nil || len(root.Statements) == 0 {
+		return nil, ParseResult{Intent: IntentUnknown}, errors.New("empty parse result")
 	}
+	return root, ParseResult{}, nil
+}
 
-	if root == nil || root.Cmd == nil {
+// parseStage post-processes one pipeline stage. Diagnostics refer to the
+// whole input.
+func parseStage(input string, stage *stageNode, piped bool, opts ParseOptions) (ParseResult, error) {
+	if stage == nil || stage.Cmd == nil {
 		return ParseResult{Intent: IntentUnknown}, errors.New("empty parse result")
 	}
+	if tmpl, ok := stage.Cmd.(*TemplateCommand); ok && !knownTemplate(opts.Templates, tmpl.Name) {
+		cmd, err := parseCreateStage(input, stage)
+		if err != nil {
+			diagnostics := []Diagnostic{parseErrorDiagnostic(input, err)}
+			return ParseResult{Intent: IntentUnknown, Diagnostics: diagnostics},
+				NewDiagnosticError(errors.New(diagnostics[0].Message), diagnostics)
+		}
+		stage.Cmd = cmd
+	}
+	if piped {
+		if err := pipeCommand(stage.Cmd); err != nil {
+			diagnostics := []Diagnostic{{
+				Severity: SeverityError,
+				Code:     "E_PARSE_PIPE",
+				Message:  err.Error(),
+				Hint:     "pipe filter results into find, show, set, done, undo, snooze or rm",
+				Input:    input,
+			}}
+			span := NewSpan(input, stage.Pos.Offset, stage.EndPos.Offset)
+			diagnostics[0].Span = &span
+			return ParseResult{Intent: IntentUnknown, Diagnostics: diagnostics}, NewDiagnosticError(err, diagnostics)
+		}
+	}
 
-	intent, cmdResult, postErr := postProcess(root.Cmd)
+	intent, cmdResult, postErr := postProcess(stage.Cmd)
 	if postErr != nil {
-		diagnostics := []Diagnostic{{
+		diag := Diagnostic{
 			Severity: SeverityError,
 			Code:     "E_PARSE",
 			Message:  postErr.Error(),
 			Hint:     "review command fields and values",
-		}}
+			Input:    input,
+		}
+		var tokenErr TokenError
+		if errors.As(postErr, &tokenErr) {
+			diag = locateToken(input, tokenErr, diag)
+		}
+		diagnostics := []Diagnostic{diag}
 		return ParseResult{Intent: intent, Command: cmdResult, Diagnostics: diagnostics},
 			NewDiagnosticError(postErr, diagnostics)
 	}
 
+	if cmd, ok := cmdResult.(*NoteCommand); ok {
+		cmd.Text = noteText(input[cmd.textStart:cmd.textEnd])
+	}
+	if cmd, ok := cmdResult.(*ContextCommand); ok && cmd.Filter != nil {
+		_, text, _ := strings.Cut(strings.TrimSpace(input[cmd.Pos.Offset:cmd.EndPos.Offset]), " ")
+		cmd.FilterText = strings.TrimSpace(text)
+	}
+
+	if intent == IntentFilter || intent == IntentContext {
+		if diag, ok := unknownFilterField(input, stage.Pos.Offset, stage.EndPos.Offset); ok {
+			return ParseResult{Intent: intent, Command: cmdResult, Diagnostics: []Diagnostic{diag}},
+				NewDiagnosticError(errors.New(diag.Message), []Diagnostic{diag})
+		}
+	}
+
 	want := IntentUnknown
 	switch opts.Mode {
 	case ModeAuto:
@@ -63,12 +158,13 @@ func Parse(input string, opts ParseOptions) (ParseResult, error) {
 		want = IntentContext
 	}
 	if want != IntentUnknown && intent != want {
-		err = errors.New("command does not match parse mode")
+		err := errors.New("command does not match parse mode")
 		diagnostics := []Diagnostic{{
 			Severity: SeverityError,
 			Code:     "E_PARSE_MODE",
 			Message:  err.Error(),
 			Hint:     "use a command valid for this parsing mode",
+			Input:    input,
 		}}
 		return ParseResult{Intent: intent, Command: cmdResult, Diagnostics: diagnostics},
 			NewDiagnosticError(err, diagnostics)
@@ -77,9 +173,25 @@ func Parse(input string, opts ParseOptions) (ParseResult, error) {
 	return ParseResult{Intent: intent, Command: cmdResult}, nil
 }
 
+func knownTemplate(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(known string) bool { return strings.EqualFold(known, name) })
+}
+
+// parseCreateStage re-reads stage as a CreateCommand. The input before the
+// stage is blanked rather than cut so token offsets still refer to input.
+func parseCreateStage(input string, stage *stageNode) (Command, error) {
+	text := strings.Repeat(" ", stage.Pos.Offset) + input[stage.Pos.Offset:stage.EndPos.Offset]
+	root, err := createParser.ParseString("", text)
+	if err != nil {
+		return nil, err
+	}
+	return root.Statements[0].Stages[0].Cmd, nil
+}
+
 // Parser interface for dependency injection.
 type Parser interface {
 	Parse(ctx context.Context, input string, opts ParseOptions) (ParseResult, error)
+	ParseProgram(ctx context.Context, input string, opts ParseOptions) (Program, error)
 }
 
 type defaultParser struct{}
@@ -94,6 +206,11 @@ func (defaultParser) Parse(_ context.Context, input string, opts ParseOptions) (
 	return Parse(input, opts)
 }
 
+// ParseProgram implements the Parser interface.
+func (defaultParser) ParseProgram(_ context.Context, input string, opts ParseOptions) (Program, error) {
+	return ParseProgram(input, opts)
+}
+
 func postProcess(cmd Command) (Intent, Command, error) {
 	switch typed := cmd.(type) {
 	case *CreateCommand:
@@ -102,7 +219,9 @@ func postProcess(cmd Command) (Intent, Command, error) {
 		}
 		return IntentCreate, typed, nil
 	case *UpdateCommand:
-		typed.postProcess()
+		if err := typed.postProcess(); err != nil {
+			return IntentUpdate, typed, err
+		}
 		return IntentUpdate, typed, nil
 	case *FilterCommand:
 		if err := typed.postProcess(); err != nil {
@@ -124,6 +243,25 @@ func postProcess(cmd Command) (Intent, Command, error) {
 			return IntentLog, typed, err
 		}
 		return IntentLog, typed, nil
+	case *DoneCommand:
+		typed.Target = defaultTarget(typed.Target)
+		return IntentDone, typed, nil
+	case *UndoCommand:
+		typed.Target = defaultTarget(typed.Target)
+		return IntentUndo, typed, nil
+	case *DeleteCommand:
+		typed.Target = defaultTarget(typed.Target)
+		return IntentDelete, typed, nil
+	case *ShowCommand:
+		typed.Target = defaultTarget(typed.Target)
+		return IntentShow, typed, nil
+	case *SnoozeCommand:
+		typed.Target = defaultTarget(typed.Target)
+		return IntentSnooze, typed, nil
+	case *NoteCommand:
+		return IntentNote, typed, nil
+	case *TemplateCommand:
+		return IntentTemplate, typed, nil
 	default:
 		return IntentUnknown, cmd, errors.New("unknown command type")
 	}
@@ -447,6 +447,110 @@ func TestParseUpdate_Targets(t *testing.T) {
 	}
 }
 
+func TestParseUpdate_MultipleTargets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantKind nlp.TargetKind
+		wantIDs  []int64
+	}{
+		{name: "comma list", input: "set 3,5,9 @phone", wantKind: nlp.TargetIDs, wantIDs: []int64{3, 5, 9}},
+		{name: "hash list", input: "set #3, #5 state:now", wantKind: nlp.TargetIDs, wantIDs: []int64{3, 5}},
+		{name: "range", input: "set 10-14 state:later", wantKind: nlp.TargetIDs, wantIDs: []int64{10, 11, 12, 13, 14}},
+		{name: "hash range", input: "set #10-12 state:later", wantKind: nlp.TargetIDs, wantIDs: []int64{10, 11, 12}},
+		{name: "hash range both ends", input: "set #10-#11 state:later", wantKind: nlp.TargetIDs, wantIDs: []int64{10, 11}},
+		{name: "list with range", input: "set 1,4-5,4 state:now", wantKind: nlp.TargetIDs, wantIDs: []int64{1, 4, 5}},
+		{name: "single id range", input: "set 7-7 state:now", wantKind: nlp.TargetID},
+		{name: "those", input: "set those state:later", wantKind: nlp.TargetLast},
+		{name: "them", input: "edit them #review", wantKind: nlp.TargetLast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "parse error")
+			cmd, ok := result.Command.(*nlp.UpdateCommand)
+			require.True(t, ok, "command type should be UpdateCommand, got %T", result.Command)
+			require.NotNil(t, cmd.Target, "target is nil")
+			assert.Equal(t, tt.wantKind, cmd.Target.Kind, "target kind mismatch")
+			assert.Equal(t, tt.wantIDs, cmd.Target.IDs, "target ids mismatch")
+			assert.NotEmpty(t, cmd.Ops, "ops should follow the target")
+		})
+	}
+}
+
+func TestParseUpdate_MultipleTargetErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"set 3, state:now",
+		"set 3,banana state:now",
+		"set 14-10 state:now",
+		"set 0-3 state:now",
+		"set 1-5000 state:now",
+	} {
+		_, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.Error(t, err, "expected parse error for %q", input)
+	}
+}
+
+func TestParseUpdate_WhereTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantExpr nlp.FilterExpr
+		wantOps  int
+	}{
+		{
+			name:     "tag filter",
+			input:    "set where #x state:later",
+			wantExpr: nlp.Predicate{Kind: nlp.PredProject, Text: "x"},
+			wantOps:  1,
+		},
+		{
+			name:  "parenthesized filter",
+			input: "set where (#old && state:later) state:now #new",
+			wantExpr: nlp.FilterBinary{
+				Op:    nlp.FilterAnd,
+				Left:  nlp.Predicate{Kind: nlp.PredProject, Text: "old"},
+				Right: nlp.Predicate{Kind: nlp.PredState, Text: "later"},
+			},
+			wantOps: 2,
+		},
+		{
+			name:     "negated filter",
+			input:    "edit where not @phone +context:desk",
+			wantExpr: nlp.FilterNot{Expr: nlp.Predicate{Kind: nlp.PredContext, Text: "phone"}},
+			wantOps:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "parse error")
+			cmd, ok := result.Command.(*nlp.UpdateCommand)
+			require.True(t, ok, "command type should be UpdateCommand, got %T", result.Command)
+			assert.Nil(t, cmd.Target, "target should be unset")
+			assert.Equal(t, tt.wantExpr, cmd.WhereExpr, "where expr mismatch")
+			assert.Len(t, cmd.Ops, tt.wantOps, "ops count mismatch")
+		})
+	}
+}
+
+func TestParseUpdate_WhereRequiresFilter(t *testing.T) {
+	t.Parallel()
+
+	_, err := nlp.Parse(`set where`, nlp.ParseOptions{})
+	require.Error(t, err, "expected parse error for missing where filter")
+}
+
 func TestParseUpdate_SetOperations(t *testing.T) {
 	t.Parallel()
 
@@ -746,14 +850,14 @@ func TestParseFilter_Predicates(t *testing.T) {
 			wantText: "42",
 		},
 		{
-			name:     "show numeric is id lookup",
-			input:    "show 3",
+			name:     "find numeric is id lookup",
+			input:    "find 3",
 			wantKind: nlp.PredID,
 			wantText: "3",
 		},
 		{
-			name:     "show hash numeric is id lookup",
-			input:    "show #3",
+			name:     "find hash numeric is id lookup",
+			input:    "find #3",
 			wantKind: nlp.PredID,
 			wantText: "3",
 		},
@@ -769,6 +873,12 @@ func TestParseFilter_Predicates(t *testing.T) {
 			wantKind: nlp.PredRecent,
 			wantText: "10",
 		},
+		{
+			name:     "available word",
+			input:    "find Available",
+			wantKind: nlp.PredAvailable,
+			wantText: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1093,6 +1203,279 @@ func TestParseFilterCommandAllowsWildcardSetPredicate(t *testing.T) {
 	assert.Equal(t, nlp.FilterWildcard, pred.Text, "due predicate wildcard mismatch")
 }
 
+func TestParseFilterMetaPredicates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  nlp.Predicate
+	}{
+		{
+			name:  "equality",
+			input: "find meta:size=m",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "size", Op: nlp.CompareEq, Text: "m"},
+		},
+		{
+			name:  "colon equality",
+			input: "find meta:size:m",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "size", Op: nlp.CompareEq, Text: "m"},
+		},
+		{
+			name:  "presence wildcard",
+			input: "find meta:points:*",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Text: nlp.FilterWildcard},
+		},
+		{
+			name:  "less than",
+			input: "find meta:points<5",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareLt, Text: "5"},
+		},
+		{
+			name:  "greater or equal with spaces",
+			input: "find meta:points >= 2.5",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareGte, Text: "2.5"},
+		},
+		{
+			name:  "range",
+			input: "find meta:points=3..8",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareRange, Text: "3", Upper: "8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "Parse error")
+			cmd, ok := result.Command.(*nlp.FilterCommand)
+			require.True(t, ok, "command type should be FilterCommand, got %T", result.Command)
+			assert.Equal(t, tt.want, cmd.Expr, "meta predicate mismatch")
+		})
+	}
+}
+
+func TestParseFilterFollowUpPredicates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  nlp.FilterExpr
+	}{
+		{
+			name:  "date",
+			input: "find until:friday",
+			want:  nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareEq, Text: "friday"},
+		},
+		{
+			name:  "on or before",
+			input: "find until:<=today",
+			want:  nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareLte, Text: "today"},
+		},
+		{
+			name:  "after",
+			input: "find until:>2026-03-01",
+			want:  nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareGt, Text: "2026-03-01"},
+		},
+		{
+			name:  "any",
+			input: "find until:*",
+			want:  nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareEq, Text: nlp.FilterWildcard},
+		},
+		{
+			name:  "none",
+			input: "find !until",
+			want:  nlp.FilterNot{Expr: nlp.Predicate{Kind: nlp.PredFollowUp, Text: nlp.FilterWildcard}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "Parse error")
+			cmd, ok := result.Command.(*nlp.FilterCommand)
+			require.True(t, ok, "command type should be FilterCommand, got %T", result.Command)
+			assert.Equal(t, tt.want, cmd.Expr, "follow-up predicate mismatch")
+		})
+	}
+}
+
+func TestParseFilterTextFieldPredicates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  nlp.Predicate
+	}{
+		{
+			name:  "title substring",
+			input: "find title:report",
+			want:  nlp.Predicate{Kind: nlp.PredTitle, Text: "report", Op: nlp.CompareContains},
+		},
+		{
+			name:  "title exact",
+			input: `find title:="Weekly report"`,
+			want:  nlp.Predicate{Kind: nlp.PredTitle, Text: "Weekly report", Op: nlp.CompareEq},
+		},
+		{
+			name:  "notes regex",
+			input: `find notes:~/^draft\s+v[0-9]/`,
+			want:  nlp.Predicate{Kind: nlp.PredNotes, Text: `^draft\s+v[0-9]`, Op: nlp.CompareRegex},
+		},
+		{
+			name:  "waiting substring",
+			input: "find waiting:alice",
+			want:  nlp.Predicate{Kind: nlp.PredWaiting, Text: "alice", Op: nlp.CompareContains},
+		},
+		{
+			name:  "waiting non-empty",
+			input: "find waiting:*",
+			want:  nlp.Predicate{Kind: nlp.PredWaiting, Text: nlp.FilterWildcard},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "Parse error")
+			cmd, ok := result.Command.(*nlp.FilterCommand)
+			require.True(t, ok, "command type should be FilterCommand, got %T", result.Command)
+			assert.Equal(t, tt.want, cmd.Expr, "predicate mismatch")
+		})
+	}
+}
+
+func TestParseFilterSortClause(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantExpr nlp.FilterExpr
+		wantSort []nlp.SortKey
+	}{
+		{
+			name:     "sort colon",
+			input:    "find #work sort:due,-updated",
+			wantExpr: nlp.Predicate{Kind: nlp.PredProject, Text: "work"},
+			wantSort: []nlp.SortKey{{Field: nlp.SortFieldDue}, {Field: nlp.SortFieldUpdated, Desc: true}},
+		},
+		{
+			name:     "order by",
+			input:    "find state:now order by due, -meta:points",
+			wantExpr: nlp.Predicate{Kind: nlp.PredState, Text: "now"},
+			wantSort: []nlp.SortKey{{Field: nlp.SortFieldDue}, {Field: nlp.SortFieldMeta, Key: "points", Desc: true}},
+		},
+		{
+			name:     "order by with direction",
+			input:    "find state:now order by title desc, meta:points asc",
+			wantExpr: nlp.Predicate{Kind: nlp.PredState, Text: "now"},
+			wantSort: []nlp.SortKey{{Field: nlp.SortFieldTitle, Desc: true}, {Field: nlp.SortFieldMeta, Key: "points"}},
+		},
+		{
+			name:     "sort clause only",
+			input:    "find order by title desc",
+			wantSort: []nlp.SortKey{{Field: nlp.SortFieldTitle, Desc: true}},
+		},
+		{
+			name:     "sort colon only",
+			input:    "find sort:-due",
+			wantSort: []nlp.SortKey{{Field: nlp.SortFieldDue, Desc: true}},
+		},
+		{
+			name:     "sort as plain text",
+			input:    "find sort the laundry",
+			wantExpr: nlp.Predicate{Kind: nlp.PredText, Text: "sort the laundry"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "Parse error")
+			cmd, ok := result.Command.(*nlp.FilterCommand)
+			require.True(t, ok, "command type should be FilterCommand, got %T", result.Command)
+			assert.Equal(t, tt.wantExpr, cmd.Expr, "expr mismatch")
+			assert.Equal(t, tt.wantSort, cmd.SortKeys, "sort keys mismatch")
+		})
+	}
+}
+
+func TestParseFilterSortClauseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"find state:now order by",
+		"find state:now sort:bogus",
+		"find order by -due desc",
+		"find",
+	} {
+		_, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.Error(t, err, "Parse(%q) should fail", input)
+	}
+}
+
+func TestParseFilterAbsentPredicates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  nlp.Predicate
+	}{
+		{
+			name:  "meta key",
+			input: "find !meta:points",
+			want:  nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Text: nlp.FilterWildcard},
+		},
+		{
+			name:  "due",
+			input: "find !due",
+			want:  nlp.Predicate{Kind: nlp.PredDue, Text: nlp.FilterWildcard},
+		},
+		{
+			name:  "projects",
+			input: "find !projects",
+			want:  nlp.Predicate{Kind: nlp.PredProject, Text: nlp.FilterWildcard},
+		},
+		{
+			name:  "notes",
+			input: "find !notes",
+			want:  nlp.Predicate{Kind: nlp.PredNotes, Text: nlp.FilterWildcard},
+		},
+		{
+			name:  "waiting",
+			input: "find !waiting",
+			want:  nlp.Predicate{Kind: nlp.PredWaiting, Text: nlp.FilterWildcard},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "Parse error")
+			cmd, ok := result.Command.(*nlp.FilterCommand)
+			require.True(t, ok, "command type should be FilterCommand, got %T", result.Command)
+			assert.Equal(t, nlp.FilterNot{Expr: tt.want}, cmd.Expr, "absent predicate mismatch")
+		})
+	}
+}
+
+func TestParseFilterMetaPredicateErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"find meta:=3", "find meta:points<", "find meta:points=3.."} {
+		_, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.Error(t, err, "Parse(%q) should fail", input)
+	}
+}
+
 func TestParseFilterCommandMissingValueReturnsError(t *testing.T) {
 	t.Parallel()
 
@@ -1125,6 +1508,7 @@ func TestParseViewCommand(t *testing.T) {
 		{name: "help view", input: "view", wantTarget: ""},
 		{name: "short alias", input: "view i", wantTarget: "inbox"},
 		{name: "calendar alias", input: "view today", wantTarget: "calendar"},
+		{name: "user-defined view", input: "view Focus", wantTarget: "focus"},
 	}
 
 	for _, tt := range tests {
@@ -1186,11 +1570,41 @@ func TestParseContextCommand(t *testing.T) {
 func TestParseViewAndContextErrors(t *testing.T) {
 	t.Parallel()
 
-	_, err := nlp.Parse("view maybe", nlp.ParseOptions{})
+	_, err := nlp.Parse("view #work", nlp.ParseOptions{})
 	require.Error(t, err, "expected parse error for invalid view")
 
 	_, err = nlp.Parse("context maybe", nlp.ParseOptions{})
 	require.Error(t, err, "expected parse error for invalid context argument")
+
+	_, err = nlp.Parse("context stat:now", nlp.ParseOptions{})
+	require.ErrorContains(t, err, "stat", "expected unknown field error in context filter")
+}
+
+func TestParseContextFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "context state:now && due:today", want: "state:now && due:today"},
+		{input: "context #work state:now", want: "#work state:now"},
+		{input: "context not @phone", want: "not @phone"},
+		{input: "context text:report", want: "text:report"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "Parse error")
+			require.Equal(t, nlp.IntentContext, result.Intent, "intent mismatch")
+			cmd, ok := result.Command.(*nlp.ContextCommand)
+			require.True(t, ok, "command type should be ContextCommand, got %T", result.Command)
+			assert.Nil(t, cmd.Arg, "filter contexts have no tag argument")
+			require.NotNil(t, cmd.Filter, "context filter should be parsed")
+			assert.Equal(t, tt.want, cmd.FilterText, "filter text mismatch")
+		})
+	}
 }
 
 func TestParseModeViewAndContext(t *testing.T) {
@@ -1211,3 +1625,389 @@ func TestParseInvalidUpdateTarget(t *testing.T) {
 	_, err := nlp.Parse(`set banana state:now`, nlp.ParseOptions{})
 	require.Error(t, err, "expected parse error for invalid update target")
 }
+
+func TestParseTaskActionVerbs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      string
+		wantIntent nlp.Intent
+		wantKind   nlp.TargetKind
+		wantID     int64
+		wantIDs    []int64
+	}{
+		{name: "done id", input: "done 3", wantIntent: nlp.IntentDone, wantKind: nlp.TargetID, wantID: 3},
+		{name: "complete synonym", input: "complete #4", wantIntent: nlp.IntentDone, wantKind: nlp.TargetID, wantID: 4},
+		{
+			name:       "finish list",
+			input:      "finish 3,5",
+			wantIntent: nlp.IntentDone,
+			wantKind:   nlp.TargetIDs,
+			wantIDs:    []int64{3, 5},
+		},
+		{name: "done defaults to selected", input: "done", wantIntent: nlp.IntentDone, wantKind: nlp.TargetSelected},
+		{name: "undo those", input: "undo those", wantIntent: nlp.IntentUndo, wantKind: nlp.TargetLast},
+		{name: "reopen synonym", input: "reopen 8", wantIntent: nlp.IntentUndo, wantKind: nlp.TargetID, wantID: 8},
+		{name: "rm pronoun", input: "rm it", wantIntent: nlp.IntentDelete, wantKind: nlp.TargetRecent},
+		{
+			name:       "delete range",
+			input:      "delete 2-4",
+			wantIntent: nlp.IntentDelete,
+			wantKind:   nlp.TargetIDs,
+			wantIDs:    []int64{2, 3, 4},
+		},
+		{name: "show id", input: "show 3", wantIntent: nlp.IntentShow, wantKind: nlp.TargetID, wantID: 3},
+		{name: "show selected", input: "show selected", wantIntent: nlp.IntentShow, wantKind: nlp.TargetSelected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "parse error")
+			require.Equal(t, tt.wantIntent, result.Intent, "intent mismatch")
+
+			var target *nlp.TargetRef
+			switch cmd := result.Command.(type) {
+			case *nlp.DoneCommand:
+				target = cmd.Target
+			case *nlp.UndoCommand:
+				target = cmd.Target
+			case *nlp.DeleteCommand:
+				target = cmd.Target
+			case *nlp.ShowCommand:
+				target = cmd.Target
+			default:
+				t.Fatalf("unexpected command type %T", result.Command)
+			}
+			require.NotNil(t, target, "target is nil")
+			assert.Equal(t, tt.wantKind, target.Kind, "target kind mismatch")
+			assert.Equal(t, tt.wantID, target.ID, "target id mismatch")
+			assert.Equal(t, tt.wantIDs, target.IDs, "target ids mismatch")
+		})
+	}
+}
+
+func TestParseReferences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		wantKind nlp.TargetKind
+		wantN    int
+	}{
+		{input: "done it", wantKind: nlp.TargetRecent, wantN: 1},
+		{input: "done This", wantKind: nlp.TargetRecent, wantN: 1},
+		{input: "done last", wantKind: nlp.TargetRecent, wantN: 1},
+		{input: "done $last", wantKind: nlp.TargetRecent, wantN: 1},
+		{input: "done that", wantKind: nlp.TargetRecent, wantN: 2},
+		{input: "done selected", wantKind: nlp.TargetSelected},
+		{input: "done ^2", wantKind: nlp.TargetResult, wantN: 2},
+		{input: "done %3", wantKind: nlp.TargetRow, wantN: 3},
+	}
+
+	for _, tt := range tests {
+		result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+		require.NoError(t, err, "Parse(%q) error", tt.input)
+		cmd, ok := result.Command.(*nlp.DoneCommand)
+		require.True(t, ok, "command type should be DoneCommand, got %T", result.Command)
+		assert.Equal(t, tt.wantKind, cmd.Target.Kind, "target kind mismatch for %q", tt.input)
+		assert.Equal(t, tt.wantN, cmd.Target.N, "target position mismatch for %q", tt.input)
+	}
+}
+
+func TestParseReferenceWordsInTitlesStayText(t *testing.T) {
+	t.Parallel()
+
+	result, err := nlp.Parse(`add "fix it later" that selected`, nlp.ParseOptions{})
+	require.NoError(t, err, "Parse error")
+	cmd, ok := result.Command.(*nlp.CreateCommand)
+	require.True(t, ok, "command type should be CreateCommand, got %T", result.Command)
+	assert.Equal(t, "fix it later that selected", cmd.Title, "title mismatch")
+}
+
+func TestParseShowWithFilterFallsBackToFilter(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"show #work", "show 3 and state:now", "show state:now"} {
+		result, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.NoError(t, err, "parse error for %q", input)
+		assert.Equal(t, nlp.IntentFilter, result.Intent, "intent mismatch for %q", input)
+	}
+}
+
+func TestParseTaskActionVerbErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"finish banana", "done 3 state:now", "rm 5-2"} {
+		_, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.Error(t, err, "expected parse error for %q", input)
+	}
+}
+
+func TestParseSnooze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input  string
+		target nlp.TargetRef
+		until  string
+	}{
+		{input: "snooze it until friday", target: nlp.TargetRef{Kind: nlp.TargetRecent, N: 1}, until: "friday"},
+		{input: "snooze 12 until next monday", target: nlp.TargetRef{Kind: nlp.TargetID, ID: 12}, until: "next monday"},
+		{input: "snooze until tomorrow 9am", target: nlp.TargetRef{Kind: nlp.TargetSelected}, until: "tomorrow 9am"},
+		{
+			input:  "Snooze 3,4 UNTIL 2026-11-02 14:30",
+			target: nlp.TargetRef{Kind: nlp.TargetIDs, IDs: []int64{3, 4}},
+			until:  "2026-11-02 14:30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "parse error for %q", tt.input)
+			assert.Equal(t, nlp.IntentSnooze, result.Intent, "intent mismatch")
+			cmd, ok := result.Command.(*nlp.SnoozeCommand)
+			require.True(t, ok, "command type should be SnoozeCommand, got %T", result.Command)
+			require.NotNil(t, cmd.Target, "target should default to the selection")
+			assert.Equal(t, tt.target, *cmd.Target, "target mismatch")
+			assert.Equal(t, tt.until, cmd.Until, "until mismatch")
+		})
+	}
+
+	for _, input := range []string{"snooze", "snooze it", "snooze 3 friday", "snooze it until"} {
+		_, err := nlp.Parse(input, nlp.ParseOptions{})
+		require.Error(t, err, "expected parse error for %q", input)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	t.Parallel()
+
+	opts := nlp.ParseOptions{Templates: []string{"onboarding", "release"}}
+	result, err := nlp.Parse(`add from Onboarding Name:Sam start:"next monday"`, opts)
+	require.NoError(t, err, "parse error")
+	assert.Equal(t, nlp.IntentTemplate, result.Intent, "intent mismatch")
+	cmd, ok := result.Command.(*nlp.TemplateCommand)
+	require.True(t, ok, "command type should be TemplateCommand, got %T", result.Command)
+	assert.Equal(t, "onboarding", cmd.Name, "name mismatch")
+	assert.Equal(t, map[string]string{"name": "Sam", "start": "next monday"}, cmd.Vars, "vars mismatch")
+
+	result, err = nlp.Parse("new from release", opts)
+	require.NoError(t, err, "parse error")
+	assert.Equal(t, nlp.IntentTemplate, result.Intent, "a template without vars should parse")
+
+	for _, input := range []string{"add from home #errands", "add from the store", "add from trip due:friday"} {
+		result, err = nlp.Parse(input, opts)
+		require.NoError(t, err, "parse error for %q", input)
+		assert.Equal(t, nlp.IntentCreate, result.Intent, "%q should create a task", input)
+	}
+
+	result, err = nlp.Parse("add from Dana name:Sam", opts)
+	require.ErrorContains(t, err, `unknown field "name:"`, "an unknown template should not take template vars")
+	result, err = nlp.Parse("add from Dana", opts)
+	require.NoError(t, err, "parse error")
+	create, ok := result.Command.(*nlp.CreateCommand)
+	require.True(t, ok, "a line naming no template should create a task, got %T", result.Command)
+	assert.Equal(t, "from Dana", create.Title, "title mismatch")
+}
+
+func TestParseNote(t *testing.T) {
+	t.Parallel()
+
+	result, err := nlp.Parse("note 12 called vendor, quote due 9:30 #work @bob", nlp.ParseOptions{})
+	require.NoError(t, err, "parse error")
+	assert.Equal(t, nlp.IntentNote, result.Intent, "intent mismatch")
+	cmd, ok := result.Command.(*nlp.NoteCommand)
+	require.True(t, ok, "command type should be NoteCommand, got %T", result.Command)
+	require.NotNil(t, cmd.Target, "target should be set")
+	assert.Equal(t, nlp.TargetRef{Kind: nlp.TargetID, ID: 12}, *cmd.Target, "target mismatch")
+	assert.Equal(t, "called vendor, quote due 9:30 #work @bob", cmd.Text, "text mismatch")
+
+	result, err = nlp.Parse(`annotate it "waiting; again"`, nlp.ParseOptions{})
+	require.NoError(t, err, "parse error")
+	cmd, ok = result.Command.(*nlp.NoteCommand)
+	require.True(t, ok, "command type should be NoteCommand, got %T", result.Command)
+	assert.Equal(t, nlp.TargetRecent, cmd.Target.Kind, "target kind mismatch")
+	assert.Equal(t, "waiting; again", cmd.Text, "quoted text mismatch")
+
+	for _, input := range []string{"note", "note it", `note "no target"`} {
+		_, err = nlp.Parse(input, nlp.ParseOptions{})
+		require.Error(t, err, "%q should need a target and text", input)
+	}
+}
+
+func TestParseNoteKeepsTextAsTyped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		text  string
+	}{
+		{input: "note it re: vendor", text: "re: vendor"},
+		{input: "note 3 price is $40 (maybe)", text: "price is $40 (maybe)"},
+		{input: "note it it's done!", text: "it's done!"},
+		{input: "note 3, 5 don't   forget", text: "don't   forget"},
+		{input: "annotate #7 see https://example.com/a?b=1&c=2#top", text: "see https://example.com/a?b=1&c=2#top"},
+		{input: `note it said "hi; there" -- ok?`, text: `said "hi; there" -- ok?`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "parse error")
+			cmd, ok := result.Command.(*nlp.NoteCommand)
+			require.True(t, ok, "command type should be NoteCommand, got %T", result.Command)
+			assert.Equal(t, tt.text, cmd.Text, "text mismatch")
+		})
+	}
+
+	program, err := nlp.ParseProgram("note it don't wait; find #work | done", nlp.ParseOptions{})
+	require.NoError(t, err, "ParseProgram error")
+	require.Len(t, program.Statements, 2, "statement count mismatch")
+	cmd, ok := program.Statements[0].Stages[0].Result.Command.(*nlp.NoteCommand)
+	require.True(t, ok, "first statement should be a note")
+	assert.Equal(t, "don't wait", cmd.Text, "text should end at ;")
+	assert.Len(t, program.Statements[1].Stages, 2, "the statement after a note should still pipe")
+}
+
+func TestParseCreateTitleKeepsWordsAsTyped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		title    string
+		contexts []string
+	}{
+		{input: "add email bob@example.com about it", title: "email bob@example.com about it"},
+		{input: "add Buy 2 apples, 3 pears", title: "Buy 2 apples, 3 pears"},
+		{input: "add learn c# and f#", title: "learn c# and f#"},
+		{input: "add ping sam@corp.io @phone", title: "ping sam@corp.io", contexts: []string{"phone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := nlp.Parse(tt.input, nlp.ParseOptions{})
+			require.NoError(t, err, "parse error")
+			cmd, ok := result.Command.(*nlp.CreateCommand)
+			require.True(t, ok, "command type should be CreateCommand, got %T", result.Command)
+			assert.Equal(t, tt.title, cmd.Title, "title mismatch")
+			var contexts []string
+			for _, op := range cmd.Ops {
+				if tag, isTag := op.(nlp.TagOp); isTag && tag.Kind == nlp.TagContext {
+					contexts = append(contexts, tag.Value)
+				}
+			}
+			assert.Equal(t, tt.contexts, contexts, "contexts mismatch")
+		})
+	}
+}
+
+func TestParseCreateTitleWordsTrackQuotes(t *testing.T) {
+	t.Parallel()
+
+	result, err := nlp.Parse(`add watch "tomorrow never dies" tonight`, nlp.ParseOptions{})
+	require.NoError(t, err, "parse error")
+	cmd, ok := result.Command.(*nlp.CreateCommand)
+	require.True(t, ok, "command type should be CreateCommand, got %T", result.Command)
+
+	assert.Equal(t, "watch tomorrow never dies tonight", cmd.Title, "title mismatch")
+	assert.Equal(t, []nlp.TitleWord{
+		{Text: "watch"},
+		{Text: "tomorrow never dies", Quoted: true},
+		{Text: "tonight"},
+	}, cmd.Words, "words mismatch")
+}
+
+func TestParseUpdate_SignedTagShorthand(t *testing.T) {
+	t.Parallel()
+
+	result, err := nlp.Parse("set 4 +#work -#home -@phone +@desk", nlp.ParseOptions{})
+	require.NoError(t, err, "parse error")
+	cmd, ok := result.Command.(*nlp.UpdateCommand)
+	require.True(t, ok, "command type should be UpdateCommand, got %T", result.Command)
+
+	assert.Equal(t, []nlp.Operation{
+		nlp.TagOp{Kind: nlp.TagProject, Value: "work"},
+		nlp.RemoveOp{Field: nlp.FieldProjects, Value: "home"},
+		nlp.RemoveOp{Field: nlp.FieldContexts, Value: "phone"},
+		nlp.TagOp{Kind: nlp.TagContext, Value: "desk"},
+	}, cmd.Ops, "ops mismatch")
+}
+
+func TestParseProgramStatementsAndPipelines(t *testing.T) {
+	t.Parallel()
+
+	program, err := nlp.ParseProgram(
+		"find #errands && state:inbox | set state:now @out; add call mom | done",
+		nlp.ParseOptions{},
+	)
+	require.NoError(t, err, "ParseProgram error")
+	require.Len(t, program.Statements, 2, "statement count mismatch")
+	assert.False(t, program.Single(), "chained line should not be single")
+
+	first := program.Statements[0].Stages
+	require.Len(t, first, 2, "first pipeline stage count mismatch")
+	assert.Equal(t, "find #errands && state:inbox", first[0].Input, "first stage input mismatch")
+	assert.Equal(t, "set state:now @out", first[1].Input, "second stage input mismatch")
+	assert.Equal(t, nlp.IntentFilter, first[0].Result.Intent, "first stage intent mismatch")
+
+	update, ok := first[1].Result.Command.(*nlp.UpdateCommand)
+	require.True(t, ok, "piped stage should be UpdateCommand, got %T", first[1].Result.Command)
+	require.NotNil(t, update.Target, "piped update target should be set")
+	assert.Equal(t, nlp.TargetLast, update.Target.Kind, "piped update should target previous tasks")
+
+	second := program.Statements[1].Stages
+	require.Len(t, second, 2, "second pipeline stage count mismatch")
+	assert.Equal(t, nlp.IntentCreate, second[0].Result.Intent, "create stage intent mismatch")
+	done, ok := second[1].Result.Command.(*nlp.DoneCommand)
+	require.True(t, ok, "piped stage should be DoneCommand, got %T", second[1].Result.Command)
+	assert.Equal(t, nlp.TargetLast, done.Target.Kind, "piped done should target previous tasks")
+}
+
+func TestParseProgramPipedStages(t *testing.T) {
+	t.Parallel()
+
+	program, err := nlp.ParseProgram("find #work | find state:now | show | set 5 state:later", nlp.ParseOptions{})
+	require.NoError(t, err, "ParseProgram error")
+	stages := program.Statements[0].Stages
+	require.Len(t, stages, 4, "stage count mismatch")
+
+	filter, ok := stages[1].Result.Command.(*nlp.FilterCommand)
+	require.True(t, ok, "piped find should be FilterCommand, got %T", stages[1].Result.Command)
+	assert.True(t, filter.Piped, "piped filter should be marked")
+	first, ok := stages[0].Result.Command.(*nlp.FilterCommand)
+	require.True(t, ok, "first find should be FilterCommand, got %T", stages[0].Result.Command)
+	assert.False(t, first.Piped, "first filter should not be piped")
+
+	show, ok := stages[2].Result.Command.(*nlp.ShowCommand)
+	require.True(t, ok, "bare show should be ShowCommand, got %T", stages[2].Result.Command)
+	assert.Equal(t, nlp.TargetLast, show.Target.Kind, "piped show should target previous tasks")
+
+	update, ok := stages[3].Result.Command.(*nlp.UpdateCommand)
+	require.True(t, ok, "piped set should be UpdateCommand, got %T", stages[3].Result.Command)
+	assert.Equal(t, nlp.TargetRef{Kind: nlp.TargetID, ID: 5}, *update.Target, "explicit target should be kept")
+}
+
+func TestParseProgramErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"find #work | add milk",
+		"find #work | view inbox",
+		"find #work | set where #x state:now",
+		"find #work |",
+		"; find #work",
+	} {
+		_, err := nlp.ParseProgram(input, nlp.ParseOptions{})
+		require.Error(t, err, "expected error for %q", input)
+	}
+
+	_, err := nlp.Parse("find #work | done", nlp.ParseOptions{})
+	require.ErrorContains(t, err, "expected a single command", "Parse should reject pipelines")
+}
@@ -0,0 +1,79 @@
+package nlp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseSortKeys parses a comma-separated sort specification such as
+// "due,-updated" or "-meta:points". A leading "-" or a trailing "desc" sorts
+// descending; a trailing "asc" is the default order.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(spec, ",")
+	keys := make([]SortKey, 0, len(parts))
+	for _, part := range parts {
+		key, err := parseSortKey(part)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseSortKey(value string) (SortKey, error) {
+	value = strings.TrimSpace(value)
+	key := SortKey{}
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		key.Desc = true
+		value = strings.TrimSpace(rest)
+	}
+	if field, direction, ok := strings.Cut(value, " "); ok {
+		direction = strings.TrimSpace(direction)
+		if !isSortDirection(direction) {
+			return SortKey{}, fmt.Errorf("unsupported sort direction %q (use asc|desc)", direction)
+		}
+		if key.Desc {
+			return SortKey{}, fmt.Errorf("sort %q uses both - and %s", "-"+value, direction)
+		}
+		key.Desc = strings.EqualFold(direction, sortDirectionDesc)
+		value = field
+	}
+
+	field, metaKey, hasKey := strings.Cut(value, ":")
+	field = strings.ToLower(strings.TrimSpace(field))
+	switch field {
+	case SortFieldID, SortFieldTitle, SortFieldState, SortFieldDue, SortFieldCreated, SortFieldUpdated,
+		SortFieldUrgency:
+		if hasKey {
+			return SortKey{}, fmt.Errorf("sort field %q does not take a key", field)
+		}
+		key.Field = field
+		return key, nil
+	case SortFieldMeta:
+		metaKey = strings.TrimSpace(metaKey)
+		if !hasKey || metaKey == "" {
+			return SortKey{}, fmt.Errorf("sort %q requires a meta key (meta:<key>)", value)
+		}
+		key.Field = SortFieldMeta
+		key.Key = metaKey
+		return key, nil
+	default:
+		return SortKey{}, fmt.Errorf("unsupported sort field %q (use %s)", value, SortFieldsUsage)
+	}
+}
+
+const (
+	sortDirectionAsc  = "asc"
+	sortDirectionDesc = "desc"
+)
+
+// isSortDirection reports whether word is an asc or desc sort direction.
+func isSortDirection(word string) bool {
+	return strings.EqualFold(word, sortDirectionAsc) || strings.EqualFold(word, sortDirectionDesc)
+}
@@ -0,0 +1,61 @@
+package nlp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := nlp.ParseSortKeys("meta:points, -meta:size")
+	require.NoError(t, err, "ParseSortKeys error")
+	assert.Equal(t, []nlp.SortKey{
+		{Field: nlp.SortFieldMeta, Key: "points"},
+		{Field: nlp.SortFieldMeta, Key: "size", Desc: true},
+	}, keys, "sort keys mismatch")
+
+	keys, err = nlp.ParseSortKeys("Due,-updated")
+	require.NoError(t, err, "ParseSortKeys(builtin) error")
+	assert.Equal(t, []nlp.SortKey{
+		{Field: nlp.SortFieldDue},
+		{Field: nlp.SortFieldUpdated, Desc: true},
+	}, keys, "builtin sort keys mismatch")
+
+	keys, err = nlp.ParseSortKeys("urgency,-due")
+	require.NoError(t, err, "ParseSortKeys(urgency) error")
+	assert.Equal(t, []nlp.SortKey{
+		{Field: nlp.SortFieldUrgency},
+		{Field: nlp.SortFieldDue, Desc: true},
+	}, keys, "urgency sort keys mismatch")
+
+	keys, err = nlp.ParseSortKeys("title desc, due ASC")
+	require.NoError(t, err, "ParseSortKeys(direction words) error")
+	assert.Equal(t, []nlp.SortKey{
+		{Field: nlp.SortFieldTitle, Desc: true},
+		{Field: nlp.SortFieldDue},
+	}, keys, "direction word sort keys mismatch")
+
+	_, err = nlp.ParseSortKeys("-due desc")
+	require.Error(t, err, "ParseSortKeys(- with direction word) should fail")
+
+	_, err = nlp.ParseSortKeys("due sideways")
+	require.Error(t, err, "ParseSortKeys(unknown direction) should fail")
+
+	keys, err = nlp.ParseSortKeys("  ")
+	require.NoError(t, err, "ParseSortKeys(empty) error")
+	assert.Empty(t, keys, "empty spec should yield no keys")
+
+	_, err = nlp.ParseSortKeys("meta:")
+	require.Error(t, err, "ParseSortKeys(meta without key) should fail")
+
+	_, err = nlp.ParseSortKeys("due:soon")
+	require.Error(t, err, "ParseSortKeys(builtin with key) should fail")
+
+	_, err = nlp.ParseSortKeys("bogus")
+	require.Error(t, err, "ParseSortKeys(unknown field) should fail")
+}
@@ -23,6 +23,13 @@ const (
 	IntentView
 	IntentContext
 	IntentLog
+	IntentDone
+	IntentUndo
+	IntentDelete
+	IntentShow
+	IntentSnooze
+	IntentTemplate
+	IntentNote
 )
 
 type Severity int
@@ -38,6 +45,10 @@ type Diagnostic struct {
 	Code     string
 	Message  string
 	Hint     string
+	// Input is the text the diagnostic refers to.
+	Input string
+	// Span locates the offending token in Input; nil when unknown.
+	Span *Span
 }
 
 type ParseResult struct {
@@ -46,6 +57,29 @@ type ParseResult struct {
 	Diagnostics []Diagnostic
 }
 
+// Program is a parsed line of one or more statements separated by ";".
+type Program struct {
+	Statements []Pipeline
+}
+
+// Single reports whether the program is one command without ";" or "|".
+func (p Program) Single() bool {
+	return len(p.Statements) == 1 && len(p.Statements[0].Stages) == 1
+}
+
+// Pipeline is a statement of one or more stages joined by "|". Each stage
+// after the first runs against the tasks of the stage before it.
+type Pipeline struct {
+	Stages []PipelineStage
+}
+
+// PipelineStage is one command of a pipeline.
+type PipelineStage struct {
+	// Input is the source text of the stage.
+	Input  string
+	Result ParseResult
+}
+
 type DiagnosticError struct {
 	err         error
 	diagnostics []Diagnostic
@@ -38,11 +38,18 @@ func _() {
 	_ = x[IntentView-4]
 	_ = x[IntentContext-5]
 	_ = x[IntentLog-6]
+	_ = x[IntentDone-7]
+	_ = x[IntentUndo-8]
+	_ = x[IntentDelete-9]
+	_ = x[IntentShow-10]
+	_ = x[IntentSnooze-11]
+	_ = x[IntentTemplate-12]
+	_ = x[IntentNote-13]
 }
 
-const _Intent_name = "IntentUnknownIntentCreateIntentUpdateIntentFilterIntentViewIntentContextIntentLog"
+const _Intent_name = "IntentUnknownIntentCreateIntentUpdateIntentFilterIntentViewIntentContextIntentLogIntentDoneIntentUndoIntentDeleteIntentShowIntentSnoozeIntentTemplateIntentNote"
 
-var _Intent_index = [...]uint8{0, 13, 25, 37, 49, 59, 72, 81}
+var _Intent_index = [...]uint8{0, 13, 25, 37, 49, 59, 72, 81, 91, 101, 113, 123, 135, 149, 159}
 
 func (i Intent) String() string {
 	idx := int(i) - 0
@@ -0,0 +1,79 @@
+package nlp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BuiltinView is one of the fixed views available in the shell and CLI.
+type BuiltinView struct {
+	Name        string
+	Aliases     []string
+	Where       string
+	Description string
+}
+
+// StalledViewName is the view of projects with open tasks but no now task.
+// It lists projects rather than filtering tasks, so it is not a BuiltinView.
+const StalledViewName = "stalled"
+
+//nolint:gochecknoglobals // constant pattern for user-defined view names
+var viewNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// BuiltinViews returns the built-in views in display order.
+func BuiltinViews() []BuiltinView {
+	return []BuiltinView{
+		{Name: viewNameInbox, Aliases: []string{"i"}, Where: "state:inbox", Description: "Inbox tasks"},
+		{Name: viewNameNow, Aliases: []string{"n"}, Where: "state:now", Description: "Now tasks"},
+		{Name: viewNameWaiting, Aliases: []string{"w"}, Where: "state:waiting", Description: "Waiting tasks"},
+		{Name: viewNameLater, Aliases: []string{"l"}, Where: "state:later", Description: "Later tasks"},
+		{
+			Name:        viewNameCalendar,
+			Aliases:     []string{"c", "today"},
+			Where:       "due:*",
+			Description: "Tasks with due dates",
+		},
+		{
+			Name:        viewNameFollowUp,
+			Aliases:     []string{"f"},
+			Where:       "state:waiting && until:<=today",
+			Description: "Waiting tasks due for follow-up",
+		},
+		{
+			Name:        viewNameAvailable,
+			Aliases:     []string{"a"},
+			Where:       "state:now && available",
+			Description: "Now tasks whose contexts are available",
+		},
+	}
+}
+
+// LookupBuiltinView resolves a built-in view by name or alias.
+func LookupBuiltinView(name string) (BuiltinView, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, view := range BuiltinViews() {
+		if view.Name == name {
+			return view, true
+		}
+		for _, alias := range view.Aliases {
+			if alias == name {
+				return view, true
+			}
+		}
+	}
+	return BuiltinView{}, false
+}
+
+// IsReservedViewName reports whether name is taken by a built-in view.
+func IsReservedViewName(name string) bool {
+	if _, ok := LookupBuiltinView(name); ok {
+		return true
+	}
+	return strings.EqualFold(strings.TrimSpace(name), StalledViewName)
+}
+
+// IsValidViewName reports whether name can be used for a user-defined view.
+// Names are lowercase letters, digits, "-" and "_".
+func IsValidViewName(name string) bool {
+	return viewNamePattern.MatchString(name)
+}
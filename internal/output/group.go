@@ -0,0 +1,154 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+const (
+	groupNoProject = "(no project)"
+	groupNoContext = "(no context)"
+	groupNoDue     = "(no due date)"
+)
+
+// TaskGroup is a labelled slice of a task list.
+type TaskGroup struct {
+	Label string
+	Tasks []*store.Task
+}
+
+type TaskGroupJSON struct {
+	Group string     `json:"group"`
+	Count int        `json:"count"`
+	Tasks []TaskJSON `json:"tasks"`
+}
+
+// GroupTasks splits tasks by project, context, state or due week while
+// keeping their list order within each group. Tasks with several projects or
+// contexts appear in each of their groups.
+func GroupTasks(tasks []*store.Task, by string) ([]TaskGroup, error) {
+	by = strings.ToLower(strings.TrimSpace(by))
+	var labelsFor func(task *store.Task) []string
+	switch by {
+	case domain.GroupByProject:
+		labelsFor = func(task *store.Task) []string { return labelsOrNone(task.Projects, groupNoProject) }
+	case domain.GroupByContext:
+		labelsFor = func(task *store.Task) []string { return labelsOrNone(task.Contexts, groupNoContext) }
+	case domain.GroupByState:
+		labelsFor = func(task *store.Task) []string { return []string{string(task.State)} }
+	case domain.GroupByDueWeek:
+		labelsFor = dueWeekLabel
+	default:
+		return nil, fmt.Errorf("invalid group %q (expected %s)", by, domain.GroupByUsage)
+	}
+
+	index := map[string]int{}
+	groups := make([]TaskGroup, 0)
+	for _, task := range tasks {
+		for _, label := range labelsFor(task) {
+			i, ok := index[label]
+			if !ok {
+				i = len(groups)
+				index[label] = i
+				groups = append(groups, TaskGroup{Label: label})
+			}
+			groups[i].Tasks = append(groups[i].Tasks, task)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		rankI, rankJ := groupRank(by, groups[i].Label), groupRank(by, groups[j].Label)
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return groups[i].Label < groups[j].Label
+	})
+	return groups, nil
+}
+
+func labelsOrNone(values []string, none string) []string {
+	if len(values) == 0 {
+		return []string{none}
+	}
+	return values
+}
+
+func dueWeekLabel(task *store.Task) []string {
+	if task.DueOn == nil {
+		return []string{groupNoDue}
+	}
+	year, week := task.DueOn.ISOWeek()
+	return []string{fmt.Sprintf("%04d-W%02d", year, week)}
+}
+
+// groupRank orders states by workflow and puts the "none" group last; other
+// groups share a rank and sort by label.
+func groupRank(by string, label string) int {
+	states := []string{
+		domain.TaskStateInbox,
+		domain.TaskStateNow,
+		domain.TaskStateWaiting,
+		domain.TaskStateLater,
+		domain.TaskStateDone,
+	}
+	switch label {
+	case groupNoProject, groupNoContext, groupNoDue:
+		return len(states)
+	}
+	if by == domain.GroupByState {
+		if i := slices.Index(states, label); i >= 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+func (w Writer) WriteTaskGroups(groups []TaskGroup) error {
+	if w.JSON {
+		payload := make([]TaskGroupJSON, 0, len(groups))
+		for _, group := range groups {
+			payload = append(payload, TaskGroupJSON{
+				Group: group.Label,
+				Count: len(group.Tasks),
+				Tasks: toTaskJSONList(group.Tasks),
+			})
+		}
+		return writeJSON(w.Out, payload)
+	}
+
+	human := w.isHumanMode()
+	if human && len(groups) == 0 {
+		_, err := fmt.Fprintln(w.Out, "No tasks found")
+		return err
+	}
+
+	var builder strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			builder.WriteByte('\n')
+		}
+		if human {
+			_, _ = fmt.Fprintf(&builder, "%s (%d)\n", pterm.ThemeDefault.PrimaryStyle.Sprint(group.Label), len(group.Tasks))
+		} else {
+			_, _ = fmt.Fprintf(&builder, "# %s (%d)\n", group.Label, len(group.Tasks))
+		}
+		for _, task := range group.Tasks {
+			if human {
+				builder.WriteString(w.formatTaskLine(task))
+			} else {
+				builder.WriteString(w.plainLine(task))
+			}
+			builder.WriteByte('\n')
+		}
+	}
+
+	_, err := fmt.Fprint(w.Out, builder.String())
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pterm/pterm"
 
 	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 type Summary struct {
@@ -20,6 +21,19 @@ type Summary struct {
 	File   string  `json:"file,omitempty"`
 }
 
+// BulkSummary reports a filter-targeted bulk operation task by task.
+type BulkSummary struct {
+	Action  string       `json:"action"`
+	Count   int64        `json:"count"`
+	Results []TaskResult `json:"results"`
+}
+
+type TaskResult struct {
+	ID     int64  `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
 func (w Writer) writeHumanTask(task *store.Task) error {
 	if task == nil {
 		return nil
@@ -34,9 +48,12 @@ func (w Writer) writeHumanTask(task *store.Task) error {
 		{Key: "Prev State", Value: formatDetailPrevState(task.PrevState)},
 		{Key: "Due", Value: w.formatDetailDate(task.DueOn, pterm.ThemeDefault.WarningMessageStyle)},
 		{Key: "Waiting For", Value: emptyDash(task.WaitingFor)},
+		{Key: "Follow Up", Value: w.formatDetailDate(task.FollowUpOn, pterm.ThemeDefault.WarningMessageStyle)},
+		{Key: "Snoozed Until", Value: emptyDash(formatSnoozedPtr(task.SnoozedUntil))},
 		{Key: "Projects", Value: formatDetailList(task.Projects, pterm.ThemeDefault.PrimaryStyle)},
 		{Key: "Contexts", Value: formatDetailList(task.Contexts, pterm.ThemeDefault.SuccessMessageStyle)},
 		{Key: "Meta", Value: metaOrDash(task.Meta)},
+		{Key: "Checklist", Value: emptyDash(checklistProgress(task.Checklist))},
 		{Key: "Created", Value: w.formatTimeOrDash(task.CreatedAt)},
 		{Key: "Updated", Value: w.formatTimeOrDash(task.UpdatedAt)},
 		{Key: "Completed", Value: w.formatTimePtrOrDash(task.CompletedAt)},
@@ -52,6 +69,17 @@ func (w Writer) writeHumanTask(task *store.Task) error {
 		builder.WriteString(": ")
 		builder.WriteString(row.Value)
 		builder.WriteByte('\n')
+		if row.Key == "Checklist" {
+			for i, item := range task.Checklist {
+				_, _ = fmt.Fprintf(&builder, "    %d. %s\n", i+1, formatChecklistItem(item))
+			}
+		}
+	}
+	if len(task.Annotations) > 0 {
+		builder.WriteString("  " + pterm.ThemeDefault.SecondaryStyle.Sprint("Annotations") + ":\n")
+		for _, annotation := range task.Annotations {
+			builder.WriteString("    " + w.formatTimeOrDash(annotation.CreatedAt) + "  " + annotation.Text + "\n")
+		}
 	}
 
 	_, err := fmt.Fprint(w.Out, builder.String())
@@ -75,6 +103,31 @@ func (w Writer) writeHumanList(tasks []*store.Task) error {
 	return err
 }
 
+func (w Writer) writeHumanScoredTasks(scored []urgency.Scored, explain bool) error {
+	if len(scored) == 0 {
+		_, err := fmt.Fprintln(w.Out, "No tasks found")
+		return err
+	}
+
+	var builder strings.Builder
+	for _, item := range scored {
+		score := pterm.ThemeDefault.InfoMessageStyle.Sprintf("%5.1f", item.Score.Total)
+		builder.WriteString(score + w.formatTaskLine(item.Task))
+		builder.WriteByte('\n')
+		if !explain {
+			continue
+		}
+		for _, factor := range item.Score.Factors {
+			line := fmt.Sprintf("%-8s %4.2f x %-5g %+6.1f", factor.Name, factor.Value, factor.Weight, factor.Points())
+			builder.WriteString("          " + pterm.ThemeDefault.SecondaryStyle.Sprint(line))
+			builder.WriteByte('\n')
+		}
+	}
+
+	_, err := fmt.Fprint(w.Out, builder.String())
+	return err
+}
+
 func writeHumanSummary(out io.Writer, summary any) error {
 	switch value := summary.(type) {
 	case Summary:
@@ -94,6 +147,18 @@ func writeHumanSummary(out io.Writer, summary any) error {
 		}
 		_, err := fmt.Fprintln(out, line)
 		return err
+	case BulkSummary:
+		var builder strings.Builder
+		_, _ = fmt.Fprintf(&builder, "%s: %d\n", value.Action, value.Count)
+		for _, result := range value.Results {
+			_, _ = fmt.Fprintf(&builder, "  %s %s %s\n",
+				pterm.ThemeDefault.PrimaryStyle.Sprint("#"+strconv.FormatInt(result.ID, 10)),
+				pterm.ThemeDefault.SecondaryStyle.Sprint(result.Status),
+				result.Title,
+			)
+		}
+		_, err := fmt.Fprint(out, builder.String())
+		return err
 	default:
 		return writeRenderedLine(out, pterm.DefaultBasicText.Sprintln(fmt.Sprintf("%v", value)))
 	}
@@ -111,6 +176,14 @@ func writePlainSummary(out io.Writer, summary any) error {
 		}
 		_, err := fmt.Fprintln(out, line)
 		return err
+	case BulkSummary:
+		var builder strings.Builder
+		_, _ = fmt.Fprintf(&builder, "%s: %d\n", value.Action, value.Count)
+		for _, result := range value.Results {
+			_, _ = fmt.Fprintf(&builder, "%d\t%s\t%s\n", result.ID, result.Status, result.Title)
+		}
+		_, err := fmt.Fprint(out, builder.String())
+		return err
 	default:
 		_, err := fmt.Fprintf(out, "%v\n", value)
 		return err
@@ -174,6 +247,86 @@ func writeHumanTagsWithCounts(out io.Writer, tags []store.NameCount) error {
 	return renderTable(out, rows)
 }
 
+func (w Writer) writeHumanStalledProjects(projects []store.StalledProject) error {
+	if len(projects) == 0 {
+		return w.WriteSuccess("No stalled projects: every project has a now task")
+	}
+	rows := pterm.TableData{{"Project", "Open", "Inbox", "Waiting", "Later", "Last activity"}}
+	for _, project := range projects {
+		rows = append(rows, []string{
+			project.Name,
+			strconv.FormatInt(project.Open, 10),
+			strconv.FormatInt(project.Inbox, 10),
+			strconv.FormatInt(project.Waiting, 10),
+			strconv.FormatInt(project.Later, 10),
+			w.formatTimeOrDash(project.LastActivity),
+		})
+	}
+	return renderTable(w.Out, rows)
+}
+
+func (w Writer) writeHumanWaitingGroups(groups []WaitingGroup) error {
+	if len(groups) == 0 {
+		return w.WriteSuccess("Nothing is waiting")
+	}
+	rows := pterm.TableData{{"Person", "ID", "Title", "Waiting", "Follow up"}}
+	for _, group := range groups {
+		for i, item := range group.Items {
+			person := ""
+			if i == 0 {
+				person = emptyDash(group.Person)
+			}
+			rows = append(rows, []string{
+				person,
+				strconv.FormatInt(item.Task.ID, 10),
+				item.Task.Title,
+				formatWaitingDays(item.Days),
+				w.formatDetailDate(item.Task.FollowUpOn, pterm.ThemeDefault.WarningMessageStyle),
+			})
+		}
+	}
+	return renderTable(w.Out, rows)
+}
+
+func (w Writer) writeHumanSnoozed(tasks []*store.Task) error {
+	if len(tasks) == 0 {
+		return w.WriteSuccess("Nothing is snoozed")
+	}
+	rows := pterm.TableData{{"ID", "Title", "Until", "Returns to"}}
+	for _, task := range tasks {
+		rows = append(rows, []string{
+			strconv.FormatInt(task.ID, 10),
+			task.Title,
+			formatSnoozedPtr(task.SnoozedUntil),
+			formatDetailState(snoozeReturnState(task)),
+		})
+	}
+	return renderTable(w.Out, rows)
+}
+
+func (w Writer) writeHumanTemplates(items []TemplateItem) error {
+	if len(items) == 0 {
+		return w.WriteSuccess("No templates")
+	}
+	rows := pterm.TableData{{"Name", "Title", "Tasks", "Variables"}}
+	for _, item := range items {
+		rows = append(rows, []string{
+			item.Name,
+			item.Title,
+			strconv.Itoa(item.Tasks),
+			emptyDash(strings.Join(item.Vars, ", ")),
+		})
+	}
+	return renderTable(w.Out, rows)
+}
+
+func formatWaitingDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return strconv.Itoa(days) + " days"
+}
+
 func writeHumanKeyValues(out io.Writer, rows []KeyValue) error {
 	tableData := pterm.TableData{{"Key", "Value"}}
 	for _, row := range rows {
@@ -245,6 +398,9 @@ func (w Writer) formatTaskLine(task *store.Task) string {
 	dueStr := w.formatTaskDueDate(task.DueOn)
 
 	line := fmt.Sprintf("  %s %s %s", idStr, task.Title, stateStr)
+	if progress := checklistProgress(task.Checklist); progress != "" {
+		line += " " + pterm.ThemeDefault.InfoMessageStyle.Sprint(progress)
+	}
 	if tags != "" {
 		line += " " + tags
 	}
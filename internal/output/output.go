@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,6 +20,7 @@ import (
 	"github.com/mholtzscher/ugh/internal/nlp"
 	"github.com/mholtzscher/ugh/internal/store"
 	"github.com/mholtzscher/ugh/internal/termutil"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 type Writer struct {
@@ -36,6 +40,8 @@ type ContextStatus struct {
 	LastIDs    []int64 `json:"lastIds,omitempty"`
 	Project    string  `json:"project,omitempty"`
 	Context    string  `json:"context,omitempty"`
+	Filter     string  `json:"filter,omitempty"`
+	Session    string  `json:"session,omitempty"`
 }
 
 type ViewHelp struct {
@@ -114,6 +120,266 @@ func (w Writer) WriteTagsWithCounts(tags []store.NameCount) error {
 	return nil
 }
 
+// StalledProjectJSON is the JSON form of a stalled project.
+type StalledProjectJSON struct {
+	Name         string `json:"name"`
+	Open         int64  `json:"open"`
+	Inbox        int64  `json:"inbox"`
+	Waiting      int64  `json:"waiting"`
+	Later        int64  `json:"later"`
+	LastActivity string `json:"lastActivity"`
+}
+
+func (w Writer) WriteStalledProjects(projects []store.StalledProject) error {
+	if w.JSON {
+		payload := make([]StalledProjectJSON, 0, len(projects))
+		for _, project := range projects {
+			payload = append(payload, StalledProjectJSON{
+				Name:         project.Name,
+				Open:         project.Open,
+				Inbox:        project.Inbox,
+				Waiting:      project.Waiting,
+				Later:        project.Later,
+				LastActivity: formatDateTime(project.LastActivity),
+			})
+		}
+		return writeJSON(w.Out, payload)
+	}
+	if w.isHumanMode() {
+		return w.writeHumanStalledProjects(projects)
+	}
+
+	for _, project := range projects {
+		lastActivity := project.LastActivity.Local()
+		if _, err := fmt.Fprintf(w.Out, "%s\t%d\t%s\n", project.Name, project.Open, formatDate(&lastActivity)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitingGroup is the waiting tasks for one person, longest waiting first.
+// Person is empty for tasks not waiting on anyone in particular.
+type WaitingGroup struct {
+	Person string
+	Items  []WaitingItem
+}
+
+// WaitingItem is a waiting task with when it entered the waiting state.
+type WaitingItem struct {
+	Task  *store.Task
+	Since time.Time
+	Days  int
+}
+
+// WaitingGroupJSON is the JSON form of a waiting group.
+type WaitingGroupJSON struct {
+	Person string            `json:"person"`
+	Tasks  []WaitingItemJSON `json:"tasks"`
+}
+
+// WaitingItemJSON is the JSON form of a waiting item.
+type WaitingItemJSON struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	WaitingSince string `json:"waitingSince"`
+	DaysWaiting  int    `json:"daysWaiting"`
+	FollowUpOn   string `json:"followUpOn,omitempty"`
+}
+
+func (w Writer) WriteWaitingGroups(groups []WaitingGroup) error {
+	if w.JSON {
+		payload := make([]WaitingGroupJSON, 0, len(groups))
+		for _, group := range groups {
+			tasks := make([]WaitingItemJSON, 0, len(group.Items))
+			for _, item := range group.Items {
+				tasks = append(tasks, WaitingItemJSON{
+					ID:           item.Task.ID,
+					Title:        item.Task.Title,
+					WaitingSince: formatDateTime(item.Since),
+					DaysWaiting:  item.Days,
+					FollowUpOn:   formatDate(item.Task.FollowUpOn),
+				})
+			}
+			payload = append(payload, WaitingGroupJSON{Person: group.Person, Tasks: tasks})
+		}
+		return writeJSON(w.Out, payload)
+	}
+	if w.isHumanMode() {
+		return w.writeHumanWaitingGroups(groups)
+	}
+
+	for _, group := range groups {
+		for _, item := range group.Items {
+			_, err := fmt.Fprintf(w.Out, "%s\t%d\t%d\t%s\t%s\n",
+				group.Person, item.Task.ID, item.Days, formatDate(item.Task.FollowUpOn), item.Task.Title)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SnoozedJSON is the JSON form of a snoozed task.
+type SnoozedJSON struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	SnoozedUntil string `json:"snoozedUntil"`
+	ReturnsTo    string `json:"returnsTo"`
+}
+
+// WriteSnoozed lists snoozed tasks with when they return and to which state.
+func (w Writer) WriteSnoozed(tasks []*store.Task) error {
+	if w.JSON {
+		payload := make([]SnoozedJSON, 0, len(tasks))
+		for _, task := range tasks {
+			payload = append(payload, SnoozedJSON{
+				ID:           task.ID,
+				Title:        task.Title,
+				SnoozedUntil: formatDateTimePtr(task.SnoozedUntil),
+				ReturnsTo:    string(snoozeReturnState(task)),
+			})
+		}
+		return writeJSON(w.Out, payload)
+	}
+	if w.isHumanMode() {
+		return w.writeHumanSnoozed(tasks)
+	}
+
+	for _, task := range tasks {
+		_, err := fmt.Fprintf(w.Out, "%d\t%s\t%s\t%s\n",
+			task.ID, formatSnoozedPtr(task.SnoozedUntil), snoozeReturnState(task), task.Title)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplateItem describes one template in a template listing.
+type TemplateItem struct {
+	Name  string
+	Title string
+	// Tasks counts the tasks the template creates, itself included.
+	Tasks int
+	Vars  []string
+}
+
+// TemplateJSON is the JSON form of a TemplateItem.
+type TemplateJSON struct {
+	Name  string   `json:"name"`
+	Title string   `json:"title"`
+	Tasks int      `json:"tasks"`
+	Vars  []string `json:"vars"`
+}
+
+// WriteTemplates lists templates with their task counts and variables.
+func (w Writer) WriteTemplates(items []TemplateItem) error {
+	if w.JSON {
+		payload := make([]TemplateJSON, 0, len(items))
+		for _, item := range items {
+			vars := item.Vars
+			if vars == nil {
+				vars = []string{}
+			}
+			payload = append(payload, TemplateJSON{Name: item.Name, Title: item.Title, Tasks: item.Tasks, Vars: vars})
+		}
+		return writeJSON(w.Out, payload)
+	}
+	if w.isHumanMode() {
+		return w.writeHumanTemplates(items)
+	}
+
+	for _, item := range items {
+		_, err := fmt.Fprintf(w.Out, "%s\t%d\t%s\t%s\n", item.Name, item.Tasks, strings.Join(item.Vars, ","), item.Title)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snoozeReturnState is the state a snoozed task wakes up in.
+func snoozeReturnState(task *store.Task) store.State {
+	if task.PrevState == nil {
+		return store.StateInbox
+	}
+	return *task.PrevState
+}
+
+// ScoredTaskJSON is the JSON form of a task with its urgency. Factors is set
+// only when the score is explained.
+type ScoredTaskJSON struct {
+	TaskJSON
+
+	Urgency float64      `json:"urgency"`
+	Factors []FactorJSON `json:"factors,omitempty"`
+}
+
+// FactorJSON is the JSON form of one urgency factor.
+type FactorJSON struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+	Points float64 `json:"points"`
+}
+
+// WriteScoredTasks writes tasks with their urgency score. With explain, each
+// task is followed by the factors that make up its score.
+func (w Writer) WriteScoredTasks(scored []urgency.Scored, explain bool) error {
+	if w.JSON {
+		payload := make([]ScoredTaskJSON, 0, len(scored))
+		for _, item := range scored {
+			entry := ScoredTaskJSON{TaskJSON: toTaskJSON(item.Task), Urgency: roundScore(item.Score.Total)}
+			if explain {
+				entry.Factors = toFactorJSONList(item.Score.Factors)
+			}
+			payload = append(payload, entry)
+		}
+		return writeJSON(w.Out, payload)
+	}
+	if w.isHumanMode() {
+		return w.writeHumanScoredTasks(scored, explain)
+	}
+
+	for _, item := range scored {
+		if _, err := fmt.Fprintf(w.Out, "%.1f\t%s\n", item.Score.Total, w.plainLine(item.Task)); err != nil {
+			return err
+		}
+		if !explain {
+			continue
+		}
+		for _, factor := range item.Score.Factors {
+			_, err := fmt.Fprintf(w.Out, "\t%s\t%.2f\t%g\t%+.1f\n",
+				factor.Name, factor.Value, factor.Weight, factor.Points())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func toFactorJSONList(factors []urgency.Factor) []FactorJSON {
+	payload := make([]FactorJSON, 0, len(factors))
+	for _, factor := range factors {
+		payload = append(payload, FactorJSON{
+			Name:   factor.Name,
+			Value:  roundScore(factor.Value),
+			Weight: factor.Weight,
+			Points: roundScore(factor.Points()),
+		})
+	}
+	return payload
+}
+
+// roundScore keeps two decimals so JSON scores stay readable.
+func roundScore(value float64) float64 {
+	const scale = 100
+	return math.Round(value*scale) / scale
+}
+
 func (w Writer) WriteSummary(summary any) error {
 	if w.JSON {
 		return writeJSON(w.Out, summary)
@@ -152,10 +418,38 @@ func (w Writer) WriteContextStatus(status ContextStatus) error {
 		{Key: formatContextLabel("Last", w.TTY), Value: formatContextIDs(status.LastIDs, w.TTY)},
 		{Key: formatContextLabel("Project", w.TTY), Value: formatContextTag(status.Project, "#", w.TTY)},
 		{Key: formatContextLabel("Context", w.TTY), Value: formatContextTag(status.Context, "@", w.TTY)},
+		{Key: formatContextLabel("Filter", w.TTY), Value: formatContextTag(status.Filter, "", w.TTY)},
+	}
+	if status.Session != "" {
+		rows = append(rows, KeyValue{Key: formatContextLabel("Session", w.TTY), Value: status.Session})
 	}
 	return w.WriteInfoBlock("Current Context:", rows)
 }
 
+// NewViewHelp lists the built-in views followed by user-defined views sorted
+// by name.
+func NewViewHelp(views map[string]config.View, usage string) ViewHelp {
+	help := ViewHelp{Usage: usage}
+	for _, view := range nlp.BuiltinViews() {
+		help.Entries = append(help.Entries, ViewHelpEntry{
+			Label:       view.Aliases[0] + ", " + view.Name,
+			Description: view.Description,
+		})
+	}
+	help.Entries = append(help.Entries, ViewHelpEntry{
+		Label:       nlp.StalledViewName,
+		Description: "Projects with no now task",
+	})
+	for _, name := range slices.Sorted(maps.Keys(views)) {
+		description := views[name].Where
+		if sortSpec := strings.TrimSpace(views[name].Sort); sortSpec != "" {
+			description += " (sort: " + sortSpec + ")"
+		}
+		help.Entries = append(help.Entries, ViewHelpEntry{Label: name, Description: description})
+	}
+	return help
+}
+
 func (w Writer) WriteViewHelp(help ViewHelp) error {
 	if w.JSON {
 		return writeJSON(w.Out, help)
@@ -214,13 +508,30 @@ func (w Writer) WriteErr(err error) error {
 			if diagnostics[0].Hint != "" {
 				line += " (hint: " + diagnostics[0].Hint + ")"
 			}
-			return w.WriteError(line)
+			if writeErr := w.WriteError(line); writeErr != nil {
+				return writeErr
+			}
+			return w.writeCaret(diagnostics[0])
 		}
 	}
 
 	return w.WriteError(err.Error())
 }
 
+// writeCaret writes the input of a positioned diagnostic with carets under
+// the offending token.
+func (w Writer) writeCaret(diag nlp.Diagnostic) error {
+	if diag.Span == nil || diag.Input == "" || strings.ContainsAny(diag.Input, "\t\n") {
+		return nil
+	}
+	carets := strings.Repeat("^", max(diag.Span.RuneEnd-diag.Span.RuneStart, 1))
+	if w.isHumanMode() {
+		carets = pterm.FgRed.Sprint(carets)
+	}
+	_, err := fmt.Fprintf(w.Out, "  %s\n  %s%s\n", diag.Input, strings.Repeat(" ", diag.Span.RuneStart), carets)
+	return err
+}
+
 func (w Writer) writePrefixLine(printer pterm.PrefixPrinter, line string) error {
 	if !w.isHumanMode() {
 		prefix := ""
@@ -235,37 +546,66 @@ func (w Writer) writePrefixLine(printer pterm.PrefixPrinter, line string) error
 }
 
 type TaskJSON struct {
-	ID          int64             `json:"id"`
-	State       string            `json:"state"`
-	Title       string            `json:"title"`
-	Notes       string            `json:"notes,omitempty"`
-	DueOn       string            `json:"dueOn,omitempty"`
-	WaitingFor  string            `json:"waitingFor,omitempty"`
-	CompletedAt string            `json:"completedAt,omitempty"`
-	Projects    []string          `json:"projects"`
-	Contexts    []string          `json:"contexts"`
-	Meta        map[string]string `json:"meta"`
-	CreatedAt   string            `json:"createdAt"`
-	UpdatedAt   string            `json:"updatedAt"`
+	ID           int64             `json:"id"`
+	State        string            `json:"state"`
+	Title        string            `json:"title"`
+	Notes        string            `json:"notes,omitempty"`
+	DueOn        string            `json:"dueOn,omitempty"`
+	WaitingFor   string            `json:"waitingFor,omitempty"`
+	FollowUpOn   string            `json:"followUpOn,omitempty"`
+	SnoozedUntil string            `json:"snoozedUntil,omitempty"`
+	CompletedAt  string            `json:"completedAt,omitempty"`
+	Projects     []string          `json:"projects"`
+	Contexts     []string          `json:"contexts"`
+	Meta         map[string]string `json:"meta"`
+	Checklist    []ChecklistJSON   `json:"checklist"`
+	Annotations  []AnnotationJSON  `json:"annotations"`
+	CreatedAt    string            `json:"createdAt"`
+	UpdatedAt    string            `json:"updatedAt"`
+}
+
+type ChecklistJSON struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+type AnnotationJSON struct {
+	Text      string `json:"text"`
+	CreatedAt string `json:"createdAt"`
 }
 
 func toTaskJSON(task *store.Task) TaskJSON {
 	projects := normalizeStringSlice(task.Projects)
 	contexts := normalizeStringSlice(task.Contexts)
 	meta := normalizeMeta(task.Meta)
+	checklist := make([]ChecklistJSON, 0, len(task.Checklist))
+	for _, item := range task.Checklist {
+		checklist = append(checklist, ChecklistJSON(item))
+	}
+	annotations := make([]AnnotationJSON, 0, len(task.Annotations))
+	for _, annotation := range task.Annotations {
+		annotations = append(annotations, AnnotationJSON{
+			Text:      annotation.Text,
+			CreatedAt: formatDateTime(annotation.CreatedAt),
+		})
+	}
 	return TaskJSON{
-		ID:          task.ID,
-		State:       string(task.State),
-		Title:       task.Title,
-		Notes:       task.Notes,
-		DueOn:       formatDate(task.DueOn),
-		WaitingFor:  task.WaitingFor,
-		CompletedAt: formatDateTimePtr(task.CompletedAt),
-		Projects:    projects,
-		Contexts:    contexts,
-		Meta:        meta,
-		CreatedAt:   formatDateTime(task.CreatedAt),
-		UpdatedAt:   formatDateTime(task.UpdatedAt),
+		ID:           task.ID,
+		State:        string(task.State),
+		Title:        task.Title,
+		Notes:        task.Notes,
+		DueOn:        formatDate(task.DueOn),
+		WaitingFor:   task.WaitingFor,
+		FollowUpOn:   formatDate(task.FollowUpOn),
+		SnoozedUntil: formatDateTimePtr(task.SnoozedUntil),
+		CompletedAt:  formatDateTimePtr(task.CompletedAt),
+		Projects:     projects,
+		Contexts:     contexts,
+		Meta:         meta,
+		Checklist:    checklist,
+		Annotations:  annotations,
+		CreatedAt:    formatDateTime(task.CreatedAt),
+		UpdatedAt:    formatDateTime(task.UpdatedAt),
 	}
 }
 
@@ -315,6 +655,28 @@ func (w Writer) plainLine(task *store.Task) string {
 	return strings.Join(fields, "\t")
 }
 
+// checklistProgress returns "[done/total]" for a task with a checklist.
+func checklistProgress(items []store.ChecklistItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+	return fmt.Sprintf("[%d/%d]", done, len(items))
+}
+
+// formatChecklistItem renders an item as "[x] text" or "[ ] text".
+func formatChecklistItem(item store.ChecklistItem) string {
+	if item.Done {
+		return "[x] " + item.Text
+	}
+	return "[ ] " + item.Text
+}
+
 const contextNoneValue = "none"
 
 func formatContextLabel(label string, tty bool) string {
@@ -401,6 +763,23 @@ func formatDate(val *time.Time) string {
 	return val.Format("2006-01-02")
 }
 
+// FormatSnoozedUntil formats a snooze date in local time, with the time of
+// day unless it is midnight.
+func FormatSnoozedUntil(val time.Time) string {
+	local := val.Local()
+	if local.Hour() == 0 && local.Minute() == 0 {
+		return local.Format("2006-01-02")
+	}
+	return local.Format("2006-01-02 15:04")
+}
+
+func formatSnoozedPtr(val *time.Time) string {
+	if val == nil {
+		return ""
+	}
+	return FormatSnoozedUntil(*val)
+}
+
 func formatDateTimePtr(val *time.Time) string {
 	if val == nil {
 		return ""
@@ -594,11 +973,15 @@ func diffTaskVersion(prev *store.TaskVersion, current *store.TaskVersion) []Task
 	appendScalarChange(&changes, "notes", old.Notes, current.Notes)
 	appendScalarChange(&changes, "due", formatDate(old.DueOn), formatDate(current.DueOn))
 	appendScalarChange(&changes, "waiting_for", old.WaitingFor, current.WaitingFor)
+	appendScalarChange(&changes, "follow_up", formatDate(old.FollowUpOn), formatDate(current.FollowUpOn))
+	appendScalarChange(&changes, "snoozed_until",
+		formatSnoozedPtr(old.SnoozedUntil), formatSnoozedPtr(current.SnoozedUntil))
 	appendScalarChange(&changes, "deleted", strconv.FormatBool(old.Deleted), strconv.FormatBool(current.Deleted))
 
 	diffListChange(&changes, "project", old.Projects, current.Projects)
 	diffListChange(&changes, "context", old.Contexts, current.Contexts)
 	diffMetaChange(&changes, old.Meta, current.Meta)
+	diffChecklistChange(&changes, old.Checklist, current.Checklist)
 
 	if len(changes) == 0 {
 		changes = append(changes, TaskVersionChange{Type: "none", Field: "snapshot", New: "no visible field changes"})
@@ -680,3 +1063,27 @@ func diffMetaChange(changes *[]TaskVersionChange, oldMeta, newMeta map[string]st
 		appendScalarChange(changes, "meta."+key, oldMeta[key], newMeta[key])
 	}
 }
+
+// diffChecklistChange matches checklist items by position, as items are
+// numbered and may repeat: an item that differs from the one at its index is
+// a change, and items past the end of the shorter list were added or removed.
+func diffChecklistChange(changes *[]TaskVersionChange, oldItems, newItems []store.ChecklistItem) {
+	for i := range min(len(oldItems), len(newItems)) {
+		if oldItems[i] == newItems[i] {
+			continue
+		}
+		*changes = append(*changes, TaskVersionChange{
+			Type: "change", Field: "checklist", Old: formatChecklistItem(oldItems[i]), New: formatChecklistItem(newItems[i]),
+		})
+	}
+	for _, item := range newItems[min(len(oldItems), len(newItems)):] {
+		*changes = append(*changes, TaskVersionChange{
+			Type: changeTypeAdd, Field: "checklist", New: formatChecklistItem(item),
+		})
+	}
+	for _, item := range oldItems[min(len(oldItems), len(newItems)):] {
+		*changes = append(*changes, TaskVersionChange{
+			Type: changeTypeRemove, Field: "checklist", Old: formatChecklistItem(item),
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Package picker lets the user choose a task by searching its title,
+// projects and contexts.
+package picker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"atomicgo.dev/cursor"
+	"github.com/pterm/pterm"
+
+	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/termutil"
+)
+
+const (
+	// maxListed caps the numbered list; typing text narrows it further.
+	maxListed = 20
+	// maxHeight is how many matches the fuzzy finder shows at once.
+	maxHeight = 10
+)
+
+var (
+	// ErrCanceled is returned when the user leaves the picker without
+	// choosing a task.
+	ErrCanceled = errors.New("no task picked")
+	// ErrNoTasks is returned when there is nothing to pick from.
+	ErrNoTasks = errors.New("no tasks to pick from")
+)
+
+// Picker chooses one task from a list. With a terminal to draw on it opens a
+// fuzzy finder; otherwise it prints a numbered list to Out and reads the
+// choice from In.
+type Picker struct {
+	In  io.Reader
+	Out io.Writer
+	// TTY is the terminal the fuzzy finder draws on; nil uses the numbered
+	// list.
+	TTY *os.File
+}
+
+// New returns a picker reading from in and prompting on out. The fuzzy finder
+// needs stdin to be a terminal and draws on stdout, or on stderr when stdout
+// is not a terminal, so $(ugh pick) still captures only the picked ID.
+func New(in io.Reader, out io.Writer) *Picker {
+	p := &Picker{In: in, Out: out}
+	if termutil.IsTerminal(os.Stdin) {
+		switch {
+		case termutil.IsTerminal(os.Stdout):
+			p.TTY = os.Stdout
+		case termutil.IsTerminal(os.Stderr):
+			p.TTY = os.Stderr
+		}
+	}
+	return p
+}
+
+// Pick returns the task the user chose.
+func (p *Picker) Pick(tasks []*store.Task) (*store.Task, error) {
+	if len(tasks) == 0 {
+		return nil, ErrNoTasks
+	}
+	if p.TTY != nil {
+		return pickFuzzy(tasks, p.TTY)
+	}
+	return p.pickNumbered(tasks)
+}
+
+// Label is the line shown for task: its ID, title, projects and contexts.
+func Label(task *store.Task) string {
+	parts := []string{fmt.Sprintf("#%d", task.ID), task.Title}
+	for _, project := range task.Projects {
+		parts = append(parts, "#"+project)
+	}
+	for _, context := range task.Contexts {
+		parts = append(parts, "@"+context)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Match reports whether every word of query appears in text as a
+// case-insensitive subsequence, so "wr rep" matches "Write report".
+func Match(query, text string) bool {
+	text = strings.ToLower(text)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !isSubsequence(word, text) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSubsequence(word, text string) bool {
+	remaining := []rune(word)
+	for _, r := range text {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// pickFuzzy runs the finder on tty. The finder always draws on os.Stdout,
+// so stdout is pointed at tty while it runs.
+func pickFuzzy(tasks []*store.Task, tty *os.File) (*store.Task, error) {
+	if tty != os.Stdout {
+		stdout := os.Stdout
+		os.Stdout = tty
+		cursor.SetTarget(tty)
+		pterm.SetDefaultOutput(tty)
+		defer func() {
+			os.Stdout = stdout
+			cursor.SetTarget(stdout)
+			pterm.SetDefaultOutput(stdout)
+		}()
+	}
+
+	labels := make([]string, 0, len(tasks))
+	byLabel := make(map[string]*store.Task, len(tasks))
+	for _, task := range tasks {
+		label := Label(task)
+		labels = append(labels, label)
+		byLabel[label] = task
+	}
+
+	canceled := false
+	choice, err := pterm.DefaultInteractiveSelect.
+		WithOptions(labels).
+		WithMaxHeight(maxHeight).
+		WithFilter(true).
+		WithOnInterruptFunc(func() { canceled = true }).
+		Show("Pick a task")
+	if err != nil {
+		return nil, fmt.Errorf("pick task: %w", err)
+	}
+	task, ok := byLabel[choice]
+	if canceled || !ok {
+		return nil, ErrCanceled
+	}
+	return task, nil
+}
+
+// pickNumbered lists the candidates and reads either a list number or text
+// that narrows the list. An empty line or end of input cancels.
+func (p *Picker) pickNumbered(tasks []*store.Task) (*store.Task, error) {
+	reader := bufio.NewReader(p.In)
+	candidates := tasks
+	for {
+		p.writeList(candidates)
+		_, _ = fmt.Fprint(p.Out, "Pick a task (number, or text to narrow): ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read choice: %w", err)
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			_, _ = fmt.Fprintln(p.Out)
+			return nil, ErrCanceled
+		}
+
+		if n, convErr := strconv.Atoi(answer); convErr == nil {
+			if n >= 1 && n <= min(len(candidates), maxListed) {
+				return candidates[n-1], nil
+			}
+			_, _ = fmt.Fprintf(p.Out, "No task numbered %d\n", n)
+		} else if narrowed := filter(candidates, answer); len(narrowed) > 0 {
+			candidates = narrowed
+		} else {
+			_, _ = fmt.Fprintf(p.Out, "No tasks match %q\n", answer)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil, ErrCanceled
+		}
+	}
+}
+
+func (p *Picker) writeList(tasks []*store.Task) {
+	for i, task := range tasks[:min(len(tasks), maxListed)] {
+		_, _ = fmt.Fprintf(p.Out, "%3d) %s\n", i+1, Label(task))
+	}
+	if len(tasks) > maxListed {
+		_, _ = fmt.Fprintf(p.Out, "     ... %d more\n", len(tasks)-maxListed)
+	}
+}
+
+func filter(tasks []*store.Task, query string) []*store.Task {
+	var matched []*store.Task
+	for _, task := range tasks {
+		if Match(query, Label(task)) {
+			matched = append(matched, task)
+		}
+	}
+	return matched
+}
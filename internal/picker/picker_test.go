@@ -0,0 +1,90 @@
+package picker_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/picker"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+func testTasks() []*store.Task {
+	return []*store.Task{
+		{ID: 3, Title: "Write report", Projects: []string{"work"}},
+		{ID: 5, Title: "Call mom", Contexts: []string{"phone"}},
+		{ID: 8, Title: "Book flights", Projects: []string{"travel"}, Contexts: []string{"laptop"}},
+	}
+}
+
+func TestLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "#8 Book flights #travel @laptop", picker.Label(testTasks()[2]), "label mismatch")
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "report", want: true},
+		{query: "wr rep", want: true},
+		{query: "WRITE", want: true},
+		{query: "#work", want: true},
+		{query: "rpt wrk", want: true},
+		{query: "phone", want: false},
+		{query: "report phone", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, picker.Match(tt.query, "#3 Write report #work"), "match mismatch")
+		})
+	}
+}
+
+func TestPickNumbered(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		wantID int64
+		output string
+	}{
+		{name: "number", input: "2\n", wantID: 5, output: "  2) #5 Call mom @phone"},
+		{name: "narrow then number", input: "travel\n1\n", wantID: 8, output: "  1) #8 Book flights"},
+		{name: "out of range retries", input: "9\n1\n", wantID: 3, output: "No task numbered 9"},
+		{name: "no match retries", input: "xyz\n3\n", wantID: 8, output: `No tasks match "xyz"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			p := &picker.Picker{In: strings.NewReader(tt.input), Out: &out}
+			task, err := p.Pick(testTasks())
+			require.NoError(t, err, "pick error")
+			assert.Equal(t, tt.wantID, task.ID, "picked task mismatch")
+			assert.Contains(t, out.String(), tt.output, "prompt output mismatch")
+		})
+	}
+}
+
+func TestPickNumberedCancels(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "\n", "nothing-matches"} {
+		p := &picker.Picker{In: strings.NewReader(input), Out: &bytes.Buffer{}}
+		_, err := p.Pick(testTasks())
+		require.ErrorIs(t, err, picker.ErrCanceled, "input %q should cancel", input)
+	}
+
+	_, err := (&picker.Picker{In: strings.NewReader("1\n"), Out: &bytes.Buffer{}}).Pick(nil)
+	require.ErrorIs(t, err, picker.ErrNoTasks, "empty list should fail")
+}
@@ -0,0 +1,59 @@
+// Package prompt asks questions on line-oriented input for the interactive
+// clarify and review walkthroughs.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrQuit reports that the user quit or that input ended at a prompt. Work
+// already done is kept.
+var ErrQuit = errors.New("quit")
+
+// Prompter writes prompts to out and reads the answers from in.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// New creates a Prompter that reads from in and writes to out.
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ReadLine prompts for and reads one trimmed line. End of input returns
+// ErrQuit.
+func (p *Prompter) ReadLine(prompt string) (string, error) {
+	p.Printf("%s", prompt)
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", ErrQuit
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ReadRune reads a single character without waiting for a newline when the
+// terminal is in raw mode. End of input returns ErrQuit.
+func (p *Prompter) ReadRune() (rune, error) {
+	key, _, err := p.in.ReadRune()
+	if errors.Is(err, io.EOF) {
+		return 0, ErrQuit
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read input: %w", err)
+	}
+	return key, nil
+}
+
+// Printf writes formatted output. Write errors are ignored, as the next read
+// reports a closed terminal.
+func (p *Prompter) Printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(p.out, format, args...)
+}
@@ -0,0 +1,44 @@
+package prompt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/prompt"
+)
+
+func TestReadLine(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	p := prompt.New(strings.NewReader("  first  \nlast"), &out)
+
+	line, err := p.ReadLine("Name: ")
+	require.NoError(t, err, "ReadLine error")
+	assert.Equal(t, "first", line, "line should be trimmed")
+
+	line, err = p.ReadLine("Again: ")
+	require.NoError(t, err, "ReadLine without newline error")
+	assert.Equal(t, "last", line, "unterminated last line mismatch")
+	assert.Equal(t, "Name: Again: ", out.String(), "prompts mismatch")
+
+	_, err = p.ReadLine("More: ")
+	require.ErrorIs(t, err, prompt.ErrQuit, "end of input should quit")
+}
+
+func TestReadRune(t *testing.T) {
+	t.Parallel()
+
+	p := prompt.New(strings.NewReader("é"), &bytes.Buffer{})
+
+	key, err := p.ReadRune()
+	require.NoError(t, err, "ReadRune error")
+	assert.Equal(t, 'é', key, "rune mismatch")
+
+	_, err = p.ReadRune()
+	require.ErrorIs(t, err, prompt.ErrQuit, "end of input should quit")
+}
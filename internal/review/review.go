@@ -0,0 +1,411 @@
+// Package review runs the GTD weekly review: a fixed checklist of steps that
+// each list the tasks worth a second look and take inline edits to them.
+package review
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/nlp/compile"
+	"github.com/mholtzscher/ugh/internal/picker"
+	"github.com/mholtzscher/ugh/internal/prompt"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+const (
+	// DefaultWaitingDays is how long a task waits before the review asks
+	// about it.
+	DefaultWaitingDays = 7
+	// DefaultUpcomingDays is how far ahead the review looks for due dates.
+	DefaultUpcomingDays = 7
+	// DefaultStaleDays is how long a later task goes untouched before the
+	// review calls it stale.
+	DefaultStaleDays = 30
+)
+
+const editPrompt = "Edit (<n> <changes>), enter for the next step, q to quit: "
+
+// Options configures a Reviewer. Zero day counts use the defaults.
+type Options struct {
+	In  io.Reader
+	Out io.Writer
+	// Now is the time the review runs at; it defaults to time.Now.
+	Now          func() time.Time
+	WaitingDays  int
+	UpcomingDays int
+	StaleDays    int
+}
+
+// Item is a task a step lists, with the reason it is listed.
+type Item struct {
+	Task *store.Task
+	Note string
+}
+
+// Step is one part of the review checklist.
+type Step struct {
+	Title string
+	// Empty is shown instead of the list when the step has no tasks.
+	Empty string
+	Items []Item
+}
+
+// Summary reports how far the review got.
+type Summary struct {
+	Steps     int `json:"steps"`
+	StepsDone int `json:"stepsDone"`
+	// Reviewed and Changed count distinct tasks.
+	Reviewed int `json:"reviewed"`
+	Changed  int `json:"changed"`
+	// Recorded is set once every step is done and the review is stored.
+	Recorded bool `json:"recorded"`
+}
+
+func (s Summary) String() string {
+	if !s.Recorded {
+		return fmt.Sprintf("Review stopped after %d of %d steps; not recorded", s.StepsDone, s.Steps)
+	}
+	return fmt.Sprintf("Weekly review complete: reviewed %d tasks, changed %d", s.Reviewed, s.Changed)
+}
+
+// Reviewer walks through the weekly review checklist.
+type Reviewer struct {
+	svc          service.Service
+	ui           *prompt.Prompter
+	now          func() time.Time
+	waitingDays  int
+	upcomingDays int
+	staleDays    int
+}
+
+// New creates a Reviewer that reads and changes tasks through svc.
+func New(svc service.Service, opts Options) *Reviewer {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Reviewer{
+		svc:          svc,
+		ui:           prompt.New(opts.In, opts.Out),
+		now:          now,
+		waitingDays:  cmp.Or(opts.WaitingDays, DefaultWaitingDays),
+		upcomingDays: cmp.Or(opts.UpcomingDays, DefaultUpcomingDays),
+		staleDays:    cmp.Or(opts.StaleDays, DefaultStaleDays),
+	}
+}
+
+// Steps builds the checklist from the current tasks: long waits, due dates,
+// projects without a next action, stale later tasks and the week's wins.
+func (r *Reviewer) Steps(ctx context.Context) ([]Step, error) {
+	todo, err := r.svc.ListTasks(ctx, service.ListTasksRequest{
+		TodoOnly: true,
+		Sort:     []nlp.SortKey{{Field: nlp.SortFieldID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	done, err := r.svc.ListTasks(ctx, service.ListTasksRequest{
+		DoneOnly: true,
+		Sort:     []nlp.SortKey{{Field: nlp.SortFieldID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list done tasks: %w", err)
+	}
+
+	now := r.now()
+	waiting, err := r.waitingStep(ctx, todo, now)
+	if err != nil {
+		return nil, err
+	}
+	return []Step{
+		waiting,
+		r.dueStep(todo, now),
+		projectsStep(todo),
+		r.staleStep(todo, now),
+		doneStep(done, now),
+	}, nil
+}
+
+func (r *Reviewer) waitingStep(ctx context.Context, tasks []*store.Task, now time.Time) (Step, error) {
+	step := Step{
+		Title: fmt.Sprintf("Waiting for more than %d days", r.waitingDays),
+		Empty: "Nothing has been waiting that long",
+	}
+	since, err := r.svc.ListWaitingSince(ctx)
+	if err != nil {
+		return Step{}, err
+	}
+	for _, task := range tasks {
+		if task.State != store.StateWaiting {
+			continue
+		}
+		start, ok := since[task.ID]
+		if !ok {
+			start = task.UpdatedAt
+		}
+		days := daysBetween(start, now)
+		if days < r.waitingDays {
+			continue
+		}
+		note := fmt.Sprintf("waiting %d days", days)
+		if task.WaitingFor != "" {
+			note += " on " + task.WaitingFor
+		}
+		step.Items = append(step.Items, Item{Task: task, Note: note})
+	}
+	slices.SortStableFunc(step.Items, func(a, b Item) int {
+		return since[a.Task.ID].Compare(since[b.Task.ID])
+	})
+	return step, nil
+}
+
+func (r *Reviewer) dueStep(tasks []*store.Task, now time.Time) Step {
+	step := Step{
+		Title: fmt.Sprintf("Overdue and due in the next %d days", r.upcomingDays),
+		Empty: "Nothing is overdue or coming up",
+	}
+	today := domain.StartOfDay(now)
+	horizon := today.AddDate(0, 0, r.upcomingDays)
+	for _, task := range tasks {
+		if task.DueOn == nil {
+			continue
+		}
+		due := domain.DateIn(*task.DueOn, now.Location())
+		if due.After(horizon) {
+			continue
+		}
+		step.Items = append(step.Items, Item{Task: task, Note: dueNote(due, today)})
+	}
+	slices.SortStableFunc(step.Items, func(a, b Item) int {
+		return a.Task.DueOn.Compare(*b.Task.DueOn)
+	})
+	return step
+}
+
+// projectsStep lists the open tasks of every project that has no now task,
+// so one of them can be made the next action.
+func projectsStep(tasks []*store.Task) Step {
+	step := Step{
+		Title: "Projects with no now action",
+		Empty: "Every project has a now action",
+	}
+	byProject := map[string][]*store.Task{}
+	active := map[string]bool{}
+	for _, task := range tasks {
+		for _, project := range task.Projects {
+			byProject[project] = append(byProject[project], task)
+			if task.State == store.StateNow {
+				active[project] = true
+			}
+		}
+	}
+	listed := map[int64]bool{}
+	for _, project := range slices.Sorted(maps.Keys(byProject)) {
+		if active[project] {
+			continue
+		}
+		for _, task := range byProject[project] {
+			if listed[task.ID] {
+				continue
+			}
+			listed[task.ID] = true
+			step.Items = append(step.Items, Item{Task: task, Note: fmt.Sprintf("#%s, %s", project, task.State)})
+		}
+	}
+	return step
+}
+
+func (r *Reviewer) staleStep(tasks []*store.Task, now time.Time) Step {
+	step := Step{
+		Title: fmt.Sprintf("Later tasks untouched for %d days", r.staleDays),
+		Empty: "No stale later tasks",
+	}
+	for _, task := range tasks {
+		if task.State != store.StateLater {
+			continue
+		}
+		days := daysBetween(task.UpdatedAt, now)
+		if days < r.staleDays {
+			continue
+		}
+		step.Items = append(step.Items, Item{Task: task, Note: fmt.Sprintf("untouched %d days", days)})
+	}
+	slices.SortStableFunc(step.Items, func(a, b Item) int {
+		return a.Task.UpdatedAt.Compare(b.Task.UpdatedAt)
+	})
+	return step
+}
+
+// doneStep lists the tasks completed since Monday.
+func doneStep(tasks []*store.Task, now time.Time) Step {
+	step := Step{
+		Title: "Done this week",
+		Empty: "Nothing completed this week yet",
+	}
+	weekStart := startOfWeek(now)
+	for _, task := range tasks {
+		if task.CompletedAt == nil || task.CompletedAt.Before(weekStart) {
+			continue
+		}
+		note := "done " + task.CompletedAt.In(now.Location()).Format("Mon")
+		step.Items = append(step.Items, Item{Task: task, Note: note})
+	}
+	slices.SortStableFunc(step.Items, func(a, b Item) int {
+		return a.Task.CompletedAt.Compare(*b.Task.CompletedAt)
+	})
+	return step
+}
+
+// Run walks through every step, applying edits as they are entered, and
+// records the review once the last step is done. Quitting keeps the edits
+// but does not count as a review.
+func (r *Reviewer) Run(ctx context.Context) (Summary, error) {
+	steps, err := r.Steps(ctx)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{Steps: len(steps)}
+	reviewed := map[int64]bool{}
+	changed := map[int64]bool{}
+	for i := range steps {
+		for _, item := range steps[i].Items {
+			reviewed[item.Task.ID] = true
+		}
+		err = r.reviewStep(ctx, &steps[i], i+1, len(steps), changed)
+		summary.Reviewed, summary.Changed = len(reviewed), len(changed)
+		if errors.Is(err, prompt.ErrQuit) {
+			return summary, nil
+		}
+		if err != nil {
+			return summary, err
+		}
+		summary.StepsDone++
+	}
+
+	_, err = r.svc.RecordReview(ctx, store.Review{
+		CompletedAt:   r.now(),
+		TasksReviewed: int64(summary.Reviewed),
+		TasksChanged:  int64(summary.Changed),
+	})
+	if err != nil {
+		return summary, err
+	}
+	summary.Recorded = true
+	return summary, nil
+}
+
+// reviewStep shows step and applies edits until the user moves on. Steps
+// with nothing to review do not wait for input.
+func (r *Reviewer) reviewStep(ctx context.Context, step *Step, n, total int, changed map[int64]bool) error {
+	r.ui.Printf("\nStep %d of %d: %s\n", n, total, step.Title)
+	if len(step.Items) == 0 {
+		r.ui.Printf("  %s\n", step.Empty)
+		return nil
+	}
+	for i, item := range step.Items {
+		r.ui.Printf("  %d. %s (%s)\n", i+1, picker.Label(item.Task), item.Note)
+	}
+
+	for {
+		line, err := r.ui.ReadLine(editPrompt)
+		if err != nil {
+			return err
+		}
+		switch line {
+		case "":
+			return nil
+		case "q":
+			return prompt.ErrQuit
+		case "?":
+			r.printHelp()
+			continue
+		}
+		task, err := r.edit(ctx, step, line)
+		if err != nil {
+			r.ui.Printf("Error: %v\n", err)
+			continue
+		}
+		changed[task.ID] = true
+		r.ui.Printf("Updated %s\n", picker.Label(task))
+	}
+}
+
+// edit applies "<n> <changes>" to the nth task of step. The changes use the
+// same syntax as "set" in the shell.
+func (r *Reviewer) edit(ctx context.Context, step *Step, line string) (*store.Task, error) {
+	number, changes, _ := strings.Cut(line, " ")
+	index, err := strconv.Atoi(number)
+	if err != nil || index < 1 || index > len(step.Items) {
+		return nil, fmt.Errorf("pick a task from 1 to %d", len(step.Items))
+	}
+	changes = strings.TrimSpace(changes)
+	if changes == "" {
+		return nil, errors.New("usage: <n> <changes>, e.g. 1 state:now due:friday")
+	}
+
+	now := r.now()
+	parsed, err := nlp.Parse("set "+changes, nlp.ParseOptions{Mode: nlp.ModeUpdate, Now: now})
+	if err != nil {
+		return nil, err
+	}
+	if cmd, ok := parsed.Command.(*nlp.UpdateCommand); ok && (cmd.Target == nil || cmd.Target.Kind != nlp.TargetSelected) {
+		return nil, errors.New("edits apply to the listed task; leave out the task id")
+	}
+	item := &step.Items[index-1]
+	req, err := compile.BuildUpdateRequest(parsed, item.Task.ID, compile.BuildOptions{
+		Now:        now,
+		MetaSchema: r.svc.MetaSchema(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	task, err := r.svc.UpdateTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	item.Task = task
+	return task, nil
+}
+
+func (r *Reviewer) printHelp() {
+	r.ui.Printf(`  <n> <changes>  change the nth task, e.g. "2 state:now", "1 due:friday #home"
+  enter          go to the next step
+  q              quit; edits are kept but the review is not recorded
+`)
+}
+
+func dueNote(due, today time.Time) string {
+	days := daysBetween(today, due)
+	switch {
+	case days < 0:
+		return fmt.Sprintf("overdue %d days, due %s", -days, due.Format(domain.DateLayoutYYYYMMDD))
+	case days == 0:
+		return "due today"
+	default:
+		return fmt.Sprintf("due in %d days, %s", days, due.Format(domain.DateLayoutYYYYMMDD))
+	}
+}
+
+// daysBetween counts whole days from start to end; it is negative when end
+// comes first.
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours() / domain.HoursPerDay)
+}
+
+// startOfWeek returns midnight on the Monday of t's week.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return domain.StartOfDay(t).AddDate(0, 0, -offset)
+}
@@ -0,0 +1,155 @@
+package review_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/review"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+const daysLater = 40
+
+func newService(t *testing.T) service.Service {
+	t.Helper()
+
+	st, err := store.Open(context.Background(), store.Options{Path: filepath.Join(t.TempDir(), "test.sqlite")})
+	require.NoError(t, err, "open store error")
+	t.Cleanup(func() { _ = st.Close() })
+	return service.NewTaskService(st)
+}
+
+// seed creates a mix of tasks and returns the time daysLater days after
+// they were created, with the date one task is due on.
+func seed(t *testing.T, svc service.Service) (time.Time, string) {
+	t.Helper()
+
+	ctx := context.Background()
+	later := time.Now().AddDate(0, 0, daysLater)
+	due := later.AddDate(0, 0, 2).Format(domain.DateLayoutYYYYMMDD)
+	reqs := []service.CreateTaskRequest{
+		{Title: "Get quote", State: "waiting", WaitingFor: "Bob"},
+		{Title: "Pay rent", State: "now", DueOn: due},
+		{Title: "Renovate", State: "later", Projects: []string{"kitchen"}},
+		{Title: "Old idea", State: "later"},
+		{Title: "Ship it", State: "now", Projects: []string{"work"}},
+		{Title: "Draft spec", State: "later", Projects: []string{"work"}},
+	}
+	for _, req := range reqs {
+		_, err := svc.CreateTask(ctx, req)
+		require.NoError(t, err, "create %q", req.Title)
+	}
+	return time.Now().AddDate(0, 0, daysLater), due
+}
+
+func newReviewer(svc service.Service, now time.Time, input string, out *bytes.Buffer) *review.Reviewer {
+	return review.New(svc, review.Options{
+		In:  strings.NewReader(input),
+		Out: out,
+		Now: func() time.Time { return now },
+	})
+}
+
+func itemIDs(step review.Step) []int64 {
+	ids := make([]int64, 0, len(step.Items))
+	for _, item := range step.Items {
+		ids = append(ids, item.Task.ID)
+	}
+	return ids
+}
+
+func TestStepsListTasksNeedingReview(t *testing.T) {
+	t.Parallel()
+
+	svc := newService(t)
+	now, due := seed(t, svc)
+
+	steps, err := newReviewer(svc, now, "", &bytes.Buffer{}).Steps(context.Background())
+	require.NoError(t, err, "steps error")
+	require.Len(t, steps, 5, "step count mismatch")
+
+	assert.Equal(t, []int64{1}, itemIDs(steps[0]), "waiting step mismatch")
+	assert.Equal(t, "waiting 40 days on Bob", steps[0].Items[0].Note, "waiting note mismatch")
+	assert.Equal(t, []int64{2}, itemIDs(steps[1]), "due step mismatch")
+	assert.Equal(t, "due in 2 days, "+due, steps[1].Items[0].Note, "due note mismatch")
+	assert.Equal(t, []int64{3}, itemIDs(steps[2]), "projects step should skip projects with a now task")
+	assert.Equal(t, "#kitchen, later", steps[2].Items[0].Note, "project note mismatch")
+	assert.Equal(t, []int64{3, 4, 6}, itemIDs(steps[3]), "stale step mismatch")
+	assert.Empty(t, steps[4].Items, "nothing was done this week")
+}
+
+func TestStepsUseThresholds(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := newService(t)
+	seed(t, svc)
+	_, err := svc.SetDone(ctx, []int64{5}, true)
+	require.NoError(t, err, "set done error")
+
+	steps, err := newReviewer(svc, time.Now(), "", &bytes.Buffer{}).Steps(ctx)
+	require.NoError(t, err, "steps error")
+	assert.Empty(t, steps[0].Items, "fresh waiting tasks are not listed")
+	assert.Empty(t, steps[1].Items, "due dates past the window are not listed")
+	assert.Equal(t, []int64{3, 6}, itemIDs(steps[2]), "work lost its now task")
+	assert.Empty(t, steps[3].Items, "fresh later tasks are not stale")
+	assert.Equal(t, []int64{5}, itemIDs(steps[4]), "done step mismatch")
+}
+
+func TestRunAppliesEditsAndRecordsReview(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := newService(t)
+	now, _ := seed(t, svc)
+
+	var out bytes.Buffer
+	input := "1 state:now\n\n\n1 state:now @home\n\n\n"
+	summary, err := newReviewer(svc, now, input, &out).Run(ctx)
+	require.NoError(t, err, "run error")
+	assert.Equal(t, review.Summary{Steps: 5, StepsDone: 5, Reviewed: 5, Changed: 2, Recorded: true}, summary,
+		"summary mismatch")
+	assert.Equal(t, "Weekly review complete: reviewed 5 tasks, changed 2", summary.String(), "summary text mismatch")
+	assert.Contains(t, out.String(), "Step 1 of 5: Waiting for more than 7 days", "step header mismatch")
+	assert.Contains(t, out.String(), "Updated #3 Renovate #kitchen @home", "edit should be reported")
+	assert.Contains(t, out.String(), "Nothing completed this week yet", "empty step should say so")
+
+	quote, err := svc.GetTask(ctx, 1)
+	require.NoError(t, err, "get task 1")
+	assert.Equal(t, store.StateNow, quote.State, "quote state mismatch")
+
+	last, err := svc.LastReview(ctx)
+	require.NoError(t, err, "last review error")
+	require.NotNil(t, last, "review should be recorded")
+	assert.Equal(t, now.Unix(), last.CompletedAt.Unix(), "completed at mismatch")
+	assert.Equal(t, int64(2), last.TasksChanged, "changed count mismatch")
+}
+
+func TestRunQuitDoesNotRecord(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := newService(t)
+	now, _ := seed(t, svc)
+
+	var out bytes.Buffer
+	summary, err := newReviewer(svc, now, "9 state:now\n1\n1 #12\nq\n", &out).Run(ctx)
+	require.NoError(t, err, "run error")
+	assert.Equal(t, "Review stopped after 0 of 5 steps; not recorded", summary.String(), "summary text mismatch")
+	assert.Contains(t, out.String(), "Error: pick a task from 1 to 1", "bad index should be reported")
+	assert.Contains(t, out.String(), "Error: usage: <n> <changes>", "missing changes should be reported")
+	assert.Contains(t, out.String(), "Error: edits apply to the listed task", "explicit ids should be rejected")
+
+	last, err := svc.LastReview(ctx)
+	require.NoError(t, err, "last review error")
+	assert.Nil(t, last, "quitting should not record a review")
+}
@@ -0,0 +1,261 @@
+// Package schedule parses context availability rules such as
+// "Mon-Fri 09:00-17:00" and reports whether they are open at a given time.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Always is the schedule of a context that can be used at any time.
+	Always = "always"
+	// Never is the schedule of a context that is switched off.
+	Never = "never"
+)
+
+const (
+	minutesPerHour = 60
+	minutesPerDay  = 24 * minutesPerHour
+	daysPerWeek    = 7
+)
+
+//nolint:gochecknoglobals // Day names are a fixed lookup table.
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Schedule is a set of weekly windows. A time is open when any window covers
+// it. The zero Schedule is never open.
+type Schedule struct {
+	spec    string
+	windows []window
+}
+
+// window covers days at minutes [start, end) after midnight. A window whose
+// end is before its start runs past midnight into the next day.
+type window struct {
+	days  [daysPerWeek]bool
+	start int
+	end   int
+}
+
+// Parse reads a schedule: "always", "never", or comma-separated rules of
+// days, times or both, e.g. "Mon-Fri 09:00-17:00, Sat 10:00-14:00". Days are
+// names, ranges such as Mon-Fri or lists such as Mon/Wed; times are
+// HH:MM-HH:MM. A rule without days applies every day and one without times
+// applies all day.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	switch strings.ToLower(spec) {
+	case "":
+		return Schedule{}, errors.New("schedule is empty")
+	case Always:
+		return Schedule{spec: Always, windows: []window{allWeek()}}, nil
+	case Never:
+		return Schedule{spec: Never}, nil
+	}
+
+	schedule := Schedule{spec: spec}
+	for rule := range strings.SplitSeq(spec, ",") {
+		parsed, err := parseRule(rule)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("schedule %q: %w", spec, err)
+		}
+		schedule.windows = append(schedule.windows, parsed)
+	}
+	return schedule, nil
+}
+
+// String returns the schedule as it was written.
+func (s Schedule) String() string {
+	return s.spec
+}
+
+// Open reports whether the schedule covers t, in t's location.
+func (s Schedule) Open(t time.Time) bool {
+	minute := t.Hour()*minutesPerHour + t.Minute()
+	today := t.Weekday()
+	yesterday := (today + daysPerWeek - 1) % daysPerWeek
+	for _, w := range s.windows {
+		switch {
+		case w.start < w.end:
+			if w.days[today] && minute >= w.start && minute < w.end {
+				return true
+			}
+		case w.days[today] && minute >= w.start:
+			return true
+		case w.days[yesterday] && minute < w.end:
+			return true
+		}
+	}
+	return false
+}
+
+func parseRule(rule string) (window, error) {
+	fields := strings.Fields(rule)
+	var days, times string
+	switch len(fields) {
+	case 1:
+		if strings.Contains(fields[0], ":") {
+			times = fields[0]
+		} else {
+			days = fields[0]
+		}
+	case 2:
+		days, times = fields[0], fields[1]
+	default:
+		return window{}, fmt.Errorf("rule %q must be days, times or days then times", strings.TrimSpace(rule))
+	}
+
+	w := allWeek()
+	if days != "" {
+		w.days = [daysPerWeek]bool{}
+		if err := w.parseDays(days); err != nil {
+			return window{}, err
+		}
+	}
+	if times != "" {
+		if err := w.parseTimes(times); err != nil {
+			return window{}, err
+		}
+	}
+	return w, nil
+}
+
+func allWeek() window {
+	w := window{end: minutesPerDay}
+	for day := range w.days {
+		w.days[day] = true
+	}
+	return w
+}
+
+// parseDays reads "Mon-Fri", "Sat/Sun" or "Tue".
+func (w *window) parseDays(value string) error {
+	for part := range strings.SplitSeq(value, "/") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := parseDay(first)
+		if err != nil {
+			return err
+		}
+		to := from
+		if isRange {
+			if to, err = parseDay(last); err != nil {
+				return err
+			}
+		}
+		for day := from; ; day = (day + 1) % daysPerWeek {
+			w.days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseTimes reads "09:00-17:00". An end of 24:00 means midnight.
+func (w *window) parseTimes(value string) error {
+	first, last, ok := strings.Cut(value, "-")
+	if !ok {
+		return fmt.Errorf("times %q must be a range such as 09:00-17:00", value)
+	}
+	start, err := parseClock(first)
+	if err != nil {
+		return err
+	}
+	end, err := parseClock(last)
+	if err != nil {
+		return err
+	}
+	if start == end || start == minutesPerDay {
+		return fmt.Errorf("times %q must start before 24:00 and end at another time", value)
+	}
+	w.start, w.end = start, end
+	return nil
+}
+
+func parseDay(value string) (time.Weekday, error) {
+	day, ok := dayNames[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return 0, fmt.Errorf("unknown day %q", value)
+	}
+	return day, nil
+}
+
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	hour, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	minute, err := strconv.Atoi(minutes)
+	if err != nil || minute < 0 || minute >= minutesPerHour {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	total := hour*minutesPerHour + minute
+	if hour < 0 || total > minutesPerDay {
+		return 0, fmt.Errorf("time %q is outside 00:00-24:00", value)
+	}
+	return total, nil
+}
+
+// Table maps context names, without "@", to their schedules. Contexts that
+// are not listed are always available.
+type Table map[string]Schedule
+
+// ParseTable parses the schedule of each context. Keys may be written with a
+// leading "@" and match contexts regardless of case.
+func ParseTable(specs map[string]string) (Table, error) {
+	table := make(Table, len(specs))
+	for name, spec := range specs {
+		parsed, err := Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("contexts.%s: %w", name, err)
+		}
+		table[normalizeName(name)] = parsed
+	}
+	return table, nil
+}
+
+// Closed returns the contexts whose schedules are not open at t, sorted.
+func (t Table) Closed(at time.Time) []string {
+	closed := make([]string, 0)
+	for name, schedule := range t {
+		if !schedule.Open(at) {
+			closed = append(closed, name)
+		}
+	}
+	slices.Sort(closed)
+	return closed
+}
+
+// Available reports whether a task with contexts can be worked on at t: it
+// has no contexts, or at least one of them is open.
+func (t Table) Available(contexts []string, at time.Time) bool {
+	if len(contexts) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(contexts, func(name string) bool {
+		schedule, ok := t[normalizeName(name)]
+		return !ok || schedule.Open(at)
+	})
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+}
@@ -0,0 +1,82 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/schedule"
+)
+
+// at returns a time in the week of Monday 2026-03-09.
+func at(day time.Weekday, hour, minute int) time.Time {
+	return time.Date(2026, 3, 8+int(day), hour, minute, 0, 0, time.UTC)
+}
+
+func TestScheduleOpen(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec string
+		at   time.Time
+		want bool
+	}{
+		{name: "always", spec: "always", at: at(time.Sunday, 3, 0), want: true},
+		{name: "never", spec: "Never", at: at(time.Monday, 12, 0), want: false},
+		{name: "weekday hours open", spec: "Mon-Fri 09:00-17:00", at: at(time.Wednesday, 9, 0), want: true},
+		{name: "weekday hours end is exclusive", spec: "Mon-Fri 09:00-17:00", at: at(time.Friday, 17, 0), want: false},
+		{name: "weekday hours on weekend", spec: "Mon-Fri 09:00-17:00", at: at(time.Saturday, 12, 0), want: false},
+		{name: "second rule", spec: "Mon-Fri 09:00-17:00, Sat 10:00-14:00", at: at(time.Saturday, 12, 0), want: true},
+		{name: "days only", spec: "sat/sun", at: at(time.Sunday, 23, 59), want: true},
+		{name: "times only", spec: "18:00-22:00", at: at(time.Tuesday, 19, 30), want: true},
+		{name: "overnight before midnight", spec: "Fri 22:00-02:00", at: at(time.Friday, 23, 0), want: true},
+		{name: "overnight after midnight", spec: "Fri 22:00-02:00", at: at(time.Saturday, 1, 0), want: true},
+		{name: "overnight wrong day", spec: "Fri 22:00-02:00", at: at(time.Friday, 1, 0), want: false},
+		{name: "wrapping day range", spec: "Fri-Mon", at: at(time.Sunday, 12, 0), want: true},
+		{name: "end of day", spec: "Tue 20:00-24:00", at: at(time.Tuesday, 23, 59), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			parsed, err := schedule.Parse(tt.spec)
+			require.NoError(t, err, "Parse(%q) error", tt.spec)
+			assert.Equal(t, tt.want, parsed.Open(tt.at), "Open() mismatch for %q", tt.spec)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{"", "Funday", "Mon 9-17", "Mon 25:00-26:00", "Mon 09:00-09:00", "Mon 09:00 17:00"} {
+		_, err := schedule.Parse(spec)
+		assert.Error(t, err, "Parse(%q) should fail", spec)
+	}
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	table, err := schedule.ParseTable(map[string]string{
+		"@office": "Mon-Fri 09:00-17:00",
+		"phone":   "always",
+		"Errands": "Sat",
+	})
+	require.NoError(t, err, "ParseTable() error")
+
+	monday := at(time.Monday, 10, 0)
+	assert.Equal(t, []string{"errands"}, table.Closed(monday), "Closed() mismatch")
+	assert.True(t, table.Available(nil, monday), "tasks without contexts are available")
+	assert.True(t, table.Available([]string{"Office"}, monday), "context names should ignore case")
+	assert.True(t, table.Available([]string{"garden"}, monday), "unscheduled contexts are available")
+	assert.False(t, table.Available([]string{"errands"}, monday), "closed context should not be available")
+	assert.True(t, table.Available([]string{"errands", "phone"}, monday), "one open context is enough")
+
+	_, err = schedule.ParseTable(map[string]string{"office": "weekdays"})
+	require.Error(t, err, "ParseTable() should reject bad schedules")
+	assert.Contains(t, err.Error(), "contexts.office", "error should name the context")
+}
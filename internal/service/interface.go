@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 // Service defines the interface for task operations.
 // This is implemented by TaskService.
 type Service interface {
 	CreateTask(ctx context.Context, req CreateTaskRequest) (*store.Task, error)
+	CreateTasks(ctx context.Context, reqs []CreateTaskRequest) ([]*store.Task, error)
 	ListTasks(ctx context.Context, req ListTasksRequest) ([]*store.Task, error)
 	ListTaskVersions(ctx context.Context, taskID int64, limit int64) ([]*store.TaskVersion, error)
 	GetTask(ctx context.Context, id int64) (*store.Task, error)
@@ -17,11 +21,27 @@ type Service interface {
 	FullUpdateTask(ctx context.Context, req FullUpdateTaskRequest) (*store.Task, error)
 	SetDone(ctx context.Context, ids []int64, done bool) (int64, error)
 	DeleteTasks(ctx context.Context, ids []int64) (int64, error)
+	BulkUpdateTasks(ctx context.Context, ids []int64, req UpdateTaskRequest) ([]BulkTaskResult, error)
+	BatchUpdateTasks(ctx context.Context, reqs []UpdateTaskRequest) ([]BulkTaskResult, error)
+	BulkSetDone(ctx context.Context, ids []int64, done bool) ([]BulkTaskResult, error)
+	BulkDeleteTasks(ctx context.Context, ids []int64) ([]BulkTaskResult, error)
 	ListProjects(ctx context.Context, req ListTagsRequest) ([]store.NameCount, error)
 	ListContexts(ctx context.Context, req ListTagsRequest) ([]store.NameCount, error)
+	ListStalledProjects(ctx context.Context) ([]store.StalledProject, error)
+	ListWaitingSince(ctx context.Context) (map[int64]time.Time, error)
+	ListSnoozed(ctx context.Context) ([]*store.Task, error)
+	SnoozeTasks(ctx context.Context, ids []int64, until time.Time) ([]*store.Task, error)
+	MakeProject(ctx context.Context, id int64, project string, next string) (*store.Task, *store.Task, error)
+	WakeSnoozed(ctx context.Context, now time.Time) ([]*store.Task, error)
+	AddChecklistItem(ctx context.Context, id int64, text string) (*store.Task, error)
+	ToggleChecklistItem(ctx context.Context, id int64, n int) (*store.Task, error)
+	RemoveChecklistItem(ctx context.Context, id int64, n int) (*store.Task, error)
+	AddAnnotation(ctx context.Context, id int64, text string) (*store.Task, error)
 	Sync(ctx context.Context) error
 	Push(ctx context.Context) error
 	SyncStatus(ctx context.Context) (*SyncStatus, error)
+	MetaSchema() domain.MetaSchema
+	Urgency() urgency.Scorer
 	Close() error
 
 	// Shell history operations
@@ -33,6 +53,16 @@ type Service interface {
 		ctx context.Context, search, intent string, success *bool, limit int64,
 	) ([]*store.ShellHistory, error)
 	ClearShellHistory(ctx context.Context) error
+
+	// Shell session operations
+	SaveShellSession(ctx context.Context, session store.ShellSession) error
+	GetShellSession(ctx context.Context, name string) (*store.ShellSession, error)
+	ListShellSessions(ctx context.Context) ([]*store.ShellSession, error)
+	DeleteShellSession(ctx context.Context, name string) (bool, error)
+
+	// Weekly review operations
+	RecordReview(ctx context.Context, review store.Review) (*store.Review, error)
+	LastReview(ctx context.Context) (*store.Review, error)
 }
 
 // Ensure TaskService implements Service.
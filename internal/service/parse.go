@@ -8,14 +8,19 @@ import (
 	"github.com/mholtzscher/ugh/internal/store"
 )
 
-func parseMetaFlags(meta []string) (map[string]string, error) {
+func parseMetaFlags(meta []string, schema domain.MetaSchema) (map[string]string, error) {
 	result := map[string]string{}
 	for _, m := range meta {
 		k, v, ok := strings.Cut(m, domain.MetaSeparatorColon)
 		if !ok {
 			return nil, domain.InvalidMetaFormatError(m)
 		}
-		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		k = strings.TrimSpace(k)
+		normalized, err := schema.NormalizeValue(k, v)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = normalized
 	}
 	return result, nil
 }
@@ -29,6 +34,14 @@ func parseDay(value string) (*time.Time, error) {
 	return &utc, nil
 }
 
+// parseOptionalDay parses value like parseDay but treats a blank value as no date.
+func parseOptionalDay(value string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil //nolint:nilnil // a blank date means no date
+	}
+	return parseDay(value)
+}
+
 func normalizeState(value string) (store.State, error) {
 	normalized, err := domain.NormalizeState(value)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/store"
 )
 
@@ -58,12 +59,31 @@ func TestParseDay(t *testing.T) {
 func TestParseMetaFlags(t *testing.T) {
 	t.Parallel()
 
-	meta, err := parseMetaFlags([]string{"a:1", " b : 2 "})
+	meta, err := parseMetaFlags([]string{"a:1", " b : 2 "}, nil)
 	require.NoError(t, err, "parseMetaFlags(valid) error")
 	require.Len(t, meta, 2, "parseMetaFlags(valid) len mismatch")
 	assert.Equal(t, "1", meta["a"], "parseMetaFlags(valid) a value mismatch")
 	assert.Equal(t, "2", meta["b"], "parseMetaFlags(valid) b value mismatch")
 
-	_, err = parseMetaFlags([]string{"missing-separator"})
+	_, err = parseMetaFlags([]string{"missing-separator"}, nil)
 	require.Error(t, err, "parseMetaFlags(invalid) should return error")
 }
+
+func TestParseMetaFlagsValidatesSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := domain.MetaSchema{
+		"points": {Type: domain.MetaTypeInt},
+		"size":   {Type: domain.MetaTypeEnum, Values: []string{"s", "m", "l"}},
+	}
+
+	meta, err := parseMetaFlags([]string{"points: 08", "size:L", "owner:alice"}, schema)
+	require.NoError(t, err, "parseMetaFlags(typed) error")
+	assert.Equal(t, map[string]string{"points": "8", "size": "l", "owner": "alice"}, meta)
+
+	_, err = parseMetaFlags([]string{"points:many"}, schema)
+	require.Error(t, err, "parseMetaFlags(invalid int) should return error")
+
+	_, err = parseMetaFlags([]string{"size:xl"}, schema)
+	require.Error(t, err, "parseMetaFlags(invalid enum) should return error")
+}
@@ -1,6 +1,9 @@
 package service
 
-import "github.com/mholtzscher/ugh/internal/nlp"
+import (
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/store"
+)
 
 type CreateTaskRequest struct {
 	Title      string
@@ -11,6 +14,7 @@ type CreateTaskRequest struct {
 	Meta       []string
 	DueOn      string
 	WaitingFor string
+	FollowUpOn string
 }
 
 type ListTasksRequest struct {
@@ -20,6 +24,7 @@ type ListTasksRequest struct {
 	Filter   nlp.FilterExpr
 	Recent   bool
 	Limit    int64
+	Sort     []nlp.SortKey
 }
 
 type ListTagsRequest struct {
@@ -35,6 +40,7 @@ type UpdateTaskRequest struct {
 	State           *string
 	DueOn           *string
 	WaitingFor      *string
+	FollowUpOn      *string
 	AddProjects     []string
 	AddContexts     []string
 	SetMeta         map[string]string
@@ -43,6 +49,7 @@ type UpdateTaskRequest struct {
 	RemoveMetaKeys  []string
 	ClearDueOn      bool
 	ClearWaitingFor bool
+	ClearFollowUpOn bool
 }
 
 type FullUpdateTaskRequest struct {
@@ -55,6 +62,9 @@ type FullUpdateTaskRequest struct {
 	Meta       map[string]string
 	DueOn      string
 	WaitingFor string
+	FollowUpOn string
+	Checklist  []store.ChecklistItem
+	Annotation string
 }
 
 type SyncStatus struct {
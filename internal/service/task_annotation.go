@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+// AddAnnotation appends a timestamped annotation to a task and returns the
+// task with its annotations.
+func (s *TaskService) AddAnnotation(ctx context.Context, id int64, text string) (*store.Task, error) {
+	if _, err := s.store.GetTask(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.AddAnnotation(ctx, id, text); err != nil {
+		return nil, err
+	}
+	return s.store.GetTask(ctx, id)
+}
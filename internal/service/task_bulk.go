@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+const (
+	BulkStatusUpdated   = "updated"
+	BulkStatusDone      = "done"
+	BulkStatusReopened  = "reopened"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusDeleted   = "deleted"
+)
+
+// BulkTaskResult reports what a bulk operation did to one task.
+type BulkTaskResult struct {
+	ID     int64
+	Title  string
+	Status string
+}
+
+// CreateTasks creates a task for each request, in order, within one
+// transaction. Any failure rolls back all creates.
+func (s *TaskService) CreateTasks(ctx context.Context, reqs []CreateTaskRequest) ([]*store.Task, error) {
+	tasks := make([]*store.Task, 0, len(reqs))
+	err := s.inTx(ctx, func(tx *TaskService) error {
+		for _, req := range reqs {
+			task, err := tx.CreateTask(ctx, req)
+			if err != nil {
+				return fmt.Errorf("create %q: %w", req.Title, err)
+			}
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// BulkUpdateTasks applies req to every task in ids within one transaction;
+// req.ID is ignored. Any failure rolls back all updates.
+func (s *TaskService) BulkUpdateTasks(
+	ctx context.Context, ids []int64, req UpdateTaskRequest,
+) ([]BulkTaskResult, error) {
+	reqs := make([]UpdateTaskRequest, 0, len(ids))
+	for _, id := range ids {
+		taskReq := req
+		taskReq.ID = id
+		reqs = append(reqs, taskReq)
+	}
+	return s.BatchUpdateTasks(ctx, reqs)
+}
+
+// BatchUpdateTasks applies each request to its own task within one
+// transaction. Any failure rolls back all updates.
+func (s *TaskService) BatchUpdateTasks(ctx context.Context, reqs []UpdateTaskRequest) ([]BulkTaskResult, error) {
+	results := make([]BulkTaskResult, 0, len(reqs))
+	err := s.inTx(ctx, func(tx *TaskService) error {
+		for _, req := range reqs {
+			task, err := tx.UpdateTask(ctx, req)
+			if err != nil {
+				return fmt.Errorf("task #%d: %w", req.ID, err)
+			}
+			results = append(results, BulkTaskResult{ID: task.ID, Title: task.Title, Status: BulkStatusUpdated})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// BulkSetDone marks every task in ids done (or reopens it) within one
+// transaction. Tasks already in the requested state are reported unchanged.
+func (s *TaskService) BulkSetDone(ctx context.Context, ids []int64, done bool) ([]BulkTaskResult, error) {
+	status := BulkStatusReopened
+	if done {
+		status = BulkStatusDone
+	}
+	results := make([]BulkTaskResult, 0, len(ids))
+	err := s.inTx(ctx, func(tx *TaskService) error {
+		for _, id := range ids {
+			task, err := tx.store.GetTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			changed, err := tx.store.SetDone(ctx, []int64{id}, done)
+			if err != nil {
+				return err
+			}
+			result := BulkTaskResult{ID: task.ID, Title: task.Title, Status: status}
+			if changed == 0 {
+				result.Status = BulkStatusUnchanged
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// BulkDeleteTasks deletes every task in ids within one transaction.
+func (s *TaskService) BulkDeleteTasks(ctx context.Context, ids []int64) ([]BulkTaskResult, error) {
+	results := make([]BulkTaskResult, 0, len(ids))
+	err := s.inTx(ctx, func(tx *TaskService) error {
+		for _, id := range ids {
+			task, err := tx.store.GetTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			if _, err = tx.store.DeleteTasks(ctx, []int64{id}); err != nil {
+				return err
+			}
+			results = append(results, BulkTaskResult{ID: task.ID, Title: task.Title, Status: BulkStatusDeleted})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *TaskService) inTx(ctx context.Context, fn func(tx *TaskService) error) error {
+	return s.store.WithTx(ctx, func(txStore *store.Store) error {
+		tx := *s
+		tx.store = txStore
+		return fn(&tx)
+	})
+}
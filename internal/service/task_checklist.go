@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+// AddChecklistItem appends an unchecked item to a task's checklist.
+func (s *TaskService) AddChecklistItem(ctx context.Context, id int64, text string) (*store.Task, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("checklist item text is required")
+	}
+	task, err := s.store.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	task.Checklist = append(task.Checklist, store.ChecklistItem{Text: text})
+	return s.store.UpdateTask(ctx, task)
+}
+
+// ToggleChecklistItem checks or unchecks item n of a task's checklist,
+// counting from 1.
+func (s *TaskService) ToggleChecklistItem(ctx context.Context, id int64, n int) (*store.Task, error) {
+	task, err := s.checklistTask(ctx, id, n)
+	if err != nil {
+		return nil, err
+	}
+	task.Checklist[n-1].Done = !task.Checklist[n-1].Done
+	return s.store.UpdateTask(ctx, task)
+}
+
+// RemoveChecklistItem removes item n of a task's checklist, counting from 1.
+func (s *TaskService) RemoveChecklistItem(ctx context.Context, id int64, n int) (*store.Task, error) {
+	task, err := s.checklistTask(ctx, id, n)
+	if err != nil {
+		return nil, err
+	}
+	task.Checklist = append(task.Checklist[:n-1], task.Checklist[n:]...)
+	return s.store.UpdateTask(ctx, task)
+}
+
+// checklistTask loads a task and checks that it has checklist item n.
+func (s *TaskService) checklistTask(ctx context.Context, id int64, n int) (*store.Task, error) {
+	task, err := s.store.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 || n > len(task.Checklist) {
+		return nil, fmt.Errorf("task #%d has no checklist item %d", id, n)
+	}
+	return task, nil
+}
@@ -1,15 +1,19 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/nlp"
 	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 const defaultRecentLimit int64 = 20
@@ -28,9 +32,22 @@ func (s *TaskService) ListTasks(ctx context.Context, req ListTasksRequest) ([]*s
 		effectiveLimit = defaultRecentLimit
 	}
 
-	opts := store.ListTasksByExprOptions{}
+	urgencyKey, sortKeys, err := splitUrgencySort(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := store.ListTasksByExprOptions{
+		MetaSchema:     s.metaSchema,
+		Sort:           sortKeys,
+		ClosedContexts: s.schedules.Closed(time.Now()),
+	}
 	opts.Recent = recentEnabled || req.Recent
 	opts.Limit = effectiveLimit
+	if urgencyKey != nil && !opts.Recent {
+		// Scores are computed here, so limit after sorting by them.
+		opts.Limit = 0
+	}
 	switch {
 	case req.All:
 		// no-op
@@ -44,7 +61,50 @@ func (s *TaskService) ListTasks(ctx context.Context, req ListTasksRequest) ([]*s
 		opts.ExcludeDone = !exprReferencesStateDone(expr) && !exprReferencesID(expr)
 	}
 
-	return s.store.ListTasksByExpr(ctx, expr, opts)
+	tasks, err := s.store.ListTasksByExpr(ctx, expr, opts)
+	if err != nil || urgencyKey == nil {
+		return tasks, err
+	}
+	return s.sortByUrgency(tasks, urgencyKey.Desc, effectiveLimit), nil
+}
+
+// splitUrgencySort separates a leading urgency key from the keys the store
+// sorts by, which then break ties between equal scores. Urgency is scored
+// after the query, so the store cannot order by it behind other keys.
+func splitUrgencySort(keys []nlp.SortKey) (*nlp.SortKey, []nlp.SortKey, error) {
+	for i, key := range keys {
+		if key.Field != nlp.SortFieldUrgency {
+			continue
+		}
+		if i > 0 {
+			return nil, nil, errors.New(
+				"urgency must be the first sort key; it is scored after the query, so later keys can only break its ties",
+			)
+		}
+		return &keys[0], keys[1:], nil
+	}
+	return nil, keys, nil
+}
+
+// sortByUrgency orders tasks least urgent first like other ascending keys, or
+// most urgent first for -urgency, keeping the store order between equal
+// scores.
+func (s *TaskService) sortByUrgency(tasks []*store.Task, desc bool, limit int64) []*store.Task {
+	scored := s.scorer.ScoreAll(tasks, time.Now())
+	slices.SortStableFunc(scored, func(a, b urgency.Scored) int {
+		if desc {
+			return cmp.Compare(b.Score.Total, a.Score.Total)
+		}
+		return cmp.Compare(a.Score.Total, b.Score.Total)
+	})
+	if limit > 0 && int64(len(scored)) > limit {
+		scored = scored[:limit]
+	}
+	sorted := make([]*store.Task, 0, len(scored))
+	for _, item := range scored {
+		sorted = append(sorted, item.Task)
+	}
+	return sorted
 }
 
 //nolint:gocognit // Recursive AST stripping needs explicit per-node branching.
@@ -168,6 +228,31 @@ func (s *TaskService) ListProjects(ctx context.Context, req ListTagsRequest) ([]
 	return s.store.ListProjectCounts(ctx, onlyDone, excludeDone)
 }
 
+// ListStalledProjects returns the projects with open tasks but no now task.
+func (s *TaskService) ListStalledProjects(ctx context.Context) ([]store.StalledProject, error) {
+	return s.store.ListStalledProjects(ctx)
+}
+
+// ListWaitingSince returns when each waiting task entered the waiting state.
+func (s *TaskService) ListWaitingSince(ctx context.Context) (map[int64]time.Time, error) {
+	return s.store.ListWaitingSince(ctx)
+}
+
+// ListSnoozed returns the snoozed tasks, soonest to return first.
+func (s *TaskService) ListSnoozed(ctx context.Context) ([]*store.Task, error) {
+	tasks, err := s.store.ListTasksByExpr(ctx, nil, store.ListTasksByExprOptions{
+		MetaSchema:  s.metaSchema,
+		OnlySnoozed: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(tasks, func(a, b *store.Task) int {
+		return cmp.Or(a.SnoozedUntil.Compare(*b.SnoozedUntil), cmp.Compare(a.ID, b.ID))
+	})
+	return tasks, nil
+}
+
 func (s *TaskService) ListContexts(ctx context.Context, req ListTagsRequest) ([]store.NameCount, error) {
 	onlyDone := req.DoneOnly
 	excludeDone := req.TodoOnly
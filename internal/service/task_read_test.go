@@ -150,3 +150,25 @@ func TestStripRecentModifier_ConflictingLimitsReturnsError(t *testing.T) {
 	require.Error(t, err, "stripRecentModifier() should reject conflicting limits")
 	assert.Contains(t, err.Error(), "conflicting recent limits", "error should mention conflict")
 }
+
+func TestSplitUrgencySort(t *testing.T) {
+	t.Parallel()
+
+	urgencyKey, rest, err := splitUrgencySort([]nlp.SortKey{
+		{Field: nlp.SortFieldUrgency, Desc: true},
+		{Field: nlp.SortFieldDue},
+	})
+	require.NoError(t, err, "splitUrgencySort() error")
+	require.NotNil(t, urgencyKey, "splitUrgencySort() should return the urgency key")
+	assert.True(t, urgencyKey.Desc, "urgency key should keep its direction")
+	assert.Equal(t, []nlp.SortKey{{Field: nlp.SortFieldDue}}, rest, "remaining keys mismatch")
+
+	urgencyKey, rest, err = splitUrgencySort([]nlp.SortKey{{Field: nlp.SortFieldDue}})
+	require.NoError(t, err, "splitUrgencySort(no urgency) error")
+	assert.Nil(t, urgencyKey, "splitUrgencySort(no urgency) should return no urgency key")
+	assert.Equal(t, []nlp.SortKey{{Field: nlp.SortFieldDue}}, rest, "keys without urgency should pass through")
+
+	_, _, err = splitUrgencySort([]nlp.SortKey{{Field: nlp.SortFieldDue}, {Field: nlp.SortFieldUrgency}})
+	require.Error(t, err, "splitUrgencySort() should reject urgency after another key")
+	assert.Contains(t, err.Error(), "first sort key", "error should explain the rule")
+}
@@ -3,19 +3,62 @@ package service
 import (
 	"context"
 
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/schedule"
 	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 type TaskService struct {
-	store *store.Store
+	store      *store.Store
+	metaSchema domain.MetaSchema
+	scorer     urgency.Scorer
+	schedules  schedule.Table
 }
 
-func NewTaskService(store *store.Store) *TaskService {
-	return &TaskService{
-		store: store,
+// Option configures optional TaskService behavior.
+type Option func(*TaskService)
+
+// WithMetaSchema validates and normalizes typed meta keys on write.
+func WithMetaSchema(schema domain.MetaSchema) Option {
+	return func(s *TaskService) {
+		s.metaSchema = schema
+	}
+}
+
+// WithUrgency sets the scorer used to sort by urgency.
+func WithUrgency(scorer urgency.Scorer) Option {
+	return func(s *TaskService) {
+		s.scorer = scorer
+	}
+}
+
+// WithSchedules sets the context schedules used by the available filter.
+func WithSchedules(schedules schedule.Table) Option {
+	return func(s *TaskService) {
+		s.schedules = schedules
 	}
 }
 
+func NewTaskService(store *store.Store, opts ...Option) *TaskService {
+	s := &TaskService{
+		store:  store,
+		scorer: urgency.NewScorer(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *TaskService) MetaSchema() domain.MetaSchema {
+	return s.metaSchema
+}
+
+func (s *TaskService) Urgency() urgency.Scorer {
+	return s.scorer
+}
+
 func (s *TaskService) Close() error {
 	return s.store.Close()
 }
@@ -66,3 +109,27 @@ func (s *TaskService) SearchShellHistory(
 func (s *TaskService) ClearShellHistory(ctx context.Context) error {
 	return s.store.ClearShellHistory(ctx)
 }
+
+func (s *TaskService) SaveShellSession(ctx context.Context, session store.ShellSession) error {
+	return s.store.SaveShellSession(ctx, session)
+}
+
+func (s *TaskService) GetShellSession(ctx context.Context, name string) (*store.ShellSession, error) {
+	return s.store.GetShellSession(ctx, name)
+}
+
+func (s *TaskService) ListShellSessions(ctx context.Context) ([]*store.ShellSession, error) {
+	return s.store.ListShellSessions(ctx)
+}
+
+func (s *TaskService) DeleteShellSession(ctx context.Context, name string) (bool, error) {
+	return s.store.DeleteShellSession(ctx, name)
+}
+
+func (s *TaskService) RecordReview(ctx context.Context, review store.Review) (*store.Review, error) {
+	return s.store.RecordReview(ctx, review)
+}
+
+func (s *TaskService) LastReview(ctx context.Context) (*store.Review, error) {
+	return s.store.LastReview(ctx)
+}
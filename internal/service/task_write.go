@@ -8,11 +8,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/store"
 )
 
 func (s *TaskService) CreateTask(ctx context.Context, req CreateTaskRequest) (*store.Task, error) {
-	meta, err := parseMetaFlags(req.Meta)
+	meta, err := parseMetaFlags(req.Meta, s.metaSchema)
 	if err != nil {
 		return nil, fmt.Errorf("parse meta: %w", err)
 	}
@@ -22,13 +23,13 @@ func (s *TaskService) CreateTask(ctx context.Context, req CreateTaskRequest) (*s
 		return nil, err
 	}
 
-	var dueOn *time.Time
-	if strings.TrimSpace(req.DueOn) != "" {
-		parsed, parseErr := parseDay(req.DueOn)
-		if parseErr != nil {
-			return nil, parseErr
-		}
-		dueOn = parsed
+	dueOn, err := parseOptionalDay(req.DueOn)
+	if err != nil {
+		return nil, err
+	}
+	followUpOn, err := parseOptionalDay(req.FollowUpOn)
+	if err != nil {
+		return nil, err
 	}
 	task := &store.Task{
 		State:      state,
@@ -36,6 +37,7 @@ func (s *TaskService) CreateTask(ctx context.Context, req CreateTaskRequest) (*s
 		Notes:      req.Notes,
 		DueOn:      dueOn,
 		WaitingFor: strings.TrimSpace(req.WaitingFor),
+		FollowUpOn: followUpOn,
 		Projects:   req.Projects,
 		Contexts:   req.Contexts,
 		Meta:       meta,
@@ -60,17 +62,20 @@ func (s *TaskService) UpdateTask(ctx context.Context, req UpdateTaskRequest) (*s
 	}
 
 	updated := &store.Task{
-		ID:          current.ID,
-		State:       current.State,
-		PrevState:   current.PrevState,
-		Title:       current.Title,
-		Notes:       current.Notes,
-		DueOn:       current.DueOn,
-		WaitingFor:  current.WaitingFor,
-		CompletedAt: current.CompletedAt,
-		Projects:    append([]string(nil), current.Projects...),
-		Contexts:    append([]string(nil), current.Contexts...),
-		Meta:        copyMeta(current.Meta),
+		ID:           current.ID,
+		State:        current.State,
+		PrevState:    current.PrevState,
+		Title:        current.Title,
+		Notes:        current.Notes,
+		DueOn:        current.DueOn,
+		WaitingFor:   current.WaitingFor,
+		FollowUpOn:   current.FollowUpOn,
+		SnoozedUntil: current.SnoozedUntil,
+		CompletedAt:  current.CompletedAt,
+		Projects:     append([]string(nil), current.Projects...),
+		Contexts:     append([]string(nil), current.Contexts...),
+		Meta:         copyMeta(current.Meta),
+		Checklist:    append([]store.ChecklistItem(nil), current.Checklist...),
 	}
 
 	if req.Title != nil {
@@ -94,21 +99,33 @@ func (s *TaskService) UpdateTask(ctx context.Context, req UpdateTaskRequest) (*s
 			updated.PrevState = nil
 			updated.CompletedAt = nil
 		}
+		// Moving a snoozed task by hand ends the snooze.
+		if state != updated.State && updated.SnoozedUntil != nil {
+			if state != store.StateDone {
+				updated.PrevState = nil
+			}
+			updated.SnoozedUntil = nil
+		}
 		updated.State = state
 	}
 	// done is represented as state=done; completion toggles are handled by the done/undo commands.
 	if req.ClearDueOn {
 		updated.DueOn = nil
 	} else if req.DueOn != nil {
-		if strings.TrimSpace(*req.DueOn) == "" {
-			updated.DueOn = nil
-		} else {
-			parsed, parseErr := parseDay(*req.DueOn)
-			if parseErr != nil {
-				return nil, parseErr
-			}
-			updated.DueOn = parsed
+		parsed, parseErr := parseOptionalDay(*req.DueOn)
+		if parseErr != nil {
+			return nil, parseErr
 		}
+		updated.DueOn = parsed
+	}
+	if req.ClearFollowUpOn {
+		updated.FollowUpOn = nil
+	} else if req.FollowUpOn != nil {
+		parsed, parseErr := parseOptionalDay(*req.FollowUpOn)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		updated.FollowUpOn = parsed
 	}
 	if req.ClearWaitingFor {
 		updated.WaitingFor = ""
@@ -131,10 +148,14 @@ func (s *TaskService) UpdateTask(ctx context.Context, req UpdateTaskRequest) (*s
 	updated.Contexts = removeStrings(updated.Contexts, req.RemoveContexts)
 
 	if len(req.SetMeta) > 0 {
+		setMeta, metaErr := s.metaSchema.NormalizeMeta(req.SetMeta)
+		if metaErr != nil {
+			return nil, metaErr
+		}
 		if updated.Meta == nil {
 			updated.Meta = map[string]string{}
 		}
-		maps.Copy(updated.Meta, req.SetMeta)
+		maps.Copy(updated.Meta, setMeta)
 	}
 	for _, k := range req.RemoveMetaKeys {
 		delete(updated.Meta, k)
@@ -143,6 +164,8 @@ func (s *TaskService) UpdateTask(ctx context.Context, req UpdateTaskRequest) (*s
 	return s.store.UpdateTask(ctx, updated)
 }
 
+// FullUpdateTask replaces every editable field of a task. A non-empty
+// Annotation is added in the same transaction as the update.
 func (s *TaskService) FullUpdateTask(ctx context.Context, req FullUpdateTaskRequest) (*store.Task, error) {
 	current, err := s.store.GetTask(ctx, req.ID)
 	if err != nil {
@@ -152,13 +175,17 @@ func (s *TaskService) FullUpdateTask(ctx context.Context, req FullUpdateTaskRequ
 	if err != nil {
 		return nil, err
 	}
-	var dueOn *time.Time
-	if strings.TrimSpace(req.DueOn) != "" {
-		parsed, parseErr := parseDay(req.DueOn)
-		if parseErr != nil {
-			return nil, parseErr
-		}
-		dueOn = parsed
+	meta, err := s.metaSchema.NormalizeMeta(req.Meta)
+	if err != nil {
+		return nil, err
+	}
+	dueOn, err := parseOptionalDay(req.DueOn)
+	if err != nil {
+		return nil, err
+	}
+	followUpOn, err := parseOptionalDay(req.FollowUpOn)
+	if err != nil {
+		return nil, err
 	}
 	updated := &store.Task{
 		ID:          current.ID,
@@ -167,9 +194,11 @@ func (s *TaskService) FullUpdateTask(ctx context.Context, req FullUpdateTaskRequ
 		Notes:       req.Notes,
 		DueOn:       dueOn,
 		WaitingFor:  strings.TrimSpace(req.WaitingFor),
+		FollowUpOn:  followUpOn,
 		Projects:    req.Projects,
 		Contexts:    req.Contexts,
-		Meta:        req.Meta,
+		Meta:        meta,
+		Checklist:   req.Checklist,
 		CompletedAt: current.CompletedAt,
 		PrevState:   current.PrevState,
 	}
@@ -183,8 +212,106 @@ func (s *TaskService) FullUpdateTask(ctx context.Context, req FullUpdateTaskRequ
 	if updated.State != store.StateDone {
 		updated.PrevState = nil
 	}
+	if updated.State == store.StateLater && current.SnoozedUntil != nil {
+		updated.PrevState = current.PrevState
+		updated.SnoozedUntil = current.SnoozedUntil
+	}
 
-	return s.store.UpdateTask(ctx, updated)
+	if req.Annotation == "" {
+		return s.store.UpdateTask(ctx, updated)
+	}
+	var task *store.Task
+	err = s.inTx(ctx, func(tx *TaskService) error {
+		if _, updateErr := tx.store.UpdateTask(ctx, updated); updateErr != nil {
+			return updateErr
+		}
+		var annotateErr error
+		task, annotateErr = tx.AddAnnotation(ctx, req.ID, req.Annotation)
+		return annotateErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// SnoozeTasks moves every task in ids to later until the given time within
+// one transaction and remembers each state so WakeSnoozed can return the
+// task there. Snoozing a snoozed task only moves the date. Any failure rolls
+// back all snoozes.
+func (s *TaskService) SnoozeTasks(ctx context.Context, ids []int64, until time.Time) ([]*store.Task, error) {
+	snoozed := make([]*store.Task, 0, len(ids))
+	err := s.inTx(ctx, func(tx *TaskService) error {
+		for _, id := range ids {
+			task, err := tx.snoozeTask(ctx, id, until)
+			if err != nil {
+				return fmt.Errorf("snooze task #%d: %w", id, err)
+			}
+			snoozed = append(snoozed, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snoozed, nil
+}
+
+// MakeProject turns a task into a project, moving it to later, and creates next
+// as the project's first action unless it is empty. Both writes happen in one
+// transaction; the created action is nil when next is empty.
+func (s *TaskService) MakeProject(
+	ctx context.Context, id int64, project string, next string,
+) (*store.Task, *store.Task, error) {
+	state := domain.TaskStateLater
+	var updated, created *store.Task
+	err := s.inTx(ctx, func(tx *TaskService) error {
+		var err error
+		updated, err = tx.UpdateTask(ctx, UpdateTaskRequest{
+			ID:          id,
+			State:       &state,
+			AddProjects: []string{project},
+		})
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		created, err = tx.CreateTask(ctx, CreateTaskRequest{
+			Title:    next,
+			State:    domain.TaskStateNow,
+			Projects: []string{project},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, created, nil
+}
+
+func (s *TaskService) snoozeTask(ctx context.Context, id int64, until time.Time) (*store.Task, error) {
+	task, err := s.store.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.State == store.StateDone {
+		return nil, fmt.Errorf("task #%d is done", id)
+	}
+	if task.SnoozedUntil == nil {
+		prev := task.State
+		task.PrevState = &prev
+	}
+	task.State = store.StateLater
+	task.SnoozedUntil = &until
+	return s.store.UpdateTask(ctx, task)
+}
+
+// WakeSnoozed returns tasks whose snooze date has passed to their earlier
+// state.
+func (s *TaskService) WakeSnoozed(ctx context.Context, now time.Time) ([]*store.Task, error) {
+	return s.store.WakeSnoozed(ctx, now)
 }
 
 func copyMeta(m map[string]string) map[string]string {
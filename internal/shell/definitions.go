@@ -0,0 +1,515 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+)
+
+const (
+	defineVar   = "let"
+	defineAlias = "alias"
+	defineMacro = "macro"
+	unsetVar    = "unset"
+	unsetAlias  = "unalias"
+
+	// maxExpansionDepth bounds nested alias and macro expansion so a
+	// definition that refers to itself fails instead of looping.
+	maxExpansionDepth = 16
+
+	shellrcDirPerm  = 0o750
+	shellrcFilePerm = 0o600
+)
+
+//nolint:gochecknoglobals // compiled once, used for every definition
+var (
+	varNamePattern     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	commandNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+)
+
+// Definitions holds the variables, aliases and macros of a shell session.
+// Variables are written $name anywhere in a command; aliases and macros are
+// used in place of a verb. Expand replaces them token by token before the
+// line is parsed, so quoted text is never touched.
+type Definitions struct {
+	vars    map[string]string
+	aliases map[string]string
+	macros  map[string]macro
+	// path is the shellrc that definitions are saved to, if any.
+	path string
+}
+
+type macro struct {
+	params []string
+	body   string
+}
+
+// NewDefinitions returns an empty set of definitions that is not persisted.
+func NewDefinitions() *Definitions {
+	return &Definitions{
+		vars:    map[string]string{},
+		aliases: map[string]string{},
+		macros:  map[string]macro{},
+	}
+}
+
+// LoadDefinitions reads the definitions in the shellrc at path. A missing
+// file yields empty definitions. Blank lines and lines starting with # are
+// skipped.
+func LoadDefinitions(path string) (*Definitions, error) {
+	defs := NewDefinitions()
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open shellrc: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !isDefineKeyword(line) {
+			return nil, fmt.Errorf("%s:%d: expected let, alias or macro", path, lineNumber)
+		}
+		if _, err := defs.define(line); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read shellrc: %w", err)
+	}
+	return defs, nil
+}
+
+// PersistTo makes Define save each change to the shellrc at path.
+func (d *Definitions) PersistTo(path string) {
+	d.path = path
+}
+
+// IsDefinition reports whether line is a let, alias, macro, unset or unalias
+// command.
+func IsDefinition(line string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+	switch strings.ToLower(keyword) {
+	case unsetVar, unsetAlias:
+		return true
+	default:
+		return isDefineKeyword(line)
+	}
+}
+
+// isDefineKeyword reports whether line is a let, alias or macro command.
+func isDefineKeyword(line string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+	switch strings.ToLower(keyword) {
+	case defineVar, defineAlias, defineMacro:
+		return true
+	default:
+		return false
+	}
+}
+
+// Define records the definition on line and returns a message describing
+// it. A keyword on its own lists the current definitions of that kind. unset
+// and unalias remove a definition. A redefined name replaces its shellrc line.
+func (d *Definitions) Define(line string) (string, error) {
+	if !isDefineKeyword(line) {
+		return d.remove(line)
+	}
+	def, err := d.define(line)
+	if err != nil {
+		return "", err
+	}
+	if def.name == "" {
+		return d.list(def.keyword), nil
+	}
+	if d.path != "" {
+		if err := rewriteShellrc(d.path, shellrcKeywords(def.keyword), def.name, def.String()); err != nil {
+			return "", err
+		}
+	}
+	if def.keyword == defineVar {
+		return fmt.Sprintf("Defined variable $%s", def.name), nil
+	}
+	return fmt.Sprintf("Defined %s %s", def.keyword, def.name), nil
+}
+
+// remove handles "unset NAME" and "unalias NAME".
+func (d *Definitions) remove(line string) (string, error) {
+	keyword, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	keyword = strings.ToLower(keyword)
+	fields := strings.Fields(rest)
+	if len(fields) != 1 {
+		return "", fmt.Errorf("usage: %s NAME", keyword)
+	}
+	name := strings.ToLower(fields[0])
+
+	var message string
+	switch keyword {
+	case unsetVar:
+		name = strings.TrimPrefix(name, "$")
+		if _, ok := d.vars[name]; !ok {
+			return "", fmt.Errorf("unknown variable $%s", name)
+		}
+		delete(d.vars, name)
+		message = fmt.Sprintf("Removed variable $%s", name)
+	default:
+		if _, ok := d.aliases[name]; ok {
+			delete(d.aliases, name)
+			message = "Removed alias " + name
+		} else if _, ok = d.macros[name]; ok {
+			delete(d.macros, name)
+			message = "Removed macro " + name
+		} else {
+			return "", fmt.Errorf("unknown alias or macro %q", name)
+		}
+	}
+	if d.path != "" {
+		if err := rewriteShellrc(d.path, shellrcKeywords(keyword), name, ""); err != nil {
+			return "", err
+		}
+	}
+	return message, nil
+}
+
+// shellrcKeywords returns the keywords whose shellrc lines share a name
+// space with keyword: variables, or aliases and macros.
+func shellrcKeywords(keyword string) []string {
+	if keyword == defineVar || keyword == unsetVar {
+		return []string{defineVar}
+	}
+	return []string{defineAlias, defineMacro}
+}
+
+// definition is one parsed let, alias or macro line.
+type definition struct {
+	keyword string
+	name    string
+	params  []string
+	body    string
+}
+
+// String returns the definition in the form it is written to the shellrc.
+func (def definition) String() string {
+	var b strings.Builder
+	b.WriteString(def.keyword + " " + def.name)
+	for _, param := range def.params {
+		b.WriteString(" $" + param)
+	}
+	b.WriteString(" = " + def.body)
+	return b.String()
+}
+
+// define parses and records line. A bare keyword records nothing and returns
+// a definition without a name.
+func (d *Definitions) define(line string) (definition, error) {
+	keyword, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	def := definition{keyword: strings.ToLower(keyword)}
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return def, nil
+	}
+
+	head, body, ok := strings.Cut(rest, "=")
+	def.body = strings.TrimSpace(body)
+	fields := strings.Fields(head)
+	if !ok || def.body == "" || len(fields) == 0 {
+		return def, fmt.Errorf("usage: %s", definitionUsage(def.keyword))
+	}
+	if _, err := nlp.Lex(def.body); err != nil {
+		return def, fmt.Errorf("%s %s: %w", def.keyword, fields[0], err)
+	}
+	def.name = strings.ToLower(fields[0])
+
+	switch def.keyword {
+	case defineVar:
+		if isReferenceVariable("$" + def.name) {
+			return def, fmt.Errorf("$%s is a built-in reference and cannot be redefined", def.name)
+		}
+		if len(fields) != 1 || !varNamePattern.MatchString(def.name) {
+			return def, fmt.Errorf("invalid variable %q; usage: %s",
+				strings.TrimSpace(head), definitionUsage(def.keyword))
+		}
+		d.vars[def.name] = def.body
+	case defineAlias:
+		if len(fields) != 1 {
+			return def, fmt.Errorf("usage: %s", definitionUsage(def.keyword))
+		}
+		if err := validateCommandName(def.name); err != nil {
+			return def, err
+		}
+		delete(d.macros, def.name)
+		d.aliases[def.name] = def.body
+	case defineMacro:
+		if err := validateCommandName(def.name); err != nil {
+			return def, err
+		}
+		params, err := macroParams(fields[1:])
+		if err != nil {
+			return def, err
+		}
+		def.params = params
+		delete(d.aliases, def.name)
+		d.macros[def.name] = macro{params: params, body: def.body}
+	}
+	return def, nil
+}
+
+func definitionUsage(keyword string) string {
+	switch keyword {
+	case defineVar:
+		return "let NAME = TEXT"
+	case defineAlias:
+		return "alias NAME = COMMAND"
+	default:
+		return "macro NAME $PARAM... = COMMAND"
+	}
+}
+
+func validateCommandName(name string) error {
+	if !commandNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	if slices.Contains(nlp.CommandVerbs(), name) || isBuiltinCommand(name) || isShellCommand(name) {
+		return fmt.Errorf("%q is a built-in command and cannot be redefined", name)
+	}
+	return nil
+}
+
+func macroParams(fields []string) ([]string, error) {
+	params := make([]string, 0, len(fields))
+	for _, field := range fields {
+		param, ok := strings.CutPrefix(field, "$")
+		param = strings.ToLower(param)
+		if !ok || !varNamePattern.MatchString(param) {
+			return nil, fmt.Errorf("invalid macro parameter %q; parameters are written $name", field)
+		}
+		if slices.Contains(params, param) {
+			return nil, fmt.Errorf("duplicate macro parameter %q", field)
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func (d *Definitions) list(keyword string) string {
+	var lines []string
+	switch keyword {
+	case defineVar:
+		for _, name := range slices.Sorted(maps.Keys(d.vars)) {
+			lines = append(lines, definition{keyword: keyword, name: name, body: d.vars[name]}.String())
+		}
+	case defineAlias:
+		for _, name := range slices.Sorted(maps.Keys(d.aliases)) {
+			lines = append(lines, definition{keyword: keyword, name: name, body: d.aliases[name]}.String())
+		}
+	case defineMacro:
+		for _, name := range slices.Sorted(maps.Keys(d.macros)) {
+			m := d.macros[name]
+			lines = append(lines, definition{keyword: keyword, name: name, params: m.params, body: m.body}.String())
+		}
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("No %s definitions", keyword)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rewriteShellrc replaces the first line at path defining name with one of
+// keywords by replacement and drops any later ones. replacement is appended
+// when no line matched and removes the definition when empty. Comments and
+// other lines are kept as written.
+func rewriteShellrc(path string, keywords []string, name string, replacement string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read shellrc: %w", err)
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+	kept := make([]string, 0, len(lines)+1)
+	placed := replacement == ""
+	for _, line := range lines {
+		keyword, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+		head, _, _ := strings.Cut(rest, "=")
+		fields := strings.Fields(head)
+		if !slices.Contains(keywords, strings.ToLower(keyword)) || len(fields) == 0 ||
+			strings.ToLower(fields[0]) != name {
+			kept = append(kept, line)
+			continue
+		}
+		if !placed {
+			kept = append(kept, replacement)
+			placed = true
+		}
+	}
+	if !placed {
+		kept = append(kept, replacement)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), shellrcDirPerm); err != nil {
+		return fmt.Errorf("create shellrc directory: %w", err)
+	}
+	content := strings.Join(kept, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	if err = os.WriteFile(path, []byte(content), shellrcFilePerm); err != nil {
+		return fmt.Errorf("write shellrc: %w", err)
+	}
+	return nil
+}
+
+// VariableNames returns the defined variable names, sorted.
+func (d *Definitions) VariableNames() []string {
+	return slices.Sorted(maps.Keys(d.vars))
+}
+
+// CommandNames returns the defined alias and macro names, sorted.
+func (d *Definitions) CommandNames() []string {
+	return slices.Sorted(slices.Values(slices.Concat(
+		slices.Collect(maps.Keys(d.aliases)),
+		slices.Collect(maps.Keys(d.macros)),
+	)))
+}
+
+// Expand replaces variables, aliases and macros in input. Expansions are
+// expanded again, so definitions may build on one another.
+func (d *Definitions) Expand(input string) (string, error) {
+	if len(d.vars) == 0 && len(d.aliases) == 0 && len(d.macros) == 0 {
+		return input, nil
+	}
+	for range maxExpansionDepth {
+		expanded, changed, err := d.expandOnce(input)
+		if err != nil || !changed {
+			return expanded, err
+		}
+		input = expanded
+	}
+	return "", fmt.Errorf("expansion exceeded %d levels; check for a definition that uses itself", maxExpansionDepth)
+}
+
+// expandOnce makes one pass over the tokens of input. Input that does not
+// lex is returned unchanged for the parser to report.
+func (d *Definitions) expandOnce(input string) (string, bool, error) {
+	tokens, err := nlp.Lex(input)
+	if err != nil {
+		return input, false, nil //nolint:nilerr // the parser reports lex errors with positions
+	}
+
+	var b strings.Builder
+	cursor := 0
+	changed := false
+	atCommand := true
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.Name {
+		case "Whitespace":
+			continue
+		case "Semicolon", "Pipe":
+			atCommand = true
+			continue
+		}
+		start := tok.Pos.Offset
+		end := start + len(tok.Value)
+
+		switch {
+		case tok.Name == "Variable" && !isReferenceVariable(tok.Value):
+			value, ok := d.vars[strings.ToLower(tok.Value[1:])]
+			if !ok {
+				return "", false, fmt.Errorf("unknown variable %s", tok.Value)
+			}
+			b.WriteString(input[cursor:start] + value)
+			cursor, changed = end, true
+		case atCommand && tok.Name == "Ident":
+			name := strings.ToLower(tok.Value)
+			if body, ok := d.aliases[name]; ok {
+				b.WriteString(input[cursor:start] + body)
+				cursor, changed = end, true
+			} else if m, ok := d.macros[name]; ok {
+				args, last := macroArgs(tokens, i+1, len(m.params))
+				if len(args) < len(m.params) {
+					return "", false, fmt.Errorf(
+						"macro %s expects %d argument(s), got %d", name, len(m.params), len(args))
+				}
+				if last > i {
+					end = tokens[last].Pos.Offset + len(tokens[last].Value)
+					i = last
+				}
+				b.WriteString(input[cursor:start] + m.expand(args))
+				cursor, changed = end, true
+			}
+		}
+		atCommand = false
+	}
+	if !changed {
+		return input, false, nil
+	}
+	b.WriteString(input[cursor:])
+	return b.String(), true, nil
+}
+
+// isReferenceVariable reports whether name, such as $last, is a task
+// reference the parser resolves rather than a user variable.
+func isReferenceVariable(name string) bool {
+	return strings.EqualFold(name, "$last")
+}
+
+// macroArgs collects up to n argument tokens after index from. It returns the
+// arguments and the index of the last token consumed.
+func macroArgs(tokens []nlp.LexToken, from int, n int) ([]string, int) {
+	args := make([]string, 0, n)
+	last := from - 1
+	for i := from; i < len(tokens) && len(args) < n; i++ {
+		switch tokens[i].Name {
+		case "Whitespace":
+			continue
+		case "Semicolon", "Pipe":
+			return args, last
+		}
+		args = append(args, tokens[i].Value)
+		last = i
+	}
+	return args, last
+}
+
+// expand substitutes args for the parameters in the macro body. Other
+// variables are left for the next expansion pass.
+func (m macro) expand(args []string) string {
+	tokens, err := nlp.Lex(m.body)
+	if err != nil {
+		return m.body
+	}
+	var b strings.Builder
+	cursor := 0
+	for _, tok := range tokens {
+		if tok.Name != "Variable" {
+			continue
+		}
+		idx := slices.Index(m.params, strings.ToLower(tok.Value[1:]))
+		if idx < 0 {
+			continue
+		}
+		b.WriteString(m.body[cursor:tok.Pos.Offset] + args[idx])
+		cursor = tok.Pos.Offset + len(tok.Value)
+	}
+	b.WriteString(m.body[cursor:])
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package shell_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/shell"
+)
+
+func TestDefinitionsExpand(t *testing.T) {
+	t.Parallel()
+
+	defs := shell.NewDefinitions()
+	for _, line := range []string{
+		"let w = #work @office",
+		"alias today = find due:today || state:now",
+		"macro triage $id = set $id state:now $w",
+		"alias urgent = today && $w",
+	} {
+		_, err := defs.Define(line)
+		require.NoError(t, err, "define %q", line)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "variable", input: "add call mom $w", want: "add call mom #work @office"},
+		{name: "alias", input: "today", want: "find due:today || state:now"},
+		{name: "alias in later stage", input: "add x; today | done", want: "add x; find due:today || state:now | done"},
+		{name: "alias only as command", input: "add plan today", want: "add plan today"},
+		{name: "macro arguments", input: "triage 12", want: "set 12 state:now #work @office"},
+		{name: "macro keeps extra words", input: "triage #4 due:friday", want: "set #4 state:now #work @office due:friday"},
+		{name: "nested", input: "urgent", want: "find due:today || state:now && #work @office"},
+		{name: "quoted text untouched", input: `add "pay $w" today`, want: `add "pay $w" today`},
+		{name: "case insensitive", input: "Today", want: "find due:today || state:now"},
+		{name: "reference variable kept", input: "done $last", want: "done $last"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := defs.Expand(tt.input)
+			require.NoError(t, err, "expand error")
+			assert.Equal(t, tt.want, got, "expansion mismatch")
+		})
+	}
+}
+
+func TestDefinitionsExpandErrors(t *testing.T) {
+	t.Parallel()
+
+	defs := shell.NewDefinitions()
+	for _, line := range []string{
+		"macro triage $id $state = set $id state:$state",
+		"alias loop = loop",
+	} {
+		_, err := defs.Define(line)
+		require.NoError(t, err, "define %q", line)
+	}
+
+	_, err := defs.Expand("add $nope")
+	require.ErrorContains(t, err, "unknown variable $nope", "unknown variable error mismatch")
+
+	_, err = defs.Expand("triage 3")
+	require.ErrorContains(t, err, "macro triage expects 2 argument(s), got 1", "missing argument error mismatch")
+
+	_, err = defs.Expand("loop")
+	require.ErrorContains(t, err, "expansion exceeded", "recursive alias should fail")
+}
+
+func TestDefinitionsDefineRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "let w", want: "usage: let NAME = TEXT"},
+		{line: "let my-var = x", want: `invalid variable "my-var"`},
+		{line: "let last = 3", want: "$last is a built-in reference"},
+		{line: "alias find = show", want: `"find" is a built-in command`},
+		{line: "alias quit = done", want: `"quit" is a built-in command`},
+		{line: "macro m id = set $id", want: `invalid macro parameter "id"`},
+		{line: "macro m $a $a = set $a", want: `duplicate macro parameter "$a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+			_, err := shell.NewDefinitions().Define(tt.line)
+			require.ErrorContains(t, err, tt.want, "error mismatch")
+		})
+	}
+}
+
+func TestDefinitionsListing(t *testing.T) {
+	t.Parallel()
+
+	defs := shell.NewDefinitions()
+	msg, err := defs.Define("alias")
+	require.NoError(t, err, "list error")
+	assert.Equal(t, "No alias definitions", msg, "empty listing mismatch")
+
+	msg, err = defs.Define("macro Triage $ID = set $ID state:now")
+	require.NoError(t, err, "define error")
+	assert.Equal(t, "Defined macro triage", msg, "define message mismatch")
+
+	msg, err = defs.Define("macro")
+	require.NoError(t, err, "list error")
+	assert.Equal(t, "macro triage $id = set $ID state:now", msg, "listing mismatch")
+	assert.Equal(t, []string{"triage"}, defs.CommandNames(), "command names mismatch")
+}
+
+func TestDefinitionsPersistAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "ugh", "shellrc")
+	defs, err := shell.LoadDefinitions(path)
+	require.NoError(t, err, "missing shellrc should load empty")
+	defs.PersistTo(path)
+
+	for _, line := range []string{"let w = #work", "alias today = find due:today"} {
+		_, err = defs.Define(line)
+		require.NoError(t, err, "define %q", line)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "read shellrc")
+	assert.Equal(t, "let w = #work\nalias today = find due:today\n", string(data), "shellrc contents mismatch")
+
+	loaded, err := shell.LoadDefinitions(path)
+	require.NoError(t, err, "load shellrc")
+	assert.Equal(t, []string{"w"}, loaded.VariableNames(), "loaded variables mismatch")
+	assert.Equal(t, []string{"today"}, loaded.CommandNames(), "loaded aliases mismatch")
+}
+
+func TestDefinitionsRedefineAndRemoveRewriteShellrc(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "shellrc")
+	require.NoError(t, os.WriteFile(path, []byte("# mine\nlet w = #work\nalias today = find due:today\n"), 0o600),
+		"write shellrc")
+	defs, err := shell.LoadDefinitions(path)
+	require.NoError(t, err, "load shellrc")
+	defs.PersistTo(path)
+
+	for _, line := range []string{"let w = #office", "macro today $n = find due:today limit:$n", "let h = #home"} {
+		_, err = defs.Define(line)
+		require.NoError(t, err, "define %q", line)
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "read shellrc")
+	assert.Equal(t, "# mine\nlet w = #office\nmacro today $n = find due:today limit:$n\nlet h = #home\n", string(data),
+		"redefinitions should replace their lines")
+
+	msg, err := defs.Define("unset $w")
+	require.NoError(t, err, "unset error")
+	assert.Equal(t, "Removed variable $w", msg, "unset message mismatch")
+	msg, err = defs.Define("unalias today")
+	require.NoError(t, err, "unalias error")
+	assert.Equal(t, "Removed macro today", msg, "unalias message mismatch")
+	_, err = defs.Define("unalias today")
+	require.ErrorContains(t, err, `unknown alias or macro "today"`, "unalias of a missing name should fail")
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err, "read shellrc")
+	assert.Equal(t, "# mine\nlet h = #home\n", string(data), "removed definitions should leave the shellrc")
+	assert.Equal(t, []string{"h"}, defs.VariableNames(), "variables mismatch")
+	assert.Empty(t, defs.CommandNames(), "commands should be removed")
+}
+
+func TestLoadDefinitionsReportsLine(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "shellrc")
+	require.NoError(t, os.WriteFile(path, []byte("# mine\nlet w = #work\nfind state:now\n"), 0o600), "write shellrc")
+
+	_, err := shell.LoadDefinitions(path)
+	require.ErrorContains(t, err, "shellrc:3: expected let, alias or macro", "load error mismatch")
+}
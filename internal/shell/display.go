@@ -27,6 +27,14 @@ func (d *Display) ShowResult(result *ExecuteResult) {
 	if result == nil {
 		return
 	}
+	if len(result.Stages) > 0 {
+		for _, stage := range result.Stages {
+			if !stage.Piped {
+				d.ShowResult(stage)
+			}
+		}
+		return
+	}
 
 	if d.showPayload(result) {
 		return
@@ -59,6 +67,10 @@ func (d *Display) showPayload(result *ExecuteResult) bool {
 		_ = d.writer.WriteTaskVersionDiff(result.Versions)
 		return true
 	}
+	if result.StalledProjects != nil {
+		_ = d.writer.WriteStalledProjects(result.StalledProjects)
+		return true
+	}
 	return false
 }
 
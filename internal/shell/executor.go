@@ -1,42 +1,122 @@
 package shell
 
 import (
+	"cmp"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/mholtzscher/ugh/internal/config"
 	"github.com/mholtzscher/ugh/internal/nlp"
 	"github.com/mholtzscher/ugh/internal/nlp/compile"
 	"github.com/mholtzscher/ugh/internal/output"
 	"github.com/mholtzscher/ugh/internal/service"
 	"github.com/mholtzscher/ugh/internal/store"
-)
-
-const (
-	viewNameInbox    = "inbox"
-	viewNameNow      = "now"
-	viewNameWaiting  = "waiting"
-	viewNameLater    = "later"
-	viewNameCalendar = "calendar"
+	"github.com/mholtzscher/ugh/internal/templates"
 )
 
 // Executor bridges NLP parsing to service execution.
 type Executor struct {
-	svc    service.Service
-	state  *SessionState
-	parser nlp.Parser
+	svc     service.Service
+	state   *SessionState
+	parser  nlp.Parser
+	views   map[string]config.View
+	tmpls   templates.Set
+	confirm ConfirmFunc
+	pick    PickFunc
+	process ProcessFunc
+	defs    *Definitions
+
+	titleDates bool
+}
+
+// ConfirmFunc previews the tasks action applies to, such as the matches of a
+// "set where" update, and reports whether to apply it.
+type ConfirmFunc func(action string, tasks []*store.Task) (bool, error)
+
+// PickFunc lets the user choose one of tasks.
+type PickFunc func(tasks []*store.Task) (*store.Task, error)
+
+// ExecutorOption configures optional Executor behavior.
+type ExecutorOption func(*Executor)
+
+// WithViews makes user-defined views available to the view command.
+func WithViews(views map[string]config.View) ExecutorOption {
+	return func(e *Executor) {
+		e.views = views
+	}
+}
+
+// WithTemplates makes templates available to "add from".
+func WithTemplates(set templates.Set) ExecutorOption {
+	return func(e *Executor) {
+		e.tmpls = set
+	}
+}
+
+// WithConfirm asks fn before applying filter-targeted updates and before
+// deleting the selected task or a pronoun's tasks. Without it they apply
+// immediately, as in scripts.
+func WithConfirm(fn ConfirmFunc) ExecutorOption {
+	return func(e *Executor) {
+		e.confirm = fn
+	}
+}
+
+// WithPicker lets select, and commands on the selected task when nothing is
+// selected, ask fn for a task. Without it they fail, as in scripts.
+func WithPicker(fn PickFunc) ExecutorOption {
+	return func(e *Executor) {
+		e.pick = fn
+	}
+}
+
+// WithProcess lets the process command walk through the inbox with fn.
+// Without it the command fails, as in scripts.
+func WithProcess(fn ProcessFunc) ExecutorOption {
+	return func(e *Executor) {
+		e.process = fn
+	}
+}
+
+// WithTitleDates moves date phrases in add titles into the due date.
+func WithTitleDates(enabled bool) ExecutorOption {
+	return func(e *Executor) {
+		e.titleDates = enabled
+	}
+}
+
+// WithDefinitions expands the variables, aliases and macros in defs and
+// records new ones there. Without it definitions last for the session only.
+func WithDefinitions(defs *Definitions) ExecutorOption {
+	return func(e *Executor) {
+		e.defs = defs
+	}
+}
+
+// isShellCommand reports whether line is handled by the shell itself rather
+// than parsed as a task command.
+func isShellCommand(line string) bool {
+	return IsDefinition(line) || IsSessionCommand(line) || IsSelectCommand(line) || IsProcessCommand(line)
 }
 
 // NewExecutor creates a new executor.
-func NewExecutor(svc service.Service, state *SessionState) *Executor {
-	return &Executor{
+func NewExecutor(svc service.Service, state *SessionState, opts ...ExecutorOption) *Executor {
+	e := &Executor{
 		svc:    svc,
 		state:  state,
 		parser: nlp.NewParser(),
+		defs:   NewDefinitions(),
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // Execute parses and executes a natural language command.
@@ -54,20 +134,97 @@ func (e *Executor) Execute(ctx context.Context, input string) (*ExecuteResult, e
 		)
 	}
 
-	// Pre-process: resolve pronouns and context
-	input = e.preprocessInput(input)
+	if IsDefinition(input) {
+		return e.executeDefine(input)
+	}
+	// Snoozed tasks come back before each command, so a long session sees
+	// them once their date passes.
+	if _, err := e.svc.WakeSnoozed(ctx, time.Now()); err != nil {
+		return nil, fmt.Errorf("wake snoozed tasks: %w", err)
+	}
+	if IsSessionCommand(input) {
+		return e.executeSession(ctx, input)
+	}
+	if IsSelectCommand(input) {
+		return e.executeSelect(ctx, input)
+	}
+	if IsProcessCommand(input) {
+		return e.executeProcess(ctx)
+	}
+
+	// Expand variables, aliases and macros; pronouns and references are
+	// resolved per command when it is compiled.
+	input, err := e.defs.Expand(input)
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse the natural language input
 	parseOpts := nlp.ParseOptions{
-		Mode: nlp.ModeAuto,
-		Now:  time.Now(),
+		Mode:      nlp.ModeAuto,
+		Now:       time.Now(),
+		Templates: e.tmpls.Names(),
 	}
 
-	parseResult, err := e.parser.Parse(ctx, input, parseOpts)
+	program, err := e.parser.ParseProgram(ctx, input, parseOpts)
 	if err != nil {
 		return nil, err
 	}
+	if program.Single() {
+		stage := compile.StagePlan{Input: input, Result: program.Statements[0].Stages[0].Result}
+		return e.executeStage(ctx, input, stage, e.state.LastTaskIDs)
+	}
+	return e.executeProgram(ctx, input, compile.BuildProgram(program))
+}
+
+// executeProgram runs the statements of a line in order. Each pipeline stage
+// after the first acts on the tasks the stage before it left in LastTaskIDs;
+// a stage that leaves none ends its pipeline. When a stage fails, the results
+// of the stages before it are returned with the error.
+func (e *Executor) executeProgram(
+	ctx context.Context,
+	input string,
+	program compile.ProgramPlan,
+) (*ExecuteResult, error) {
+	stages := make([]*ExecuteResult, 0, len(program.Pipelines))
+	run := func(stage compile.StagePlan, ids []int64) ([]int64, error) {
+		if stage.Piped {
+			stages[len(stages)-1].Piped = true
+		}
+		result, err := e.executeStage(ctx, input, stage, ids)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stage.Input, err)
+		}
+		stages = append(stages, result)
+		return e.state.LastTaskIDs, nil
+	}
+	skip := func(stage compile.StagePlan) {
+		stages = append(stages, &ExecuteResult{
+			Intent:    "pipeline",
+			Message:   fmt.Sprintf("No tasks for %q", stage.Input),
+			Level:     ResultLevelInfo,
+			Summary:   "skipped " + stage.Input,
+			Timestamp: time.Now(),
+		})
+	}
+	if err := program.Run(e.state.LastTaskIDs, run, skip); err != nil {
+		if len(stages) == 0 {
+			return nil, err
+		}
+		return combineResults(stages), err
+	}
+	return combineResults(stages), nil
+}
 
+// executeStage compiles and runs one parsed command. input is the whole line,
+// which diagnostics refer to; ids are the tasks a piped stage acts on.
+func (e *Executor) executeStage(
+	ctx context.Context,
+	input string,
+	stage compile.StagePlan,
+	ids []int64,
+) (*ExecuteResult, error) {
+	parseResult := stage.Result
 	// Check for parse diagnostics
 	if len(parseResult.Diagnostics) > 0 {
 		for _, diag := range parseResult.Diagnostics {
@@ -78,41 +235,69 @@ func (e *Executor) Execute(ctx context.Context, input string) (*ExecuteResult, e
 	}
 
 	// Inject sticky context into the AST (post-parse, pre-compile)
-	e.injectContext(parseResult)
+	if err := e.injectContext(ctx, parseResult); err != nil {
+		return nil, err
+	}
+	if err := e.pickSelection(ctx, parseResult.Command); err != nil {
+		return nil, err
+	}
 
 	// Compile the parse result to an execution plan
 	buildOpts := compile.BuildOptions{
-		SelectedTaskID: e.state.SelectedTaskID,
-		Now:            time.Now(),
+		SelectedTaskID:  e.state.SelectedTaskID,
+		LastTaskIDs:     e.state.LastTaskIDs,
+		PreviousResults: e.state.PreviousResults,
+		Now:             time.Now(),
+		MetaSchema:      e.svc.MetaSchema(),
+		TitleDates:      e.titleDates,
 	}
 
-	plan, err := compile.Build(parseResult, buildOpts)
+	plan, err := stage.Build(ids, buildOpts)
 	if err != nil {
-		return nil, fmt.Errorf("compile: %w", err)
+		return nil, fmt.Errorf("compile: %w", nlp.LocateError(input, err))
 	}
 
 	// Execute the plan
 	return e.executePlan(ctx, plan, parseResult)
 }
 
-func (e *Executor) preprocessInput(input string) string {
-	// Replace pronouns with actual IDs (only if IDs exist)
-	if lastID := e.getLastTaskID(); lastID != 0 {
-		input = strings.ReplaceAll(input, " it ", fmt.Sprintf(" %d ", lastID))
-		input = strings.ReplaceAll(input, " this ", fmt.Sprintf(" %d ", lastID))
-		input = strings.ReplaceAll(input, " last ", fmt.Sprintf(" %d ", lastID))
+func (e *Executor) executeDefine(input string) (*ExecuteResult, error) {
+	message, err := e.defs.Define(input)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResult{
+		Intent:    "define",
+		Message:   message,
+		Level:     ResultLevelInfo,
+		Summary:   input,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// combineResults merges the stage results of a multi-command line. The
+// combined result keeps the stages for display, the task IDs of the last
+// stage and the most severe level.
+func combineResults(stages []*ExecuteResult) *ExecuteResult {
+	combined := &ExecuteResult{
+		Intent:    "pipeline",
+		Stages:    stages,
+		Level:     ResultLevelInfo,
+		Timestamp: time.Now(),
 	}
-	if secondToLastID := e.getSecondToLastTaskID(); secondToLastID != 0 {
-		input = strings.ReplaceAll(input, " that ", fmt.Sprintf(" %d ", secondToLastID))
+	summaries := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		summaries = append(summaries, stage.Summary)
+		combined.Level = max(combined.Level, stage.Level)
 	}
-	if selectedID := e.getSelectedTaskID(); selectedID != 0 {
-		input = strings.ReplaceAll(input, " selected ", fmt.Sprintf(" %d ", selectedID))
+	if len(stages) > 0 {
+		combined.TaskIDs = stages[len(stages)-1].TaskIDs
 	}
-
-	return input
+	combined.Summary = strings.Join(summaries, "; ")
+	return combined
 }
 
-func (e *Executor) injectContext(parseResult nlp.ParseResult) {
+func (e *Executor) injectContext(ctx context.Context, parseResult nlp.ParseResult) error {
 	switch cmd := parseResult.Command.(type) {
 	case *nlp.CreateCommand:
 		cmd.InjectProject(e.state.ContextProject)
@@ -123,28 +308,42 @@ func (e *Executor) injectContext(parseResult nlp.ParseResult) {
 	case *nlp.FilterCommand:
 		cmd.InjectProject(e.state.ContextProject)
 		cmd.InjectContext(e.state.ContextContext)
+		filter, err := e.stickyFilter(ctx)
+		if err != nil {
+			return err
+		}
+		cmd.InjectFilter(filter)
 	}
+	return nil
 }
 
-func (e *Executor) getLastTaskID() int64 {
-	if len(e.state.LastTaskIDs) > 0 {
-		return e.state.LastTaskIDs[len(e.state.LastTaskIDs)-1]
+// stickyFilter parses the context filter expression, if one is set.
+func (e *Executor) stickyFilter(ctx context.Context) (nlp.FilterExpr, error) {
+	if e.state.ContextFilter == "" {
+		return nil, nil //nolint:nilnil // no sticky filter is not an error
 	}
-	return 0
-}
-
-func (e *Executor) getSecondToLastTaskID() int64 {
-	if len(e.state.LastTaskIDs) > 1 {
-		return e.state.LastTaskIDs[len(e.state.LastTaskIDs)-2]
+	parseOpts := nlp.ParseOptions{Mode: nlp.ModeAuto, Now: time.Now()}
+	parsed, err := e.parser.Parse(ctx, "find "+e.state.ContextFilter, parseOpts)
+	if err != nil {
+		return nil, fmt.Errorf("context filter %q: %w", e.state.ContextFilter, err)
 	}
-	return e.getLastTaskID()
+	filter, ok := parsed.Command.(*nlp.FilterCommand)
+	if !ok {
+		return nil, fmt.Errorf("context filter %q is not a filter", e.state.ContextFilter)
+	}
+	return filter.Expr, nil
 }
 
-func (e *Executor) getSelectedTaskID() int64 {
-	if e.state.SelectedTaskID != nil {
-		return *e.state.SelectedTaskID
-	}
-	return 0
+// maxPreviousResults caps how far back ^N references reach, after ^1 (the
+// last command).
+const maxPreviousResults = 9
+
+// rememberResult makes ids the tasks of the last command. The tasks of earlier
+// commands stay reachable as ^2, ^3 and so on.
+func (e *Executor) rememberResult(ids []int64) {
+	previous := append([][]int64{e.state.LastTaskIDs}, e.state.PreviousResults...)
+	e.state.PreviousResults = previous[:min(len(previous), maxPreviousResults)]
+	e.state.LastTaskIDs = ids
 }
 
 func (e *Executor) executePlan(
@@ -165,6 +364,20 @@ func (e *Executor) executePlan(
 		return e.executeContext(parseResult)
 	case nlp.IntentLog:
 		return e.executeLog(ctx, plan)
+	case nlp.IntentDone:
+		return e.executeSetDone(ctx, plan, true)
+	case nlp.IntentUndo:
+		return e.executeSetDone(ctx, plan, false)
+	case nlp.IntentDelete:
+		return e.executeDelete(ctx, plan)
+	case nlp.IntentShow:
+		return e.executeShow(ctx, plan)
+	case nlp.IntentSnooze:
+		return e.executeSnooze(ctx, plan)
+	case nlp.IntentTemplate:
+		return e.executeTemplate(ctx, plan)
+	case nlp.IntentNote:
+		return e.executeNote(ctx, plan)
 	case nlp.IntentUnknown:
 		return nil, errors.New("unknown intent: could not determine command type")
 	default:
@@ -181,8 +394,11 @@ func (e *Executor) executeView(ctx context.Context, parseResult nlp.ParseResult)
 	if cmd.Target == nil {
 		return e.showViewHelp(), nil
 	}
+	if strings.EqualFold(cmd.Target.Name, nlp.StalledViewName) {
+		return e.showStalledProjects(ctx)
+	}
 
-	filterQuery, err := viewFilterQuery(cmd.Target.Name)
+	filterQuery, err := e.viewFilterQuery(cmd.Target.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +412,17 @@ func (e *Executor) executeContext(parseResult nlp.ParseResult) (*ExecuteResult,
 		return nil, errors.New("invalid context command")
 	}
 
+	if cmd.Filter != nil {
+		e.state.ContextFilter = cmd.FilterText
+		return &ExecuteResult{
+			Intent:    "context",
+			Message:   "Set sticky filter to " + cmd.FilterText,
+			Level:     ResultLevelInfo,
+			Summary:   "context filter " + cmd.FilterText,
+			Timestamp: time.Now(),
+		}, nil
+	}
+
 	if cmd.Arg == nil {
 		return e.showContext(), nil
 	}
@@ -203,6 +430,7 @@ func (e *Executor) executeContext(parseResult nlp.ParseResult) (*ExecuteResult,
 	if cmd.Arg.Clear {
 		e.state.ContextProject = ""
 		e.state.ContextContext = ""
+		e.state.ContextFilter = ""
 		return &ExecuteResult{
 			Intent:    "context",
 			Message:   "Context filters cleared",
@@ -253,7 +481,7 @@ func (e *Executor) executeLog(ctx context.Context, plan compile.Plan) (*ExecuteR
 		return nil, errors.New("task not found")
 	}
 
-	e.state.LastTaskIDs = []int64{taskID}
+	e.rememberResult([]int64{taskID})
 
 	return &ExecuteResult{
 		Intent:    "show log",
@@ -265,21 +493,35 @@ func (e *Executor) executeLog(ctx context.Context, plan compile.Plan) (*ExecuteR
 	}, nil
 }
 
-func viewFilterQuery(viewName string) (string, error) {
-	switch strings.ToLower(strings.TrimSpace(viewName)) {
-	case viewNameInbox:
-		return "find state:inbox", nil
-	case viewNameNow:
-		return "find state:now", nil
-	case viewNameWaiting:
-		return "find state:waiting", nil
-	case viewNameLater:
-		return "find state:later", nil
-	case viewNameCalendar:
-		return "find due:*", nil
-	default:
+// showStalledProjects lists the projects with open tasks but no now task.
+func (e *Executor) showStalledProjects(ctx context.Context) (*ExecuteResult, error) {
+	projects, err := e.svc.ListStalledProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list stalled projects: %w", err)
+	}
+	return &ExecuteResult{
+		Intent:          "view",
+		StalledProjects: projects,
+		Level:           ResultLevelInfo,
+		Summary:         fmt.Sprintf("showed %d stalled projects", len(projects)),
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+func (e *Executor) viewFilterQuery(viewName string) (string, error) {
+	if builtin, ok := nlp.LookupBuiltinView(viewName); ok {
+		return "find " + builtin.Where, nil
+	}
+
+	view, ok := e.views[strings.ToLower(strings.TrimSpace(viewName))]
+	if !ok || strings.TrimSpace(view.Where) == "" {
 		return "", fmt.Errorf("unknown view: %s", viewName)
 	}
+	query := "find " + view.Where
+	if strings.TrimSpace(view.Sort) != "" {
+		query += " sort:" + view.Sort
+	}
+	return query, nil
 }
 
 func (e *Executor) showContext() *ExecuteResult {
@@ -288,6 +530,8 @@ func (e *Executor) showContext() *ExecuteResult {
 		LastIDs:    e.state.LastTaskIDs,
 		Project:    e.state.ContextProject,
 		Context:    e.state.ContextContext,
+		Filter:     e.state.ContextFilter,
+		Session:    e.state.Name,
 	}
 
 	return &ExecuteResult{
@@ -328,16 +572,7 @@ func formatControlRune(r rune) string {
 }
 
 func (e *Executor) showViewHelp() *ExecuteResult {
-	help := output.ViewHelp{
-		Entries: []output.ViewHelpEntry{
-			{Label: "i, inbox", Description: "Inbox tasks"},
-			{Label: "n, now", Description: "Now tasks"},
-			{Label: "w, waiting", Description: "Waiting tasks"},
-			{Label: "l, later", Description: "Later tasks"},
-			{Label: "c, calendar", Description: "Tasks with due dates"},
-		},
-		Usage: "view <name> (e.g., view i or view inbox)",
-	}
+	help := output.NewViewHelp(e.views, "view <name> (e.g., view i or view inbox)")
 
 	return &ExecuteResult{
 		Intent:    "view",
@@ -358,11 +593,15 @@ func (e *Executor) executeCreate(ctx context.Context, plan compile.Plan) (*Execu
 		return nil, fmt.Errorf("create task: %w", err)
 	}
 
-	e.state.LastTaskIDs = []int64{task.ID}
+	e.rememberResult([]int64{task.ID})
 
+	message := formatTaskCreated(task)
+	for _, diag := range plan.Diagnostics {
+		message += "\n  " + diag.Message
+	}
 	return &ExecuteResult{
 		Intent:    "create",
-		Message:   formatTaskCreated(task),
+		Message:   message,
 		TaskIDs:   []int64{task.ID},
 		Level:     ResultLevelSuccess,
 		Summary:   fmt.Sprintf("created task #%d", task.ID),
@@ -374,13 +613,37 @@ func (e *Executor) executeUpdate(ctx context.Context, plan compile.Plan) (*Execu
 	if plan.Update == nil {
 		return nil, errors.New("no update request compiled")
 	}
+	if plan.Where != nil {
+		return e.executeBulkUpdate(ctx, plan)
+	}
+	// Check every target first so a range such as 1-50 names the missing
+	// tasks instead of failing on the first one.
+	ids := []int64{plan.Update.ID}
+	if len(plan.Updates) > 1 {
+		ids = make([]int64, 0, len(plan.Updates))
+		for _, req := range plan.Updates {
+			ids = append(ids, req.ID)
+		}
+	}
+	if _, missing, err := e.loadTargets(ctx, ids); err != nil {
+		return nil, err
+	} else if len(missing) > 0 {
+		return nil, errTasksNotFound(missing)
+	}
+	if len(plan.Updates) > 1 {
+		results, err := e.svc.BatchUpdateTasks(ctx, plan.Updates)
+		if err != nil {
+			return nil, fmt.Errorf("update tasks: %w", err)
+		}
+		return e.bulkUpdateResult(results), nil
+	}
 
 	task, err := e.svc.UpdateTask(ctx, *plan.Update)
 	if err != nil {
 		return nil, fmt.Errorf("update task: %w", err)
 	}
 
-	e.state.LastTaskIDs = []int64{task.ID}
+	e.rememberResult([]int64{task.ID})
 	if plan.Target.Kind == nlp.TargetSelected {
 		e.state.SelectedTaskID = &task.ID
 	}
@@ -395,6 +658,273 @@ func (e *Executor) executeUpdate(ctx context.Context, plan compile.Plan) (*Execu
 	}, nil
 }
 
+func (e *Executor) executeBulkUpdate(ctx context.Context, plan compile.Plan) (*ExecuteResult, error) {
+	tasks, err := e.svc.ListTasks(ctx, service.ListTasksRequest{Filter: plan.Where})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return &ExecuteResult{
+			Intent:    "update",
+			Message:   "No tasks matched",
+			Level:     ResultLevelInfo,
+			Summary:   "updated 0 tasks",
+			Timestamp: time.Now(),
+		}, nil
+	}
+	slices.SortFunc(tasks, func(a, b *store.Task) int { return cmp.Compare(a.ID, b.ID) })
+
+	if e.confirm != nil {
+		ok, confirmErr := e.confirm("set", tasks)
+		if confirmErr != nil {
+			return nil, confirmErr
+		}
+		if !ok {
+			return &ExecuteResult{
+				Intent:    "update",
+				Message:   "Update cancelled",
+				Level:     ResultLevelWarning,
+				Summary:   "cancelled update",
+				Timestamp: time.Now(),
+			}, nil
+		}
+	}
+
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	results, err := e.svc.BulkUpdateTasks(ctx, ids, *plan.Update)
+	if err != nil {
+		return nil, fmt.Errorf("update tasks: %w", err)
+	}
+	return e.bulkUpdateResult(results), nil
+}
+
+// bulkUpdateResult summarizes a multi-task update and makes its tasks the
+// targets of follow-up pronouns.
+func (e *Executor) bulkUpdateResult(results []service.BulkTaskResult) *ExecuteResult {
+	ids := make([]int64, 0, len(results))
+	lines := make([]string, 0, len(results)+1)
+	lines = append(lines, fmt.Sprintf("Updated %d task(s):", len(results)))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+		lines = append(lines, fmt.Sprintf("  #%d %s", result.ID, result.Title))
+	}
+	e.rememberResult(ids)
+
+	return &ExecuteResult{
+		Intent:    "update",
+		Message:   strings.Join(lines, "\n"),
+		TaskIDs:   ids,
+		Level:     ResultLevelSuccess,
+		Summary:   fmt.Sprintf("updated %d tasks", len(results)),
+		Timestamp: time.Now(),
+	}
+}
+
+func (e *Executor) executeSetDone(ctx context.Context, plan compile.Plan, done bool) (*ExecuteResult, error) {
+	intent, verb, summaryVerb := "done", "Completed", "completed"
+	if !done {
+		intent, verb, summaryVerb = "undo", "Reopened", "reopened"
+	}
+	tasks, missing, err := e.loadTargets(ctx, plan.TaskIDs)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		if (task.State == store.StateDone) != done {
+			ids = append(ids, task.ID)
+		}
+	}
+	if _, err = e.svc.SetDone(ctx, ids, done); err != nil {
+		return nil, fmt.Errorf("%s tasks: %w", intent, err)
+	}
+
+	e.rememberResult(ids)
+	return targetsResult(intent, verb, summaryVerb, ids, missing), nil
+}
+
+// loadTargets loads the tasks in ids, in order, and returns the IDs that have
+// no task separately.
+func (e *Executor) loadTargets(ctx context.Context, ids []int64) ([]*store.Task, []int64, error) {
+	tasks := make([]*store.Task, 0, len(ids))
+	var missing []int64
+	for _, id := range ids {
+		task, err := e.svc.GetTask(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			missing = append(missing, id)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("get task #%d: %w", id, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, missing, nil
+}
+
+// errTasksNotFound reports the IDs in missing as not found.
+func errTasksNotFound(missing []int64) error {
+	if len(missing) == 1 {
+		return fmt.Errorf("task #%d not found", missing[0])
+	}
+	return fmt.Errorf("tasks %s not found", formatTaskIDs(missing))
+}
+
+// targetsResult reports the tasks a command changed and the targets it
+// skipped because they do not exist.
+func targetsResult(intent, verb, summaryVerb string, ids, missing []int64) *ExecuteResult {
+	message := fmt.Sprintf("%s %d task(s): %s", verb, len(ids), formatTaskIDs(ids))
+	if len(ids) == 0 {
+		message = fmt.Sprintf("%s no tasks", verb)
+	}
+	level := ResultLevelSuccess
+	if len(missing) > 0 {
+		message += "; " + errTasksNotFound(missing).Error()
+		level = ResultLevelWarning
+	}
+	return &ExecuteResult{
+		Intent:    intent,
+		Message:   message,
+		TaskIDs:   ids,
+		Level:     level,
+		Summary:   fmt.Sprintf("%s %d tasks", summaryVerb, len(ids)),
+		Timestamp: time.Now(),
+	}
+}
+
+func (e *Executor) executeDelete(ctx context.Context, plan compile.Plan) (*ExecuteResult, error) {
+	tasks, missing, err := e.loadTargets(ctx, plan.TaskIDs)
+	if err != nil {
+		return nil, err
+	}
+	if plan.ImplicitTarget && e.confirm != nil && len(tasks) > 0 {
+		ok, confirmErr := e.confirm("delete", tasks)
+		if confirmErr != nil {
+			return nil, confirmErr
+		}
+		if !ok {
+			return &ExecuteResult{
+				Intent:    "delete",
+				Message:   "Delete cancelled",
+				Level:     ResultLevelWarning,
+				Summary:   "cancelled delete",
+				Timestamp: time.Now(),
+			}, nil
+		}
+	}
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	if _, err = e.svc.DeleteTasks(ctx, ids); err != nil {
+		return nil, fmt.Errorf("delete tasks: %w", err)
+	}
+
+	// Deleted tasks can no longer be targeted by pronouns.
+	e.rememberResult(nil)
+	if selected := e.state.SelectedTaskID; selected != nil && slices.Contains(ids, *selected) {
+		e.state.SelectedTaskID = nil
+	}
+	return targetsResult("delete", "Deleted", "deleted", ids, missing), nil
+}
+
+func (e *Executor) executeSnooze(ctx context.Context, plan compile.Plan) (*ExecuteResult, error) {
+	if _, err := e.svc.SnoozeTasks(ctx, plan.TaskIDs, plan.SnoozeUntil); err != nil {
+		return nil, err
+	}
+
+	e.rememberResult(plan.TaskIDs)
+	return &ExecuteResult{
+		Intent: "snooze",
+		Message: fmt.Sprintf(
+			"Snoozed %d task(s) until %s: %s",
+			len(plan.TaskIDs), output.FormatSnoozedUntil(plan.SnoozeUntil), formatTaskIDs(plan.TaskIDs),
+		),
+		TaskIDs:   plan.TaskIDs,
+		Level:     ResultLevelSuccess,
+		Summary:   fmt.Sprintf("snoozed %d tasks", len(plan.TaskIDs)),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (e *Executor) executeNote(ctx context.Context, plan compile.Plan) (*ExecuteResult, error) {
+	for _, id := range plan.TaskIDs {
+		if _, err := e.svc.AddAnnotation(ctx, id, plan.NoteText); err != nil {
+			return nil, fmt.Errorf("note task #%d: %w", id, err)
+		}
+	}
+
+	e.rememberResult(plan.TaskIDs)
+	return &ExecuteResult{
+		Intent:    "note",
+		Message:   fmt.Sprintf("Added a note to %d task(s): %s", len(plan.TaskIDs), formatTaskIDs(plan.TaskIDs)),
+		TaskIDs:   plan.TaskIDs,
+		Level:     ResultLevelSuccess,
+		Summary:   fmt.Sprintf("noted %d tasks", len(plan.TaskIDs)),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (e *Executor) executeTemplate(ctx context.Context, plan compile.Plan) (*ExecuteResult, error) {
+	tmpl, err := e.tmpls.Lookup(plan.TemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := templates.Create(ctx, e.svc, tmpl, plan.TemplateVars, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", plan.TemplateName, err)
+	}
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	e.rememberResult(ids)
+
+	return &ExecuteResult{
+		Intent:    "template",
+		Message:   fmt.Sprintf("Created %d task(s) from %s: %s", len(ids), plan.TemplateName, formatTaskIDs(ids)),
+		TaskIDs:   ids,
+		Level:     ResultLevelSuccess,
+		Summary:   fmt.Sprintf("created %d tasks from %s", len(ids), plan.TemplateName),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (e *Executor) executeShow(ctx context.Context, plan compile.Plan) (*ExecuteResult, error) {
+	tasks := make([]*store.Task, 0, len(plan.TaskIDs))
+	for _, id := range plan.TaskIDs {
+		task, err := e.svc.GetTask(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get task #%d: %w", id, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	e.rememberResult(plan.TaskIDs)
+	if len(tasks) == 1 {
+		return &ExecuteResult{
+			Intent:    "show",
+			Task:      tasks[0],
+			Level:     ResultLevelInfo,
+			TaskIDs:   plan.TaskIDs,
+			Summary:   fmt.Sprintf("showed task #%d", tasks[0].ID),
+			Timestamp: time.Now(),
+		}, nil
+	}
+	return &ExecuteResult{
+		Intent:    "filter",
+		Tasks:     tasks,
+		Level:     ResultLevelInfo,
+		TaskIDs:   plan.TaskIDs,
+		Summary:   fmt.Sprintf("showed %d tasks", len(tasks)),
+		Timestamp: time.Now(),
+	}, nil
+}
+
 func (e *Executor) executeFilter(
 	ctx context.Context,
 	plan compile.Plan,
@@ -414,7 +944,7 @@ func (e *Executor) executeFilter(
 	for i, task := range tasks {
 		taskIDs[i] = task.ID
 	}
-	e.state.LastTaskIDs = taskIDs
+	e.rememberResult(taskIDs)
 
 	showVerb := false
 	if cmd, ok := parseResult.Command.(*nlp.FilterCommand); ok {
@@ -449,3 +979,11 @@ func formatTaskCreated(task *store.Task) string {
 func formatTaskUpdated(task *store.Task) string {
 	return fmt.Sprintf("Updated task #%d: %s", task.ID, task.Title)
 }
+
+func formatTaskIDs(ids []int64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("#%d", id))
+	}
+	return strings.Join(parts, ", ")
+}
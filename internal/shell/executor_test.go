@@ -2,18 +2,26 @@ package shell_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mholtzscher/ugh/internal/clarify"
+	"github.com/mholtzscher/ugh/internal/config"
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/nlp"
 	"github.com/mholtzscher/ugh/internal/service"
 	"github.com/mholtzscher/ugh/internal/shell"
 	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/templates"
+	"github.com/mholtzscher/ugh/internal/urgency"
 )
 
 func TestExecuteCreateQuotedHashStillInjectsStickyContext(t *testing.T) {
@@ -68,6 +76,479 @@ func TestExecuteUpdateInjectsMissingTags(t *testing.T) {
 	assert.True(t, contains(svc.lastUpdate.AddContexts, "phone"), "add contexts should contain injected 'phone'")
 }
 
+func TestExecuteUpdateMultipleTargetsRunsBatch(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "set 3,5 @phone")
+	require.NoError(t, err, "execute error")
+
+	require.Len(t, svc.lastBatch, 2, "batch size mismatch")
+	assert.Equal(t, int64(3), svc.lastBatch[0].ID, "first id mismatch")
+	assert.Equal(t, int64(5), svc.lastBatch[1].ID, "second id mismatch")
+	assert.Equal(t, []int64{3, 5}, result.TaskIDs, "result ids mismatch")
+	assert.Equal(t, "updated 2 tasks", result.Summary, "summary mismatch")
+}
+
+func TestExecuteUpdateThoseUsesLastTaskIDs(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{LastTaskIDs: []int64{4, 7}})
+
+	_, err := exec.Execute(context.Background(), "set those state:later")
+	require.NoError(t, err, "execute error")
+
+	require.Len(t, svc.lastBatch, 2, "batch size mismatch")
+	assert.Equal(t, int64(4), svc.lastBatch[0].ID, "first id mismatch")
+	assert.Equal(t, int64(7), svc.lastBatch[1].ID, "second id mismatch")
+}
+
+func TestExecuteUpdateWhereAppliesToMatches(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{tasks: []*store.Task{{ID: 9, Title: "b"}, {ID: 3, Title: "a"}}}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "set where #old state:now")
+	require.NoError(t, err, "execute error")
+
+	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredProject), "filter should have project predicate")
+	assert.Equal(t, []int64{3, 9}, svc.lastBulk, "bulk ids should be in id order")
+	require.NotNil(t, svc.lastUpdate.State, "state is nil")
+	assert.Equal(t, "now", *svc.lastUpdate.State, "state mismatch")
+	assert.Equal(t, []int64{3, 9}, result.TaskIDs, "result ids mismatch")
+	assert.Equal(t, []int64{3, 9}, state.LastTaskIDs, "last ids mismatch")
+}
+
+func TestExecuteUpdateWhereDeclinedSkipsUpdate(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{tasks: []*store.Task{{ID: 3, Title: "a"}}}
+	var previewed []*store.Task
+	confirm := func(_ string, tasks []*store.Task) (bool, error) {
+		previewed = tasks
+		return false, nil
+	}
+	exec := shell.NewExecutor(svc, &shell.SessionState{}, shell.WithConfirm(confirm))
+
+	result, err := exec.Execute(context.Background(), "set where #old state:now")
+	require.NoError(t, err, "execute error")
+
+	assert.Len(t, previewed, 1, "confirm should preview matches")
+	assert.Nil(t, svc.lastBulk, "declined update should not apply")
+	assert.Equal(t, "Update cancelled", result.Message, "message mismatch")
+}
+
+func TestExecuteDoneAndUndoSetCompletion(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "complete 3,5")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, []int64{3, 5}, svc.lastDone, "done ids mismatch")
+	assert.True(t, svc.doneValue, "complete should mark tasks done")
+	assert.Equal(t, "done", result.Intent, "intent mismatch")
+	assert.Equal(t, "Completed 2 task(s): #3, #5", result.Message, "message mismatch")
+	assert.Equal(t, []int64{3, 5}, state.LastTaskIDs, "last ids mismatch")
+
+	result, err = exec.Execute(context.Background(), "reopen those")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, []int64{3, 5}, svc.lastDone, "undo ids mismatch")
+	assert.False(t, svc.doneValue, "reopen should mark tasks not done")
+	assert.Equal(t, "undo", result.Intent, "intent mismatch")
+
+	result, err = exec.Execute(context.Background(), "reopen 3,7")
+	require.NoError(t, err, "execute error")
+	assert.Empty(t, svc.lastDone, "open tasks should not be reopened")
+	assert.Equal(t, "Reopened no tasks", result.Message, "message mismatch")
+}
+
+func TestExecuteDoneAndDeleteSkipMissingTasks(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{missing: []int64{98, 99}}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "done 3,99")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, []int64{3}, svc.lastDone, "only existing tasks should be completed")
+	assert.Equal(t, "Completed 1 task(s): #3; task #99 not found", result.Message, "message mismatch")
+	assert.Equal(t, shell.ResultLevelWarning, result.Level, "level mismatch")
+	assert.Equal(t, []int64{3}, state.LastTaskIDs, "missing tasks should not be remembered")
+
+	result, err = exec.Execute(context.Background(), "rm 98,99")
+	require.NoError(t, err, "execute error")
+	assert.Empty(t, svc.lastDelete, "missing tasks should not be deleted")
+	assert.Equal(t, "Deleted no tasks; tasks #98, #99 not found", result.Message, "message mismatch")
+}
+
+func TestExecuteUpdateReportsMissingTasks(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{missing: []int64{4, 5}}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+
+	_, err := exec.Execute(context.Background(), "set 1-5 #work")
+	require.EqualError(t, err, "tasks #4, #5 not found", "error mismatch")
+	assert.Empty(t, svc.lastBatch, "no task should be updated")
+
+	_, err = exec.Execute(context.Background(), "set 4 #work")
+	require.EqualError(t, err, "task #4 not found", "error mismatch")
+	assert.Zero(t, svc.lastUpdate.ID, "no task should be updated")
+}
+
+func TestExecuteSnoozeLastTask(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{LastTaskIDs: []int64{8}}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "snooze it until 2099-01-01")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, []int64{8}, svc.snoozed, "snoozed ids mismatch")
+	assert.Equal(t, time.Date(2099, 1, 1, 0, 0, 0, 0, time.Local), svc.snoozeTo, "snooze date mismatch")
+	assert.Equal(t, "snooze", result.Intent, "intent mismatch")
+	assert.Equal(t, "Snoozed 1 task(s) until 2099-01-01: #8", result.Message, "message mismatch")
+
+	_, err = exec.Execute(context.Background(), "snooze it until yesterday")
+	require.Error(t, err, "snoozing into the past should fail")
+}
+
+func TestExecuteWakesSnoozedTasksBeforeEachCommand(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+
+	_, err := exec.Execute(context.Background(), "find state:later")
+	require.NoError(t, err, "execute error")
+	_, err = exec.Execute(context.Background(), "find state:now")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, 2, svc.wakes, "every command should wake snoozed tasks first")
+
+	_, err = exec.Execute(context.Background(), "let w = #work")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, 2, svc.wakes, "definitions do not touch tasks")
+}
+
+func TestExecuteNote(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{LastTaskIDs: []int64{12}}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "note it called vendor, waiting on quote from @bob")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, "note", result.Intent, "intent mismatch")
+	assert.Equal(t, "Added a note to 1 task(s): #12", result.Message, "message mismatch")
+
+	_, err = exec.Execute(context.Background(), `note 3,5 "ship; then invoice"`)
+	require.NoError(t, err, "execute quoted note error")
+	assert.Equal(t, []string{
+		"12:called vendor, waiting on quote from @bob",
+		"3:ship; then invoice",
+		"5:ship; then invoice",
+	}, svc.notes, "notes mismatch")
+
+	_, err = exec.Execute(context.Background(), "note it")
+	require.Error(t, err, "a note without text should fail")
+}
+
+func TestExecuteAddFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state, shell.WithTemplates(templates.Set{
+		"onboarding": {
+			Title:    "Onboard {name}",
+			Projects: []string{"hiring"},
+			Tasks:    []config.Template{{Title: "Order laptop for {name}"}},
+		},
+	}))
+	ctx := context.Background()
+
+	result, err := exec.Execute(ctx, "add from onboarding name:Sam")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, "template", result.Intent, "intent mismatch")
+	assert.Equal(t, "Created 2 task(s) from onboarding: #1, #1", result.Message, "message mismatch")
+	assert.Equal(t, "Order laptop for Sam", svc.lastCreate.Title, "child title mismatch")
+	assert.Equal(t, []string{"hiring"}, svc.lastCreate.Projects, "child should inherit projects")
+
+	_, err = exec.Execute(ctx, "add from onboarding")
+	require.ErrorContains(t, err, "missing template variables: name", "missing vars should fail")
+
+	result, err = exec.Execute(ctx, "add from Dana")
+	require.NoError(t, err, "add from an unknown template should create a task")
+	assert.Equal(t, "create", result.Intent, "intent mismatch")
+	assert.Equal(t, "from Dana", svc.lastCreate.Title, "title mismatch")
+}
+
+func TestExecuteSelectPicksTask(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{tasks: []*store.Task{{ID: 4, Title: "Write report"}, {ID: 9, Title: "Call mom"}}}
+	state := &shell.SessionState{}
+	var offered []*store.Task
+	exec := shell.NewExecutor(svc, state, shell.WithPicker(func(tasks []*store.Task) (*store.Task, error) {
+		offered = tasks
+		return tasks[1], nil
+	}))
+	ctx := context.Background()
+
+	result, err := exec.Execute(ctx, "select")
+	require.NoError(t, err, "select error")
+	assert.Len(t, offered, 2, "picker should be offered the open tasks")
+	assert.True(t, svc.lastFilter.TodoOnly, "picker should list open tasks")
+	assert.Equal(t, "Selected task #9: Call mom", result.Message, "message mismatch")
+	require.NotNil(t, state.SelectedTaskID, "select should set the selection")
+	assert.Equal(t, int64(9), *state.SelectedTaskID, "selected task mismatch")
+
+	_, err = exec.Execute(ctx, "select done")
+	require.NoError(t, err, "select done error")
+	assert.Equal(t, []int64{9}, svc.lastDone, "select done should complete the picked task")
+}
+
+func TestExecutePicksMissingSelection(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{tasks: []*store.Task{{ID: 6, Title: "Plan trip"}}}
+	state := &shell.SessionState{}
+	picks := 0
+	exec := shell.NewExecutor(svc, state, shell.WithPicker(func(tasks []*store.Task) (*store.Task, error) {
+		picks++
+		return tasks[0], nil
+	}))
+	ctx := context.Background()
+
+	_, err := exec.Execute(ctx, "done")
+	require.NoError(t, err, "done error")
+	assert.Equal(t, []int64{6}, svc.lastDone, "done should act on the picked task")
+
+	_, err = exec.Execute(ctx, "undo")
+	require.NoError(t, err, "undo error")
+	assert.Equal(t, 1, picks, "the picked task should stay selected")
+
+	_, err = exec.Execute(ctx, "done 3")
+	require.NoError(t, err, "done by id error")
+	assert.Equal(t, 1, picks, "explicit targets should not pick")
+}
+
+func TestExecutePickListsTasksForVerb(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  service.ListTasksRequest
+	}{
+		{input: "undo", want: service.ListTasksRequest{DoneOnly: true}},
+		{input: "show", want: service.ListTasksRequest{All: true}},
+		{input: "rm", want: service.ListTasksRequest{All: true}},
+		{input: "set state:now", want: service.ListTasksRequest{TodoOnly: true}},
+		{input: "select undo", want: service.ListTasksRequest{DoneOnly: true}},
+		{input: "select show", want: service.ListTasksRequest{All: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &recordingService{tasks: []*store.Task{{ID: 6, Title: "Plan trip"}}}
+			pick := func(tasks []*store.Task) (*store.Task, error) { return tasks[0], nil }
+			exec := shell.NewExecutor(svc, &shell.SessionState{}, shell.WithPicker(pick))
+			_, err := exec.Execute(context.Background(), tt.input)
+			require.NoError(t, err, "execute error")
+			assert.Equal(t, tt.want, svc.lastFilter, "picker list request mismatch")
+		})
+	}
+}
+
+func TestExecuteSelectWithoutPicker(t *testing.T) {
+	t.Parallel()
+
+	exec := shell.NewExecutor(&recordingService{}, &shell.SessionState{})
+	_, err := exec.Execute(context.Background(), "select")
+	require.ErrorContains(t, err, "select needs an interactive shell", "error mismatch")
+
+	_, err = exec.Execute(context.Background(), "done")
+	require.ErrorContains(t, err, "selected target requires SelectedTaskID", "scripts should not pick")
+}
+
+func TestExecuteProcessRunsInbox(t *testing.T) {
+	t.Parallel()
+
+	exec := shell.NewExecutor(&recordingService{}, &shell.SessionState{},
+		shell.WithProcess(func(context.Context) (clarify.Summary, error) {
+			return clarify.Summary{Total: 5, Processed: 2, Quit: true}, nil
+		}))
+
+	result, err := exec.Execute(context.Background(), "process")
+	require.NoError(t, err, "process error")
+	assert.Equal(t, "Processed 2 of 5 inbox tasks; 3 left", result.Message, "message mismatch")
+	assert.Equal(t, shell.ResultLevelInfo, result.Level, "quitting early is not a success")
+
+	_, err = shell.NewExecutor(&recordingService{}, &shell.SessionState{}).Execute(context.Background(), "process")
+	require.ErrorContains(t, err, "process needs an interactive shell", "scripts should not process")
+}
+
+func TestExecuteDeleteClearsDeletedSelection(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	selected := int64(4)
+	state := &shell.SessionState{SelectedTaskID: &selected, LastTaskIDs: []int64{4}}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "rm selected")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, []int64{4}, svc.lastDelete, "delete ids mismatch")
+	assert.Equal(t, "Deleted 1 task(s): #4", result.Message, "message mismatch")
+	assert.Nil(t, state.SelectedTaskID, "deleted task should not stay selected")
+	assert.Empty(t, state.LastTaskIDs, "deleted tasks should not stay as pronoun targets")
+}
+
+func TestExecuteDeleteImplicitTargetAsksFirst(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{LastTaskIDs: []int64{4}}
+	var actions []string
+	exec := shell.NewExecutor(svc, state, shell.WithConfirm(func(action string, _ []*store.Task) (bool, error) {
+		actions = append(actions, action)
+		return false, nil
+	}))
+
+	result, err := exec.Execute(context.Background(), "delete it")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, []string{"delete"}, actions, "pronoun delete should ask first")
+	assert.Nil(t, svc.lastDelete, "declined delete should not apply")
+	assert.Equal(t, "Delete cancelled", result.Message, "message mismatch")
+
+	_, err = exec.Execute(context.Background(), "delete 4")
+	require.NoError(t, err, "execute error")
+	assert.Len(t, actions, 1, "explicit ids should not ask")
+	assert.Equal(t, []int64{4}, svc.lastDelete, "delete ids mismatch")
+}
+
+func TestExecuteShowTargetGetsTask(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "show 12")
+	require.NoError(t, err, "execute error")
+	require.NotNil(t, result.Task, "show should return one task")
+	assert.Equal(t, int64(12), result.Task.ID, "task id mismatch")
+	assert.Equal(t, "show", result.Intent, "intent mismatch")
+
+	result, err = exec.Execute(context.Background(), "show 1,2")
+	require.NoError(t, err, "execute error")
+	assert.Len(t, result.Tasks, 2, "show with several targets should list tasks")
+}
+
+func TestExecuteInvalidStateSuggestsClosest(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+
+	_, err := exec.Execute(context.Background(), "set 3 state:nwo")
+	require.Error(t, err, "invalid state should fail")
+
+	var diagnosticErr nlp.DiagnosticError
+	require.ErrorAs(t, err, &diagnosticErr, "error should carry diagnostics")
+	diag := diagnosticErr.Diagnostics()[0]
+	assert.Equal(t, "did you mean now?", diag.Hint, "hint mismatch")
+	require.NotNil(t, diag.Span, "span should be set")
+	assert.Equal(t, "nwo", diag.Input[diag.Span.Start:diag.Span.End], "span should cover the state")
+}
+
+func TestExecutePipelineRunsStagesOnPreviousTasks(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{tasks: []*store.Task{{ID: 3}, {ID: 8}}}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+
+	result, err := exec.Execute(context.Background(), "find #errands && state:inbox | set state:now @out; done 8")
+	require.NoError(t, err, "execute error")
+	require.Len(t, result.Stages, 3, "stage results mismatch")
+	assert.Equal(t, "pipeline", result.Intent, "intent mismatch")
+	assert.Equal(t, "found 2 tasks; updated 2 tasks; completed 1 tasks", result.Summary, "summary mismatch")
+	assert.True(t, result.Stages[0].Piped, "filter stage should be marked as piped")
+	assert.False(t, result.Stages[1].Piped, "last pipeline stage should be shown")
+
+	require.Len(t, svc.lastBatch, 2, "set should apply to every filtered task")
+	assert.Equal(t, int64(3), svc.lastBatch[0].ID, "first batch id mismatch")
+	assert.Equal(t, int64(8), svc.lastBatch[1].ID, "second batch id mismatch")
+	assert.Equal(t, []int64{8}, svc.lastDone, "done ids mismatch")
+	assert.Equal(t, []int64{8}, state.LastTaskIDs, "last task ids should come from the last stage")
+}
+
+func TestExecutePipelineStopsWithoutTasks(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+
+	result, err := exec.Execute(context.Background(), "find #errands | done")
+	require.NoError(t, err, "execute error")
+	require.Len(t, result.Stages, 2, "stage results mismatch")
+	assert.Equal(t, `No tasks for "done"`, result.Stages[1].Message, "skip message mismatch")
+	assert.Nil(t, svc.lastDone, "done should not run without tasks")
+}
+
+func TestExecuteProgramReturnsResultsBeforeFailure(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+
+	result, err := exec.Execute(context.Background(), "done 3; set 4 state:nwo; done 5")
+	require.Error(t, err, "invalid stage should fail")
+	assert.Contains(t, err.Error(), "set 4 state:nwo", "error should name the failed stage")
+	require.NotNil(t, result, "stages before the failure should be returned")
+	require.Len(t, result.Stages, 1, "stage results mismatch")
+	assert.Equal(t, "Completed 1 task(s): #3", result.Stages[0].Message, "completed stage mismatch")
+	assert.Equal(t, []int64{3}, svc.lastDone, "stages after the failure should not run")
+}
+
+func TestExecuteExpandsDefinitions(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+	ctx := context.Background()
+
+	result, err := exec.Execute(ctx, "let w = #work @office")
+	require.NoError(t, err, "let error")
+	assert.Equal(t, "define", result.Intent, "intent mismatch")
+	assert.Equal(t, "Defined variable $w", result.Message, "message mismatch")
+
+	_, err = exec.Execute(ctx, "macro triage $id = set $id state:now $w")
+	require.NoError(t, err, "macro error")
+
+	_, err = exec.Execute(ctx, "triage 12")
+	require.NoError(t, err, "execute error")
+	assert.Equal(t, int64(12), svc.lastUpdate.ID, "update id mismatch")
+	require.NotNil(t, svc.lastUpdate.State, "state should be set")
+	assert.Equal(t, "now", *svc.lastUpdate.State, "state mismatch")
+	assert.Equal(t, []string{"work"}, svc.lastUpdate.AddProjects, "projects mismatch")
+	assert.Equal(t, []string{"office"}, svc.lastUpdate.AddContexts, "contexts mismatch")
+}
+
 func TestExecuteFilterStickyProjectWrapsEntireOrExpression(t *testing.T) {
 	t.Parallel()
 
@@ -122,6 +603,20 @@ type recordingService struct {
 	lastCreate service.CreateTaskRequest
 	lastUpdate service.UpdateTaskRequest
 	lastFilter service.ListTasksRequest
+	lastBulk   []int64
+	lastBatch  []service.UpdateTaskRequest
+	lastDone   []int64
+	doneValue  bool
+	lastDelete []int64
+	tasks      []*store.Task
+	sessions   map[string]store.ShellSession
+	stalled    []store.StalledProject
+	snoozed    []int64
+	snoozeTo   time.Time
+	wakes      int
+	done       map[int64]bool
+	missing    []int64
+	notes      []string
 }
 
 func (s *recordingService) CreateTask(_ context.Context, req service.CreateTaskRequest) (*store.Task, error) {
@@ -135,8 +630,25 @@ func (s *recordingService) CreateTask(_ context.Context, req service.CreateTaskR
 	}, nil
 }
 
+func (s *recordingService) CreateTasks(
+	ctx context.Context, reqs []service.CreateTaskRequest,
+) ([]*store.Task, error) {
+	tasks := make([]*store.Task, 0, len(reqs))
+	for _, req := range reqs {
+		task, err := s.CreateTask(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
 func (s *recordingService) ListTasks(_ context.Context, req service.ListTasksRequest) ([]*store.Task, error) {
 	s.lastFilter = req
+	if s.tasks != nil {
+		return s.tasks, nil
+	}
 	return []*store.Task{}, nil
 }
 
@@ -144,8 +656,15 @@ func (*recordingService) ListTaskVersions(_ context.Context, _ int64, _ int64) (
 	return []*store.TaskVersion{}, nil
 }
 
-func (s *recordingService) GetTask(_ context.Context, _ int64) (*store.Task, error) {
-	return &store.Task{}, nil
+func (s *recordingService) GetTask(_ context.Context, id int64) (*store.Task, error) {
+	if slices.Contains(s.missing, id) {
+		return nil, sql.ErrNoRows
+	}
+	task := &store.Task{ID: id, State: store.StateInbox}
+	if s.done[id] {
+		task.State = store.StateDone
+	}
+	return task, nil
 }
 
 func (s *recordingService) UpdateTask(_ context.Context, req service.UpdateTaskRequest) (*store.Task, error) {
@@ -157,12 +676,52 @@ func (*recordingService) FullUpdateTask(_ context.Context, _ service.FullUpdateT
 	return &store.Task{}, nil
 }
 
-func (*recordingService) SetDone(_ context.Context, _ []int64, _ bool) (int64, error) {
-	return 0, nil
+func (s *recordingService) SetDone(_ context.Context, ids []int64, done bool) (int64, error) {
+	s.lastDone = ids
+	s.doneValue = done
+	if s.done == nil {
+		s.done = map[int64]bool{}
+	}
+	for _, id := range ids {
+		s.done[id] = done
+	}
+	return int64(len(ids)), nil
 }
 
-func (*recordingService) DeleteTasks(_ context.Context, _ []int64) (int64, error) {
-	return 0, nil
+func (s *recordingService) DeleteTasks(_ context.Context, ids []int64) (int64, error) {
+	s.lastDelete = ids
+	return int64(len(ids)), nil
+}
+
+func (s *recordingService) BulkUpdateTasks(
+	_ context.Context, ids []int64, req service.UpdateTaskRequest,
+) ([]service.BulkTaskResult, error) {
+	s.lastBulk = ids
+	s.lastUpdate = req
+	results := make([]service.BulkTaskResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, service.BulkTaskResult{ID: id, Status: service.BulkStatusUpdated})
+	}
+	return results, nil
+}
+
+func (s *recordingService) BatchUpdateTasks(
+	_ context.Context, reqs []service.UpdateTaskRequest,
+) ([]service.BulkTaskResult, error) {
+	s.lastBatch = reqs
+	results := make([]service.BulkTaskResult, 0, len(reqs))
+	for _, req := range reqs {
+		results = append(results, service.BulkTaskResult{ID: req.ID, Status: service.BulkStatusUpdated})
+	}
+	return results, nil
+}
+
+func (*recordingService) BulkSetDone(_ context.Context, _ []int64, _ bool) ([]service.BulkTaskResult, error) {
+	return nil, nil
+}
+
+func (*recordingService) BulkDeleteTasks(_ context.Context, _ []int64) ([]service.BulkTaskResult, error) {
+	return nil, nil
 }
 
 func (*recordingService) ListProjects(_ context.Context, _ service.ListTagsRequest) ([]store.NameCount, error) {
@@ -173,6 +732,61 @@ func (*recordingService) ListContexts(_ context.Context, _ service.ListTagsReque
 	return []store.NameCount{}, nil
 }
 
+func (s *recordingService) ListStalledProjects(_ context.Context) ([]store.StalledProject, error) {
+	return s.stalled, nil
+}
+
+func (s *recordingService) ListWaitingSince(_ context.Context) (map[int64]time.Time, error) {
+	return map[int64]time.Time{}, nil
+}
+
+func (*recordingService) ListSnoozed(_ context.Context) ([]*store.Task, error) {
+	return []*store.Task{}, nil
+}
+
+func (s *recordingService) MakeProject(
+	ctx context.Context, id int64, project string, next string,
+) (*store.Task, *store.Task, error) {
+	updated := &store.Task{ID: id, State: store.StateLater, Projects: []string{project}}
+	if next == "" {
+		return updated, nil, nil
+	}
+	created, err := s.CreateTask(ctx, service.CreateTaskRequest{Title: next, Projects: []string{project}})
+	return updated, created, err
+}
+
+func (s *recordingService) SnoozeTasks(_ context.Context, ids []int64, until time.Time) ([]*store.Task, error) {
+	s.snoozed = append(s.snoozed, ids...)
+	s.snoozeTo = until
+	tasks := make([]*store.Task, 0, len(ids))
+	for _, id := range ids {
+		tasks = append(tasks, &store.Task{ID: id, State: store.StateLater, SnoozedUntil: &until})
+	}
+	return tasks, nil
+}
+
+func (s *recordingService) WakeSnoozed(_ context.Context, _ time.Time) ([]*store.Task, error) {
+	s.wakes++
+	return nil, nil
+}
+
+func (*recordingService) AddChecklistItem(_ context.Context, id int64, _ string) (*store.Task, error) {
+	return &store.Task{ID: id}, nil
+}
+
+func (*recordingService) ToggleChecklistItem(_ context.Context, id int64, _ int) (*store.Task, error) {
+	return &store.Task{ID: id}, nil
+}
+
+func (*recordingService) RemoveChecklistItem(_ context.Context, id int64, _ int) (*store.Task, error) {
+	return &store.Task{ID: id}, nil
+}
+
+func (s *recordingService) AddAnnotation(_ context.Context, id int64, text string) (*store.Task, error) {
+	s.notes = append(s.notes, fmt.Sprintf("%d:%s", id, text))
+	return &store.Task{ID: id}, nil
+}
+
 func (*recordingService) Sync(_ context.Context) error {
 	return nil
 }
@@ -185,6 +799,14 @@ func (*recordingService) SyncStatus(_ context.Context) (*service.SyncStatus, err
 	return &service.SyncStatus{}, nil
 }
 
+func (*recordingService) MetaSchema() domain.MetaSchema {
+	return nil
+}
+
+func (*recordingService) Urgency() urgency.Scorer {
+	return urgency.NewScorer()
+}
+
 func (*recordingService) Close() error {
 	return nil
 }
@@ -209,6 +831,45 @@ func (*recordingService) ClearShellHistory(_ context.Context) error {
 	return nil
 }
 
+func (s *recordingService) SaveShellSession(_ context.Context, session store.ShellSession) error {
+	if s.sessions == nil {
+		s.sessions = map[string]store.ShellSession{}
+	}
+	s.sessions[session.Name] = session
+	return nil
+}
+
+func (s *recordingService) GetShellSession(_ context.Context, name string) (*store.ShellSession, error) {
+	session, ok := s.sessions[name]
+	if !ok {
+		return nil, nil //nolint:nilnil // matches the store: a missing session is not an error
+	}
+	return &session, nil
+}
+
+func (s *recordingService) ListShellSessions(_ context.Context) ([]*store.ShellSession, error) {
+	sessions := make([]*store.ShellSession, 0, len(s.sessions))
+	for _, name := range slices.Sorted(maps.Keys(s.sessions)) {
+		session := s.sessions[name]
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+func (s *recordingService) DeleteShellSession(_ context.Context, name string) (bool, error) {
+	_, ok := s.sessions[name]
+	delete(s.sessions, name)
+	return ok, nil
+}
+
+func (*recordingService) RecordReview(_ context.Context, review store.Review) (*store.Review, error) {
+	return &review, nil
+}
+
+func (*recordingService) LastReview(_ context.Context) (*store.Review, error) {
+	return nil, nil //nolint:nilnil // matches the store: no review yet is not an error
+}
+
 func contains(values []string, want string) bool {
 	return slices.Contains(values, want)
 }
@@ -483,6 +1144,33 @@ func TestExecuteSelectedPronounSubstitution(t *testing.T) {
 	assert.Equal(t, "updated via selected", updated.Title, "task title mismatch")
 }
 
+func TestExecuteReferencesResolveOnTokens(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{tasks: []*store.Task{{ID: 3}, {ID: 8}}}
+	state := &shell.SessionState{LastTaskIDs: []int64{5}}
+	exec := shell.NewExecutor(svc, state)
+	ctx := context.Background()
+
+	_, err := exec.Execute(ctx, `add "fix it later" selected`)
+	require.NoError(t, err, "create error")
+	assert.Equal(t, "fix it later selected", svc.lastCreate.Title, "pronouns in titles should stay text")
+
+	_, err = exec.Execute(ctx, "find #errands")
+	require.NoError(t, err, "find error")
+
+	_, err = exec.Execute(ctx, "done %2")
+	require.NoError(t, err, "done %2 error")
+	assert.Equal(t, []int64{8}, svc.lastDone, "%2 should be the second row of the find")
+
+	_, err = exec.Execute(ctx, "rm ^2")
+	require.NoError(t, err, "rm ^2 error")
+	assert.Equal(t, []int64{3, 8}, svc.lastDelete, "^2 should be the tasks of the find")
+
+	_, err = exec.Execute(ctx, "undo it")
+	require.Error(t, err, "it should not refer to deleted tasks")
+}
+
 func TestExecuteCreateWithoutContext(t *testing.T) {
 	t.Parallel()
 
@@ -550,7 +1238,7 @@ func TestExecuteViewShowsHelp(t *testing.T) {
 	require.NotNil(t, result, "result should not be nil")
 	assert.Equal(t, "view", result.Intent, "intent mismatch")
 	require.NotNil(t, result.ViewHelp, "view help should be set")
-	assert.Len(t, result.ViewHelp.Entries, 5, "view help entries mismatch")
+	assert.Len(t, result.ViewHelp.Entries, 8, "view help entries mismatch")
 	assert.Equal(t, "view <name> (e.g., view i or view inbox)", result.ViewHelp.Usage, "usage mismatch")
 }
 
@@ -567,6 +1255,20 @@ func TestExecuteViewRunsFilterQuery(t *testing.T) {
 	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredState), "filter should include state predicate")
 }
 
+func TestExecuteViewStalledListsProjects(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{stalled: []store.StalledProject{{Name: "kitchen", Open: 2, Waiting: 2}}}
+	exec := shell.NewExecutor(svc, &shell.SessionState{})
+
+	result, err := exec.Execute(context.Background(), "view stalled")
+	require.NoError(t, err, "execute error")
+	require.NotNil(t, result, "result should not be nil")
+	assert.Equal(t, "view", result.Intent, "intent mismatch")
+	assert.Equal(t, svc.stalled, result.StalledProjects, "stalled projects mismatch")
+	assert.Nil(t, result.Tasks, "stalled view should not run a task filter")
+}
+
 func TestExecuteViewCalendarRunsDueSetFilter(t *testing.T) {
 	t.Parallel()
 
@@ -599,6 +1301,122 @@ func TestExecuteViewRespectsStickyContext(t *testing.T) {
 	)
 }
 
+func TestExecuteContextFilterAppliesToFindAndView(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+	ctx := context.Background()
+
+	result, err := exec.Execute(ctx, "context state:now && due:today")
+	require.NoError(t, err, "context error")
+	assert.Equal(t, "Set sticky filter to state:now && due:today", result.Message, "message mismatch")
+	assert.Equal(t, "state:now && due:today", state.ContextFilter, "context filter mismatch")
+
+	_, err = exec.Execute(ctx, "find #work")
+	require.NoError(t, err, "find error")
+	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredProject), "find should keep its own predicate")
+	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredDue), "find should include sticky due")
+
+	_, err = exec.Execute(ctx, "view inbox")
+	require.NoError(t, err, "view error")
+	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredDue), "view should include sticky due")
+
+	_, err = exec.Execute(ctx, "context clear")
+	require.NoError(t, err, "clear error")
+	assert.Empty(t, state.ContextFilter, "clear should drop the filter")
+}
+
+func TestExecuteContextAvailableFollowsSchedule(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{}
+	exec := shell.NewExecutor(svc, state)
+	ctx := context.Background()
+
+	_, err := exec.Execute(ctx, "context available")
+	require.NoError(t, err, "context available error")
+	assert.Equal(t, "available", state.ContextFilter, "context filter mismatch")
+
+	_, err = exec.Execute(ctx, "find #work")
+	require.NoError(t, err, "find error")
+	assert.True(
+		t,
+		hasPredicateKind(svc.lastFilter.Filter, nlp.PredAvailable),
+		"find should include the sticky available filter",
+	)
+
+	_, err = exec.Execute(ctx, "view available")
+	require.NoError(t, err, "view available error")
+	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredAvailable), "view should filter by availability")
+}
+
+func TestExecuteSessionCommands(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	state := &shell.SessionState{ContextProject: "work", LastTaskIDs: []int64{4}}
+	exec := shell.NewExecutor(svc, state)
+	ctx := context.Background()
+
+	_, err := exec.Execute(ctx, "session save")
+	require.ErrorContains(t, err, "usage: session save NAME", "unnamed save should fail")
+
+	result, err := exec.Execute(ctx, "session save work")
+	require.NoError(t, err, "save error")
+	assert.Equal(t, "Saved session work", result.Message, "save message mismatch")
+	assert.Equal(t, "work", state.Name, "save should make the session current")
+
+	result, err = exec.Execute(ctx, "session list")
+	require.NoError(t, err, "list error")
+	assert.Contains(t, result.Message, "* work", "list should mark the current session")
+
+	restored := &shell.SessionState{}
+	found, err := shell.RestoreSession(ctx, svc, "work", restored)
+	require.NoError(t, err, "restore error")
+	assert.True(t, found, "saved session should be found")
+	assert.Equal(t, "work", restored.ContextProject, "restored project mismatch")
+	assert.Equal(t, []int64{4}, restored.LastTaskIDs, "restored last tasks mismatch")
+
+	_, err = exec.Execute(ctx, "session drop work")
+	require.NoError(t, err, "drop error")
+	assert.Empty(t, state.Name, "dropping the current session should unname it")
+
+	_, err = exec.Execute(ctx, "session drop work")
+	require.ErrorContains(t, err, `no session named "work"`, "second drop should fail")
+
+	_, err = exec.Execute(ctx, "session save my/session")
+	require.ErrorContains(t, err, "invalid session name", "bad name should fail")
+}
+
+func TestExecuteViewRunsUserDefinedView(t *testing.T) {
+	t.Parallel()
+
+	svc := &recordingService{}
+	exec := shell.NewExecutor(svc, &shell.SessionState{}, shell.WithViews(map[string]config.View{
+		"focus": {Where: "state:now && #work", Sort: "due"},
+	}))
+
+	result, err := exec.Execute(context.Background(), "view focus")
+	require.NoError(t, err, "execute error")
+	require.NotNil(t, result, "result should not be nil")
+	assert.Equal(t, "filter", result.Intent, "user view should execute as filter")
+	assert.True(t, hasPredicateKind(svc.lastFilter.Filter, nlp.PredProject), "filter should include project predicate")
+	assert.Equal(t, []nlp.SortKey{{Field: nlp.SortFieldDue}}, svc.lastFilter.Sort, "view sort mismatch")
+
+	help, err := exec.Execute(context.Background(), "view")
+	require.NoError(t, err, "execute help error")
+	require.NotNil(t, help.ViewHelp, "view help should be set")
+	assert.Len(t, help.ViewHelp.Entries, 9, "view help should list the user view")
+	assert.Equal(t, "stalled", help.ViewHelp.Entries[7].Label, "stalled view should follow the built-in views")
+	assert.Equal(t, "focus", help.ViewHelp.Entries[8].Label, "user view label mismatch")
+
+	_, err = exec.Execute(context.Background(), "view missing")
+	require.Error(t, err, "unknown view should fail")
+}
+
 func TestExecuteContextSetShowAndClear(t *testing.T) {
 	t.Parallel()
 
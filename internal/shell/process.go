@@ -0,0 +1,42 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mholtzscher/ugh/internal/clarify"
+)
+
+const processKeyword = "process"
+
+// ProcessFunc walks through the inbox one task at a time.
+type ProcessFunc func(ctx context.Context) (clarify.Summary, error)
+
+// IsProcessCommand reports whether line starts inbox processing.
+func IsProcessCommand(line string) bool {
+	return strings.EqualFold(strings.TrimSpace(line), processKeyword)
+}
+
+func (e *Executor) executeProcess(ctx context.Context) (*ExecuteResult, error) {
+	if e.process == nil {
+		return nil, errors.New("process needs an interactive shell")
+	}
+	summary, err := e.process(ctx)
+	if err != nil {
+		return nil, err
+	}
+	level := ResultLevelSuccess
+	if summary.Quit || summary.Total == 0 {
+		level = ResultLevelInfo
+	}
+	return &ExecuteResult{
+		Intent:    processKeyword,
+		Message:   summary.String(),
+		Level:     level,
+		Summary:   fmt.Sprintf("processed %d of %d inbox tasks", summary.Processed, summary.Total),
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -2,8 +2,11 @@ package shell
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"slices"
+	"strings"
 
 	"github.com/chzyer/readline"
 	"github.com/pterm/pterm"
@@ -13,20 +16,23 @@ import (
 
 // Prompt wraps readline functionality.
 type Prompt struct {
-	rl *readline.Instance
+	rl      *readline.Instance
+	session string
 }
 
 // NewPrompt creates a new interactive prompt with history loaded from SQLite.
-func NewPrompt(svc service.Service) (*Prompt, error) {
-	promptText := pterm.ThemeDefault.PrimaryStyle.Sprint("➜ ") + pterm.ThemeDefault.SecondaryStyle.Sprint("ugh> ")
-
+// viewNames lists user-defined views and people the known names that
+// completion offers.
+func NewPrompt(
+	svc service.Service, viewNames []string, people []string, defs *Definitions, session string,
+) (*Prompt, error) {
 	cfg := &readline.Config{
-		Prompt:          promptText,
+		Prompt:          promptText(session),
 		InterruptPrompt: "^C",
 		EOFPrompt:       "exit",
-		AutoComplete:    newShellCompleter(svc),
+		AutoComplete:    newShellCompleter(svc, viewNames, people, defs),
 	}
-	cfg.Painter = newShellPainter()
+	cfg.Painter = newShellPainter(defs)
 
 	rl, err := readline.NewEx(cfg)
 	if err != nil {
@@ -44,7 +50,25 @@ func NewPrompt(svc service.Service) (*Prompt, error) {
 		}
 	}
 
-	return &Prompt{rl: rl}, nil
+	return &Prompt{rl: rl, session: session}, nil
+}
+
+// promptText renders the prompt, naming the session when there is one.
+func promptText(session string) string {
+	name := "ugh"
+	if session != "" {
+		name += "[" + session + "]"
+	}
+	return pterm.ThemeDefault.PrimaryStyle.Sprint("➜ ") + pterm.ThemeDefault.SecondaryStyle.Sprint(name+"> ")
+}
+
+// SetSession updates the session name shown in the prompt.
+func (p *Prompt) SetSession(session string) {
+	if session == p.session {
+		return
+	}
+	p.session = session
+	p.rl.SetPrompt(promptText(session))
 }
 
 // Readline reads a single line of input.
@@ -52,6 +76,28 @@ func (p *Prompt) Readline() (string, error) {
 	return p.rl.Readline()
 }
 
+// Confirm asks a yes/no question on the prompt line; anything but y/yes,
+// including Ctrl-C, declines.
+func (p *Prompt) Confirm(question string) (bool, error) {
+	original := p.rl.Config.Prompt
+	p.rl.SetPrompt(question + " [y/N] ")
+	defer p.rl.SetPrompt(original)
+
+	line, err := p.rl.Readline()
+	if err != nil {
+		if errors.Is(err, readline.ErrInterrupt) || errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
 // Close closes the prompt.
 func (p *Prompt) Close() error {
 	return p.rl.Close()
@@ -2,6 +2,8 @@ package shell
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"sort"
 	"strings"
 	"unicode"
@@ -24,19 +26,22 @@ func commandSuggestions() []string {
 		"add", "create", "new",
 		"set", "edit", "update",
 		"find", "show", "list", "filter",
+		"done", "complete", "finish", "undo", "reopen", "rm", "delete", "snooze",
+		"note", "annotate",
 		"context", "view", "help", "clear", "quit", "exit",
 		"log", "activity",
+		"let", "alias", "macro", "unset", "unalias", "session", "select", "process",
 	}
 }
 
 func genericSuggestions() []string {
 	return []string{
-		"title:", "notes:", "due:", "waiting:", "state:",
+		"title:", "notes:", "due:", "waiting:", "until:", "state:",
 		"project:", "projects:", "context:", "contexts:",
 		"+project:", "+context:", "-project:", "-context:",
-		"!due", "!waiting", "!notes",
+		"!due", "!waiting", "!until", "!notes",
 		"and", "or", "not", "&&", "||",
-		"today", "tomorrow",
+		"today", "tomorrow", "available",
 	}
 }
 
@@ -55,17 +60,23 @@ func viewSuggestions() []string {
 		"w", "waiting",
 		"l", "later",
 		"c", "calendar", "today",
+		"f", "follow-up",
+		"a", "available",
+		nlp.StalledViewName,
 	}
 }
 
 type shellCompleter struct {
 	listProjects func(context.Context) ([]string, error)
 	listContexts func(context.Context) ([]string, error)
+	viewNames    []string
+	people       []string
+	defs         *Definitions
 }
 
 var _ readline.AutoCompleter = (*shellCompleter)(nil)
 
-func newShellCompleter(svc service.Service) *shellCompleter {
+func newShellCompleter(svc service.Service, viewNames []string, people []string, defs *Definitions) *shellCompleter {
 	return &shellCompleter{
 		listProjects: func(ctx context.Context) ([]string, error) {
 			rows, err := svc.ListProjects(ctx, service.ListTagsRequest{})
@@ -81,6 +92,9 @@ func newShellCompleter(svc service.Service) *shellCompleter {
 			}
 			return extractNames(rows), nil
 		},
+		viewNames: viewNames,
+		people:    people,
+		defs:      defs,
 	}
 }
 
@@ -115,9 +129,9 @@ func (c *shellCompleter) Do(line []rune, pos int) ([][]rune, int) {
 }
 
 func (c *shellCompleter) suggest(tokens []nlp.LexToken, fragment string, fragmentLower string) []string {
-	nonWhitespace := filterNonWhitespace(tokens)
+	nonWhitespace := filterNonWhitespace(currentStage(tokens))
 	if len(nonWhitespace) == 0 {
-		return filterCandidates(fragment, commandSuggestions())
+		return filterCandidates(fragment, c.commandCandidates())
 	}
 
 	if suggestions, handled := c.viewCommandSuggestions(nonWhitespace, fragment); handled {
@@ -128,12 +142,19 @@ func (c *shellCompleter) suggest(tokens []nlp.LexToken, fragment string, fragmen
 		return suggestions
 	}
 
+	if suggestions, handled := sessionCommandSuggestions(nonWhitespace, fragment); handled {
+		return suggestions
+	}
+
 	if strings.HasPrefix(fragmentLower, "#") {
 		return filterCandidates(fragment, prefixed(c.projectNames(), "#"))
 	}
 	if strings.HasPrefix(fragmentLower, "@") {
 		return filterCandidates(fragment, prefixed(c.contextNames(), "@"))
 	}
+	if strings.HasPrefix(fragment, "$") {
+		return filterCandidates(fragment, prefixed(c.variableNames(), "$"))
+	}
 
 	if strings.HasPrefix(fragmentLower, "state:") {
 		return filterCandidates(fragment, stateSuggestions())
@@ -148,6 +169,8 @@ func (c *shellCompleter) suggest(tokens []nlp.LexToken, fragment string, fragmen
 			return filterCandidates(fragment, withValuePrefix(fieldPrefix, valuePrefix, c.projectNames()))
 		case "context:", "contexts:", "+context:", "+contexts:", "-context:", "-contexts:":
 			return filterCandidates(fragment, withValuePrefix(fieldPrefix, valuePrefix, c.contextNames()))
+		case "waiting:", "waiting-for:", "waiting_for:":
+			return filterCandidates(fragment, withValuePrefix(fieldPrefix, valuePrefix, c.people))
 		}
 	}
 
@@ -158,7 +181,7 @@ func (c *shellCompleter) suggest(tokens []nlp.LexToken, fragment string, fragmen
 		return dedupe(candidates)
 	}
 
-	candidates := append([]string{}, commandSuggestions()...)
+	candidates := c.commandCandidates()
 	candidates = append(candidates, genericSuggestions()...)
 	return filterCandidates(fragment, dedupe(candidates))
 }
@@ -176,7 +199,7 @@ func (c *shellCompleter) viewCommandSuggestions(nonWhitespace []nlp.LexToken, fr
 
 	if fragment == "" {
 		if len(nonWhitespace) == 1 {
-			return filterCandidates(fragment, viewSuggestions()), true
+			return filterCandidates(fragment, c.viewCandidates()), true
 		}
 		return nil, true
 	}
@@ -185,7 +208,11 @@ func (c *shellCompleter) viewCommandSuggestions(nonWhitespace []nlp.LexToken, fr
 		return nil, true
 	}
 
-	return filterCandidates(fragment, viewSuggestions()), true
+	return filterCandidates(fragment, c.viewCandidates()), true
+}
+
+func (c *shellCompleter) viewCandidates() []string {
+	return dedupe(append(viewSuggestions(), c.viewNames...))
 }
 
 func (c *shellCompleter) contextCommandSuggestions(nonWhitespace []nlp.LexToken, fragment string) ([]string, bool) {
@@ -204,6 +231,36 @@ func (c *shellCompleter) contextCommandSuggestions(nonWhitespace []nlp.LexToken,
 	return filterCandidates(fragment, dedupe(candidates)), true
 }
 
+func sessionCommandSuggestions(nonWhitespace []nlp.LexToken, fragment string) ([]string, bool) {
+	if len(nonWhitespace) == 0 ||
+		nonWhitespace[0].Name != identTokenName ||
+		!strings.EqualFold(nonWhitespace[0].Value, sessionKeyword) {
+		return nil, false
+	}
+	if fragment != "" && len(nonWhitespace) == 1 {
+		return nil, false
+	}
+	if (fragment == "" && len(nonWhitespace) == 1) || (fragment != "" && len(nonWhitespace) == 2) {
+		return filterCandidates(fragment, []string{"list", "save", "drop"}), true
+	}
+	return nil, true
+}
+
+// commandCandidates returns the verbs plus the user's aliases and macros.
+func (c *shellCompleter) commandCandidates() []string {
+	if c.defs == nil {
+		return commandSuggestions()
+	}
+	return append(commandSuggestions(), c.defs.CommandNames()...)
+}
+
+func (c *shellCompleter) variableNames() []string {
+	if c.defs == nil {
+		return nil
+	}
+	return c.defs.VariableNames()
+}
+
 func (c *shellCompleter) projectNames() []string {
 	if c.listProjects == nil {
 		return nil
@@ -357,6 +414,17 @@ func hasOpenQuote(tokens []nlp.LexToken) bool {
 	return open > 0
 }
 
+// currentStage returns the tokens after the last ";" or "|", so each command
+// of a chained line completes like a line of its own.
+func currentStage(tokens []nlp.LexToken) []nlp.LexToken {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].Name == "Semicolon" || tokens[i].Name == "Pipe" {
+			return tokens[i+1:]
+		}
+	}
+	return tokens
+}
+
 func filterNonWhitespace(tokens []nlp.LexToken) []nlp.LexToken {
 	out := make([]nlp.LexToken, 0, len(tokens))
 	for _, tok := range tokens {
@@ -368,15 +436,20 @@ func filterNonWhitespace(tokens []nlp.LexToken) []nlp.LexToken {
 	return out
 }
 
-type shellPainter struct{}
+type shellPainter struct {
+	defs *Definitions
+}
 
 var _ readline.Painter = (*shellPainter)(nil)
 
-func newShellPainter() *shellPainter {
-	return &shellPainter{}
+func newShellPainter(defs *Definitions) *shellPainter {
+	if defs == nil {
+		defs = NewDefinitions()
+	}
+	return &shellPainter{defs: defs}
 }
 
-func (*shellPainter) Paint(line []rune, _ int) []rune {
+func (p *shellPainter) Paint(line []rune, pos int) []rune {
 	if len(line) == 0 {
 		return line
 	}
@@ -389,6 +462,8 @@ func (*shellPainter) Paint(line []rune, _ int) []rune {
 	if len(tokens) == 0 {
 		return line
 	}
+	errSpan, hasErr := p.liveErrorSpan(input, pos)
+	commands := p.defs.CommandNames()
 
 	var b strings.Builder
 	cursor := 0
@@ -404,6 +479,12 @@ func (*shellPainter) Paint(line []rune, _ int) []rune {
 		}
 
 		style, ok := styleForToken(tok)
+		if tok.Name == identTokenName && slices.Contains(commands, strings.ToLower(tok.Value)) {
+			style, ok = pterm.ThemeDefault.HighlightStyle, true
+		}
+		if hasErr && start < errSpan.End && end > errSpan.Start {
+			style, ok = errorTokenStyle(), true
+		}
 		if !ok {
 			b.WriteString(input[start:end])
 		} else {
@@ -419,6 +500,35 @@ func (*shellPainter) Paint(line []rune, _ int) []rune {
 	return []rune(b.String())
 }
 
+// liveErrorSpan returns the span of the error in the line being typed. Errors
+// in the word under the cursor are left alone until the cursor moves past it.
+// Lines that use definitions are not marked, as spans in the expanded line do
+// not match what was typed.
+func (p *shellPainter) liveErrorSpan(input string, pos int) (nlp.Span, bool) {
+	if isBuiltinCommand(strings.ToLower(strings.TrimSpace(input))) || isShellCommand(input) {
+		return nlp.Span{}, false
+	}
+	if expanded, err := p.defs.Expand(input); err != nil || expanded != input {
+		return nlp.Span{}, false
+	}
+	_, err := nlp.ParseProgram(input, nlp.ParseOptions{Mode: nlp.ModeAuto})
+	var diagnosticErr nlp.DiagnosticError
+	if !errors.As(err, &diagnosticErr) {
+		return nlp.Span{}, false
+	}
+	for _, diag := range diagnosticErr.Diagnostics() {
+		if diag.Span == nil || diag.Span.Start == diag.Span.End || diag.Span.RuneEnd >= pos {
+			continue
+		}
+		return *diag.Span, true
+	}
+	return nlp.Span{}, false
+}
+
+func errorTokenStyle() pterm.Style {
+	return pterm.Style{pterm.FgRed, pterm.Underscore}
+}
+
 func styleForToken(tok nlp.LexToken) (pterm.Style, bool) {
 	switch tok.Name {
 	case "Quoted", "QuoteStart", "QuoteEnd", "StringText", "StringEscape", "StringBackslash":
@@ -429,9 +539,9 @@ func styleForToken(tok nlp.LexToken) (pterm.Style, bool) {
 		return pterm.ThemeDefault.SuccessMessageStyle, true
 	case "SetField", "AddField", "RemoveField", "ClearField", "ClearOp", "AddOp", "RemoveOp":
 		return pterm.ThemeDefault.SecondaryStyle, true
-	case "AndOp", "OrOp":
+	case "AndOp", "OrOp", "Semicolon", "Pipe":
 		return pterm.ThemeDefault.InfoMessageStyle, true
-	case "HashNumber":
+	case "HashNumber", "ResultRef", "RowRef", "Variable", "VariablePrefix":
 		return pterm.ThemeDefault.InfoMessageStyle, true
 	case "Ident":
 		lower := strings.ToLower(tok.Value)
@@ -441,8 +551,13 @@ func styleForToken(tok nlp.LexToken) (pterm.Style, bool) {
 		if lower == "add" || lower == "create" || lower == "new" ||
 			lower == "set" || lower == "edit" || lower == "update" ||
 			lower == "find" || lower == "show" || lower == "list" || lower == "filter" ||
+			lower == "done" || lower == "complete" || lower == "finish" ||
+			lower == "undo" || lower == "reopen" || lower == "rm" || lower == "delete" || lower == "snooze" ||
+			lower == "note" || lower == "annotate" ||
 			lower == "view" || lower == "context" ||
-			lower == "log" || lower == "activity" {
+			lower == "log" || lower == "activity" ||
+			lower == "let" || lower == "alias" || lower == "macro" || lower == "unset" || lower == "unalias" ||
+			lower == sessionKeyword || lower == selectKeyword || lower == processKeyword {
 			return pterm.ThemeDefault.HighlightStyle, true
 		}
 	}
@@ -50,6 +50,29 @@ func TestShellCompleterStateValue(t *testing.T) {
 	assert.Contains(t, completionStrings(suffixes), "ow", "state:n should complete to state:now")
 }
 
+func TestShellCompleterWaitingPeople(t *testing.T) {
+	t.Parallel()
+
+	completer := &shellCompleter{people: []string{"Alice", "Bob"}}
+	suffixes, offset := completer.Do([]rune("set 3 waiting:al"), len([]rune("set 3 waiting:al")))
+
+	require.Equal(t, len([]rune("waiting:al")), offset, "offset should match field fragment")
+	assert.Equal(t, []string{"ice"}, completionStrings(suffixes), "waiting:al should complete to waiting:Alice")
+}
+
+func TestShellCompleterTaskActionVerbs(t *testing.T) {
+	t.Parallel()
+
+	completer := &shellCompleter{}
+	suffixes, offset := completer.Do([]rune("reo"), len([]rune("reo")))
+
+	require.Equal(t, len([]rune("reo")), offset, "offset should match verb fragment")
+	assert.Contains(t, completionStrings(suffixes), "pen", "reo should complete to reopen")
+
+	suffixes, _ = completer.Do([]rune("del"), len([]rune("del")))
+	assert.Contains(t, completionStrings(suffixes), "ete", "del should complete to delete")
+}
+
 func TestShellCompleterContextCommandProject(t *testing.T) {
 	t.Parallel()
 
@@ -119,10 +142,20 @@ func TestShellCompleterViewCommandAlias(t *testing.T) {
 	assert.Contains(t, completionStrings(suffixes), "ow", "view n should complete to now")
 }
 
+func TestShellCompleterViewCommandIncludesUserViews(t *testing.T) {
+	t.Parallel()
+
+	completer := &shellCompleter{viewNames: []string{"focus"}}
+
+	suffixes, offset := completer.Do([]rune("view fo"), len([]rune("view fo")))
+	require.Equal(t, 2, offset, "offset should match typed fragment")
+	assert.Contains(t, completionStrings(suffixes), "cus", "view fo should complete to focus")
+}
+
 func TestShellPainterHighlightsTokens(t *testing.T) {
 	t.Parallel()
 
-	painter := newShellPainter()
+	painter := newShellPainter(nil)
 	line := `add "milk" #work @phone`
 	painted := string(painter.Paint([]rune(line), len([]rune(line))))
 
@@ -144,7 +177,7 @@ func TestShellPainterHighlightsTokens(t *testing.T) {
 func TestShellPainterHighlightsViewAndContextCommands(t *testing.T) {
 	t.Parallel()
 
-	painter := newShellPainter()
+	painter := newShellPainter(nil)
 	paintedView := string(painter.Paint([]rune("view inbox"), len([]rune("view inbox"))))
 	assert.Contains(
 		t,
@@ -162,6 +195,56 @@ func TestShellPainterHighlightsViewAndContextCommands(t *testing.T) {
 	)
 }
 
+func TestShellPainterHighlightsTaskActionVerbs(t *testing.T) {
+	t.Parallel()
+
+	painter := newShellPainter(nil)
+	for _, verb := range []string{"done", "complete", "finish", "undo", "reopen", "rm", "delete", "snooze"} {
+		line := verb + " 3"
+		painted := string(painter.Paint([]rune(line), len([]rune(line))))
+		assert.Contains(
+			t,
+			painted,
+			pterm.ThemeDefault.HighlightStyle.Sprint(verb),
+			"%s command should be highlighted",
+			verb,
+		)
+	}
+}
+
+func TestShellCompleterSuggestsCommandsAfterPipe(t *testing.T) {
+	t.Parallel()
+
+	completer := &shellCompleter{}
+	line := "find #work | "
+	suffixes, _ := completer.Do([]rune(line), len([]rune(line)))
+	assert.Contains(t, completionStrings(suffixes), "done", "commands should be suggested after a pipe")
+	assert.NotContains(t, completionStrings(suffixes), "!due", "field ops should not start a command")
+}
+
+func TestShellPainterUnderlinesErrors(t *testing.T) {
+	t.Parallel()
+
+	painter := newShellPainter(nil)
+	errorStyle := errorTokenStyle()
+
+	line := "ad buy milk"
+	painted := string(painter.Paint([]rune(line), len([]rune(line))))
+	assert.Contains(t, painted, errorStyle.Sprint("ad"), "unknown verb should be underlined")
+
+	line = "add milk stat:now"
+	painted = string(painter.Paint([]rune(line), len([]rune(line))))
+	assert.Contains(t, painted, errorStyle.Sprint("stat"), "unknown field should be underlined")
+
+	line = "ad"
+	painted = string(painter.Paint([]rune(line), len([]rune(line))))
+	assert.NotContains(t, painted, errorStyle.Sprint("ad"), "word under the cursor should not be underlined")
+
+	line = "add buy milk"
+	painted = string(painter.Paint([]rune(line), len([]rune(line))))
+	assert.NotContains(t, painted, errorStyle.Sprint("buy"), "valid input should not be underlined")
+}
+
 func completionStrings(suffixes [][]rune) []string {
 	out := make([]string, 0, len(suffixes))
 	for _, suffix := range suffixes {
@@ -169,3 +252,24 @@ func completionStrings(suffixes [][]rune) []string {
 	}
 	return out
 }
+
+func TestShellCompleterSuggestsDefinitions(t *testing.T) {
+	t.Parallel()
+
+	defs := NewDefinitions()
+	for _, line := range []string{"let work = #work", "alias today = find due:today", "macro triage $id = set $id"} {
+		_, err := defs.Define(line)
+		require.NoError(t, err, "define %q", line)
+	}
+	completer := &shellCompleter{defs: defs}
+
+	suffixes, _ := completer.Do([]rune("tri"), len("tri"))
+	assert.Contains(t, completionStrings(suffixes), "age", "macro names should complete as commands")
+
+	suffixes, _ = completer.Do([]rune("find | to"), len("find | to"))
+	assert.Contains(t, completionStrings(suffixes), "day", "aliases should complete after a pipe")
+
+	suffixes, offset := completer.Do([]rune("add call $"), len("add call $"))
+	require.Equal(t, 1, offset, "offset should cover the $ fragment")
+	assert.Contains(t, completionStrings(suffixes), "work", "variables should complete after $")
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,8 +15,13 @@ import (
 	"github.com/pterm/pterm"
 	"github.com/pterm/pterm/putils"
 
+	"github.com/mholtzscher/ugh/internal/clarify"
+	"github.com/mholtzscher/ugh/internal/config"
 	"github.com/mholtzscher/ugh/internal/output"
+	"github.com/mholtzscher/ugh/internal/picker"
 	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/templates"
 	"github.com/mholtzscher/ugh/internal/termutil"
 )
 
@@ -32,16 +39,36 @@ type Options struct {
 	Mode      Mode
 	InputFile string
 	Writer    output.Writer
+	Views     map[string]config.View
+	Templates templates.Set
+	// TitleDates moves date phrases in add titles into the due date.
+	TitleDates bool
+	// People are the known names offered when completing waiting:.
+	People []string
+	// Shellrc holds let, alias and macro definitions loaded at startup. The
+	// interactive shell appends new definitions to it.
+	Shellrc string
+	// Session names the saved session to resume. The session is saved after
+	// every command.
+	Session string
 }
 
 // SessionState tracks the current shell session context.
 type SessionState struct {
+	// Name is the saved session the state belongs to. Unnamed sessions are
+	// not saved.
+	Name           string
 	SelectedTaskID *int64
 	LastTaskIDs    []int64
-	ContextProject string
-	ContextContext string
-	StartTime      time.Time
-	CommandCount   int
+	// PreviousResults holds the task IDs of the commands before the last one,
+	// most recent first.
+	PreviousResults [][]int64
+	ContextProject  string
+	ContextContext  string
+	// ContextFilter is a filter expression ANDed into every find and view.
+	ContextFilter string
+	StartTime     time.Time
+	CommandCount  int
 }
 
 // REPL manages the interactive shell session.
@@ -51,6 +78,7 @@ type REPL struct {
 	state    *SessionState
 	prompt   *Prompt
 	executor *Executor
+	defs     *Definitions
 	display  *Display
 	history  *History
 }
@@ -71,7 +99,42 @@ func NewREPL(svc service.Service, opts Options) *REPL {
 
 // Run starts the REPL loop.
 func (r *REPL) Run(ctx context.Context) error {
-	r.executor = NewExecutor(r.service, r.state)
+	defs := NewDefinitions()
+	if r.options.Shellrc != "" {
+		loaded, err := LoadDefinitions(r.options.Shellrc)
+		if err != nil {
+			return fmt.Errorf("load shellrc: %w", err)
+		}
+		defs = loaded
+	}
+	r.defs = defs
+
+	if r.options.Session != "" {
+		if _, err := RestoreSession(ctx, r.service, r.options.Session, r.state); err != nil {
+			return fmt.Errorf("restore session: %w", err)
+		}
+	}
+
+	opts := []ExecutorOption{
+		WithViews(r.options.Views),
+		WithTemplates(r.options.Templates),
+		WithTitleDates(r.options.TitleDates),
+		WithDefinitions(defs),
+	}
+	if r.options.Mode == ModeInteractive {
+		defs.PersistTo(r.options.Shellrc)
+		processor := clarify.New(r.service, clarify.Options{
+			In:  os.Stdin,
+			Out: os.Stdout,
+			Raw: clarify.TerminalRaw(os.Stdin),
+		})
+		opts = append(opts,
+			WithConfirm(r.confirmBulk),
+			WithPicker(picker.New(os.Stdin, os.Stdout).Pick),
+			WithProcess(processor.Run),
+		)
+	}
+	r.executor = NewExecutor(r.service, r.state, opts...)
 
 	switch r.options.Mode {
 	case ModeInteractive:
@@ -86,7 +149,9 @@ func (r *REPL) Run(ctx context.Context) error {
 }
 
 func (r *REPL) runInteractive(ctx context.Context) error {
-	prompt, err := NewPrompt(r.service)
+	prompt, err := NewPrompt(
+		r.service, slices.Sorted(maps.Keys(r.options.Views)), r.options.People, r.defs, r.state.Name,
+	)
 	if err != nil {
 		return fmt.Errorf("initialize prompt: %w", err)
 	}
@@ -128,6 +193,18 @@ func (r *REPL) runInteractive(ctx context.Context) error {
 	}
 }
 
+// confirmBulk previews the tasks action applies to and asks before applying
+// it.
+func (r *REPL) confirmBulk(action string, tasks []*store.Task) (bool, error) {
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "%s will apply to %d task(s):\n", action, len(tasks))
+	for _, task := range tasks {
+		_, _ = fmt.Fprintf(&builder, "  #%d %s\n", task.ID, task.Title)
+	}
+	_, _ = fmt.Fprint(os.Stdout, builder.String())
+	return r.prompt.Confirm("Apply?")
+}
+
 func (r *REPL) showIntro() {
 	bigText, _ := pterm.DefaultBigText.WithLetters(putils.LettersFromString("ugh")).Srender()
 	pterm.Println(bigText)
@@ -172,6 +249,17 @@ func (r *REPL) runScript(ctx context.Context, rdr io.Reader) error {
 
 var errQuit = errors.New("quit requested")
 
+// isBuiltinCommand reports whether cmd, lowercased and trimmed, is handled by
+// the REPL itself rather than the DSL.
+func isBuiltinCommand(cmd string) bool {
+	switch cmd {
+	case "quit", "exit", "q", "help", "?", "clear":
+		return true
+	default:
+		return false
+	}
+}
+
 func (r *REPL) processCommand(ctx context.Context, input string) error {
 	cmd := strings.ToLower(strings.TrimSpace(input))
 
@@ -188,17 +276,25 @@ func (r *REPL) processCommand(ctx context.Context, input string) error {
 
 	r.state.CommandCount++
 
+	// A line of several commands that fails part way returns the results of
+	// the commands that ran along with the error.
 	result, err := r.executor.Execute(ctx, input)
-	if err != nil {
+	if result == nil {
 		return err
 	}
+	if saveErr := SaveSession(ctx, r.service, r.state); saveErr != nil {
+		return fmt.Errorf("save session: %w", saveErr)
+	}
+	if r.prompt != nil {
+		r.prompt.SetSession(r.state.Name)
+	}
 
-	if histErr := r.history.Record(ctx, input, true, result.Summary, result.Intent); histErr != nil {
+	if histErr := r.history.Record(ctx, input, err == nil, result.Summary, result.Intent); histErr != nil {
 		_ = histErr
 	}
 
 	r.display.ShowResult(result)
-	return nil
+	return err
 }
 
 func (r *REPL) showHelp() {
@@ -224,32 +320,56 @@ func (r *REPL) showHelp() {
 			success("view n/now") + "       Now tasks\n" +
 			success("view w/waiting") + "   Waiting tasks\n" +
 			success("view l/later") + "     Later tasks\n" +
-			success("view c/calendar") + "  Tasks with due dates")
+			success("view c/calendar") + "  Tasks with due dates\n" +
+			success("view f/follow-up") + " Waiting tasks due for follow-up\n" +
+			success("view a/available") + " Now tasks whose contexts are available\n" +
+			success("view stalled") + "     Projects with no now task")
 
 	// Examples panel
 	pterm.DefaultBox.WithTitle(success("Examples")).WithRightPadding(1).WithLeftPadding(1).Println(
 		success("add buy milk tomorrow #groceries @store") + "\n" +
 			success("add task due:tomorrow state:inbox") + "\n" +
+			success("add from onboarding name:Sam") + "\n" +
 			success("set selected state:done") + "\n" +
 			success("set 123 title:new title +project:work") + "\n" +
+			success("set where (#old and state:later) state:now") + "\n" +
+			success("done 3,5") + "\n" +
+			success("reopen those") + "\n" +
+			success("snooze it until friday") + "\n" +
+			success("note it called vendor, waiting on quote") + "\n" +
+			success("rm selected") + "\n" +
 			success("find state:now") + "\n" +
 			success("find state:now and project:work") + "\n" +
 			success("find state:now or not state:done") + "\n" +
 			success("find (state:now or state:waiting) and project:work") + "\n" +
 			success("show 3") + "\n" +
 			success("show #work") + "\n" +
-			success("filter context:urgent"))
+			success("filter context:urgent") + "\n" +
+			success("find #errands && state:inbox | set state:now @out") + "\n" +
+			success("add call mom; done those") + "\n" +
+			success("let w = #work @office") + "\n" +
+			success("alias today = find due:today || state:now") + "\n" +
+			success("macro triage $id = set $id state:now $w"))
 
 	// Syntax panel - colors match the explanatory boxes
 	syntaxContent := warning("add/create/new") + " " +
 		text("<title>") + " " +
 		secondary("[operations...]") + "\n" +
+		warning("add from") + " " + text("<template>") + " " + secondary("[name:value...]") + "\n" +
 		warning("set/edit/update") + " " +
-		text("<target>") + " " +
+		text("<target> | where <filter>") + " " +
 		secondary("[operations...]") + "\n" +
+		warning("done/complete/finish, undo/reopen, rm/delete") + " " +
+		text("[target]") + "\n" +
+		warning("snooze") + " " + text("[target] until <date>") + "\n" +
+		warning("note/annotate") + " " + text("<target> <text>") + "\n" +
 		warning("find/show/list/filter") + " " +
 		info("<expr>") + " " +
-		warning("(and/or/not, parentheses)")
+		warning("(and/or/not, parentheses)") + "\n" +
+		text("<command>") + " " + warning(";") + " " + text("<command>") + "    run in order\n" +
+		text("<command>") + " " + warning("|") + " " + text("<command>") + "    act on the tasks found\n" +
+		warning("let/alias/macro") + " " + text("<name> [$params] = <text>") + "    saved to shellrc\n" +
+		warning("unset/unalias") + " " + text("<name>") + "    remove a definition"
 	pterm.DefaultBox.WithTitle(warning("Syntax")).
 		WithRightPadding(1).
 		WithLeftPadding(1).
@@ -275,12 +395,38 @@ func (r *REPL) showHelp() {
 	// Targets panel
 	pterm.DefaultBox.WithTitle(text("Targets")).WithRightPadding(1).WithLeftPadding(1).Println(
 		text("selected") + "          Currently selected task\n" +
-			text("#123") + "              Task ID")
+			text("#123") + "              Task ID\n" +
+			text("3,5,9 or 10-14") + "    Several task IDs\n" +
+			text("it, $last / that") + "  Last / second-to-last task\n" +
+			text("those or ^1") + "       Tasks from the last command\n" +
+			text("^2, ^3") + "            Tasks from earlier commands\n" +
+			text("%3") + "                Third row of the last result")
 
 	// Context panel
 	pterm.DefaultBox.WithTitle(primary("Context (sticky filters)")).WithRightPadding(1).WithLeftPadding(1).Println(
 		primary("context") + "            Show current context state\n" +
 			primary("context #project") + "   Set default project context\n" +
 			primary("context @context") + "   Set default context filter\n" +
+			primary("context state:now") + "  AND a filter into finds and views\n" +
+			primary("context available") + "  Follow the [contexts] schedules\n" +
 			primary("context clear") + "      Clear all context filters")
+
+	// Selection panel
+	pterm.DefaultBox.WithTitle(primary("Selection")).WithRightPadding(1).WithLeftPadding(1).Println(
+		primary("select") + "             Pick a task by fuzzy search and select it\n" +
+			primary("select done") + "        Pick a task and run a command on it\n" +
+			secondary("done, set, show") + "    Open the picker when nothing is selected")
+
+	// Inbox panel
+	pterm.DefaultBox.WithTitle(primary("Inbox")).WithRightPadding(1).WithLeftPadding(1).Println(
+		primary("process") + "            Decide on each inbox task with single keys\n" +
+			secondary("n l w p c d x P s q") + " now, later, waiting, project, context, due,\n" +
+			"                   delete, make project, skip, quit")
+
+	// Sessions panel
+	pterm.DefaultBox.WithTitle(primary("Sessions")).WithRightPadding(1).WithLeftPadding(1).Println(
+		primary("session") + "            List saved sessions\n" +
+			primary("session save NAME") + "  Save and switch to a named session\n" +
+			primary("session drop NAME") + "  Delete a saved session\n" +
+			secondary("ugh shell --session NAME") + " resumes a session")
 }
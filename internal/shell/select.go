@@ -0,0 +1,127 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+const selectKeyword = "select"
+
+// IsSelectCommand reports whether line picks a task, optionally followed by
+// a command to run on it.
+func IsSelectCommand(line string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+	return strings.EqualFold(keyword, selectKeyword)
+}
+
+// executeSelect picks a task and selects it. "select done" or
+// "select edit state:now" then runs the command on the picked task.
+func (e *Executor) executeSelect(ctx context.Context, input string) (*ExecuteResult, error) {
+	_, action, _ := strings.Cut(strings.TrimSpace(input), " ")
+	action = strings.TrimSpace(action)
+	var actionCmd nlp.Command
+	if action != "" {
+		// Only the verb matters here; the command is parsed again once the
+		// picked ID is in place.
+		if parsed, parseErr := e.parser.Parse(ctx, action, nlp.ParseOptions{Now: time.Now()}); parseErr == nil {
+			actionCmd = parsed.Command
+		}
+	}
+	task, err := e.pickTask(ctx, actionCmd)
+	if err != nil {
+		return nil, err
+	}
+	e.state.SelectedTaskID = &task.ID
+
+	if action != "" {
+		verb, rest, _ := strings.Cut(action, " ")
+		return e.Execute(ctx, strings.TrimSpace(verb+" "+strconv.FormatInt(task.ID, 10)+" "+rest))
+	}
+
+	e.rememberResult([]int64{task.ID})
+	return &ExecuteResult{
+		Intent:    selectKeyword,
+		Message:   fmt.Sprintf("Selected task #%d: %s", task.ID, task.Title),
+		Level:     ResultLevelInfo,
+		TaskIDs:   []int64{task.ID},
+		Summary:   fmt.Sprintf("selected #%d", task.ID),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// pickSelection lets the user pick the task for a command that acts on the
+// selected task when nothing is selected yet.
+func (e *Executor) pickSelection(ctx context.Context, cmd nlp.Command) error {
+	if e.pick == nil || e.state.SelectedTaskID != nil || !targetsSelection(cmd) {
+		return nil
+	}
+	task, err := e.pickTask(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	e.state.SelectedTaskID = &task.ID
+	return nil
+}
+
+// pickTask lets the user pick a task for cmd, which may be nil.
+func (e *Executor) pickTask(ctx context.Context, cmd nlp.Command) (*store.Task, error) {
+	if e.pick == nil {
+		return nil, errors.New("select needs an interactive shell")
+	}
+	tasks, err := e.svc.ListTasks(ctx, pickRequest(cmd))
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	return e.pick(tasks)
+}
+
+// pickRequest lists the tasks cmd can act on, as the CLI's picker does: done
+// tasks for undo, every task for show, rm and log, and open tasks otherwise.
+func pickRequest(cmd nlp.Command) service.ListTasksRequest {
+	switch cmd.(type) {
+	case *nlp.UndoCommand:
+		return service.ListTasksRequest{DoneOnly: true}
+	case *nlp.ShowCommand, *nlp.DeleteCommand, *nlp.LogCommand:
+		return service.ListTasksRequest{All: true}
+	default:
+		return service.ListTasksRequest{TodoOnly: true}
+	}
+}
+
+// targetsSelection reports whether cmd acts on the selected task, either
+// explicitly or because it names no target.
+func targetsSelection(cmd nlp.Command) bool {
+	var target *nlp.TargetRef
+	switch c := cmd.(type) {
+	case *nlp.UpdateCommand:
+		if c.WhereExpr != nil {
+			return false
+		}
+		target = c.Target
+	case *nlp.DoneCommand:
+		target = c.Target
+	case *nlp.UndoCommand:
+		target = c.Target
+	case *nlp.DeleteCommand:
+		target = c.Target
+	case *nlp.ShowCommand:
+		target = c.Target
+	case *nlp.SnoozeCommand:
+		target = c.Target
+	case *nlp.NoteCommand:
+		target = c.Target
+	case *nlp.LogCommand:
+		target = c.Target
+	default:
+		return false
+	}
+	return target == nil || target.Kind == nlp.TargetSelected
+}
@@ -0,0 +1,173 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+const sessionKeyword = "session"
+
+//nolint:gochecknoglobals // compiled once, used for every session name
+var sessionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// IsSessionCommand reports whether line is a session list, save or drop
+// command.
+func IsSessionCommand(line string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+	return strings.EqualFold(keyword, sessionKeyword)
+}
+
+// ValidateSessionName reports whether name can name a saved session.
+func ValidateSessionName(name string) error {
+	if !sessionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid session name %q; use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// RestoreSession loads the saved session name into state. A session that has
+// not been saved yet starts empty and is created when first saved. It
+// reports whether a saved session was found.
+func RestoreSession(ctx context.Context, svc service.Service, name string, state *SessionState) (bool, error) {
+	if err := ValidateSessionName(name); err != nil {
+		return false, err
+	}
+	state.Name = name
+	saved, err := svc.GetShellSession(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if saved == nil {
+		return false, nil
+	}
+	state.SelectedTaskID = saved.SelectedTaskID
+	state.LastTaskIDs = saved.LastTaskIDs
+	state.PreviousResults = saved.PreviousResults
+	state.ContextProject = saved.ContextProject
+	state.ContextContext = saved.ContextContext
+	state.ContextFilter = saved.ContextFilter
+	return true, nil
+}
+
+// SaveSession stores state under its session name. States without a name
+// are not saved.
+func SaveSession(ctx context.Context, svc service.Service, state *SessionState) error {
+	if state.Name == "" {
+		return nil
+	}
+	return svc.SaveShellSession(ctx, store.ShellSession{
+		Name:            state.Name,
+		SelectedTaskID:  state.SelectedTaskID,
+		LastTaskIDs:     state.LastTaskIDs,
+		PreviousResults: state.PreviousResults,
+		ContextProject:  state.ContextProject,
+		ContextContext:  state.ContextContext,
+		ContextFilter:   state.ContextFilter,
+	})
+}
+
+func (e *Executor) executeSession(ctx context.Context, input string) (*ExecuteResult, error) {
+	fields := strings.Fields(input)
+	action := "list"
+	if len(fields) > 1 {
+		action = strings.ToLower(fields[1])
+	}
+	args := fields[min(len(fields), 2):]
+
+	switch {
+	case action == "list" && len(args) == 0:
+		return e.listSessions(ctx)
+	case action == "save" && len(args) <= 1:
+		name := e.state.Name
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if name == "" {
+			return nil, errors.New("usage: session save NAME")
+		}
+		return e.saveSession(ctx, name)
+	case action == "drop" && len(args) == 1:
+		return e.dropSession(ctx, args[0])
+	default:
+		return nil, errors.New("usage: session [list | save [NAME] | drop NAME]")
+	}
+}
+
+func (e *Executor) listSessions(ctx context.Context) (*ExecuteResult, error) {
+	sessions, err := e.svc.ListShellSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return &ExecuteResult{
+			Intent:    sessionKeyword,
+			Message:   "No saved sessions",
+			Level:     ResultLevelInfo,
+			Summary:   "listed 0 sessions",
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	lines := make([]string, 0, len(sessions)+1)
+	lines = append(lines, "Sessions:")
+	for _, session := range sessions {
+		marker := " "
+		if session.Name == e.state.Name {
+			marker = "*"
+		}
+		updated := time.Unix(session.UpdatedAt, 0).Local().Format("2006-01-02 15:04")
+		lines = append(lines, fmt.Sprintf("%s %s  updated %s", marker, session.Name, updated))
+	}
+	return &ExecuteResult{
+		Intent:    sessionKeyword,
+		Message:   strings.Join(lines, "\n"),
+		Level:     ResultLevelInfo,
+		Summary:   fmt.Sprintf("listed %d sessions", len(sessions)),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (e *Executor) saveSession(ctx context.Context, name string) (*ExecuteResult, error) {
+	if err := ValidateSessionName(name); err != nil {
+		return nil, err
+	}
+	e.state.Name = name
+	if err := SaveSession(ctx, e.svc, e.state); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	return &ExecuteResult{
+		Intent:    sessionKeyword,
+		Message:   fmt.Sprintf("Saved session %s", name),
+		Level:     ResultLevelSuccess,
+		Summary:   "saved session " + name,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (e *Executor) dropSession(ctx context.Context, name string) (*ExecuteResult, error) {
+	found, err := e.svc.DeleteShellSession(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("drop session: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("no session named %q", name)
+	}
+	// The shell keeps its state but stops saving it under the dropped name.
+	if e.state.Name == name {
+		e.state.Name = ""
+	}
+	return &ExecuteResult{
+		Intent:    sessionKeyword,
+		Message:   fmt.Sprintf("Dropped session %s", name),
+		Level:     ResultLevelSuccess,
+		Summary:   "dropped session " + name,
+		Timestamp: time.Now(),
+	}, nil
+}
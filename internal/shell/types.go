@@ -18,17 +18,24 @@ const (
 
 // ExecuteResult contains the result of command execution.
 type ExecuteResult struct {
-	Intent    string
-	Message   string
-	TaskIDs   []int64
-	Task      *store.Task
-	Tasks     []*store.Task
-	Versions  []*store.TaskVersion
-	Context   *output.ContextStatus
-	ViewHelp  *output.ViewHelp
-	Level     ResultLevel
-	Summary   string
-	Timestamp time.Time
+	Intent   string
+	Message  string
+	TaskIDs  []int64
+	Task     *store.Task
+	Tasks    []*store.Task
+	Versions []*store.TaskVersion
+	// StalledProjects is the payload of the stalled view.
+	StalledProjects []store.StalledProject
+	Context         *output.ContextStatus
+	ViewHelp        *output.ViewHelp
+	Level           ResultLevel
+	Summary         string
+	Timestamp       time.Time
+	// Stages holds the per-command results of a line with ";" or "|".
+	Stages []*ExecuteResult
+	// Piped marks a stage whose tasks were passed on with "|". Only the last
+	// stage of a pipeline is displayed.
+	Piped bool
 }
 
 // ExecuteOptions provides options for command execution.
@@ -3,15 +3,28 @@ package store
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/nlp"
 )
 
-type filterSQLBuilder struct{}
+type filterSQLBuilder struct {
+	metaSchema     domain.MetaSchema
+	closedContexts []string
+	// regexMatches holds task IDs matching each regex predicate. SQLite has
+	// no portable REGEXP function, so regexes are resolved in Go beforehand.
+	regexMatches map[regexKey][]int64
+}
+
+type regexKey struct {
+	kind    nlp.PredicateKind
+	pattern string
+}
 
 func (b *filterSQLBuilder) Build(expr nlp.FilterExpr) (string, []any, error) {
 	sqlizer, err := b.buildExpr(expr)
@@ -65,6 +78,8 @@ func (b *filterSQLBuilder) buildPredicate(pred nlp.Predicate) (sq.Sqlizer, error
 			return sq.Expr("(t.due_on IS NOT NULL AND t.due_on != '')"), nil
 		}
 		return sq.Eq{"t.due_on": value}, nil
+	case nlp.PredFollowUp:
+		return buildFollowUpPredicate(pred.Op, value)
 	case nlp.PredProject:
 		if value != nlp.FilterWildcard {
 			return sq.Expr(
@@ -85,16 +100,26 @@ func (b *filterSQLBuilder) buildPredicate(pred nlp.Predicate) (sq.Sqlizer, error
 		if value == "" {
 			return sq.Expr("1=1"), nil
 		}
-		like := "%" + value + "%"
+		like := containsPattern(value)
 
 		return sq.Or{
-			sq.Like{"t.title": like},
-			sq.Like{"t.notes": like},
-			sq.Expr("EXISTS (SELECT 1 FROM json_each(t.projects_json) WHERE value LIKE ?)", like),
-			sq.Expr("EXISTS (SELECT 1 FROM json_each(t.contexts_json) WHERE value LIKE ?)", like),
-			sq.Expr("EXISTS (SELECT 1 FROM json_each(t.meta_json) WHERE key LIKE ? OR value LIKE ?)", like, like),
+			sq.Expr("t.title "+likeEscaped, like),
+			sq.Expr("t.notes "+likeEscaped, like),
+			sq.Expr("EXISTS (SELECT 1 FROM json_each(t.projects_json) WHERE value "+likeEscaped+")", like),
+			sq.Expr("EXISTS (SELECT 1 FROM json_each(t.contexts_json) WHERE value "+likeEscaped+")", like),
+			sq.Expr(
+				"EXISTS (SELECT 1 FROM json_each(t.meta_json) WHERE key "+likeEscaped+" OR value "+likeEscaped+")",
+				like, like,
+			),
+			sq.Expr(
+				"EXISTS (SELECT 1 FROM task_annotations a WHERE a.task_id = t.id AND a.text "+likeEscaped+")",
+				like,
+			),
 		}, nil
 	case nlp.PredID:
+		if len(pred.IDs) > 0 {
+			return sq.Eq{"t.id": pred.IDs}, nil
+		}
 		id, err := strconv.ParseInt(value, 10, 64)
 		if err != nil || id <= 0 {
 			return nil, fmt.Errorf("invalid id predicate %q", pred.Text)
@@ -102,7 +127,303 @@ func (b *filterSQLBuilder) buildPredicate(pred nlp.Predicate) (sq.Sqlizer, error
 		return sq.Eq{"t.id": id}, nil
 	case nlp.PredRecent:
 		return nil, errors.New("recent modifier must be stripped before SQL build")
+	case nlp.PredAvailable:
+		return b.buildAvailablePredicate(), nil
+	case nlp.PredMeta:
+		return b.buildMetaPredicate(pred)
+	case nlp.PredTitle, nlp.PredNotes, nlp.PredWaiting:
+		return b.buildTextFieldPredicate(pred)
 	default:
 		return nil, fmt.Errorf("unsupported predicate kind %v", pred.Kind)
 	}
 }
+
+// buildAvailablePredicate matches tasks with no contexts or at least one
+// context that is not closed.
+func (b *filterSQLBuilder) buildAvailablePredicate() sq.Sqlizer {
+	if len(b.closedContexts) == 0 {
+		return sq.Expr("1=1")
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(b.closedContexts)), ",")
+	args := make([]any, 0, len(b.closedContexts))
+	for _, name := range b.closedContexts {
+		args = append(args, name)
+	}
+	return sq.Or{
+		sq.Expr("json_array_length(t.contexts_json) = 0"),
+		sq.Expr("EXISTS (SELECT 1 FROM json_each(t.contexts_json) WHERE lower(value) NOT IN ("+placeholders+"))", args...),
+	}
+}
+
+func buildFollowUpPredicate(op nlp.CompareOp, value string) (sq.Sqlizer, error) {
+	if value == nlp.FilterWildcard {
+		return sq.Expr("(t.follow_up_on IS NOT NULL AND t.follow_up_on != '')"), nil
+	}
+	switch op {
+	case nlp.CompareEq:
+		return sq.Eq{"t.follow_up_on": value}, nil
+	case nlp.CompareLt:
+		return sq.Lt{"t.follow_up_on": value}, nil
+	case nlp.CompareLte:
+		return sq.LtOrEq{"t.follow_up_on": value}, nil
+	case nlp.CompareGt:
+		return sq.Gt{"t.follow_up_on": value}, nil
+	case nlp.CompareGte:
+		return sq.GtOrEq{"t.follow_up_on": value}, nil
+	case nlp.CompareRange, nlp.CompareContains, nlp.CompareRegex:
+		return nil, fmt.Errorf("comparison %v is not supported for %v", op, nlp.PredFollowUp)
+	default:
+		return nil, fmt.Errorf("unsupported comparison %v", op)
+	}
+}
+
+// likeEscaped is a LIKE comparison whose pattern escapes wildcards with a
+// backslash; pair it with containsPattern.
+const likeEscaped = `LIKE ? ESCAPE '\'`
+
+//nolint:gochecknoglobals // Replacer is immutable and shared.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns a LIKE pattern matching value as a literal substring.
+func containsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
+
+func textFieldColumn(kind nlp.PredicateKind) string {
+	switch kind {
+	case nlp.PredTitle:
+		return "t.title"
+	case nlp.PredNotes:
+		return "t.notes"
+	default:
+		return "COALESCE(t.waiting_for, '')"
+	}
+}
+
+func (b *filterSQLBuilder) buildTextFieldPredicate(pred nlp.Predicate) (sq.Sqlizer, error) {
+	column := textFieldColumn(pred.Kind)
+	value := strings.TrimSpace(pred.Text)
+	if value == nlp.FilterWildcard {
+		return sq.Expr(column + " != ''"), nil
+	}
+
+	switch pred.Op {
+	case nlp.CompareContains:
+		return sq.Expr(column+" "+likeEscaped, containsPattern(value)), nil
+	case nlp.CompareEq:
+		return sq.Expr(column+" = ? COLLATE NOCASE", value), nil
+	case nlp.CompareRegex:
+		ids, ok := b.regexMatches[regexKey{kind: pred.Kind, pattern: value}]
+		if !ok {
+			return nil, fmt.Errorf("regex predicate %q was not resolved", value)
+		}
+		if len(ids) == 0 {
+			return sq.Expr("1=0"), nil
+		}
+		return sq.Eq{"t.id": ids}, nil
+	case nlp.CompareLt, nlp.CompareLte, nlp.CompareGt, nlp.CompareGte, nlp.CompareRange:
+		return nil, fmt.Errorf("comparison %v is not supported for %v", pred.Op, pred.Kind)
+	default:
+		return nil, fmt.Errorf("unsupported comparison %v", pred.Op)
+	}
+}
+
+func collectRegexPredicates(expr nlp.FilterExpr, out map[regexKey]*regexp.Regexp) error {
+	switch typed := expr.(type) {
+	case nlp.Predicate:
+		if typed.Op != nlp.CompareRegex {
+			return nil
+		}
+		key := regexKey{kind: typed.Kind, pattern: strings.TrimSpace(typed.Text)}
+		if _, ok := out[key]; ok {
+			return nil
+		}
+		re, err := regexp.Compile(key.pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", key.pattern, err)
+		}
+		out[key] = re
+		return nil
+	case nlp.FilterBinary:
+		if err := collectRegexPredicates(typed.Left, out); err != nil {
+			return err
+		}
+		return collectRegexPredicates(typed.Right, out)
+	case nlp.FilterNot:
+		return collectRegexPredicates(typed.Expr, out)
+	default:
+		return nil
+	}
+}
+
+// regexFreeConjuncts returns the top-level AND operands of expr that contain
+// no regex predicate. Every match of expr satisfies all of them, so they can
+// narrow the rows scanned when resolving regexes.
+func regexFreeConjuncts(expr nlp.FilterExpr) []nlp.FilterExpr {
+	if binary, ok := expr.(nlp.FilterBinary); ok && binary.Op == nlp.FilterAnd {
+		return append(regexFreeConjuncts(binary.Left), regexFreeConjuncts(binary.Right)...)
+	}
+	if hasRegexPredicate(expr) {
+		return nil
+	}
+	return []nlp.FilterExpr{expr}
+}
+
+func hasRegexPredicate(expr nlp.FilterExpr) bool {
+	switch typed := expr.(type) {
+	case nlp.Predicate:
+		return typed.Op == nlp.CompareRegex
+	case nlp.FilterBinary:
+		return hasRegexPredicate(typed.Left) || hasRegexPredicate(typed.Right)
+	case nlp.FilterNot:
+		return hasRegexPredicate(typed.Expr)
+	default:
+		return false
+	}
+}
+
+func (b *filterSQLBuilder) buildMetaPredicate(pred nlp.Predicate) (sq.Sqlizer, error) {
+	key := strings.TrimSpace(pred.Key)
+	value := strings.TrimSpace(pred.Text)
+	if value == nlp.FilterWildcard {
+		if key == "" {
+			return sq.Expr("EXISTS (SELECT 1 FROM json_each(t.meta_json))"), nil
+		}
+		return sq.Expr("json_extract(t.meta_json, ?) IS NOT NULL", metaJSONPath(key)), nil
+	}
+	if key == "" {
+		return nil, errors.New("meta predicate requires a key")
+	}
+
+	field, typed := b.metaSchema.Field(key)
+	if pred.Op != nlp.CompareEq && typed && !field.Ordered() {
+		return nil, fmt.Errorf("meta %q (%s) does not support ordered comparisons", key, field.Type)
+	}
+	column, args := metaValueSQL(key, field, typed)
+
+	low, err := metaOperand(field, typed, value)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, low)
+
+	switch pred.Op {
+	case nlp.CompareEq:
+		return sq.Expr(column+" = ?", args...), nil
+	case nlp.CompareLt:
+		return sq.Expr(column+" < ?", args...), nil
+	case nlp.CompareLte:
+		return sq.Expr(column+" <= ?", args...), nil
+	case nlp.CompareGt:
+		return sq.Expr(column+" > ?", args...), nil
+	case nlp.CompareGte:
+		return sq.Expr(column+" >= ?", args...), nil
+	case nlp.CompareRange:
+		high, highErr := metaOperand(field, typed, pred.Upper)
+		if highErr != nil {
+			return nil, highErr
+		}
+		return sq.Expr(column+" BETWEEN ? AND ?", append(args, high)...), nil
+	default:
+		return nil, fmt.Errorf("unsupported meta comparison %v", pred.Op)
+	}
+}
+
+// metaJSONPath quotes key so keys containing dots are addressed literally.
+func metaJSONPath(key string) string {
+	return `$."` + key + `"`
+}
+
+// metaValueSQL returns a SQL expression for the typed value of a meta key.
+// Numeric keys compare as REAL, enums by declared rank, everything else as text.
+func metaValueSQL(key string, field domain.MetaField, typed bool) (string, []any) {
+	path := metaJSONPath(key)
+	if !typed {
+		return "json_extract(t.meta_json, ?)", []any{path}
+	}
+	switch field.Type {
+	case domain.MetaTypeInt, domain.MetaTypeFloat:
+		return "CAST(json_extract(t.meta_json, ?) AS REAL)", []any{path}
+	case domain.MetaTypeEnum:
+		var b strings.Builder
+		args := []any{path}
+		b.WriteString("(CASE json_extract(t.meta_json, ?)")
+		for i, value := range field.Values {
+			b.WriteString(" WHEN ? THEN " + strconv.Itoa(i))
+			args = append(args, value)
+		}
+		b.WriteString(" END)")
+		return b.String(), args
+	case domain.MetaTypeDate, domain.MetaTypeBool:
+		return "json_extract(t.meta_json, ?)", []any{path}
+	default:
+		return "json_extract(t.meta_json, ?)", []any{path}
+	}
+}
+
+func metaOperand(field domain.MetaField, typed bool, value string) (any, error) {
+	if !typed {
+		return value, nil
+	}
+	normalized, err := field.Normalize(value)
+	if err != nil {
+		return nil, err
+	}
+	switch field.Type {
+	case domain.MetaTypeInt, domain.MetaTypeFloat:
+		return strconv.ParseFloat(normalized, 64)
+	case domain.MetaTypeEnum:
+		rank, _ := field.Rank(normalized)
+		return rank, nil
+	case domain.MetaTypeDate, domain.MetaTypeBool:
+		return normalized, nil
+	default:
+		return normalized, nil
+	}
+}
+
+// buildOrderBy returns ORDER BY clauses for explicit sort keys.
+func (b *filterSQLBuilder) buildOrderBy(keys []nlp.SortKey) ([]sq.Sqlizer, error) {
+	clauses := make([]sq.Sqlizer, 0, len(keys)*2)
+	for _, key := range keys {
+		direction := " ASC"
+		if key.Desc {
+			direction = " DESC"
+		}
+		switch key.Field {
+		case nlp.SortFieldID:
+			clauses = append(clauses, sq.Expr("t.id"+direction))
+		case nlp.SortFieldTitle:
+			clauses = append(clauses, sq.Expr("t.title COLLATE NOCASE"+direction))
+		case nlp.SortFieldState:
+			clauses = append(clauses, sq.Expr(
+				"CASE t.state WHEN 'inbox' THEN 0 WHEN 'now' THEN 1 WHEN 'waiting' THEN 2 "+
+					"WHEN 'later' THEN 3 ELSE 4 END"+direction,
+			))
+		case nlp.SortFieldDue:
+			// Tasks without a due date always sort last.
+			clauses = append(clauses,
+				sq.Expr("CASE WHEN t.due_on IS NULL OR t.due_on = '' THEN 1 ELSE 0 END"),
+				sq.Expr("t.due_on"+direction),
+			)
+		case nlp.SortFieldCreated:
+			clauses = append(clauses, sq.Expr("t.created_at"+direction))
+		case nlp.SortFieldUpdated:
+			clauses = append(clauses, sq.Expr("t.updated_at"+direction))
+		case nlp.SortFieldMeta:
+			if strings.TrimSpace(key.Key) == "" {
+				return nil, errors.New("meta sort requires a key")
+			}
+			field, typed := b.metaSchema.Field(key.Key)
+			column, args := metaValueSQL(key.Key, field, typed)
+			// Tasks without the key always sort last.
+			clauses = append(clauses,
+				sq.Expr("json_extract(t.meta_json, ?) IS NULL", metaJSONPath(key.Key)),
+				sq.Expr(column+direction, args...),
+			)
+		default:
+			return nil, fmt.Errorf("unsupported sort field %q", key.Field)
+		}
+	}
+	return clauses, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/nlp"
 )
 
@@ -44,7 +45,8 @@ func TestFilterSQLBuilder_TextSearchAddsLikeArgs(t *testing.T) {
 	require.NoError(t, err, "Build() error")
 
 	assert.Contains(t, clause, "t.title LIKE ?", "clause should contain title LIKE")
-	require.Len(t, args, 6, "args len mismatch")
+	assert.Contains(t, clause, "task_annotations", "clause should search annotations")
+	require.Len(t, args, 7, "args len mismatch")
 	for i, arg := range args {
 		assert.Equal(t, "%paper%", arg, "args[%d] mismatch", i)
 	}
@@ -58,6 +60,16 @@ func TestFilterSQLBuilder_InvalidIDReturnsError(t *testing.T) {
 	require.Error(t, err, "Build() should return invalid id error")
 }
 
+func TestFilterSQLBuilder_IDSetIsOneInClause(t *testing.T) {
+	t.Parallel()
+
+	b := &filterSQLBuilder{}
+	clause, args, err := b.Build(nlp.Predicate{Kind: nlp.PredID, IDs: []int64{3, 5, 9}})
+	require.NoError(t, err, "Build() error")
+	assert.Equal(t, "t.id IN (?,?,?)", clause, "clause mismatch")
+	assert.Equal(t, []any{int64(3), int64(5), int64(9)}, args, "args mismatch")
+}
+
 func TestFilterSQLBuilder_WildcardDuePredicateHasNoArgs(t *testing.T) {
 	t.Parallel()
 
@@ -113,3 +125,99 @@ func TestFilterSQLBuilder_NotWrapsNestedOrExpression(t *testing.T) {
 	assert.Equal(t, "now", args[0], "args[0] mismatch")
 	assert.Equal(t, "waiting", args[1], "args[1] mismatch")
 }
+
+func TestFilterSQLBuilder_MetaPresenceUsesQuotedPath(t *testing.T) {
+	t.Parallel()
+
+	b := &filterSQLBuilder{}
+	clause, args, err := b.Build(nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Text: nlp.FilterWildcard})
+	require.NoError(t, err, "Build() error")
+
+	assert.Equal(t, "json_extract(t.meta_json, ?) IS NOT NULL", clause, "clause mismatch")
+	assert.Equal(t, []any{`$."points"`}, args, "args mismatch")
+}
+
+func TestFilterSQLBuilder_TextFieldPredicates(t *testing.T) {
+	t.Parallel()
+
+	b := &filterSQLBuilder{regexMatches: map[regexKey][]int64{
+		{kind: nlp.PredTitle, pattern: "^a"}: {3, 5},
+		{kind: nlp.PredNotes, pattern: "^b"}: {},
+	}}
+
+	tests := []struct {
+		name       string
+		pred       nlp.Predicate
+		wantClause string
+		wantArgs   []any
+	}{
+		{
+			name:       "title contains",
+			pred:       nlp.Predicate{Kind: nlp.PredTitle, Op: nlp.CompareContains, Text: "report"},
+			wantClause: `t.title LIKE ? ESCAPE '\'`,
+			wantArgs:   []any{"%report%"},
+		},
+		{
+			name:       "title contains escapes wildcards",
+			pred:       nlp.Predicate{Kind: nlp.PredTitle, Op: nlp.CompareContains, Text: `50%_off\`},
+			wantClause: `t.title LIKE ? ESCAPE '\'`,
+			wantArgs:   []any{`%50\%\_off\\%`},
+		},
+		{
+			name:       "notes exact",
+			pred:       nlp.Predicate{Kind: nlp.PredNotes, Op: nlp.CompareEq, Text: "done"},
+			wantClause: "t.notes = ? COLLATE NOCASE",
+			wantArgs:   []any{"done"},
+		},
+		{
+			name:       "waiting non-empty",
+			pred:       nlp.Predicate{Kind: nlp.PredWaiting, Text: nlp.FilterWildcard},
+			wantClause: "COALESCE(t.waiting_for, '') != ''",
+		},
+		{
+			name:       "title regex",
+			pred:       nlp.Predicate{Kind: nlp.PredTitle, Op: nlp.CompareRegex, Text: "^a"},
+			wantClause: "t.id IN (?,?)",
+			wantArgs:   []any{int64(3), int64(5)},
+		},
+		{
+			name:       "notes regex without matches",
+			pred:       nlp.Predicate{Kind: nlp.PredNotes, Op: nlp.CompareRegex, Text: "^b"},
+			wantClause: "1=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			clause, args, err := b.Build(tt.pred)
+			require.NoError(t, err, "Build() error")
+			assert.Equal(t, tt.wantClause, clause, "clause mismatch")
+			assert.Equal(t, tt.wantArgs, args, "args mismatch")
+		})
+	}
+}
+
+func TestFilterSQLBuilder_TypedMetaComparisons(t *testing.T) {
+	t.Parallel()
+
+	b := &filterSQLBuilder{metaSchema: domain.MetaSchema{
+		"points": {Type: domain.MetaTypeInt},
+		"size":   {Type: domain.MetaTypeEnum, Values: []string{"s", "m", "l"}},
+	}}
+
+	clause, args, err := b.Build(nlp.Predicate{
+		Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareRange, Text: "3", Upper: "8",
+	})
+	require.NoError(t, err, "Build(range) error")
+	assert.Equal(t, "CAST(json_extract(t.meta_json, ?) AS REAL) BETWEEN ? AND ?", clause, "range clause mismatch")
+	assert.Equal(t, []any{`$."points"`, 3.0, 8.0}, args, "range args mismatch")
+
+	clause, args, err = b.Build(nlp.Predicate{Kind: nlp.PredMeta, Key: "size", Op: nlp.CompareGt, Text: "s"})
+	require.NoError(t, err, "Build(enum) error")
+	assert.Contains(t, clause, "CASE json_extract(t.meta_json, ?)", "enum clause should rank values")
+	assert.Equal(t, 0, args[len(args)-1], "enum operand should be its rank")
+
+	_, _, err = b.Build(nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareLt, Text: "many"})
+	require.Error(t, err, "Build(invalid int) should fail")
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+
+CREATE TABLE shell_sessions (
+    name TEXT PRIMARY KEY,
+    updated_at INTEGER NOT NULL,
+    selected_task_id INTEGER,
+    last_task_ids_json TEXT NOT NULL DEFAULT '[]',
+    previous_results_json TEXT NOT NULL DEFAULT '[]',
+    context_project TEXT NOT NULL DEFAULT '',
+    context_context TEXT NOT NULL DEFAULT '',
+    context_filter TEXT NOT NULL DEFAULT ''
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS shell_sessions;
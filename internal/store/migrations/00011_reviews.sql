@@ -0,0 +1,12 @@
+-- +goose Up
+
+CREATE TABLE reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    completed_at INTEGER NOT NULL,
+    tasks_reviewed INTEGER NOT NULL DEFAULT 0,
+    tasks_changed INTEGER NOT NULL DEFAULT 0
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS reviews;
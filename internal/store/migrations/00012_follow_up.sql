@@ -0,0 +1,9 @@
+-- +goose Up
+
+ALTER TABLE task_versions ADD COLUMN follow_up_on TEXT;
+ALTER TABLE tasks_current ADD COLUMN follow_up_on TEXT;
+
+-- +goose Down
+
+ALTER TABLE tasks_current DROP COLUMN follow_up_on;
+ALTER TABLE task_versions DROP COLUMN follow_up_on;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+ALTER TABLE task_versions ADD COLUMN snoozed_until INTEGER;
+ALTER TABLE tasks_current ADD COLUMN snoozed_until INTEGER;
+
+-- +goose Down
+
+ALTER TABLE tasks_current DROP COLUMN snoozed_until;
+ALTER TABLE task_versions DROP COLUMN snoozed_until;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+ALTER TABLE task_versions ADD COLUMN checklist_json TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE tasks_current ADD COLUMN checklist_json TEXT NOT NULL DEFAULT '[]';
+
+-- +goose Down
+
+ALTER TABLE tasks_current DROP COLUMN checklist_json;
+ALTER TABLE task_versions DROP COLUMN checklist_json;
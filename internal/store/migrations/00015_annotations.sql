@@ -0,0 +1,15 @@
+-- +goose Up
+
+CREATE TABLE task_annotations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  task_id INTEGER NOT NULL,
+  text TEXT NOT NULL,
+  created_at INTEGER NOT NULL,
+  FOREIGN KEY(task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_task_annotations_task ON task_annotations(task_id, created_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS task_annotations;
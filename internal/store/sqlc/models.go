@@ -8,6 +8,13 @@ import (
 	"database/sql"
 )
 
+type Review struct {
+	ID            int64 `json:"id"`
+	CompletedAt   int64 `json:"completed_at"`
+	TasksReviewed int64 `json:"tasks_reviewed"`
+	TasksChanged  int64 `json:"tasks_changed"`
+}
+
 type ShellHistory struct {
 	ID            int64          `json:"id"`
 	Timestamp     int64          `json:"timestamp"`
@@ -17,41 +24,65 @@ type ShellHistory struct {
 	Intent        sql.NullString `json:"intent"`
 }
 
+type ShellSession struct {
+	Name                string        `json:"name"`
+	UpdatedAt           int64         `json:"updated_at"`
+	SelectedTaskID      sql.NullInt64 `json:"selected_task_id"`
+	LastTaskIdsJson     string        `json:"last_task_ids_json"`
+	PreviousResultsJson string        `json:"previous_results_json"`
+	ContextProject      string        `json:"context_project"`
+	ContextContext      string        `json:"context_context"`
+	ContextFilter       string        `json:"context_filter"`
+}
+
 type Task struct {
 	ID        int64 `json:"id"`
 	CreatedAt int64 `json:"created_at"`
 }
 
+type TaskAnnotation struct {
+	ID        int64  `json:"id"`
+	TaskID    int64  `json:"task_id"`
+	Text      string `json:"text"`
+	CreatedAt int64  `json:"created_at"`
+}
+
 type TaskVersion struct {
-	VersionID    int64          `json:"version_id"`
-	TaskID       int64          `json:"task_id"`
-	State        string         `json:"state"`
-	PrevState    sql.NullString `json:"prev_state"`
-	Title        string         `json:"title"`
-	Notes        string         `json:"notes"`
-	DueOn        sql.NullString `json:"due_on"`
-	WaitingFor   sql.NullString `json:"waiting_for"`
-	CompletedAt  sql.NullInt64  `json:"completed_at"`
-	UpdatedAt    int64          `json:"updated_at"`
-	Deleted      int64          `json:"deleted"`
-	ProjectsJson string         `json:"projects_json"`
-	ContextsJson string         `json:"contexts_json"`
-	MetaJson     string         `json:"meta_json"`
+	VersionID     int64          `json:"version_id"`
+	TaskID        int64          `json:"task_id"`
+	State         string         `json:"state"`
+	PrevState     sql.NullString `json:"prev_state"`
+	Title         string         `json:"title"`
+	Notes         string         `json:"notes"`
+	DueOn         sql.NullString `json:"due_on"`
+	WaitingFor    sql.NullString `json:"waiting_for"`
+	CompletedAt   sql.NullInt64  `json:"completed_at"`
+	UpdatedAt     int64          `json:"updated_at"`
+	Deleted       int64          `json:"deleted"`
+	ProjectsJson  string         `json:"projects_json"`
+	ContextsJson  string         `json:"contexts_json"`
+	MetaJson      string         `json:"meta_json"`
+	FollowUpOn    sql.NullString `json:"follow_up_on"`
+	SnoozedUntil  sql.NullInt64  `json:"snoozed_until"`
+	ChecklistJson string         `json:"checklist_json"`
 }
 
 type TasksCurrent struct {
-	ID           int64          `json:"id"`
-	State        string         `json:"state"`
-	PrevState    sql.NullString `json:"prev_state"`
-	Title        string         `json:"title"`
-	Notes        string         `json:"notes"`
-	DueOn        sql.NullString `json:"due_on"`
-	WaitingFor   sql.NullString `json:"waiting_for"`
-	CompletedAt  sql.NullInt64  `json:"completed_at"`
-	CreatedAt    int64          `json:"created_at"`
-	UpdatedAt    int64          `json:"updated_at"`
-	ProjectsJson string         `json:"projects_json"`
-	ContextsJson string         `json:"contexts_json"`
-	MetaJson     string         `json:"meta_json"`
-	VersionID    int64          `json:"version_id"`
+	ID            int64          `json:"id"`
+	State         string         `json:"state"`
+	PrevState     sql.NullString `json:"prev_state"`
+	Title         string         `json:"title"`
+	Notes         string         `json:"notes"`
+	DueOn         sql.NullString `json:"due_on"`
+	WaitingFor    sql.NullString `json:"waiting_for"`
+	CompletedAt   sql.NullInt64  `json:"completed_at"`
+	CreatedAt     int64          `json:"created_at"`
+	UpdatedAt     int64          `json:"updated_at"`
+	ProjectsJson  string         `json:"projects_json"`
+	ContextsJson  string         `json:"contexts_json"`
+	MetaJson      string         `json:"meta_json"`
+	VersionID     int64          `json:"version_id"`
+	FollowUpOn    sql.NullString `json:"follow_up_on"`
+	SnoozedUntil  sql.NullInt64  `json:"snoozed_until"`
+	ChecklistJson string         `json:"checklist_json"`
 }
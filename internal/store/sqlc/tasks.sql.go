@@ -29,32 +29,38 @@ SELECT
   CAST(notes AS TEXT) AS notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   created_at,
   updated_at,
   projects_json,
   contexts_json,
   meta_json,
+  checklist_json,
   version_id
 FROM tasks_current
 WHERE id = ?
 `
 
 type GetTaskRow struct {
-	ID           int64          `json:"id"`
-	State        string         `json:"state"`
-	PrevState    sql.NullString `json:"prev_state"`
-	Title        string         `json:"title"`
-	Notes        string         `json:"notes"`
-	DueOn        sql.NullString `json:"due_on"`
-	WaitingFor   sql.NullString `json:"waiting_for"`
-	CompletedAt  sql.NullInt64  `json:"completed_at"`
-	CreatedAt    int64          `json:"created_at"`
-	UpdatedAt    int64          `json:"updated_at"`
-	ProjectsJson string         `json:"projects_json"`
-	ContextsJson string         `json:"contexts_json"`
-	MetaJson     string         `json:"meta_json"`
-	VersionID    int64          `json:"version_id"`
+	ID            int64          `json:"id"`
+	State         string         `json:"state"`
+	PrevState     sql.NullString `json:"prev_state"`
+	Title         string         `json:"title"`
+	Notes         string         `json:"notes"`
+	DueOn         sql.NullString `json:"due_on"`
+	WaitingFor    sql.NullString `json:"waiting_for"`
+	FollowUpOn    sql.NullString `json:"follow_up_on"`
+	SnoozedUntil  sql.NullInt64  `json:"snoozed_until"`
+	CompletedAt   sql.NullInt64  `json:"completed_at"`
+	CreatedAt     int64          `json:"created_at"`
+	UpdatedAt     int64          `json:"updated_at"`
+	ProjectsJson  string         `json:"projects_json"`
+	ContextsJson  string         `json:"contexts_json"`
+	MetaJson      string         `json:"meta_json"`
+	ChecklistJson string         `json:"checklist_json"`
+	VersionID     int64          `json:"version_id"`
 }
 
 func (q *Queries) GetTask(ctx context.Context, id int64) (GetTaskRow, error) {
@@ -68,12 +74,15 @@ func (q *Queries) GetTask(ctx context.Context, id int64) (GetTaskRow, error) {
 		&i.Notes,
 		&i.DueOn,
 		&i.WaitingFor,
+		&i.FollowUpOn,
+		&i.SnoozedUntil,
 		&i.CompletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ProjectsJson,
 		&i.ContextsJson,
 		&i.MetaJson,
+		&i.ChecklistJson,
 		&i.VersionID,
 	)
 	return i, err
@@ -96,32 +105,38 @@ INSERT INTO task_versions (
   notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   updated_at,
   deleted,
   projects_json,
   contexts_json,
-  meta_json
+  meta_json,
+  checklist_json
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING version_id
 `
 
 type InsertTaskVersionParams struct {
-	TaskID       int64          `json:"task_id"`
-	State        string         `json:"state"`
-	PrevState    sql.NullString `json:"prev_state"`
-	Title        string         `json:"title"`
-	Notes        string         `json:"notes"`
-	DueOn        sql.NullString `json:"due_on"`
-	WaitingFor   sql.NullString `json:"waiting_for"`
-	CompletedAt  sql.NullInt64  `json:"completed_at"`
-	UpdatedAt    int64          `json:"updated_at"`
-	Deleted      int64          `json:"deleted"`
-	ProjectsJson string         `json:"projects_json"`
-	ContextsJson string         `json:"contexts_json"`
-	MetaJson     string         `json:"meta_json"`
+	TaskID        int64          `json:"task_id"`
+	State         string         `json:"state"`
+	PrevState     sql.NullString `json:"prev_state"`
+	Title         string         `json:"title"`
+	Notes         string         `json:"notes"`
+	DueOn         sql.NullString `json:"due_on"`
+	WaitingFor    sql.NullString `json:"waiting_for"`
+	FollowUpOn    sql.NullString `json:"follow_up_on"`
+	SnoozedUntil  sql.NullInt64  `json:"snoozed_until"`
+	CompletedAt   sql.NullInt64  `json:"completed_at"`
+	UpdatedAt     int64          `json:"updated_at"`
+	Deleted       int64          `json:"deleted"`
+	ProjectsJson  string         `json:"projects_json"`
+	ContextsJson  string         `json:"contexts_json"`
+	MetaJson      string         `json:"meta_json"`
+	ChecklistJson string         `json:"checklist_json"`
 }
 
 func (q *Queries) InsertTaskVersion(ctx context.Context, arg InsertTaskVersionParams) (int64, error) {
@@ -133,12 +148,15 @@ func (q *Queries) InsertTaskVersion(ctx context.Context, arg InsertTaskVersionPa
 		arg.Notes,
 		arg.DueOn,
 		arg.WaitingFor,
+		arg.FollowUpOn,
+		arg.SnoozedUntil,
 		arg.CompletedAt,
 		arg.UpdatedAt,
 		arg.Deleted,
 		arg.ProjectsJson,
 		arg.ContextsJson,
 		arg.MetaJson,
+		arg.ChecklistJson,
 	)
 	var version_id int64
 	err := row.Scan(&version_id)
@@ -155,12 +173,15 @@ SELECT
   notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   updated_at,
   deleted,
   projects_json,
   contexts_json,
-  meta_json
+  meta_json,
+  checklist_json
 FROM task_versions
 WHERE task_id = ?
 ORDER BY version_id DESC
@@ -190,12 +211,15 @@ func (q *Queries) ListTaskVersions(ctx context.Context, arg ListTaskVersionsPara
 			&i.Notes,
 			&i.DueOn,
 			&i.WaitingFor,
+			&i.FollowUpOn,
+			&i.SnoozedUntil,
 			&i.CompletedAt,
 			&i.UpdatedAt,
 			&i.Deleted,
 			&i.ProjectsJson,
 			&i.ContextsJson,
 			&i.MetaJson,
+			&i.ChecklistJson,
 		); err != nil {
 			return nil, err
 		}
@@ -219,15 +243,18 @@ INSERT INTO tasks_current (
   notes,
   due_on,
   waiting_for,
+  follow_up_on,
+  snoozed_until,
   completed_at,
   created_at,
   updated_at,
   projects_json,
   contexts_json,
   meta_json,
+  checklist_json,
   version_id
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 ON CONFLICT(id) DO UPDATE SET
   state = excluded.state,
@@ -236,30 +263,36 @@ ON CONFLICT(id) DO UPDATE SET
   notes = excluded.notes,
   due_on = excluded.due_on,
   waiting_for = excluded.waiting_for,
+  follow_up_on = excluded.follow_up_on,
+  snoozed_until = excluded.snoozed_until,
   completed_at = excluded.completed_at,
   created_at = excluded.created_at,
   updated_at = excluded.updated_at,
   projects_json = excluded.projects_json,
   contexts_json = excluded.contexts_json,
   meta_json = excluded.meta_json,
+  checklist_json = excluded.checklist_json,
   version_id = excluded.version_id
 `
 
 type UpsertTaskCurrentParams struct {
-	ID           int64          `json:"id"`
-	State        string         `json:"state"`
-	PrevState    sql.NullString `json:"prev_state"`
-	Title        string         `json:"title"`
-	Notes        string         `json:"notes"`
-	DueOn        sql.NullString `json:"due_on"`
-	WaitingFor   sql.NullString `json:"waiting_for"`
-	CompletedAt  sql.NullInt64  `json:"completed_at"`
-	CreatedAt    int64          `json:"created_at"`
-	UpdatedAt    int64          `json:"updated_at"`
-	ProjectsJson string         `json:"projects_json"`
-	ContextsJson string         `json:"contexts_json"`
-	MetaJson     string         `json:"meta_json"`
-	VersionID    int64          `json:"version_id"`
+	ID            int64          `json:"id"`
+	State         string         `json:"state"`
+	PrevState     sql.NullString `json:"prev_state"`
+	Title         string         `json:"title"`
+	Notes         string         `json:"notes"`
+	DueOn         sql.NullString `json:"due_on"`
+	WaitingFor    sql.NullString `json:"waiting_for"`
+	FollowUpOn    sql.NullString `json:"follow_up_on"`
+	SnoozedUntil  sql.NullInt64  `json:"snoozed_until"`
+	CompletedAt   sql.NullInt64  `json:"completed_at"`
+	CreatedAt     int64          `json:"created_at"`
+	UpdatedAt     int64          `json:"updated_at"`
+	ProjectsJson  string         `json:"projects_json"`
+	ContextsJson  string         `json:"contexts_json"`
+	MetaJson      string         `json:"meta_json"`
+	ChecklistJson string         `json:"checklist_json"`
+	VersionID     int64          `json:"version_id"`
 }
 
 func (q *Queries) UpsertTaskCurrent(ctx context.Context, arg UpsertTaskCurrentParams) error {
@@ -271,12 +304,15 @@ func (q *Queries) UpsertTaskCurrent(ctx context.Context, arg UpsertTaskCurrentPa
 		arg.Notes,
 		arg.DueOn,
 		arg.WaitingFor,
+		arg.FollowUpOn,
+		arg.SnoozedUntil,
 		arg.CompletedAt,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.ProjectsJson,
 		arg.ContextsJson,
 		arg.MetaJson,
+		arg.ChecklistJson,
 		arg.VersionID,
 	)
 	return err
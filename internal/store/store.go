@@ -11,6 +11,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -28,6 +29,8 @@ type Store struct {
 	db      *sql.DB
 	syncDB  *tursogo.TursoSyncDb
 	queries *sqlc.Queries
+	// conn runs ad-hoc queries: db normally, the open transaction inside WithTx.
+	conn sqlc.DBTX
 }
 
 type Options struct {
@@ -122,7 +125,7 @@ func Open(ctx context.Context, opts Options) (*Store, error) {
 		return nil, fmt.Errorf("migrate: %w", err)
 	}
 
-	store := &Store{db: db, syncDB: syncDB, queries: sqlc.New(db)}
+	store := &Store{db: db, syncDB: syncDB, queries: sqlc.New(db), conn: db}
 	return store, nil
 }
 
@@ -159,6 +162,24 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// WithTx runs fn with a Store bound to a single transaction. The transaction
+// commits when fn returns nil and rolls back otherwise.
+func (s *Store) WithTx(ctx context.Context, fn func(tx *Store) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	txStore := &Store{db: s.db, syncDB: s.syncDB, queries: s.queries.WithTx(tx), conn: tx}
+	if err = fn(txStore); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (s *Store) CreateTask(ctx context.Context, task *Task) (*Task, error) {
 	if task == nil {
 		return nil, errors.New("task is required")
@@ -197,49 +218,55 @@ func (s *Store) CreateTask(ctx context.Context, task *Task) (*Task, error) {
 		return nil, fmt.Errorf("insert task identity: %w", err)
 	}
 	var identityID int64
-	err = s.db.QueryRowContext(ctx, "SELECT id FROM tasks ORDER BY id DESC LIMIT 1").Scan(&identityID)
+	err = s.conn.QueryRowContext(ctx, "SELECT id FROM tasks ORDER BY id DESC LIMIT 1").Scan(&identityID)
 	if err != nil {
 		return nil, fmt.Errorf("read task identity id: %w", err)
 	}
 
-	projectsJSON, contextsJSON, metaJSON, err := encodeTaskDetails(task)
+	projectsJSON, contextsJSON, metaJSON, checklistJSON, err := encodeTaskDetails(task)
 	if err != nil {
 		return nil, fmt.Errorf("encode task details: %w", err)
 	}
 
 	versionID, err := s.queries.InsertTaskVersion(ctx, sqlc.InsertTaskVersionParams{
-		TaskID:       identityID,
-		State:        string(task.State),
-		PrevState:    prevStateNull,
-		Title:        task.Title,
-		Notes:        task.Notes,
-		DueOn:        nullDate(task.DueOn),
-		WaitingFor:   nullString(task.WaitingFor),
-		CompletedAt:  nullUnixTime(completedAt),
-		UpdatedAt:    updatedAt,
-		Deleted:      0,
-		ProjectsJson: projectsJSON,
-		ContextsJson: contextsJSON,
-		MetaJson:     metaJSON,
+		TaskID:        identityID,
+		State:         string(task.State),
+		PrevState:     prevStateNull,
+		Title:         task.Title,
+		Notes:         task.Notes,
+		DueOn:         nullDate(task.DueOn),
+		WaitingFor:    nullString(task.WaitingFor),
+		FollowUpOn:    nullDate(task.FollowUpOn),
+		SnoozedUntil:  nullUnixTime(task.SnoozedUntil),
+		CompletedAt:   nullUnixTime(completedAt),
+		UpdatedAt:     updatedAt,
+		Deleted:       0,
+		ProjectsJson:  projectsJSON,
+		ContextsJson:  contextsJSON,
+		MetaJson:      metaJSON,
+		ChecklistJson: checklistJSON,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("insert task version: %w", err)
 	}
 	err = s.queries.UpsertTaskCurrent(ctx, sqlc.UpsertTaskCurrentParams{
-		ID:           identityID,
-		State:        string(task.State),
-		PrevState:    prevStateNull,
-		Title:        task.Title,
-		Notes:        task.Notes,
-		DueOn:        nullDate(task.DueOn),
-		WaitingFor:   nullString(task.WaitingFor),
-		CompletedAt:  nullUnixTime(completedAt),
-		CreatedAt:    createdAt,
-		UpdatedAt:    updatedAt,
-		ProjectsJson: projectsJSON,
-		ContextsJson: contextsJSON,
-		MetaJson:     metaJSON,
-		VersionID:    versionID,
+		ID:            identityID,
+		State:         string(task.State),
+		PrevState:     prevStateNull,
+		Title:         task.Title,
+		Notes:         task.Notes,
+		DueOn:         nullDate(task.DueOn),
+		WaitingFor:    nullString(task.WaitingFor),
+		FollowUpOn:    nullDate(task.FollowUpOn),
+		SnoozedUntil:  nullUnixTime(task.SnoozedUntil),
+		CompletedAt:   nullUnixTime(completedAt),
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		ProjectsJson:  projectsJSON,
+		ContextsJson:  contextsJSON,
+		MetaJson:      metaJSON,
+		ChecklistJson: checklistJSON,
+		VersionID:     versionID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("upsert current task: %w", err)
@@ -286,45 +313,51 @@ func (s *Store) UpdateTask(ctx context.Context, task *Task) (*Task, error) {
 	}
 
 	params := sqlc.InsertTaskVersionParams{
-		TaskID:      task.ID,
-		State:       string(task.State),
-		PrevState:   prevStateNull,
-		Title:       task.Title,
-		Notes:       task.Notes,
-		DueOn:       nullDate(task.DueOn),
-		WaitingFor:  nullString(task.WaitingFor),
-		CompletedAt: nullUnixTime(completedAt),
-		UpdatedAt:   updatedAt,
-		Deleted:     0,
+		TaskID:       task.ID,
+		State:        string(task.State),
+		PrevState:    prevStateNull,
+		Title:        task.Title,
+		Notes:        task.Notes,
+		DueOn:        nullDate(task.DueOn),
+		WaitingFor:   nullString(task.WaitingFor),
+		FollowUpOn:   nullDate(task.FollowUpOn),
+		SnoozedUntil: nullUnixTime(task.SnoozedUntil),
+		CompletedAt:  nullUnixTime(completedAt),
+		UpdatedAt:    updatedAt,
+		Deleted:      0,
 	}
 
-	projectsJSON, contextsJSON, metaJSON, err := encodeTaskDetails(task)
+	projectsJSON, contextsJSON, metaJSON, checklistJSON, err := encodeTaskDetails(task)
 	if err != nil {
 		return nil, fmt.Errorf("encode task details: %w", err)
 	}
 	params.ProjectsJson = projectsJSON
 	params.ContextsJson = contextsJSON
 	params.MetaJson = metaJSON
+	params.ChecklistJson = checklistJSON
 
 	versionID, err := s.queries.InsertTaskVersion(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("insert task version: %w", err)
 	}
 	if upsertErr := s.queries.UpsertTaskCurrent(ctx, sqlc.UpsertTaskCurrentParams{
-		ID:           task.ID,
-		State:        string(task.State),
-		PrevState:    prevStateNull,
-		Title:        task.Title,
-		Notes:        task.Notes,
-		DueOn:        nullDate(task.DueOn),
-		WaitingFor:   nullString(task.WaitingFor),
-		CompletedAt:  nullUnixTime(completedAt),
-		CreatedAt:    current.CreatedAt.UTC().Unix(),
-		UpdatedAt:    updatedAt,
-		ProjectsJson: projectsJSON,
-		ContextsJson: contextsJSON,
-		MetaJson:     metaJSON,
-		VersionID:    versionID,
+		ID:            task.ID,
+		State:         string(task.State),
+		PrevState:     prevStateNull,
+		Title:         task.Title,
+		Notes:         task.Notes,
+		DueOn:         nullDate(task.DueOn),
+		WaitingFor:    nullString(task.WaitingFor),
+		FollowUpOn:    nullDate(task.FollowUpOn),
+		SnoozedUntil:  nullUnixTime(task.SnoozedUntil),
+		CompletedAt:   nullUnixTime(completedAt),
+		CreatedAt:     current.CreatedAt.UTC().Unix(),
+		UpdatedAt:     updatedAt,
+		ProjectsJson:  projectsJSON,
+		ContextsJson:  contextsJSON,
+		MetaJson:      metaJSON,
+		ChecklistJson: checklistJSON,
+		VersionID:     versionID,
 	}); upsertErr != nil {
 		return nil, fmt.Errorf("upsert current task: %w", upsertErr)
 	}
@@ -338,7 +371,14 @@ func (s *Store) GetTask(ctx context.Context, id int64) (*Task, error) {
 		return nil, err
 	}
 
-	return fromGetRow(row)
+	task, err := fromGetRow(row)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.attachAnnotations(ctx, []*Task{task}); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
 func (s *Store) ListTaskVersions(ctx context.Context, taskID int64, limit int64) ([]*TaskVersion, error) {
@@ -371,9 +411,17 @@ func (s *Store) ListTasksByExpr(
 	} else if opts.ExcludeDone {
 		conditions = append(conditions, sq.Expr("t.state != 'done'"))
 	}
+	if opts.OnlySnoozed {
+		conditions = append(conditions, sq.Expr("t.state = 'later' AND t.snoozed_until IS NOT NULL"))
+	}
 
+	builder := &filterSQLBuilder{metaSchema: opts.MetaSchema, closedContexts: opts.ClosedContexts}
 	if expr != nil {
-		builder := &filterSQLBuilder{}
+		regexMatches, err := s.resolveRegexPredicates(ctx, builder, expr, conditions)
+		if err != nil {
+			return nil, err
+		}
+		builder.regexMatches = regexMatches
 		exprClause, exprArgs, err := builder.Build(expr)
 		if err != nil {
 			return nil, fmt.Errorf("build filter SQL: %w", err)
@@ -389,15 +437,26 @@ func (s *Store) ListTasksByExpr(
 		"CAST(t.notes AS TEXT) AS notes",
 		"t.due_on",
 		"t.waiting_for",
+		"t.follow_up_on",
+		"t.snoozed_until",
 		"t.completed_at",
 		"t.created_at",
 		"t.updated_at",
 		"t.projects_json",
 		"t.contexts_json",
 		"t.meta_json",
+		"t.checklist_json",
 	).
 		From("tasks_current t")
 
+	sortClauses, err := builder.buildOrderBy(opts.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("build sort SQL: %w", err)
+	}
+	for _, clause := range sortClauses {
+		queryBuilder = queryBuilder.OrderByClause(clause)
+	}
+
 	if opts.Recent {
 		queryBuilder = queryBuilder.OrderBy("t.updated_at DESC", "t.version_id DESC")
 	} else {
@@ -422,7 +481,7 @@ func (s *Store) ListTasksByExpr(
 	if err != nil {
 		return nil, fmt.Errorf("build list tasks query: %w", err)
 	}
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list tasks by expression: %w", err)
 	}
@@ -439,12 +498,15 @@ func (s *Store) ListTasksByExpr(
 			&row.Notes,
 			&row.DueOn,
 			&row.WaitingFor,
+			&row.FollowUpOn,
+			&row.SnoozedUntil,
 			&row.CompletedAt,
 			&row.CreatedAt,
 			&row.UpdatedAt,
 			&row.ProjectsJSON,
 			&row.ContextsJSON,
 			&row.MetaJSON,
+			&row.ChecklistJSON,
 		); scanErr != nil {
 			return nil, fmt.Errorf("scan task row: %w", scanErr)
 		}
@@ -457,24 +519,107 @@ func (s *Store) ListTasksByExpr(
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return nil, fmt.Errorf("iterate task rows: %w", rowsErr)
 	}
+	if err = s.attachAnnotations(ctx, tasks); err != nil {
+		return nil, err
+	}
 
 	return tasks, nil
 }
 
 type listTaskRow struct {
-	ID           int64
-	State        string
-	PrevState    sql.NullString
-	Title        string
-	Notes        string
-	DueOn        sql.NullString
-	WaitingFor   sql.NullString
-	CompletedAt  sql.NullInt64
-	CreatedAt    int64
-	UpdatedAt    int64
-	ProjectsJSON string
-	ContextsJSON string
-	MetaJSON     string
+	ID            int64
+	State         string
+	PrevState     sql.NullString
+	Title         string
+	Notes         string
+	DueOn         sql.NullString
+	WaitingFor    sql.NullString
+	FollowUpOn    sql.NullString
+	SnoozedUntil  sql.NullInt64
+	CompletedAt   sql.NullInt64
+	CreatedAt     int64
+	UpdatedAt     int64
+	ProjectsJSON  string
+	ContextsJSON  string
+	MetaJSON      string
+	ChecklistJSON string
+}
+
+// resolveRegexPredicates evaluates every regex predicate in expr against the
+// current tasks and returns the matching IDs per predicate. Only tasks passing
+// conditions and the regex-free top-level conjuncts of expr are scanned.
+func (s *Store) resolveRegexPredicates(
+	ctx context.Context,
+	builder *filterSQLBuilder,
+	expr nlp.FilterExpr,
+	conditions []sq.Sqlizer,
+) (map[regexKey][]int64, error) {
+	patterns := map[regexKey]*regexp.Regexp{}
+	if err := collectRegexPredicates(expr, patterns); err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return nil, nil //nolint:nilnil // No regex predicates means nothing to resolve.
+	}
+
+	queryBuilder := sq.Select(
+		"t.id",
+		"CAST(t.title AS TEXT)",
+		"CAST(t.notes AS TEXT)",
+		"COALESCE(t.waiting_for, '')",
+	).From("tasks_current t")
+	for _, condition := range conditions {
+		queryBuilder = queryBuilder.Where(condition)
+	}
+	for _, conjunct := range regexFreeConjuncts(expr) {
+		clause, err := builder.buildExpr(conjunct)
+		if err != nil {
+			return nil, fmt.Errorf("build filter SQL: %w", err)
+		}
+		queryBuilder = queryBuilder.Where(clause)
+	}
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build regex filter query: %w", err)
+	}
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("scan tasks for regex filter: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make(map[regexKey][]int64, len(patterns))
+	for key := range patterns {
+		matches[key] = []int64{}
+	}
+	for rows.Next() {
+		var (
+			id                       int64
+			title, notes, waitingFor string
+		)
+		if scanErr := rows.Scan(&id, &title, &notes, &waitingFor); scanErr != nil {
+			return nil, fmt.Errorf("scan task for regex filter: %w", scanErr)
+		}
+		for key, re := range patterns {
+			var value string
+			switch key.kind {
+			case nlp.PredTitle:
+				value = title
+			case nlp.PredNotes:
+				value = notes
+			default:
+				value = waitingFor
+			}
+			if re.MatchString(value) {
+				matches[key] = append(matches[key], id)
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tasks for regex filter: %w", err)
+	}
+	return matches, nil
 }
 
 //nolint:gocognit,nestif // Done/undo snapshot logic is centralized for consistency.
@@ -500,6 +645,7 @@ func (s *Store) SetDone(ctx context.Context, ids []int64, done bool) (int64, err
 		next.Projects = append([]string(nil), task.Projects...)
 		next.Contexts = append([]string(nil), task.Contexts...)
 		next.Meta = copyStringMap(task.Meta)
+		next.Checklist = append([]ChecklistItem(nil), task.Checklist...)
 
 		if done {
 			if task.State == StateDone {
@@ -509,6 +655,7 @@ func (s *Store) SetDone(ctx context.Context, ids []int64, done bool) (int64, err
 			next.PrevState = &prev
 			next.State = StateDone
 			next.CompletedAt = &now
+			next.SnoozedUntil = nil
 		} else {
 			if task.State != StateDone {
 				continue
@@ -522,7 +669,7 @@ func (s *Store) SetDone(ctx context.Context, ids []int64, done bool) (int64, err
 			next.CompletedAt = nil
 		}
 
-		projectsJSON, contextsJSON, metaJSON, encErr := encodeTaskDetails(&next)
+		projectsJSON, contextsJSON, metaJSON, checklistJSON, encErr := encodeTaskDetails(&next)
 		if encErr != nil {
 			return 0, fmt.Errorf("encode task details: %w", encErr)
 		}
@@ -538,38 +685,44 @@ func (s *Store) SetDone(ctx context.Context, ids []int64, done bool) (int64, err
 		}
 
 		versionID, insertErr := s.queries.InsertTaskVersion(ctx, sqlc.InsertTaskVersionParams{
-			TaskID:       task.ID,
-			State:        string(next.State),
-			PrevState:    prevStateNull,
-			Title:        next.Title,
-			Notes:        next.Notes,
-			DueOn:        nullDate(next.DueOn),
-			WaitingFor:   nullString(next.WaitingFor),
-			CompletedAt:  completedValue,
-			UpdatedAt:    updatedAt,
-			Deleted:      0,
-			ProjectsJson: projectsJSON,
-			ContextsJson: contextsJSON,
-			MetaJson:     metaJSON,
+			TaskID:        task.ID,
+			State:         string(next.State),
+			PrevState:     prevStateNull,
+			Title:         next.Title,
+			Notes:         next.Notes,
+			DueOn:         nullDate(next.DueOn),
+			WaitingFor:    nullString(next.WaitingFor),
+			FollowUpOn:    nullDate(next.FollowUpOn),
+			SnoozedUntil:  nullUnixTime(next.SnoozedUntil),
+			CompletedAt:   completedValue,
+			UpdatedAt:     updatedAt,
+			Deleted:       0,
+			ProjectsJson:  projectsJSON,
+			ContextsJson:  contextsJSON,
+			MetaJson:      metaJSON,
+			ChecklistJson: checklistJSON,
 		})
 		if insertErr != nil {
 			return 0, fmt.Errorf("insert task version: %w", insertErr)
 		}
 		err = s.queries.UpsertTaskCurrent(ctx, sqlc.UpsertTaskCurrentParams{
-			ID:           task.ID,
-			State:        string(next.State),
-			PrevState:    prevStateNull,
-			Title:        next.Title,
-			Notes:        next.Notes,
-			DueOn:        nullDate(next.DueOn),
-			WaitingFor:   nullString(next.WaitingFor),
-			CompletedAt:  completedValue,
-			CreatedAt:    task.CreatedAt.UTC().Unix(),
-			UpdatedAt:    updatedAt,
-			ProjectsJson: projectsJSON,
-			ContextsJson: contextsJSON,
-			MetaJson:     metaJSON,
-			VersionID:    versionID,
+			ID:            task.ID,
+			State:         string(next.State),
+			PrevState:     prevStateNull,
+			Title:         next.Title,
+			Notes:         next.Notes,
+			DueOn:         nullDate(next.DueOn),
+			WaitingFor:    nullString(next.WaitingFor),
+			FollowUpOn:    nullDate(next.FollowUpOn),
+			SnoozedUntil:  nullUnixTime(next.SnoozedUntil),
+			CompletedAt:   completedValue,
+			CreatedAt:     task.CreatedAt.UTC().Unix(),
+			UpdatedAt:     updatedAt,
+			ProjectsJson:  projectsJSON,
+			ContextsJson:  contextsJSON,
+			MetaJson:      metaJSON,
+			ChecklistJson: checklistJSON,
+			VersionID:     versionID,
 		})
 		if err != nil {
 			return 0, fmt.Errorf("upsert current task: %w", err)
@@ -594,25 +747,28 @@ func (s *Store) DeleteTasks(ctx context.Context, ids []int64) (int64, error) {
 			}
 			return 0, err
 		}
-		projectsJSON, contextsJSON, metaJSON, encErr := encodeTaskDetails(task)
+		projectsJSON, contextsJSON, metaJSON, checklistJSON, encErr := encodeTaskDetails(task)
 		if encErr != nil {
 			return 0, fmt.Errorf("encode task details: %w", encErr)
 		}
 
 		_, insertErr := s.queries.InsertTaskVersion(ctx, sqlc.InsertTaskVersionParams{
-			TaskID:       task.ID,
-			State:        string(task.State),
-			PrevState:    nullState(task.PrevState),
-			Title:        task.Title,
-			Notes:        task.Notes,
-			DueOn:        nullDate(task.DueOn),
-			WaitingFor:   nullString(task.WaitingFor),
-			CompletedAt:  nullUnixTime(task.CompletedAt),
-			UpdatedAt:    updatedAt,
-			Deleted:      1,
-			ProjectsJson: projectsJSON,
-			ContextsJson: contextsJSON,
-			MetaJson:     metaJSON,
+			TaskID:        task.ID,
+			State:         string(task.State),
+			PrevState:     nullState(task.PrevState),
+			Title:         task.Title,
+			Notes:         task.Notes,
+			DueOn:         nullDate(task.DueOn),
+			WaitingFor:    nullString(task.WaitingFor),
+			FollowUpOn:    nullDate(task.FollowUpOn),
+			SnoozedUntil:  nullUnixTime(task.SnoozedUntil),
+			CompletedAt:   nullUnixTime(task.CompletedAt),
+			UpdatedAt:     updatedAt,
+			Deleted:       1,
+			ProjectsJson:  projectsJSON,
+			ContextsJson:  contextsJSON,
+			MetaJson:      metaJSON,
+			ChecklistJson: checklistJSON,
 		})
 		if insertErr != nil {
 			return 0, fmt.Errorf("insert tombstone version: %w", insertErr)
@@ -626,7 +782,7 @@ func (s *Store) DeleteTasks(ctx context.Context, ids []int64) (int64, error) {
 	return deleted, nil
 }
 
-func encodeTaskDetails(task *Task) (string, string, string, error) {
+func encodeTaskDetails(task *Task) (string, string, string, string, error) {
 	projects := uniqueStrings(cleanNames(task.Projects))
 	contexts := uniqueStrings(cleanNames(task.Contexts))
 
@@ -640,22 +796,30 @@ func encodeTaskDetails(task *Task) (string, string, string, error) {
 
 	projectsJSON, err := json.Marshal(projects)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", "", err
 	}
 	contextsJSON, err := json.Marshal(contexts)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", "", err
 	}
 	metaJSON, err := json.Marshal(meta)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", "", err
+	}
+	checklist := task.Checklist
+	if checklist == nil {
+		checklist = []ChecklistItem{}
+	}
+	checklistJSON, err := json.Marshal(checklist)
+	if err != nil {
+		return "", "", "", "", err
 	}
 
-	return string(projectsJSON), string(contextsJSON), string(metaJSON), nil
+	return string(projectsJSON), string(contextsJSON), string(metaJSON), string(checklistJSON), nil
 }
 
 func (s *Store) ListProjectCounts(ctx context.Context, onlyDone bool, excludeDone bool) ([]NameCount, error) {
-	rows, err := s.db.QueryContext(
+	rows, err := s.conn.QueryContext(
 		ctx,
 		`SELECT p.value AS name, COUNT(t.id) AS count
 FROM tasks_current t
@@ -687,8 +851,151 @@ ORDER BY p.value ASC;`,
 	return result, nil
 }
 
+// ListStalledProjects returns the projects that have open tasks but no now
+// task, least recently active first.
+func (s *Store) ListStalledProjects(ctx context.Context) ([]StalledProject, error) {
+	rows, err := s.conn.QueryContext(
+		ctx,
+		`SELECT p.value AS name,
+  SUM(CASE WHEN t.state != 'done' THEN 1 ELSE 0 END) AS open_count,
+  SUM(CASE WHEN t.state = 'inbox' THEN 1 ELSE 0 END) AS inbox_count,
+  SUM(CASE WHEN t.state = 'waiting' THEN 1 ELSE 0 END) AS waiting_count,
+  SUM(CASE WHEN t.state = 'later' THEN 1 ELSE 0 END) AS later_count,
+  MAX(t.updated_at) AS last_activity
+FROM tasks_current t
+JOIN json_each(t.projects_json) p
+GROUP BY p.value
+HAVING open_count > 0
+  AND SUM(CASE WHEN t.state = 'now' THEN 1 ELSE 0 END) = 0
+ORDER BY last_activity ASC, p.value ASC;`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stalled projects: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]StalledProject, 0)
+	for rows.Next() {
+		var row StalledProject
+		var lastActivity int64
+		scanErr := rows.Scan(&row.Name, &row.Open, &row.Inbox, &row.Waiting, &row.Later, &lastActivity)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scan stalled projects: %w", scanErr)
+		}
+		row.LastActivity = time.Unix(lastActivity, 0).UTC()
+		result = append(result, row)
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate stalled projects: %w", rowsErr)
+	}
+
+	return result, nil
+}
+
+// ListWaitingSince returns when each waiting task entered the waiting state,
+// keyed by task id: the oldest version since the task was last in another
+// state.
+func (s *Store) ListWaitingSince(ctx context.Context) (map[int64]time.Time, error) {
+	rows, err := s.conn.QueryContext(
+		ctx,
+		`SELECT t.id,
+  COALESCE((
+    SELECT MIN(v.updated_at)
+    FROM task_versions v
+    WHERE v.task_id = t.id
+      AND v.version_id > COALESCE((
+        SELECT MAX(o.version_id)
+        FROM task_versions o
+        WHERE o.task_id = t.id AND o.state != 'waiting'
+      ), 0)
+  ), t.updated_at) AS waiting_since
+FROM tasks_current t
+WHERE t.state = 'waiting';`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list waiting since: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]time.Time)
+	for rows.Next() {
+		var id, since int64
+		if scanErr := rows.Scan(&id, &since); scanErr != nil {
+			return nil, fmt.Errorf("scan waiting since: %w", scanErr)
+		}
+		result[id] = time.Unix(since, 0).UTC()
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate waiting since: %w", rowsErr)
+	}
+
+	return result, nil
+}
+
+// WakeSnoozed returns snoozed tasks whose date is at or before now to the
+// state they had before the snooze, or inbox when it is unknown, and returns
+// the woken tasks. All tasks wake in one transaction.
+func (s *Store) WakeSnoozed(ctx context.Context, now time.Time) ([]*Task, error) {
+	var woken []*Task
+	err := s.WithTx(ctx, func(tx *Store) error {
+		var err error
+		woken, err = tx.wakeSnoozed(ctx, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return woken, nil
+}
+
+func (s *Store) wakeSnoozed(ctx context.Context, now time.Time) ([]*Task, error) {
+	rows, err := s.conn.QueryContext(
+		ctx,
+		`SELECT id FROM tasks_current
+WHERE state = 'later' AND snoozed_until IS NOT NULL AND snoozed_until <= ?
+ORDER BY snoozed_until ASC, id ASC;`,
+		now.UTC().Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list snoozed tasks: %w", err)
+	}
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if scanErr := rows.Scan(&id); scanErr != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan snoozed task: %w", scanErr)
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate snoozed tasks: %w", rowsErr)
+	}
+
+	woken := make([]*Task, 0, len(ids))
+	for _, id := range ids {
+		task, getErr := s.GetTask(ctx, id)
+		if getErr != nil {
+			return nil, getErr
+		}
+		task.State = StateInbox
+		if task.PrevState != nil {
+			task.State = *task.PrevState
+		}
+		task.PrevState = nil
+		task.SnoozedUntil = nil
+		updated, updateErr := s.UpdateTask(ctx, task)
+		if updateErr != nil {
+			return nil, updateErr
+		}
+		woken = append(woken, updated)
+	}
+	return woken, nil
+}
+
 func (s *Store) ListContextCounts(ctx context.Context, onlyDone bool, excludeDone bool) ([]NameCount, error) {
-	rows, err := s.db.QueryContext(
+	rows, err := s.conn.QueryContext(
 		ctx,
 		`SELECT c.value AS name, COUNT(t.id) AS count
 FROM tasks_current t
@@ -819,72 +1126,87 @@ func (s *Store) ClearShellHistory(ctx context.Context) error {
 }
 
 func fromGetRow(row sqlc.GetTaskRow) (*Task, error) {
-	projects, contexts, meta, err := decodeTaskDetails(row.ProjectsJson, row.ContextsJson, row.MetaJson)
+	projects, contexts, meta, checklist, err := decodeTaskDetails(
+		row.ProjectsJson, row.ContextsJson, row.MetaJson, row.ChecklistJson,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("decode task details: %w", err)
 	}
 
 	return &Task{
-		ID:          row.ID,
-		State:       State(row.State),
-		PrevState:   parseStatePtr(row.PrevState),
-		Title:       row.Title,
-		Notes:       row.Notes,
-		DueOn:       parseDate(row.DueOn),
-		WaitingFor:  row.WaitingFor.String,
-		CompletedAt: parseUnixTime(row.CompletedAt),
-		Projects:    projects,
-		Contexts:    contexts,
-		Meta:        meta,
-		CreatedAt:   time.Unix(row.CreatedAt, 0).UTC(),
-		UpdatedAt:   time.Unix(row.UpdatedAt, 0).UTC(),
+		ID:           row.ID,
+		State:        State(row.State),
+		PrevState:    parseStatePtr(row.PrevState),
+		Title:        row.Title,
+		Notes:        row.Notes,
+		DueOn:        parseDate(row.DueOn),
+		WaitingFor:   row.WaitingFor.String,
+		FollowUpOn:   parseDate(row.FollowUpOn),
+		SnoozedUntil: parseUnixTime(row.SnoozedUntil),
+		CompletedAt:  parseUnixTime(row.CompletedAt),
+		Projects:     projects,
+		Contexts:     contexts,
+		Meta:         meta,
+		Checklist:    checklist,
+		CreatedAt:    time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt:    time.Unix(row.UpdatedAt, 0).UTC(),
 	}, nil
 }
 
 func fromListRow(row listTaskRow) (*Task, error) {
-	projects, contexts, meta, err := decodeTaskDetails(row.ProjectsJSON, row.ContextsJSON, row.MetaJSON)
+	projects, contexts, meta, checklist, err := decodeTaskDetails(
+		row.ProjectsJSON, row.ContextsJSON, row.MetaJSON, row.ChecklistJSON,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("decode task details: %w", err)
 	}
 
 	return &Task{
-		ID:          row.ID,
-		State:       State(row.State),
-		PrevState:   parseStatePtr(row.PrevState),
-		Title:       row.Title,
-		Notes:       row.Notes,
-		DueOn:       parseDate(row.DueOn),
-		WaitingFor:  row.WaitingFor.String,
-		CompletedAt: parseUnixTime(row.CompletedAt),
-		Projects:    projects,
-		Contexts:    contexts,
-		Meta:        meta,
-		CreatedAt:   time.Unix(row.CreatedAt, 0).UTC(),
-		UpdatedAt:   time.Unix(row.UpdatedAt, 0).UTC(),
+		ID:           row.ID,
+		State:        State(row.State),
+		PrevState:    parseStatePtr(row.PrevState),
+		Title:        row.Title,
+		Notes:        row.Notes,
+		DueOn:        parseDate(row.DueOn),
+		WaitingFor:   row.WaitingFor.String,
+		FollowUpOn:   parseDate(row.FollowUpOn),
+		SnoozedUntil: parseUnixTime(row.SnoozedUntil),
+		CompletedAt:  parseUnixTime(row.CompletedAt),
+		Projects:     projects,
+		Contexts:     contexts,
+		Meta:         meta,
+		Checklist:    checklist,
+		CreatedAt:    time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt:    time.Unix(row.UpdatedAt, 0).UTC(),
 	}, nil
 }
 
 func fromVersionRow(row sqlc.TaskVersion) (*TaskVersion, error) {
-	projects, contexts, meta, err := decodeTaskDetails(row.ProjectsJson, row.ContextsJson, row.MetaJson)
+	projects, contexts, meta, checklist, err := decodeTaskDetails(
+		row.ProjectsJson, row.ContextsJson, row.MetaJson, row.ChecklistJson,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("decode task version details: %w", err)
 	}
 
 	return &TaskVersion{
-		VersionID:   row.VersionID,
-		TaskID:      row.TaskID,
-		State:       State(row.State),
-		PrevState:   parseStatePtr(row.PrevState),
-		Title:       row.Title,
-		Notes:       row.Notes,
-		DueOn:       parseDate(row.DueOn),
-		WaitingFor:  row.WaitingFor.String,
-		CompletedAt: parseUnixTime(row.CompletedAt),
-		UpdatedAt:   time.Unix(row.UpdatedAt, 0).UTC(),
-		Deleted:     row.Deleted != 0,
-		Projects:    projects,
-		Contexts:    contexts,
-		Meta:        meta,
+		VersionID:    row.VersionID,
+		TaskID:       row.TaskID,
+		State:        State(row.State),
+		PrevState:    parseStatePtr(row.PrevState),
+		Title:        row.Title,
+		Notes:        row.Notes,
+		DueOn:        parseDate(row.DueOn),
+		WaitingFor:   row.WaitingFor.String,
+		FollowUpOn:   parseDate(row.FollowUpOn),
+		SnoozedUntil: parseUnixTime(row.SnoozedUntil),
+		CompletedAt:  parseUnixTime(row.CompletedAt),
+		UpdatedAt:    time.Unix(row.UpdatedAt, 0).UTC(),
+		Deleted:      row.Deleted != 0,
+		Projects:     projects,
+		Contexts:     contexts,
+		Meta:         meta,
+		Checklist:    checklist,
 	}, nil
 }
 
@@ -923,6 +1245,209 @@ func boolToInt(value bool) int64 {
 	return 0
 }
 
+// SaveShellSession creates or replaces the named shell session.
+func (s *Store) SaveShellSession(ctx context.Context, session ShellSession) error {
+	lastJSON, err := json.Marshal(nonNilIDs(session.LastTaskIDs))
+	if err != nil {
+		return fmt.Errorf("marshal last task ids: %w", err)
+	}
+	previous := session.PreviousResults
+	if previous == nil {
+		previous = [][]int64{}
+	}
+	previousJSON, err := json.Marshal(previous)
+	if err != nil {
+		return fmt.Errorf("marshal previous results: %w", err)
+	}
+	var selected sql.NullInt64
+	if session.SelectedTaskID != nil {
+		selected = sql.NullInt64{Int64: *session.SelectedTaskID, Valid: true}
+	}
+	err = s.queries.UpsertShellSession(ctx, sqlc.UpsertShellSessionParams{
+		Name:                session.Name,
+		UpdatedAt:           time.Now().UTC().Unix(),
+		SelectedTaskID:      selected,
+		LastTaskIdsJson:     string(lastJSON),
+		PreviousResultsJson: string(previousJSON),
+		ContextProject:      session.ContextProject,
+		ContextContext:      session.ContextContext,
+		ContextFilter:       session.ContextFilter,
+	})
+	if err != nil {
+		return fmt.Errorf("save shell session: %w", err)
+	}
+	return nil
+}
+
+// GetShellSession returns the named shell session, or nil if there is none.
+func (s *Store) GetShellSession(ctx context.Context, name string) (*ShellSession, error) {
+	row, err := s.queries.GetShellSession(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // a missing session is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get shell session: %w", err)
+	}
+	return fromSessionRow(row)
+}
+
+// ListShellSessions returns the saved shell sessions, most recently used
+// first.
+func (s *Store) ListShellSessions(ctx context.Context) ([]*ShellSession, error) {
+	rows, err := s.queries.ListShellSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list shell sessions: %w", err)
+	}
+	sessions := make([]*ShellSession, 0, len(rows))
+	for _, row := range rows {
+		session, convErr := fromSessionRow(row)
+		if convErr != nil {
+			return nil, convErr
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// DeleteShellSession removes the named shell session and reports whether it
+// existed.
+func (s *Store) DeleteShellSession(ctx context.Context, name string) (bool, error) {
+	count, err := s.queries.DeleteShellSession(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("delete shell session: %w", err)
+	}
+	return count > 0, nil
+}
+
+func fromSessionRow(row sqlc.ShellSession) (*ShellSession, error) {
+	session := &ShellSession{
+		Name:           row.Name,
+		UpdatedAt:      row.UpdatedAt,
+		ContextProject: row.ContextProject,
+		ContextContext: row.ContextContext,
+		ContextFilter:  row.ContextFilter,
+	}
+	if row.SelectedTaskID.Valid {
+		id := row.SelectedTaskID.Int64
+		session.SelectedTaskID = &id
+	}
+	if err := json.Unmarshal([]byte(row.LastTaskIdsJson), &session.LastTaskIDs); err != nil {
+		return nil, fmt.Errorf("decode last task ids for session %q: %w", row.Name, err)
+	}
+	if err := json.Unmarshal([]byte(row.PreviousResultsJson), &session.PreviousResults); err != nil {
+		return nil, fmt.Errorf("decode previous results for session %q: %w", row.Name, err)
+	}
+	return session, nil
+}
+
+// AddAnnotation appends a timestamped annotation to a task.
+func (s *Store) AddAnnotation(ctx context.Context, taskID int64, text string) (*Annotation, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("annotation text is required")
+	}
+	row, err := s.queries.InsertTaskAnnotation(ctx, sqlc.InsertTaskAnnotationParams{
+		TaskID:    taskID,
+		Text:      text,
+		CreatedAt: time.Now().UTC().Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("add annotation: %w", err)
+	}
+	return &Annotation{
+		ID:        row.ID,
+		TaskID:    row.TaskID,
+		Text:      row.Text,
+		CreatedAt: time.Unix(row.CreatedAt, 0).UTC(),
+	}, nil
+}
+
+// attachAnnotations loads the annotations of tasks, oldest first.
+func (s *Store) attachAnnotations(ctx context.Context, tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*Task, len(tasks))
+	ids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+
+	query, args, err := sq.Select("id", "task_id", "text", "created_at").
+		From("task_annotations").
+		Where(sq.Eq{"task_id": ids}).
+		OrderBy("created_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build annotations query: %w", err)
+	}
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("list annotations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			annotation Annotation
+			createdAt  int64
+		)
+		if scanErr := rows.Scan(&annotation.ID, &annotation.TaskID, &annotation.Text, &createdAt); scanErr != nil {
+			return fmt.Errorf("scan annotation: %w", scanErr)
+		}
+		annotation.CreatedAt = time.Unix(createdAt, 0).UTC()
+		task := byID[annotation.TaskID]
+		task.Annotations = append(task.Annotations, annotation)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("iterate annotations: %w", err)
+	}
+	return nil
+}
+
+// RecordReview stores a completed weekly review.
+func (s *Store) RecordReview(ctx context.Context, review Review) (*Review, error) {
+	row, err := s.queries.InsertReview(ctx, sqlc.InsertReviewParams{
+		CompletedAt:   review.CompletedAt.UTC().Unix(),
+		TasksReviewed: review.TasksReviewed,
+		TasksChanged:  review.TasksChanged,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record review: %w", err)
+	}
+	return fromReviewRow(row), nil
+}
+
+// LastReview returns the most recently completed review, or nil when there
+// has been none.
+func (s *Store) LastReview(ctx context.Context) (*Review, error) {
+	row, err := s.queries.GetLastReview(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no review yet is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get last review: %w", err)
+	}
+	return fromReviewRow(row), nil
+}
+
+func fromReviewRow(row sqlc.Review) *Review {
+	return &Review{
+		ID:            row.ID,
+		CompletedAt:   time.Unix(row.CompletedAt, 0).UTC(),
+		TasksReviewed: row.TasksReviewed,
+		TasksChanged:  row.TasksChanged,
+	}
+}
+
+func nonNilIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
+
 func nullString(value string) sql.NullString {
 	if value == "" {
 		return sql.NullString{}
@@ -951,29 +1476,38 @@ func nullState(value *State) sql.NullString {
 	return sql.NullString{String: string(*value), Valid: true}
 }
 
-func decodeTaskDetails(projectsJSON, contextsJSON, metaJSON string) ([]string, []string, map[string]string, error) {
+func decodeTaskDetails(
+	projectsJSON, contextsJSON, metaJSON, checklistJSON string,
+) ([]string, []string, map[string]string, []ChecklistItem, error) {
 	projects := []string{}
 	if strings.TrimSpace(projectsJSON) != "" {
 		if err := json.Unmarshal([]byte(projectsJSON), &projects); err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 	}
 
 	contexts := []string{}
 	if strings.TrimSpace(contextsJSON) != "" {
 		if err := json.Unmarshal([]byte(contextsJSON), &contexts); err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 	}
 
 	meta := map[string]string{}
 	if strings.TrimSpace(metaJSON) != "" {
 		if err := json.Unmarshal([]byte(metaJSON), &meta); err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
+		}
+	}
+
+	checklist := []ChecklistItem{}
+	if strings.TrimSpace(checklistJSON) != "" {
+		if err := json.Unmarshal([]byte(checklistJSON), &checklist); err != nil {
+			return nil, nil, nil, nil, err
 		}
 	}
 
-	return projects, contexts, meta, nil
+	return projects, contexts, meta, checklist, nil
 }
 
 func copyStringMap(input map[string]string) map[string]string {
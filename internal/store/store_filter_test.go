@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mholtzscher/ugh/internal/domain"
 	"github.com/mholtzscher/ugh/internal/nlp"
 )
 
@@ -103,6 +104,237 @@ func TestListTasksByExpr_NestedBooleanFilterIDs(t *testing.T) {
 	assert.Equal(t, wantIDs, gotIDs, "ListTasksByExpr(nested) ids mismatch")
 }
 
+func TestListTasksByExpr_TypedMetaFilterAndSort(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+	schema := domain.MetaSchema{"points": {Type: domain.MetaTypeInt}}
+
+	small, err := s.CreateTask(ctx, &Task{Title: "Small", State: StateNow, Meta: map[string]string{"points": "2"}})
+	require.NoError(t, err, "CreateTask(small) error")
+	large, err := s.CreateTask(ctx, &Task{Title: "Large", State: StateNow, Meta: map[string]string{"points": "13"}})
+	require.NoError(t, err, "CreateTask(large) error")
+	medium, err := s.CreateTask(ctx, &Task{Title: "Medium", State: StateNow, Meta: map[string]string{"points": "5"}})
+	require.NoError(t, err, "CreateTask(medium) error")
+	unsized, err := s.CreateTask(ctx, &Task{Title: "Unsized", State: StateNow})
+	require.NoError(t, err, "CreateTask(unsized) error")
+
+	// Numeric comparison: "13" > "3" even though it sorts before it as text.
+	tasks, err := s.ListTasksByExpr(ctx, nlp.Predicate{
+		Kind: nlp.PredMeta, Key: "points", Op: nlp.CompareGt, Text: "3",
+	}, ListTasksByExprOptions{MetaSchema: schema, Sort: []nlp.SortKey{{Field: nlp.SortFieldMeta, Key: "points"}}})
+	require.NoError(t, err, "ListTasksByExpr(points>3) error")
+	assert.Equal(t, []int64{medium.ID, large.ID}, taskIDs(tasks), "points>3 ids mismatch")
+
+	tasks, err = s.ListTasksByExpr(ctx, nil, ListTasksByExprOptions{
+		MetaSchema: schema,
+		Sort:       []nlp.SortKey{{Field: nlp.SortFieldMeta, Key: "points", Desc: true}},
+	})
+	require.NoError(t, err, "ListTasksByExpr(sort) error")
+	assert.Equal(t, []int64{large.ID, medium.ID, small.ID, unsized.ID}, taskIDs(tasks), "sort ids mismatch")
+
+	tasks, err = s.ListTasksByExpr(ctx, nlp.FilterNot{
+		Expr: nlp.Predicate{Kind: nlp.PredMeta, Key: "points", Text: nlp.FilterWildcard},
+	}, ListTasksByExprOptions{MetaSchema: schema})
+	require.NoError(t, err, "ListTasksByExpr(!meta:points) error")
+	assert.Equal(t, []int64{unsized.ID}, taskIDs(tasks), "!meta:points ids mismatch")
+}
+
+func TestListTasksByExpr_TextFieldPredicates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	alice, err := s.CreateTask(ctx, &Task{Title: "Review", State: StateWaiting, WaitingFor: "Alice Smith"})
+	require.NoError(t, err, "CreateTask(alice) error")
+	bob, err := s.CreateTask(ctx, &Task{Title: "Contract", State: StateWaiting, WaitingFor: "Bob"})
+	require.NoError(t, err, "CreateTask(bob) error")
+	none, err := s.CreateTask(ctx, &Task{Title: "Solo", State: StateNow, Notes: "draft v2"})
+	require.NoError(t, err, "CreateTask(none) error")
+
+	tests := []struct {
+		name string
+		expr nlp.FilterExpr
+		want []int64
+	}{
+		{
+			name: "waiting substring",
+			expr: nlp.Predicate{Kind: nlp.PredWaiting, Op: nlp.CompareContains, Text: "alice"},
+			want: []int64{alice.ID},
+		},
+		{
+			name: "waiting exact",
+			expr: nlp.Predicate{Kind: nlp.PredWaiting, Op: nlp.CompareEq, Text: "bob"},
+			want: []int64{bob.ID},
+		},
+		{
+			name: "waiting regex",
+			expr: nlp.Predicate{Kind: nlp.PredWaiting, Op: nlp.CompareRegex, Text: "^(Alice|Bob)$"},
+			want: []int64{bob.ID},
+		},
+		{
+			name: "waiting empty",
+			expr: nlp.FilterNot{Expr: nlp.Predicate{Kind: nlp.PredWaiting, Text: nlp.FilterWildcard}},
+			want: []int64{none.ID},
+		},
+		{
+			name: "not notes regex",
+			expr: nlp.FilterNot{Expr: nlp.Predicate{Kind: nlp.PredNotes, Op: nlp.CompareRegex, Text: `v\d`}},
+			want: []int64{alice.ID, bob.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks, listErr := s.ListTasksByExpr(ctx, tt.expr, ListTasksByExprOptions{})
+			require.NoError(t, listErr, "ListTasksByExpr() error")
+			assert.ElementsMatch(t, tt.want, taskIDs(tasks), "ids mismatch")
+		})
+	}
+}
+
+func TestListTasksByExpr_FollowUpPredicates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	early := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC)
+	alice, err := s.CreateTask(ctx, &Task{Title: "Quote", State: StateWaiting, WaitingFor: "alice", FollowUpOn: &early})
+	require.NoError(t, err, "CreateTask(alice) error")
+	bob, err := s.CreateTask(ctx, &Task{Title: "Invoice", State: StateWaiting, WaitingFor: "bob", FollowUpOn: &late})
+	require.NoError(t, err, "CreateTask(bob) error")
+	none, err := s.CreateTask(ctx, &Task{Title: "Call", State: StateWaiting, WaitingFor: "carol"})
+	require.NoError(t, err, "CreateTask(none) error")
+
+	tests := []struct {
+		name string
+		expr nlp.FilterExpr
+		want []int64
+	}{
+		{
+			name: "equal",
+			expr: nlp.Predicate{Kind: nlp.PredFollowUp, Text: "2026-03-09"},
+			want: []int64{bob.ID},
+		},
+		{
+			name: "on or before",
+			expr: nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareLte, Text: "2026-03-05"},
+			want: []int64{alice.ID},
+		},
+		{
+			name: "after",
+			expr: nlp.Predicate{Kind: nlp.PredFollowUp, Op: nlp.CompareGt, Text: "2026-03-02"},
+			want: []int64{bob.ID},
+		},
+		{
+			name: "any",
+			expr: nlp.Predicate{Kind: nlp.PredFollowUp, Text: nlp.FilterWildcard},
+			want: []int64{alice.ID, bob.ID},
+		},
+		{
+			name: "none",
+			expr: nlp.FilterNot{Expr: nlp.Predicate{Kind: nlp.PredFollowUp, Text: nlp.FilterWildcard}},
+			want: []int64{none.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks, listErr := s.ListTasksByExpr(ctx, tt.expr, ListTasksByExprOptions{})
+			require.NoError(t, listErr, "ListTasksByExpr() error")
+			assert.ElementsMatch(t, tt.want, taskIDs(tasks), "ids mismatch")
+		})
+	}
+}
+
+func TestListTasksByExpr_AvailablePredicate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	office, err := s.CreateTask(ctx, &Task{Title: "Print", State: StateNow, Contexts: []string{"Office"}})
+	require.NoError(t, err, "CreateTask(office) error")
+	phone, err := s.CreateTask(ctx, &Task{Title: "Call", State: StateNow, Contexts: []string{"phone"}})
+	require.NoError(t, err, "CreateTask(phone) error")
+	either, err := s.CreateTask(ctx, &Task{Title: "Email", State: StateNow, Contexts: []string{"office", "phone"}})
+	require.NoError(t, err, "CreateTask(either) error")
+	anywhere, err := s.CreateTask(ctx, &Task{Title: "Think", State: StateNow})
+	require.NoError(t, err, "CreateTask(anywhere) error")
+
+	expr := nlp.Predicate{Kind: nlp.PredAvailable}
+	tasks, err := s.ListTasksByExpr(ctx, expr, ListTasksByExprOptions{ClosedContexts: []string{"office"}})
+	require.NoError(t, err, "ListTasksByExpr(office closed) error")
+	assert.ElementsMatch(t, []int64{phone.ID, either.ID, anywhere.ID}, taskIDs(tasks), "office closed ids mismatch")
+
+	tasks, err = s.ListTasksByExpr(ctx, nlp.FilterNot{Expr: expr},
+		ListTasksByExprOptions{ClosedContexts: []string{"office", "phone"}})
+	require.NoError(t, err, "ListTasksByExpr(not available) error")
+	assert.ElementsMatch(t, []int64{office.ID, phone.ID, either.ID}, taskIDs(tasks), "not available ids mismatch")
+
+	tasks, err = s.ListTasksByExpr(ctx, expr, ListTasksByExprOptions{})
+	require.NoError(t, err, "ListTasksByExpr(nothing closed) error")
+	assert.Len(t, tasks, 4, "every task should be available when nothing is closed")
+}
+
+func TestListTasksByExpr_SortKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	march := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	bravo, err := s.CreateTask(ctx, &Task{Title: "bravo", State: StateNow, DueOn: &april})
+	require.NoError(t, err, "CreateTask(bravo) error")
+	alpha, err := s.CreateTask(ctx, &Task{Title: "Alpha", State: StateLater})
+	require.NoError(t, err, "CreateTask(alpha) error")
+	charlie, err := s.CreateTask(ctx, &Task{Title: "charlie", State: StateInbox, DueOn: &march})
+	require.NoError(t, err, "CreateTask(charlie) error")
+
+	tests := []struct {
+		name string
+		sort []nlp.SortKey
+		want []int64
+	}{
+		{
+			name: "title ignores case",
+			sort: []nlp.SortKey{{Field: nlp.SortFieldTitle}},
+			want: []int64{alpha.ID, bravo.ID, charlie.ID},
+		},
+		{
+			name: "due descending keeps undated last",
+			sort: []nlp.SortKey{{Field: nlp.SortFieldDue, Desc: true}},
+			want: []int64{bravo.ID, charlie.ID, alpha.ID},
+		},
+		{
+			name: "state follows workflow order",
+			sort: []nlp.SortKey{{Field: nlp.SortFieldState}},
+			want: []int64{charlie.ID, bravo.ID, alpha.ID},
+		},
+		{
+			name: "id descending",
+			sort: []nlp.SortKey{{Field: nlp.SortFieldID, Desc: true}},
+			want: []int64{charlie.ID, alpha.ID, bravo.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tasks, listErr := s.ListTasksByExpr(ctx, nil, ListTasksByExprOptions{Sort: tt.sort})
+			require.NoError(t, listErr, "ListTasksByExpr() error")
+			assert.Equal(t, tt.want, taskIDs(tasks), "ids mismatch")
+		})
+	}
+}
+
 func taskIDs(tasks []*Task) []int64 {
 	ids := make([]int64, 0, len(tasks))
 	for _, task := range tasks {
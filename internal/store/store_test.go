@@ -0,0 +1,349 @@
+//nolint:testpackage // Tests share the openTestStore helper with the filter tests.
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/nlp"
+)
+
+func TestWithTx_CommitsOnSuccess(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+	task, err := s.CreateTask(ctx, &Task{Title: "Commit me", State: StateNow})
+	require.NoError(t, err, "CreateTask error")
+
+	err = s.WithTx(ctx, func(tx *Store) error {
+		_, doneErr := tx.SetDone(ctx, []int64{task.ID}, true)
+		return doneErr
+	})
+	require.NoError(t, err, "WithTx error")
+
+	got, err := s.GetTask(ctx, task.ID)
+	require.NoError(t, err, "GetTask error")
+	assert.Equal(t, StateDone, got.State, "state after commit")
+}
+
+func TestWithTx_RollsBackOnError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+	first, err := s.CreateTask(ctx, &Task{Title: "First", State: StateNow})
+	require.NoError(t, err, "CreateTask(first) error")
+	second, err := s.CreateTask(ctx, &Task{Title: "Second", State: StateNow})
+	require.NoError(t, err, "CreateTask(second) error")
+
+	errBoom := errors.New("boom")
+	err = s.WithTx(ctx, func(tx *Store) error {
+		if _, doneErr := tx.SetDone(ctx, []int64{first.ID}, true); doneErr != nil {
+			return doneErr
+		}
+		if _, delErr := tx.DeleteTasks(ctx, []int64{second.ID}); delErr != nil {
+			return delErr
+		}
+		return errBoom
+	})
+	require.ErrorIs(t, err, errBoom)
+
+	got, err := s.GetTask(ctx, first.ID)
+	require.NoError(t, err, "GetTask(first) error")
+	assert.Equal(t, StateNow, got.State, "first task state after rollback")
+	_, err = s.GetTask(ctx, second.ID)
+	require.NoError(t, err, "second task should survive rollback")
+}
+
+func TestShellSessionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	missing, err := s.GetShellSession(ctx, "work")
+	require.NoError(t, err, "GetShellSession(missing) error")
+	assert.Nil(t, missing, "missing session should be nil")
+
+	selected := int64(7)
+	err = s.SaveShellSession(ctx, ShellSession{
+		Name:            "work",
+		SelectedTaskID:  &selected,
+		LastTaskIDs:     []int64{7, 8},
+		PreviousResults: [][]int64{{3}, {}},
+		ContextProject:  "work",
+		ContextFilter:   "state:now && due:today",
+	})
+	require.NoError(t, err, "SaveShellSession error")
+	require.NoError(t, s.SaveShellSession(ctx, ShellSession{Name: "home", ContextContext: "home"}), "save home")
+
+	got, err := s.GetShellSession(ctx, "work")
+	require.NoError(t, err, "GetShellSession error")
+	require.NotNil(t, got, "saved session should load")
+	assert.Equal(t, &selected, got.SelectedTaskID, "selected task mismatch")
+	assert.Equal(t, []int64{7, 8}, got.LastTaskIDs, "last task ids mismatch")
+	assert.Equal(t, [][]int64{{3}, {}}, got.PreviousResults, "previous results mismatch")
+	assert.Equal(t, "work", got.ContextProject, "project context mismatch")
+	assert.Equal(t, "state:now && due:today", got.ContextFilter, "context filter mismatch")
+
+	sessions, err := s.ListShellSessions(ctx)
+	require.NoError(t, err, "ListShellSessions error")
+	assert.Len(t, sessions, 2, "session count mismatch")
+
+	dropped, err := s.DeleteShellSession(ctx, "work")
+	require.NoError(t, err, "DeleteShellSession error")
+	assert.True(t, dropped, "existing session should be dropped")
+	dropped, err = s.DeleteShellSession(ctx, "work")
+	require.NoError(t, err, "DeleteShellSession(again) error")
+	assert.False(t, dropped, "dropped session should be gone")
+}
+
+func TestListStalledProjects(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	tasks := []*Task{
+		{Title: "Ship it", State: StateNow, Projects: []string{"work"}},
+		{Title: "Plan", State: StateLater, Projects: []string{"work"}},
+		{Title: "Quote", State: StateWaiting, Projects: []string{"kitchen"}},
+		{Title: "Tiles", State: StateLater, Projects: []string{"kitchen", "home"}},
+		{Title: "Sort mail", State: StateInbox, Projects: []string{"home"}},
+		{Title: "Paint", State: StateDone, Projects: []string{"garden"}},
+	}
+	for _, task := range tasks {
+		_, err := s.CreateTask(ctx, task)
+		require.NoError(t, err, "CreateTask(%q) error", task.Title)
+	}
+
+	projects, err := s.ListStalledProjects(ctx)
+	require.NoError(t, err, "ListStalledProjects error")
+	require.Len(t, projects, 2, "work has a now task and garden is finished")
+
+	byName := map[string]StalledProject{}
+	for _, project := range projects {
+		byName[project.Name] = project
+		assert.False(t, project.LastActivity.IsZero(), "last activity should be set for %s", project.Name)
+	}
+	assert.Equal(t, StalledProject{Name: "home", Open: 2, Inbox: 1, Later: 1}, withoutActivity(byName["home"]),
+		"home counts mismatch")
+	assert.Equal(t, StalledProject{Name: "kitchen", Open: 2, Waiting: 1, Later: 1}, withoutActivity(byName["kitchen"]),
+		"kitchen counts mismatch")
+}
+
+func withoutActivity(project StalledProject) StalledProject {
+	project.LastActivity = time.Time{}
+	return project
+}
+
+func TestListWaitingSince(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	// Each step records a version; backdate them one day apart so the
+	// waiting start is the first version of the current waiting stretch.
+	steps := []struct {
+		title string
+		state State
+	}{
+		{title: "Quote", state: StateWaiting},
+		{title: "Quote for tiles", state: StateWaiting},
+		{title: "Quote for tiles", state: StateNow},
+		{title: "Quote for tiles", state: StateWaiting},
+		{title: "Quote for floor tiles", state: StateWaiting},
+	}
+	task, err := s.CreateTask(ctx, &Task{Title: steps[0].title, State: steps[0].state})
+	require.NoError(t, err, "CreateTask error")
+	for _, step := range steps[1:] {
+		task.Title, task.State = step.title, step.state
+		task, err = s.UpdateTask(ctx, task)
+		require.NoError(t, err, "UpdateTask(%s) error", step.state)
+	}
+	start := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	_, err = s.conn.ExecContext(ctx,
+		"UPDATE task_versions SET updated_at = ? + (version_id - 1) * 86400 WHERE task_id = ?",
+		start.Unix(), task.ID)
+	require.NoError(t, err, "backdate versions error")
+
+	fresh, err := s.CreateTask(ctx, &Task{Title: "Reply", State: StateWaiting})
+	require.NoError(t, err, "CreateTask(fresh) error")
+	_, err = s.CreateTask(ctx, &Task{Title: "Ship", State: StateNow})
+	require.NoError(t, err, "CreateTask(now) error")
+
+	since, err := s.ListWaitingSince(ctx)
+	require.NoError(t, err, "ListWaitingSince error")
+	require.Len(t, since, 2, "only waiting tasks are listed")
+	assert.Equal(t, start.AddDate(0, 0, 3), since[task.ID], "waiting should restart after the now version")
+	assert.Equal(t, fresh.UpdatedAt.Unix(), since[fresh.ID].Unix(), "a new waiting task waits from creation")
+}
+
+func TestWakeSnoozed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+	now := time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	waiting, nowState := StateWaiting, StateNow
+
+	due, err := s.CreateTask(ctx, &Task{Title: "Call back", State: StateLater, PrevState: &waiting, SnoozedUntil: &past})
+	require.NoError(t, err, "CreateTask(due) error")
+	unknown, err := s.CreateTask(ctx, &Task{Title: "Old snooze", State: StateLater, SnoozedUntil: &past})
+	require.NoError(t, err, "CreateTask(unknown) error")
+	pending, err := s.CreateTask(ctx, &Task{
+		Title: "Renew", State: StateLater, PrevState: &nowState, SnoozedUntil: &future,
+	})
+	require.NoError(t, err, "CreateTask(pending) error")
+
+	snoozed, err := s.ListTasksByExpr(ctx, nil, ListTasksByExprOptions{OnlySnoozed: true})
+	require.NoError(t, err, "ListTasksByExpr(OnlySnoozed) error")
+	assert.Len(t, snoozed, 3, "all snoozed tasks should be listed")
+
+	woken, err := s.WakeSnoozed(ctx, now)
+	require.NoError(t, err, "WakeSnoozed error")
+	require.Len(t, woken, 2, "only tasks past their date should wake")
+	assert.Equal(t, due.ID, woken[0].ID, "woken order mismatch")
+	assert.Equal(t, StateWaiting, woken[0].State, "task should return to its previous state")
+	assert.Nil(t, woken[0].SnoozedUntil, "woken task should not stay snoozed")
+	assert.Nil(t, woken[0].PrevState, "woken task should forget its previous state")
+	assert.Equal(t, unknown.ID, woken[1].ID, "woken order mismatch")
+	assert.Equal(t, StateInbox, woken[1].State, "task without a previous state should return to inbox")
+
+	got, err := s.GetTask(ctx, pending.ID)
+	require.NoError(t, err, "GetTask(pending) error")
+	assert.Equal(t, StateLater, got.State, "task before its date should stay later")
+	require.NotNil(t, got.SnoozedUntil, "pending snooze date should be kept")
+	assert.True(t, future.Equal(*got.SnoozedUntil), "snooze date should round trip")
+
+	_, err = s.SetDone(ctx, []int64{pending.ID}, true)
+	require.NoError(t, err, "SetDone error")
+	got, err = s.GetTask(ctx, pending.ID)
+	require.NoError(t, err, "GetTask(done) error")
+	assert.Nil(t, got.SnoozedUntil, "completing a task should end its snooze")
+}
+
+func TestReviewRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	none, err := s.LastReview(ctx)
+	require.NoError(t, err, "LastReview(none) error")
+	assert.Nil(t, none, "no review should be nil")
+
+	first := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	_, err = s.RecordReview(ctx, Review{CompletedAt: first, TasksReviewed: 4})
+	require.NoError(t, err, "RecordReview(first) error")
+	second, err := s.RecordReview(ctx, Review{CompletedAt: first.AddDate(0, 0, 7), TasksReviewed: 6, TasksChanged: 2})
+	require.NoError(t, err, "RecordReview(second) error")
+
+	last, err := s.LastReview(ctx)
+	require.NoError(t, err, "LastReview error")
+	require.NotNil(t, last, "recorded review should load")
+	assert.Equal(t, second.ID, last.ID, "last review id mismatch")
+	assert.Equal(t, first.AddDate(0, 0, 7), last.CompletedAt, "completed at mismatch")
+	assert.Equal(t, int64(6), last.TasksReviewed, "reviewed count mismatch")
+	assert.Equal(t, int64(2), last.TasksChanged, "changed count mismatch")
+}
+
+func TestChecklistRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	task, err := s.CreateTask(ctx, &Task{Title: "Pack", State: StateNow})
+	require.NoError(t, err, "CreateTask error")
+	assert.Empty(t, task.Checklist, "new task should have no checklist")
+
+	task.Checklist = []ChecklistItem{{Text: "passport", Done: true}, {Text: "charger"}}
+	_, err = s.UpdateTask(ctx, task)
+	require.NoError(t, err, "UpdateTask error")
+
+	got, err := s.GetTask(ctx, task.ID)
+	require.NoError(t, err, "GetTask error")
+	assert.Equal(t, task.Checklist, got.Checklist, "checklist should round trip")
+
+	listed, err := s.ListTasksByExpr(ctx, nil, ListTasksByExprOptions{})
+	require.NoError(t, err, "ListTasksByExpr error")
+	require.Len(t, listed, 1, "task should be listed")
+	assert.Equal(t, task.Checklist, listed[0].Checklist, "listed checklist mismatch")
+
+	_, err = s.SetDone(ctx, []int64{task.ID}, true)
+	require.NoError(t, err, "SetDone error")
+	versions, err := s.ListTaskVersions(ctx, task.ID, 0)
+	require.NoError(t, err, "ListTaskVersions error")
+	require.Len(t, versions, 3, "each write should add a version")
+	assert.Equal(t, task.Checklist, versions[0].Checklist, "done version should keep the checklist")
+	assert.Empty(t, versions[2].Checklist, "first version had no checklist")
+}
+
+func TestAnnotations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	task, err := s.CreateTask(ctx, &Task{Title: "Order parts", State: StateWaiting})
+	require.NoError(t, err, "CreateTask error")
+	other, err := s.CreateTask(ctx, &Task{Title: "Water plants"})
+	require.NoError(t, err, "CreateTask(other) error")
+
+	_, err = s.AddAnnotation(ctx, task.ID, "  called vendor, waiting on quote ")
+	require.NoError(t, err, "AddAnnotation(first) error")
+	_, err = s.AddAnnotation(ctx, task.ID, "quote arrived")
+	require.NoError(t, err, "AddAnnotation(second) error")
+	_, err = s.AddAnnotation(ctx, task.ID, " ")
+	require.Error(t, err, "AddAnnotation should reject empty text")
+
+	got, err := s.GetTask(ctx, task.ID)
+	require.NoError(t, err, "GetTask error")
+	require.Len(t, got.Annotations, 2, "annotations should load with the task")
+	assert.Equal(t, "called vendor, waiting on quote", got.Annotations[0].Text, "oldest annotation comes first")
+	assert.Equal(t, "quote arrived", got.Annotations[1].Text, "annotation order mismatch")
+
+	_, err = s.UpdateTask(ctx, &Task{ID: task.ID, Title: "Order parts", State: StateNow})
+	require.NoError(t, err, "UpdateTask error")
+	got, err = s.GetTask(ctx, task.ID)
+	require.NoError(t, err, "GetTask(updated) error")
+	assert.Len(t, got.Annotations, 2, "editing a task should keep its annotations")
+
+	matches, err := s.ListTasksByExpr(ctx, nlp.Predicate{Kind: nlp.PredText, Text: "vendor"}, ListTasksByExprOptions{})
+	require.NoError(t, err, "ListTasksByExpr(text) error")
+	require.Len(t, matches, 1, "text search should match annotations")
+	assert.Equal(t, task.ID, matches[0].ID, "matched task mismatch")
+	assert.Len(t, matches[0].Annotations, 2, "listed tasks carry their annotations")
+
+	got, err = s.GetTask(ctx, other.ID)
+	require.NoError(t, err, "GetTask(other) error")
+	assert.Empty(t, got.Annotations, "other task has no annotations")
+}
+
+func TestListTasksByExprLargeIDSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := openTestStore(t)
+	task, err := s.CreateTask(ctx, &Task{Title: "Piped", State: StateNow})
+	require.NoError(t, err, "CreateTask error")
+
+	ids := make([]int64, 0, 5000)
+	for id := int64(1); id <= 5000; id++ {
+		ids = append(ids, id)
+	}
+	tasks, err := s.ListTasksByExpr(ctx, nlp.Predicate{Kind: nlp.PredID, IDs: ids}, ListTasksByExprOptions{})
+	require.NoError(t, err, "ListTasksByExpr should accept thousands of ids")
+	require.Len(t, tasks, 1, "matches mismatch")
+	assert.Equal(t, task.ID, tasks[0].ID, "matched task mismatch")
+}
@@ -1,6 +1,11 @@
 package store
 
-import "time"
+import (
+	"time"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/nlp"
+)
 
 type State string
 
@@ -13,19 +18,38 @@ const (
 )
 
 type Task struct {
-	ID          int64
-	State       State
-	PrevState   *State
-	Title       string
-	Notes       string
-	DueOn       *time.Time
-	WaitingFor  string
-	CompletedAt *time.Time
-	Projects    []string
-	Contexts    []string
-	Meta        map[string]string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           int64
+	State        State
+	PrevState    *State
+	Title        string
+	Notes        string
+	DueOn        *time.Time
+	WaitingFor   string
+	FollowUpOn   *time.Time
+	SnoozedUntil *time.Time
+	CompletedAt  *time.Time
+	Projects     []string
+	Contexts     []string
+	Meta         map[string]string
+	Checklist    []ChecklistItem
+	Annotations  []Annotation
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ChecklistItem is one step of a task's checklist, too small to be a task.
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Annotation is a timestamped comment on a task. Annotations are kept in
+// their own table and only ever appended, so they survive concurrent edits.
+type Annotation struct {
+	ID        int64
+	TaskID    int64
+	Text      string
+	CreatedAt time.Time
 }
 
 type ListTasksByExprOptions struct {
@@ -33,6 +57,13 @@ type ListTasksByExprOptions struct {
 	OnlyDone    bool
 	Recent      bool
 	Limit       int64
+	Sort        []nlp.SortKey
+	MetaSchema  domain.MetaSchema
+	// ClosedContexts are the lowercase contexts that cannot be used now; the
+	// available predicate matches tasks with no contexts or another context.
+	ClosedContexts []string
+	// OnlySnoozed keeps later tasks that have a snooze date.
+	OnlySnoozed bool
 }
 
 type NameCount struct {
@@ -49,19 +80,55 @@ type ShellHistory struct {
 	Intent        string
 }
 
+// StalledProject is a project with open tasks but no now task, with the
+// counts of its open tasks by state.
+type StalledProject struct {
+	Name    string
+	Open    int64
+	Inbox   int64
+	Waiting int64
+	Later   int64
+	// LastActivity is the latest update to any of the project's tasks,
+	// done ones included.
+	LastActivity time.Time
+}
+
+// ShellSession is the saved state of a named shell session.
+type ShellSession struct {
+	Name            string
+	UpdatedAt       int64
+	SelectedTaskID  *int64
+	LastTaskIDs     []int64
+	PreviousResults [][]int64
+	ContextProject  string
+	ContextContext  string
+	ContextFilter   string
+}
+
+// Review is a completed weekly review.
+type Review struct {
+	ID            int64
+	CompletedAt   time.Time
+	TasksReviewed int64
+	TasksChanged  int64
+}
+
 type TaskVersion struct {
-	VersionID   int64
-	TaskID      int64
-	State       State
-	PrevState   *State
-	Title       string
-	Notes       string
-	DueOn       *time.Time
-	WaitingFor  string
-	CompletedAt *time.Time
-	UpdatedAt   time.Time
-	Deleted     bool
-	Projects    []string
-	Contexts    []string
-	Meta        map[string]string
+	VersionID    int64
+	TaskID       int64
+	State        State
+	PrevState    *State
+	Title        string
+	Notes        string
+	DueOn        *time.Time
+	WaitingFor   string
+	FollowUpOn   *time.Time
+	SnoozedUntil *time.Time
+	CompletedAt  *time.Time
+	UpdatedAt    time.Time
+	Deleted      bool
+	Projects     []string
+	Contexts     []string
+	Meta         map[string]string
+	Checklist    []ChecklistItem
 }
@@ -0,0 +1,317 @@
+// Package templates creates groups of tasks from named templates. Templates
+// come from [templates] in the config and from TOML files in the templates
+// directory next to it.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/mholtzscher/ugh/internal/config"
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+// DirName is the directory next to the config file that holds template files.
+const DirName = "templates"
+
+const (
+	fileExt     = ".toml"
+	dirMode     = 0o750
+	fileMode    = 0o600
+	daysPerWeek = 7
+)
+
+//nolint:gochecknoglobals // Compiled patterns are fixed lookup tables.
+var (
+	namePattern        = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	placeholderPattern = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_-]*)\}`)
+	offsetPattern      = regexp.MustCompile(`^([+-]?[0-9]+)([dw])$`)
+)
+
+// Set maps lowercase template names to templates.
+type Set map[string]config.Template
+
+// Load returns the configured templates together with the *.toml files in
+// dir, each named after its file. A missing dir holds no templates; a name
+// defined in both places is an error.
+func Load(dir string, configured map[string]config.Template) (Set, error) {
+	set := make(Set, len(configured))
+	for name, tmpl := range configured {
+		set[normalizeName(name)] = tmpl
+	}
+	if dir == "" {
+		return set, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return set, nil
+		}
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt {
+			continue
+		}
+		name := normalizeName(strings.TrimSuffix(entry.Name(), fileExt))
+		path := filepath.Join(dir, entry.Name())
+		if _, ok := set[name]; ok {
+			return nil, fmt.Errorf("template %q is defined in both the config and %s", name, path)
+		}
+		var tmpl config.Template
+		if _, err = toml.DecodeFile(path, &tmpl); err != nil {
+			return nil, fmt.Errorf("template %s: %w", path, err)
+		}
+		set[name] = tmpl
+	}
+	return set, nil
+}
+
+// Lookup returns the template called name, ignoring case.
+func (s Set) Lookup(name string) (config.Template, error) {
+	tmpl, ok := s[normalizeName(name)]
+	if !ok {
+		return config.Template{}, fmt.Errorf("unknown template: %s", name)
+	}
+	return tmpl, nil
+}
+
+// Names returns the template names in order.
+func (s Set) Names() []string {
+	return slices.Sorted(maps.Keys(s))
+}
+
+// Count returns the number of tasks tmpl creates, itself included.
+func Count(tmpl config.Template) int {
+	count := 1
+	for _, child := range tmpl.Tasks {
+		count += Count(child)
+	}
+	return count
+}
+
+// Variables returns the lowercase placeholder names used in tmpl, sorted.
+func Variables(tmpl config.Template) []string {
+	e := expander{vars: map[string]string{}, missing: map[string]bool{}}
+	e.collect(tmpl)
+	return slices.Sorted(maps.Keys(e.missing))
+}
+
+// ValidName reports whether name can be used as a template file name.
+func ValidName(name string) bool {
+	return namePattern.MatchString(normalizeName(name))
+}
+
+// ParseVars reads name=value pairs, as given to --var.
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("variable %q must be name=value", pair)
+		}
+		vars[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+	return vars, nil
+}
+
+// Expand fills the placeholders of tmpl from vars and returns the requests
+// for the template task followed by its child tasks, depth first. Children
+// add the projects and contexts of their parent to their own. Variable names
+// ignore case; every placeholder must have a value.
+func Expand(tmpl config.Template, vars map[string]string, now time.Time) ([]service.CreateTaskRequest, error) {
+	lowered := make(map[string]string, len(vars))
+	for name, value := range vars {
+		lowered[strings.ToLower(name)] = value
+	}
+	e := expander{vars: lowered, missing: map[string]bool{}, now: now}
+	reqs, err := e.expand(tmpl, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.missing) > 0 {
+		return nil, fmt.Errorf("missing template variables: %s", strings.Join(slices.Sorted(maps.Keys(e.missing)), ", "))
+	}
+	return reqs, nil
+}
+
+// Create expands tmpl and creates its tasks in order within one transaction,
+// so a failure leaves none of them behind.
+func Create(
+	ctx context.Context, svc service.Service, tmpl config.Template, vars map[string]string, now time.Time,
+) ([]*store.Task, error) {
+	reqs, err := Expand(tmpl, vars, now)
+	if err != nil {
+		return nil, err
+	}
+	return svc.CreateTasks(ctx, reqs)
+}
+
+// FromTask captures task as a template. Its due date becomes an offset from
+// now; done and inbox tasks leave the state to the default.
+func FromTask(task *store.Task, now time.Time) config.Template {
+	tmpl := config.Template{
+		Title:    task.Title,
+		Notes:    task.Notes,
+		Projects: slices.Clone(task.Projects),
+		Contexts: slices.Clone(task.Contexts),
+		Meta:     maps.Clone(task.Meta),
+	}
+	switch task.State {
+	case store.StateNow, store.StateWaiting, store.StateLater:
+		tmpl.State = string(task.State)
+	case store.StateInbox, store.StateDone:
+	}
+	if task.DueOn != nil {
+		days := domain.DateIn(*task.DueOn, now.Location()).Sub(domain.StartOfDay(now)).Hours() / domain.HoursPerDay
+		tmpl.Due = strconv.Itoa(int(math.Round(days))) + "d"
+	}
+	return tmpl
+}
+
+// Save writes tmpl to <dir>/<name>.toml and returns the path. An existing
+// file is only replaced when overwrite is set.
+func Save(dir string, name string, tmpl config.Template, overwrite bool) (string, error) {
+	name = normalizeName(name)
+	if !ValidName(name) {
+		return "", fmt.Errorf("invalid template name %q: use letters, digits, - and _", name)
+	}
+	path := filepath.Join(dir, name+fileExt)
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "", fmt.Errorf("template %s already exists: %s", name, path)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tmpl); err != nil {
+		return "", fmt.Errorf("encode template: %w", err)
+	}
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return "", fmt.Errorf("create templates dir: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), fileMode); err != nil {
+		return "", fmt.Errorf("write template: %w", err)
+	}
+	return path, nil
+}
+
+// DueOffset resolves a due offset such as "3d", "+2w" or "-1d" against now.
+func DueOffset(offset string, now time.Time) (time.Time, error) {
+	match := offsetPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(offset)))
+	if match == nil {
+		return time.Time{}, fmt.Errorf("due %q must be an offset such as 3d or 2w", offset)
+	}
+	count, err := strconv.Atoi(match[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("due %q: %w", offset, err)
+	}
+	if match[2] == "w" {
+		count *= daysPerWeek
+	}
+	return domain.StartOfDay(now).AddDate(0, 0, count), nil
+}
+
+type expander struct {
+	vars    map[string]string
+	missing map[string]bool
+	now     time.Time
+}
+
+func (e *expander) expand(
+	tmpl config.Template, projects []string, contexts []string,
+) ([]service.CreateTaskRequest, error) {
+	req := service.CreateTaskRequest{
+		Title:    e.fill(tmpl.Title),
+		Notes:    e.fill(tmpl.Notes),
+		State:    e.fill(tmpl.State),
+		Projects: appendMissing(slices.Clone(projects), e.fillAll(tmpl.Projects)),
+		Contexts: appendMissing(slices.Clone(contexts), e.fillAll(tmpl.Contexts)),
+	}
+	for _, key := range slices.Sorted(maps.Keys(tmpl.Meta)) {
+		req.Meta = append(req.Meta, e.fill(key)+":"+e.fill(tmpl.Meta[key]))
+	}
+	if due := e.fill(tmpl.Due); due != "" {
+		day, err := DueOffset(due, e.now)
+		if err != nil {
+			return nil, err
+		}
+		req.DueOn = day.Format(time.DateOnly)
+	}
+
+	reqs := []service.CreateTaskRequest{req}
+	for _, child := range tmpl.Tasks {
+		childReqs, err := e.expand(child, req.Projects, req.Contexts)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, childReqs...)
+	}
+	return reqs, nil
+}
+
+// collect records the placeholders of tmpl and its children as missing.
+func (e *expander) collect(tmpl config.Template) {
+	for _, text := range []string{tmpl.Title, tmpl.Notes, tmpl.State, tmpl.Due} {
+		e.fill(text)
+	}
+	e.fillAll(tmpl.Projects)
+	e.fillAll(tmpl.Contexts)
+	for key, value := range tmpl.Meta {
+		e.fill(key)
+		e.fill(value)
+	}
+	for _, child := range tmpl.Tasks {
+		e.collect(child)
+	}
+}
+
+// fill replaces {name} placeholders, recording names without a value.
+func (e *expander) fill(text string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := strings.ToLower(placeholder[1 : len(placeholder)-1])
+		value, ok := e.vars[name]
+		if !ok {
+			e.missing[name] = true
+			return placeholder
+		}
+		return value
+	})
+}
+
+func (e *expander) fillAll(values []string) []string {
+	filled := make([]string, 0, len(values))
+	for _, value := range values {
+		filled = append(filled, e.fill(value))
+	}
+	return filled
+}
+
+// appendMissing adds the values not already in list, ignoring case.
+func appendMissing(list []string, values []string) []string {
+	for _, value := range values {
+		if !slices.ContainsFunc(list, func(have string) bool { return strings.EqualFold(have, value) }) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,160 @@
+package templates_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/config"
+	"github.com/mholtzscher/ugh/internal/service"
+	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/templates"
+)
+
+func onboarding() config.Template {
+	return config.Template{
+		Title:    "Onboard {name}",
+		Notes:    "Starts {Start}",
+		State:    "now",
+		Projects: []string{"hiring"},
+		Contexts: []string{"office"},
+		Meta:     map[string]string{"owner": "{name}", "area": "people"},
+		Due:      "2w",
+		Tasks: []config.Template{
+			{Title: "Order laptop for {name}", Projects: []string{"it", "Hiring"}, Due: "+3d"},
+			{
+				Title:    "Plan first week",
+				Contexts: []string{"desk"},
+				Tasks:    []config.Template{{Title: "Book lunch with {name}", Due: "-1d"}},
+			},
+		},
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	reqs, err := templates.Expand(onboarding(), map[string]string{"Name": "Sam", "start": "Monday"}, now)
+	require.NoError(t, err, "Expand() error")
+
+	assert.Equal(t, []service.CreateTaskRequest{
+		{
+			Title:    "Onboard Sam",
+			Notes:    "Starts Monday",
+			State:    "now",
+			Projects: []string{"hiring"},
+			Contexts: []string{"office"},
+			Meta:     []string{"area:people", "owner:Sam"},
+			DueOn:    "2026-03-24",
+		},
+		{
+			Title:    "Order laptop for Sam",
+			Projects: []string{"hiring", "it"},
+			Contexts: []string{"office"},
+			DueOn:    "2026-03-13",
+		},
+		{
+			Title:    "Plan first week",
+			Projects: []string{"hiring"},
+			Contexts: []string{"office", "desk"},
+		},
+		{
+			Title:    "Book lunch with Sam",
+			Projects: []string{"hiring"},
+			Contexts: []string{"office", "desk"},
+			DueOn:    "2026-03-09",
+		},
+	}, reqs, "requests mismatch")
+
+	_, err = templates.Expand(onboarding(), map[string]string{"other": "x"}, now)
+	require.Error(t, err, "Expand() should fail without the variables")
+	assert.Equal(t, "missing template variables: name, start", err.Error(), "error should list missing variables")
+
+	_, err = templates.Expand(config.Template{Title: "Ship", Due: "friday"}, nil, now)
+	assert.Error(t, err, "Expand() should reject due dates that are not offsets")
+}
+
+func TestCountAndVariables(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 4, templates.Count(onboarding()), "count mismatch")
+	assert.Equal(t, []string{"name", "start"}, templates.Variables(onboarding()), "variables mismatch")
+	assert.Empty(t, templates.Variables(config.Template{Title: "Plain"}), "plain template has no variables")
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := "title = \"Pack for {place}\"\ndue = \"1d\"\n\n[[tasks]]\ntitle = \"Passport\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Travel.toml"), []byte(file), 0o600), "write template")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600), "write other file")
+
+	set, err := templates.Load(dir, map[string]config.Template{"Release": {Title: "Release {version}"}})
+	require.NoError(t, err, "Load() error")
+	assert.Equal(t, []string{"release", "travel"}, set.Names(), "names mismatch")
+
+	travel, err := set.Lookup("TRAVEL")
+	require.NoError(t, err, "Lookup() error")
+	assert.Equal(t, config.Template{
+		Title: "Pack for {place}",
+		Due:   "1d",
+		Tasks: []config.Template{{Title: "Passport"}},
+	}, travel, "template file mismatch")
+
+	_, err = set.Lookup("moving")
+	require.EqualError(t, err, "unknown template: moving", "unknown template error mismatch")
+
+	_, err = templates.Load(dir, map[string]config.Template{"travel": {Title: "Trip"}})
+	require.Error(t, err, "Load() should reject a name defined twice")
+
+	set, err = templates.Load(filepath.Join(dir, "missing"), nil)
+	require.NoError(t, err, "a missing templates directory should be empty")
+	assert.Empty(t, set, "set should be empty")
+}
+
+func TestSaveFromTask(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	tmpl := templates.FromTask(&store.Task{
+		ID:       42,
+		State:    store.StateWaiting,
+		Title:    "Renew passport",
+		Notes:    "Bring photos",
+		DueOn:    &due,
+		Projects: []string{"travel"},
+		Meta:     map[string]string{"cost": "120"},
+	}, now)
+	assert.Equal(t, config.Template{
+		Title:    "Renew passport",
+		Notes:    "Bring photos",
+		State:    "waiting",
+		Projects: []string{"travel"},
+		Meta:     map[string]string{"cost": "120"},
+		Due:      "5d",
+	}, tmpl, "FromTask() mismatch")
+	assert.Empty(t, templates.FromTask(&store.Task{State: store.StateDone}, now).State, "done state is not kept")
+
+	dir := filepath.Join(t.TempDir(), templates.DirName)
+	path, err := templates.Save(dir, "Passport", tmpl, false)
+	require.NoError(t, err, "Save() error")
+	assert.Equal(t, filepath.Join(dir, "passport.toml"), path, "path mismatch")
+
+	_, err = templates.Save(dir, "passport", tmpl, false)
+	require.Error(t, err, "Save() should not replace a template without overwrite")
+	_, err = templates.Save(dir, "passport", tmpl, true)
+	require.NoError(t, err, "Save() with overwrite error")
+	_, err = templates.Save(dir, "../escape", tmpl, true)
+	require.Error(t, err, "Save() should reject names that are not file names")
+
+	set, err := templates.Load(dir, nil)
+	require.NoError(t, err, "Load() error")
+	assert.Equal(t, tmpl, set["passport"], "saved template should load back")
+}
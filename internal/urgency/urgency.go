@@ -0,0 +1,221 @@
+// Package urgency scores tasks by how pressing they are. The score is a sum of
+// weighted factors, each between 0 and 1, so every part of it can be shown.
+package urgency
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mholtzscher/ugh/internal/domain"
+	"github.com/mholtzscher/ugh/internal/store"
+)
+
+// Factor names, in the order they are reported.
+const (
+	FactorDue     = "due"
+	FactorAge     = "age"
+	FactorState   = "state"
+	FactorProject = "project"
+	FactorContext = "context"
+	FactorBlocked = "blocked"
+	FactorWaiting = "waiting"
+)
+
+// BlockedKey is the meta key that marks a task as blocked, e.g. meta:blocked:vendor.
+const BlockedKey = "blocked"
+
+const (
+	// dueHorizonDays is how far ahead a due date starts to count; the due
+	// factor grows from 0 there to 1 on the due date.
+	dueHorizonDays = 14
+	// ageFullDays is the age at which the age factor reaches 1.
+	ageFullDays = 365
+	// inboxState is the state factor of inbox tasks; now tasks score 1.
+	inboxState = 0.5
+)
+
+// Weights scale each factor of the score. Negative weights push tasks down.
+type Weights struct {
+	Due     float64
+	Age     float64
+	State   float64
+	Project float64
+	Context float64
+	Blocked float64
+	Waiting float64
+}
+
+// DefaultWeights returns the weights used when the config sets none.
+func DefaultWeights() Weights {
+	return Weights{
+		Due:     12,
+		Age:     2,
+		State:   4,
+		Project: 3,
+		Context: 2,
+		Blocked: -5,
+		Waiting: -3,
+	}
+}
+
+// Scorer computes urgency scores.
+type Scorer struct {
+	Weights Weights
+	// Projects maps project names to priorities, usually between 0 and 1. A
+	// task takes the highest priority of its projects.
+	Projects map[string]float64
+	// Context is the context being worked in, without "@". Tasks in it score
+	// the context factor; Rank drops tasks that only belong to other contexts.
+	Context string
+}
+
+// NewScorer returns a scorer with the default weights.
+func NewScorer() Scorer {
+	return Scorer{Weights: DefaultWeights()}
+}
+
+// Factor is one weighted part of a score.
+type Factor struct {
+	Name   string
+	Value  float64
+	Weight float64
+}
+
+// Points returns the factor's contribution to the score.
+func (f Factor) Points() float64 {
+	return f.Value * f.Weight
+}
+
+// Score is a task's urgency with the factors that produced it. Factors lists
+// only the factors that apply to the task.
+type Score struct {
+	Total   float64
+	Factors []Factor
+}
+
+// Scored is a task with its score.
+type Scored struct {
+	Task  *store.Task
+	Score Score
+}
+
+// Score computes the urgency of task at now.
+func (s Scorer) Score(task *store.Task, now time.Time) Score {
+	candidates := []Factor{
+		{Name: FactorDue, Value: dueValue(task.DueOn, now), Weight: s.Weights.Due},
+		{Name: FactorAge, Value: ageValue(task.CreatedAt, now), Weight: s.Weights.Age},
+		{Name: FactorState, Value: stateValue(task.State), Weight: s.Weights.State},
+		{Name: FactorProject, Value: s.projectValue(task.Projects), Weight: s.Weights.Project},
+		{Name: FactorContext, Value: boolValue(s.inContext(task)), Weight: s.Weights.Context},
+		{Name: FactorBlocked, Value: boolValue(isBlocked(task)), Weight: s.Weights.Blocked},
+		{Name: FactorWaiting, Value: boolValue(task.State == store.StateWaiting), Weight: s.Weights.Waiting},
+	}
+
+	score := Score{}
+	for _, factor := range candidates {
+		if factor.Value == 0 || factor.Weight == 0 {
+			continue
+		}
+		score.Factors = append(score.Factors, factor)
+		score.Total += factor.Points()
+	}
+	return score
+}
+
+// ScoreAll scores tasks in their given order.
+func (s Scorer) ScoreAll(tasks []*store.Task, now time.Time) []Scored {
+	scored := make([]Scored, 0, len(tasks))
+	for _, task := range tasks {
+		scored = append(scored, Scored{Task: task, Score: s.Score(task, now)})
+	}
+	return scored
+}
+
+// Rank scores the open tasks that can be done in the scorer's context, most
+// urgent first. Ties keep the order of tasks.
+func (s Scorer) Rank(tasks []*store.Task, now time.Time) []Scored {
+	open := make([]*store.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.State == store.StateDone || !s.available(task) {
+			continue
+		}
+		open = append(open, task)
+	}
+	scored := s.ScoreAll(open, now)
+	slices.SortStableFunc(scored, func(a, b Scored) int {
+		return cmp.Compare(b.Score.Total, a.Score.Total)
+	})
+	return scored
+}
+
+// available reports whether task can be done in the scorer's context: it has
+// the context or no context at all.
+func (s Scorer) available(task *store.Task) bool {
+	return s.Context == "" || len(task.Contexts) == 0 || s.inContext(task)
+}
+
+func (s Scorer) inContext(task *store.Task) bool {
+	if s.Context == "" {
+		return false
+	}
+	return slices.ContainsFunc(task.Contexts, func(name string) bool {
+		return strings.EqualFold(name, s.Context)
+	})
+}
+
+func (s Scorer) projectValue(projects []string) float64 {
+	best := 0.0
+	for _, project := range projects {
+		best = max(best, s.Projects[project])
+	}
+	return best
+}
+
+// dueValue is 1 for tasks due today or overdue and falls to 0 over
+// dueHorizonDays before the due date.
+func dueValue(due *time.Time, now time.Time) float64 {
+	if due == nil {
+		return 0
+	}
+	days := domain.DateIn(*due, now.Location()).Sub(domain.StartOfDay(now)).Hours() / domain.HoursPerDay
+	if days <= 0 {
+		return 1
+	}
+	return max(0, 1-days/dueHorizonDays)
+}
+
+// ageValue counts whole days, so tasks added today have no age.
+func ageValue(created time.Time, now time.Time) float64 {
+	if created.IsZero() {
+		return 0
+	}
+	days := math.Floor(now.Sub(created).Hours() / domain.HoursPerDay)
+	return min(1, max(0, days/ageFullDays))
+}
+
+func stateValue(state store.State) float64 {
+	switch state {
+	case store.StateNow:
+		return 1
+	case store.StateInbox:
+		return inboxState
+	case store.StateWaiting, store.StateLater, store.StateDone:
+		return 0
+	default:
+		return 0
+	}
+}
+
+func isBlocked(task *store.Task) bool {
+	return strings.TrimSpace(task.Meta[BlockedKey]) != ""
+}
+
+func boolValue(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
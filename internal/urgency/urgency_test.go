@@ -0,0 +1,112 @@
+package urgency_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mholtzscher/ugh/internal/store"
+	"github.com/mholtzscher/ugh/internal/urgency"
+)
+
+func TestScoreFactors(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)
+	scorer := urgency.NewScorer()
+	scorer.Projects = map[string]float64{"launch": 1, "chores": 0.5}
+	scorer.Context = "office"
+
+	score := scorer.Score(&store.Task{
+		State:     store.StateNow,
+		DueOn:     &due,
+		CreatedAt: now.AddDate(0, 0, -73),
+		Projects:  []string{"chores", "launch"},
+		Contexts:  []string{"Office"},
+		Meta:      map[string]string{urgency.BlockedKey: "vendor"},
+	}, now)
+
+	names := make([]string, 0, len(score.Factors))
+	for _, factor := range score.Factors {
+		names = append(names, factor.Name)
+	}
+	assert.Equal(t, []string{
+		urgency.FactorDue,
+		urgency.FactorAge,
+		urgency.FactorState,
+		urgency.FactorProject,
+		urgency.FactorContext,
+		urgency.FactorBlocked,
+	}, names, "factor names mismatch")
+	assert.InDelta(t, 0.5, score.Factors[0].Value, 1e-9, "due a week out should be half way")
+	assert.InDelta(t, 0.2, score.Factors[1].Value, 1e-9, "73 days should be a fifth of a year")
+	assert.InDelta(t, 1.0, score.Factors[3].Value, 1e-9, "project should take the highest priority")
+	assert.InDelta(t, 6+0.4+4+3+2-5, score.Total, 1e-9, "total mismatch")
+}
+
+func TestScoreDueAndState(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	overdue := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	farOff := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	scorer := urgency.Scorer{Weights: urgency.Weights{Due: 10, State: 2, Waiting: -3}}
+
+	tests := []struct {
+		name string
+		task store.Task
+		want float64
+	}{
+		{name: "overdue", task: store.Task{State: store.StateLater, DueOn: &overdue}, want: 10},
+		{name: "due beyond horizon", task: store.Task{State: store.StateLater, DueOn: &farOff}, want: 0},
+		{name: "inbox counts half", task: store.Task{State: store.StateInbox}, want: 1},
+		{name: "waiting penalty", task: store.Task{State: store.StateWaiting}, want: -3},
+		{name: "zero weight is skipped", task: store.Task{State: store.StateNow, CreatedAt: now.AddDate(-2, 0, 0)}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			score := scorer.Score(&tt.task, now)
+			assert.InDelta(t, tt.want, score.Total, 1e-9, "score mismatch")
+			for _, factor := range score.Factors {
+				assert.NotZero(t, factor.Points(), "factor %s should contribute", factor.Name)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tasks := []*store.Task{
+		{ID: 1, State: store.StateLater},
+		{ID: 2, State: store.StateNow, Contexts: []string{"home"}},
+		{ID: 3, State: store.StateDone, DueOn: &today},
+		{ID: 4, State: store.StateNow},
+		{ID: 5, State: store.StateLater, DueOn: &today, Contexts: []string{"office"}},
+	}
+
+	scorer := urgency.NewScorer()
+	ids := func(scored []urgency.Scored) []int64 {
+		out := make([]int64, 0, len(scored))
+		for _, item := range scored {
+			out = append(out, item.Task.ID)
+		}
+		return out
+	}
+
+	ranked := scorer.Rank(tasks, now)
+	assert.Equal(t, []int64{5, 2, 4, 1}, ids(ranked), "rank should drop done tasks and keep order on ties")
+
+	scorer.Context = "office"
+	ranked = scorer.Rank(tasks, now)
+	require.Len(t, ranked, 3, "rank should drop tasks only in other contexts")
+	assert.Equal(t, []int64{5, 4, 1}, ids(ranked), "context rank mismatch")
+	assert.InDelta(t, 14.0, ranked[0].Score.Total, 1e-9, "context match should add its weight")
+}